@@ -0,0 +1,74 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// RateLimits are the request rates allowed for an account; zero means the
+// limit is not enforced or not reported
+type RateLimits struct {
+	RequestsPerMinute int `json:"requests_per_minute"`
+	TokensPerMinute   int `json:"tokens_per_minute"`
+}
+
+// Account describes the subscription of the API key
+type Account struct {
+	Tier       string     `json:"tier"`
+	RateLimits RateLimits `json:"rate_limits"`
+	// MonthlyTokens is the monthly token allowance; zero when unlimited or
+	// not reported
+	MonthlyTokens   int    `json:"monthly_tokens"`
+	RemainingTokens int    `json:"remaining_tokens"`
+	ResetsAt        string `json:"resets_at,omitempty"` // UTC timestamp in ISO 8601 format
+}
+
+// AccountResponse represents the response from the account endpoint
+type AccountResponse struct {
+	Account Account `json:"account"`
+}
+
+// GetAccount retrieves the subscription tier, rate limits and remaining
+// allowance of the account
+func (c *Client) GetAccount(ctx context.Context) (*AccountResponse, error) {
+	resp, err := c.doRequest(ctx, "GET", "/account", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var accountResp AccountResponse
+	if err := json.NewDecoder(resp.Body).Decode(&accountResp); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return &accountResp, nil
+}
+
+// Fits reports whether tokens fit in the remaining monthly allowance. It is
+// always true for accounts without a reported allowance.
+func (a Account) Fits(tokens int) bool {
+	return a.MonthlyTokens <= 0 || tokens <= a.RemainingTokens
+}
+
+// EstimateBatchTokens returns a rough upper bound of the tokens a batch of
+// requests consumes: the estimated prompt plus MaxTokens, or
+// defaultCompletion for requests without MaxTokens. Use it with
+// Account.Fits to check a batch job before submitting it.
+func EstimateBatchTokens(reqs []ChatCompletionRequest, defaultCompletion int) int {
+	total := 0
+	for _, req := range reqs {
+		total += EstimateMessagesTokens(req.Messages)
+		completion := defaultCompletion
+		if req.MaxTokens != nil {
+			completion = *req.MaxTokens
+		}
+		n := 1
+		if req.N != nil && *req.N > 1 {
+			n = *req.N
+		}
+		total += completion * n
+	}
+	return total
+}
@@ -0,0 +1,38 @@
+package vultrai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccount(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+	mockTransport.SetResponse("GET", "/account", 200, AccountResponse{Account: Account{
+		Tier:            "pro",
+		RateLimits:      RateLimits{RequestsPerMinute: 600, TokensPerMinute: 200000},
+		MonthlyTokens:   1000000,
+		RemainingTokens: 2500,
+	}})
+
+	resp, err := client.GetAccount(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "pro", resp.Account.Tier)
+	assert.Equal(t, 600, resp.Account.RateLimits.RequestsPerMinute)
+	assert.True(t, resp.Account.Fits(2500))
+	assert.False(t, resp.Account.Fits(2501))
+	assert.True(t, Account{}.Fits(1<<30))
+}
+
+func TestEstimateBatchTokens(t *testing.T) {
+	maxTokens, n := 100, 2
+	reqs := []ChatCompletionRequest{
+		{Messages: []Message{{Role: "user", Content: "12345678"}}, MaxTokens: &maxTokens},
+		{Messages: []Message{{Role: "user", Content: "1234"}}, MaxTokens: &maxTokens, N: &n},
+		{Messages: []Message{{Role: "user", Content: ""}}},
+	}
+	// Prompts: 4+2, 4+1, 4; completions: 100, 200, 50
+	assert.Equal(t, 365, EstimateBatchTokens(reqs, 50))
+}
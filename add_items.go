@@ -0,0 +1,119 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// BatchOptions configures AddItems
+type BatchOptions struct {
+	Concurrency int // items added in parallel (default 4)
+}
+
+// AddItemResult is the outcome of adding one item with AddItems
+type AddItemResult struct {
+	Item *CollectionItem // the added item, nil when Err is set
+	Err  error
+}
+
+// AddItemsResponse reports the outcome of AddItems. Results is in the
+// order of the requests.
+type AddItemsResponse struct {
+	Results []AddItemResult
+	Usage   Usage // summed over the added items
+}
+
+// Succeeded returns the number of items that were added
+func (r *AddItemsResponse) Succeeded() int {
+	n := 0
+	for _, result := range r.Results {
+		if result.Err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed returns the indexes of the requests that failed
+func (r *AddItemsResponse) Failed() []int {
+	var failed []int
+	for i, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, i)
+		}
+	}
+	return failed
+}
+
+// AddItems adds items to a collection with opts.Concurrency workers. A
+// failed item doesn't stop the others: the response always reports every
+// item, and the error joins the failures when there are any, so the failed
+// requests can be retried from Failed. Once ctx is done no further items
+// are started, and those left report the context's error.
+func (c *Client) AddItems(ctx context.Context, collectionID string, reqs []AddItemRequest, opts BatchOptions) (*AddItemsResponse, error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	resp := &AddItemsResponse{Results: make([]AddItemResult, len(reqs))}
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for w := 0; w < min(concurrency, len(reqs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				itemResp, err := c.AddItem(ctx, collectionID, reqs[i])
+				if err != nil {
+					resp.Results[i].Err = err
+					continue
+				}
+				resp.Results[i].Item = &itemResp.Item
+
+				mu.Lock()
+				resp.Usage.PromptTokens += itemResp.Usage.PromptTokens
+				resp.Usage.CompletionTokens += itemResp.Usage.CompletionTokens
+				resp.Usage.TotalTokens += itemResp.Usage.TotalTokens
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// Items not handed out before ctx is done fail with its error
+	sent := feedIndexes(ctx, indexes, len(reqs))
+	close(indexes)
+	wg.Wait()
+	for i := sent; i < len(reqs); i++ {
+		resp.Results[i].Err = ctx.Err()
+	}
+
+	var errs []error
+	for i, result := range resp.Results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("item %d: %w", i, result.Err))
+		}
+	}
+	if len(errs) > 0 {
+		return resp, fmt.Errorf("error adding %d of %d items: %w", len(errs), len(reqs), errors.Join(errs...))
+	}
+	return resp, nil
+}
+
+// feedIndexes sends 0 to n-1 on indexes until ctx is done and returns how
+// many were sent
+func feedIndexes(ctx context.Context, indexes chan<- int, n int) int {
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			return i
+		}
+	}
+	return n
+}
@@ -0,0 +1,83 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddItems(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		var item AddItemRequest
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&item))
+		if item.Content == "bad" {
+			return textResponse(400, `{"error":"invalid content"}`), nil
+		}
+		body, _ := json.Marshal(AddItemResponse{
+			Item:  CollectionItem{ID: "item-" + item.Content, Description: item.Description},
+			Usage: Usage{PromptTokens: 2, TotalTokens: 2},
+		})
+		return textResponse(200, string(body)), nil
+	})
+	client := NewClient("key", WithBaseURL("https://api.test.local"), WithHTTPClient(&http.Client{Transport: transport}))
+
+	reqs := []AddItemRequest{{Content: "a"}, {Content: "bad"}, {Content: "c"}, {Content: "d"}, {Content: "e"}}
+	resp, err := client.AddItems(context.Background(), "col", reqs, BatchOptions{Concurrency: 2})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 5 items")
+
+	require.Len(t, resp.Results, 5)
+	assert.Equal(t, "item-a", resp.Results[0].Item.ID)
+	assert.Nil(t, resp.Results[1].Item)
+	assert.Error(t, resp.Results[1].Err)
+	assert.Equal(t, "item-e", resp.Results[4].Item.ID)
+	assert.Equal(t, 4, resp.Succeeded())
+	assert.Equal(t, []int{1}, resp.Failed())
+	assert.Equal(t, 8, resp.Usage.TotalTokens)
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+
+	resp, err = client.AddItems(context.Background(), "col", reqs[:1], BatchOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Succeeded())
+}
+
+func TestAddItemsCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls atomic.Int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if calls.Add(1) == 2 {
+			cancel()
+		}
+		return textResponse(200, `{"item":{"id":"item"}}`), nil
+	})
+	client := NewClient("key", WithBaseURL("https://api.test.local"), WithHTTPClient(&http.Client{Transport: transport}))
+
+	reqs := make([]AddItemRequest, 50)
+	before := runtime.NumGoroutine()
+	resp, err := client.AddItems(ctx, "col", reqs, BatchOptions{Concurrency: 1})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// Work stops once the context is cancelled
+	assert.LessOrEqual(t, calls.Load(), int32(3))
+	assert.Len(t, resp.Results, 50)
+	assert.ErrorIs(t, resp.Results[49].Err, context.Canceled)
+	waitForGoroutines(t, before)
+}
@@ -0,0 +1,300 @@
+// Package agents provides a small agent framework on top of the Vultr
+// Inference SDK. An Agent combines a model, a system prompt, tools and
+// memory, and runs a bounded tool-calling loop that produces a structured
+// trace of every step.
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	vultrai "github.com/eqba1/vultrai"
+)
+
+const defaultMaxSteps = 8
+
+// ErrMaxSteps is returned when the agent does not produce a final answer
+// within its step limit
+var ErrMaxSteps = errors.New("agent exceeded maximum number of steps")
+
+// ChatClient is the subset of the client used by agents
+type ChatClient interface {
+	CreateChatCompletion(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.ChatCompletionResponse, error)
+}
+
+// StepType identifies what happened in a step
+type StepType string
+
+const (
+	StepModel StepType = "model" // a chat completion call
+	StepTool  StepType = "tool"  // a tool invocation
+)
+
+// Step is one entry in an agent run trace
+type Step struct {
+	Index    int               `json:"index"`
+	Type     StepType          `json:"type"`
+	Message  *vultrai.Message  `json:"message,omitempty"`
+	ToolCall *vultrai.ToolCall `json:"tool_call,omitempty"`
+	Output   string            `json:"output,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Usage    vultrai.Usage     `json:"usage"`
+	Duration time.Duration     `json:"duration"`
+}
+
+// Result is the outcome of an agent run
+type Result struct {
+	Output string        `json:"output"`
+	Steps  []Step        `json:"steps"`
+	Usage  vultrai.Usage `json:"usage"`
+}
+
+// TraceJSON returns the run trace as indented JSON
+func (r *Result) TraceJSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Agent runs a model with tools and memory
+type Agent struct {
+	client       ChatClient
+	model        string
+	systemPrompt string
+	tools        map[string]Tool
+	toolOrder    []string
+	memory       Memory
+	longTerm     LongTermMemory
+	maxSteps     int
+	chatOptions  []vultrai.ChatOption
+	onStep       func(Step)
+}
+
+// Option configures an Agent
+type Option func(*Agent)
+
+// WithSystemPrompt sets the agent's system prompt
+func WithSystemPrompt(prompt string) Option {
+	return func(a *Agent) {
+		a.systemPrompt = prompt
+	}
+}
+
+// WithTools registers tools the agent may call
+func WithTools(tools ...Tool) Option {
+	return func(a *Agent) {
+		for _, tool := range tools {
+			a.AddTool(tool)
+		}
+	}
+}
+
+// WithMemory sets the conversation memory. The default is an unbounded
+// BufferMemory.
+func WithMemory(memory Memory) Option {
+	return func(a *Agent) {
+		a.memory = memory
+	}
+}
+
+// WithLongTermMemory sets a long-term memory that is consulted before each
+// run and updated with each completed exchange
+func WithLongTermMemory(memory LongTermMemory) Option {
+	return func(a *Agent) {
+		a.longTerm = memory
+	}
+}
+
+// WithMaxSteps limits the number of model calls per run
+func WithMaxSteps(n int) Option {
+	return func(a *Agent) {
+		a.maxSteps = n
+	}
+}
+
+// WithChatOptions applies options to every chat completion request
+func WithChatOptions(options ...vultrai.ChatOption) Option {
+	return func(a *Agent) {
+		a.chatOptions = append(a.chatOptions, options...)
+	}
+}
+
+// WithStepCallback registers a function called after every step
+func WithStepCallback(fn func(Step)) Option {
+	return func(a *Agent) {
+		a.onStep = fn
+	}
+}
+
+// New creates an agent for the given model
+func New(client ChatClient, model string, options ...Option) *Agent {
+	agent := &Agent{
+		client:   client,
+		model:    model,
+		tools:    make(map[string]Tool),
+		memory:   NewBufferMemory(0),
+		maxSteps: defaultMaxSteps,
+	}
+
+	for _, option := range options {
+		option(agent)
+	}
+
+	return agent
+}
+
+// AddTool registers a tool, replacing any tool with the same name
+func (a *Agent) AddTool(tool Tool) {
+	if _, exists := a.tools[tool.Name]; !exists {
+		a.toolOrder = append(a.toolOrder, tool.Name)
+	}
+	a.tools[tool.Name] = tool
+}
+
+// Memory returns the agent's conversation memory
+func (a *Agent) Memory() Memory {
+	return a.memory
+}
+
+// Run sends input to the agent and loops through tool calls until the model
+// produces a final answer or the step limit is reached
+func (a *Agent) Run(ctx context.Context, input string) (*Result, error) {
+	system, err := a.buildSystemPrompt(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []vultrai.Message
+	if system != "" {
+		messages = append(messages, vultrai.CreateSystemMessage(system))
+	}
+	messages = append(messages, a.memory.Messages()...)
+
+	newMessages := []vultrai.Message{vultrai.CreateUserMessage(input)}
+	result := &Result{}
+
+	for modelCalls := 0; modelCalls < a.maxSteps; modelCalls++ {
+		req := vultrai.ChatCompletionRequest{
+			Model:    a.model,
+			Messages: append(append([]vultrai.Message(nil), messages...), newMessages...),
+			Tools:    a.toolDefinitions(),
+		}
+		for _, option := range a.chatOptions {
+			option(&req)
+		}
+
+		start := time.Now()
+		resp, err := a.client.CreateChatCompletion(ctx, req)
+		if err != nil {
+			a.addStep(result, Step{Type: StepModel, Error: err.Error(), Duration: time.Since(start)})
+			return result, err
+		}
+		if len(resp.Choices) == 0 {
+			err := errors.New("model returned no choices")
+			a.addStep(result, Step{Type: StepModel, Error: err.Error(), Duration: time.Since(start)})
+			return result, err
+		}
+
+		message := resp.Choices[0].Message
+		addUsage(&result.Usage, resp.Usage)
+		a.addStep(result, Step{Type: StepModel, Message: &message, Usage: resp.Usage, Duration: time.Since(start)})
+		newMessages = append(newMessages, message)
+
+		if len(message.ToolCalls) == 0 {
+			result.Output = message.Content
+			a.memory.Add(newMessages...)
+			if a.longTerm != nil {
+				if err := a.longTerm.Remember(ctx, fmt.Sprintf("User: %s\nAssistant: %s", input, message.Content)); err != nil {
+					return result, fmt.Errorf("error updating long-term memory: %w", err)
+				}
+			}
+			return result, nil
+		}
+
+		for _, call := range message.ToolCalls {
+			newMessages = append(newMessages, a.callTool(ctx, result, call))
+		}
+	}
+
+	return result, ErrMaxSteps
+}
+
+// callTool executes a tool call and returns the tool message for the model.
+// Tool failures are reported back to the model rather than aborting the run.
+func (a *Agent) callTool(ctx context.Context, result *Result, call vultrai.ToolCall) vultrai.Message {
+	start := time.Now()
+	step := Step{Type: StepTool, ToolCall: &call}
+
+	var output string
+	tool, ok := a.tools[call.Function.Name]
+	if !ok {
+		step.Error = fmt.Sprintf("unknown tool %q", call.Function.Name)
+		output = "error: " + step.Error
+	} else if out, err := tool.Handler(ctx, call.Function.Arguments); err != nil {
+		step.Error = err.Error()
+		output = "error: " + err.Error()
+	} else {
+		output = out
+	}
+
+	step.Output = output
+	step.Duration = time.Since(start)
+	a.addStep(result, step)
+
+	return vultrai.Message{Role: "tool", Content: output, ToolCallID: call.ID}
+}
+
+func (a *Agent) addStep(result *Result, step Step) {
+	step.Index = len(result.Steps)
+	result.Steps = append(result.Steps, step)
+	if a.onStep != nil {
+		a.onStep(step)
+	}
+}
+
+func (a *Agent) buildSystemPrompt(ctx context.Context, input string) (string, error) {
+	if a.longTerm == nil {
+		return a.systemPrompt, nil
+	}
+
+	memories, err := a.longTerm.Recall(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("error recalling long-term memory: %w", err)
+	}
+	if len(memories) == 0 {
+		return a.systemPrompt, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(a.systemPrompt)
+	if sb.Len() > 0 {
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString("Relevant memories:\n")
+	for _, memory := range memories {
+		sb.WriteString("- ")
+		sb.WriteString(memory)
+		sb.WriteString("\n")
+	}
+	return strings.TrimRight(sb.String(), "\n"), nil
+}
+
+func (a *Agent) toolDefinitions() []vultrai.Tool {
+	if len(a.toolOrder) == 0 {
+		return nil
+	}
+
+	defs := make([]vultrai.Tool, 0, len(a.toolOrder))
+	for _, name := range a.toolOrder {
+		defs = append(defs, a.tools[name].Definition())
+	}
+	return defs
+}
+
+func addUsage(total *vultrai.Usage, u vultrai.Usage) {
+	total.PromptTokens += u.PromptTokens
+	total.CompletionTokens += u.CompletionTokens
+	total.TotalTokens += u.TotalTokens
+}
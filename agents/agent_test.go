@@ -0,0 +1,161 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/eqba1/vultrai/vultraitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func weatherTool() Tool {
+	return NewTool("get_weather", "Get the weather for a city", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"city": map[string]interface{}{"type": "string"},
+		},
+	}, func(ctx context.Context, arguments string) (string, error) {
+		var args struct {
+			City string `json:"city"`
+		}
+		if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+			return "", err
+		}
+		return "sunny in " + args.City, nil
+	})
+}
+
+func TestAgentRunWithTools(t *testing.T) {
+	var requests []vultrai.ChatCompletionRequest
+	mock := &vultraitest.MockClient{
+		CreateChatCompletionFunc: func(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.ChatCompletionResponse, error) {
+			requests = append(requests, req)
+			if len(requests) == 1 {
+				return vultraitest.ToolCallResponse(vultraitest.ToolCall{
+					Name:      "get_weather",
+					Arguments: map[string]string{"city": "Paris"},
+				}), nil
+			}
+			last := req.Messages[len(req.Messages)-1]
+			return vultraitest.ChatResponse("It is " + last.Content), nil
+		},
+	}
+
+	var steps []Step
+	agent := New(mock, "test-model",
+		WithSystemPrompt("You are a weather bot."),
+		WithTools(weatherTool()),
+		WithStepCallback(func(s Step) { steps = append(steps, s) }),
+	)
+
+	result, err := agent.Run(context.Background(), "Weather in Paris?")
+	require.NoError(t, err)
+
+	assert.Equal(t, "It is sunny in Paris", result.Output)
+	require.Len(t, result.Steps, 3)
+	assert.Equal(t, StepModel, result.Steps[0].Type)
+	assert.Equal(t, StepTool, result.Steps[1].Type)
+	assert.Equal(t, "sunny in Paris", result.Steps[1].Output)
+	assert.Equal(t, result.Steps, steps)
+
+	require.Len(t, requests[0].Tools, 1)
+	assert.Equal(t, "get_weather", requests[0].Tools[0].Function.Name)
+	assert.Equal(t, "system", requests[0].Messages[0].Role)
+
+	toolMessage := requests[1].Messages[len(requests[1].Messages)-1]
+	assert.Equal(t, "tool", toolMessage.Role)
+	assert.Equal(t, "call_1", toolMessage.ToolCallID)
+
+	// user, assistant tool call, tool result, final answer
+	assert.Len(t, agent.Memory().Messages(), 4)
+
+	trace, err := result.TraceJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(trace), "get_weather")
+}
+
+func TestAgentMaxSteps(t *testing.T) {
+	mock := &vultraitest.MockClient{
+		CreateChatCompletionFunc: func(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.ChatCompletionResponse, error) {
+			return vultraitest.ToolCallResponse(vultraitest.ToolCall{Name: "get_weather", Arguments: `{"city":"Oslo"}`}), nil
+		},
+	}
+
+	agent := New(mock, "test-model", WithTools(weatherTool()), WithMaxSteps(2))
+	_, err := agent.Run(context.Background(), "loop forever")
+	assert.ErrorIs(t, err, ErrMaxSteps)
+	assert.Equal(t, 2, mock.CallCount("CreateChatCompletion"))
+}
+
+func TestAgentUnknownToolReportedToModel(t *testing.T) {
+	calls := 0
+	mock := &vultraitest.MockClient{
+		CreateChatCompletionFunc: func(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.ChatCompletionResponse, error) {
+			calls++
+			if calls == 1 {
+				return vultraitest.ToolCallResponse(vultraitest.ToolCall{Name: "missing", Arguments: "{}"}), nil
+			}
+			return vultraitest.ChatResponse(req.Messages[len(req.Messages)-1].Content), nil
+		},
+	}
+
+	result, err := New(mock, "test-model").Run(context.Background(), "hi")
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, `unknown tool "missing"`)
+}
+
+func TestAgentLongTermMemory(t *testing.T) {
+	var added []vultrai.AddItemRequest
+	mock := &vultraitest.MockClient{
+		SearchCollectionFunc: func(ctx context.Context, id string, req vultrai.SearchRequest) (*vultrai.SearchResponse, error) {
+			return vultraitest.SearchResults("User likes tea"), nil
+		},
+		AddItemFunc: func(ctx context.Context, collectionID string, req vultrai.AddItemRequest) (*vultrai.AddItemResponse, error) {
+			added = append(added, req)
+			return &vultrai.AddItemResponse{}, nil
+		},
+		CreateChatCompletionFunc: func(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.ChatCompletionResponse, error) {
+			return vultraitest.ChatResponse(req.Messages[0].Content), nil
+		},
+	}
+
+	agent := New(mock, "test-model", WithLongTermMemory(NewCollectionMemory(mock, "coll-1", 3)))
+	result, err := agent.Run(context.Background(), "What do I like?")
+	require.NoError(t, err)
+
+	assert.Contains(t, result.Output, "- User likes tea")
+	require.Len(t, added, 1)
+	assert.Contains(t, added[0].Content, "What do I like?")
+}
+
+func TestAgentModelError(t *testing.T) {
+	boom := errors.New("boom")
+	mock := &vultraitest.MockClient{
+		CreateChatCompletionFunc: func(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.ChatCompletionResponse, error) {
+			return nil, boom
+		},
+	}
+
+	result, err := New(mock, "test-model").Run(context.Background(), "hi")
+	assert.ErrorIs(t, err, boom)
+	require.Len(t, result.Steps, 1)
+	assert.Equal(t, "boom", result.Steps[0].Error)
+}
+
+func TestBufferMemoryDropsOrphanedToolMessages(t *testing.T) {
+	memory := NewBufferMemory(2)
+	memory.Add(
+		vultrai.CreateUserMessage("q"),
+		vultrai.Message{Role: "assistant", ToolCalls: []vultrai.ToolCall{{ID: "1"}}},
+		vultrai.Message{Role: "tool", Content: "result", ToolCallID: "1"},
+		vultrai.CreateAssistantMessage("a"),
+	)
+
+	messages := memory.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "a", messages[0].Content)
+}
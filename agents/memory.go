@@ -0,0 +1,118 @@
+package agents
+
+import (
+	"context"
+	"sync"
+
+	vultrai "github.com/eqba1/vultrai"
+)
+
+// Memory holds the conversation history an agent carries between runs
+type Memory interface {
+	Messages() []vultrai.Message
+	Add(messages ...vultrai.Message)
+	Reset()
+}
+
+// BufferMemory keeps the most recent messages in memory
+type BufferMemory struct {
+	mu          sync.Mutex
+	maxMessages int
+	messages    []vultrai.Message
+}
+
+// NewBufferMemory creates a memory holding at most maxMessages messages.
+// Zero means unbounded.
+func NewBufferMemory(maxMessages int) *BufferMemory {
+	return &BufferMemory{maxMessages: maxMessages}
+}
+
+// Messages returns a copy of the stored messages
+func (m *BufferMemory) Messages() []vultrai.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]vultrai.Message(nil), m.messages...)
+}
+
+// Add appends messages, dropping the oldest when over capacity. Tool
+// results orphaned by the cut are dropped as well, since the model rejects
+// tool messages without their originating call.
+func (m *BufferMemory) Add(messages ...vultrai.Message) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, messages...)
+	if m.maxMessages <= 0 || len(m.messages) <= m.maxMessages {
+		return
+	}
+
+	trimmed := m.messages[len(m.messages)-m.maxMessages:]
+	for len(trimmed) > 0 && trimmed[0].Role == "tool" {
+		trimmed = trimmed[1:]
+	}
+	m.messages = append([]vultrai.Message(nil), trimmed...)
+}
+
+// Reset clears the memory
+func (m *BufferMemory) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = nil
+}
+
+// LongTermMemory stores knowledge outside the conversation window
+type LongTermMemory interface {
+	// Recall returns memories relevant to query
+	Recall(ctx context.Context, query string) ([]string, error)
+	// Remember stores text for later recall
+	Remember(ctx context.Context, text string) error
+}
+
+// CollectionClient is the subset of the client used by CollectionMemory
+type CollectionClient interface {
+	SearchCollection(ctx context.Context, id string, req vultrai.SearchRequest) (*vultrai.SearchResponse, error)
+	AddItem(ctx context.Context, collectionID string, req vultrai.AddItemRequest) (*vultrai.AddItemResponse, error)
+}
+
+// CollectionMemory is a LongTermMemory backed by a vector store collection
+type CollectionMemory struct {
+	client       CollectionClient
+	collectionID string
+	maxResults   int
+}
+
+// NewCollectionMemory creates a long-term memory stored in collectionID.
+// At most maxResults memories are recalled per run; zero means no limit.
+func NewCollectionMemory(client CollectionClient, collectionID string, maxResults int) *CollectionMemory {
+	return &CollectionMemory{
+		client:       client,
+		collectionID: collectionID,
+		maxResults:   maxResults,
+	}
+}
+
+// Recall searches the collection for memories relevant to query
+func (m *CollectionMemory) Recall(ctx context.Context, query string) ([]string, error) {
+	resp, err := m.client.SearchCollection(ctx, m.collectionID, vultrai.SearchRequest{Input: query})
+	if err != nil {
+		return nil, err
+	}
+
+	var memories []string
+	for _, result := range resp.Results {
+		if m.maxResults > 0 && len(memories) >= m.maxResults {
+			break
+		}
+		memories = append(memories, result.Content)
+	}
+	return memories, nil
+}
+
+// Remember adds text to the collection
+func (m *CollectionMemory) Remember(ctx context.Context, text string) error {
+	_, err := m.client.AddItem(ctx, m.collectionID, vultrai.AddItemRequest{
+		Content:     text,
+		Description: "agent memory",
+	})
+	return err
+}
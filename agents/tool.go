@@ -0,0 +1,40 @@
+package agents
+
+import (
+	"context"
+
+	vultrai "github.com/eqba1/vultrai"
+)
+
+// ToolHandler executes a tool call. arguments is the raw JSON produced by
+// the model; the returned string is sent back to the model as the result.
+type ToolHandler func(ctx context.Context, arguments string) (string, error)
+
+// Tool is a capability the agent may invoke. Package tools builds Tools
+// from Go functions.
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  interface{} // JSON Schema for the arguments object
+	Handler     ToolHandler
+}
+
+// NewTool creates a tool
+func NewTool(name, description string, parameters interface{}, handler ToolHandler) Tool {
+	return Tool{
+		Name:        name,
+		Description: description,
+		Parameters:  parameters,
+		Handler:     handler,
+	}
+}
+
+// Definition returns the tool declaration sent to the model
+func (t Tool) Definition() vultrai.Tool {
+	parameters := t.Parameters
+	if parameters == nil {
+		parameters = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+
+	return vultrai.NewFunctionTool(t.Name, t.Description, parameters)
+}
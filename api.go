@@ -0,0 +1,70 @@
+package vultrai
+
+import (
+	"context"
+	"io"
+)
+
+// API is the set of endpoint operations offered by *Client. Depend on it
+// instead of *Client to substitute a fake in tests; vultraitest.MockClient
+// implements it.
+//
+// It holds the endpoint methods and their direct wrappers only. Helpers that
+// compose several calls, such as AddItems, AddFileFromPath, ListItemsFunc,
+// SearchCollectionFunc, StreamChatCompletionChan and MeasureCompletion, are
+// left out so that fakes don't have to reimplement them.
+type API interface {
+	// Chat completions
+	CreateChatCompletion(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error)
+	CreateChatCompletionStream(ctx context.Context, req ChatCompletionRequest) (*StreamReader, error)
+	CreateRAGChatCompletion(ctx context.Context, req RAGChatCompletionRequest) (*ChatCompletionResponse, error)
+	CreateRAGChatCompletionStream(ctx context.Context, req RAGChatCompletionRequest) (*StreamReader, error)
+	StreamChatCompletion(ctx context.Context, req ChatCompletionRequest, callback StreamCallback, options ...StreamOption) error
+	StreamRAGChatCompletion(ctx context.Context, req RAGChatCompletionRequest, callback StreamCallback, options ...StreamOption) error
+	SimpleChatCompletion(ctx context.Context, model, prompt string) (*ChatCompletionResponse, error)
+	ChatWithMessages(ctx context.Context, model string, messages []Message, options ...ChatOption) (*ChatCompletionResponse, error)
+
+	// Embeddings, audio and images
+	CreateEmbeddings(ctx context.Context, req EmbeddingRequest) (*EmbeddingResponse, error)
+	CreateSpeech(ctx context.Context, req TTSRequest) ([]byte, error)
+	SimpleSpeech(ctx context.Context, text string, options ...TTSOption) ([]byte, error)
+	OpenSpeech(ctx context.Context, req TTSRequest) (io.ReadCloser, error)
+	CreateSpeechStream(ctx context.Context, req TTSRequest, w io.Writer) (int64, error)
+	CreateTranscription(ctx context.Context, req TranscriptionRequest) (*TranscriptionResponse, error)
+	GenerateImage(ctx context.Context, req ImageGenerationRequest) (*ImageGenerationResponse, error)
+	SimpleImageGeneration(ctx context.Context, prompt string) (*ImageGenerationResponse, error)
+	GenerateImageWithOptions(ctx context.Context, prompt string, options ...ImageOption) (*ImageGenerationResponse, error)
+
+	// Vector store
+	CreateCollection(ctx context.Context, req CreateCollectionRequest) (*CreateCollectionResponse, error)
+	ListCollections(ctx context.Context) (*ListCollectionsResponse, error)
+	UpdateCollection(ctx context.Context, id string, req UpdateCollectionRequest) (*UpdateCollectionResponse, error)
+	DeleteCollection(ctx context.Context, id string) error
+	SearchCollection(ctx context.Context, id string, req SearchRequest) (*SearchResponse, error)
+	ListItems(ctx context.Context, collectionID string) (*ListItemsResponse, error)
+	AddItem(ctx context.Context, collectionID string, req AddItemRequest) (*AddItemResponse, error)
+	GetItem(ctx context.Context, collectionID, itemID string) (*GetItemResponse, error)
+	UpdateItem(ctx context.Context, collectionID, itemID string, req UpdateItemRequest) (*UpdateItemResponse, error)
+	DeleteItem(ctx context.Context, collectionID, itemID string) error
+	ListFiles(ctx context.Context, collectionID string) (*ListFilesResponse, error)
+	AddFile(ctx context.Context, collectionID string, file io.Reader, filename string) (*AddFileResponse, error)
+	GetFile(ctx context.Context, collectionID, fileID string) (*GetFileResponse, error)
+	DeleteFile(ctx context.Context, collectionID, fileID string) error
+
+	// Batches
+	CreateBatch(ctx context.Context, req CreateBatchRequest) (*BatchResponse, error)
+	GetBatch(ctx context.Context, batchID string) (*BatchResponse, error)
+	ListBatches(ctx context.Context) (*ListBatchesResponse, error)
+	DownloadBatchResults(ctx context.Context, batchID string) ([]BatchResult, error)
+
+	// Account
+	GetUsage(ctx context.Context) (*UsageResponse, error)
+	GetAccount(ctx context.Context) (*AccountResponse, error)
+	ListModels(ctx context.Context) (*ListModelsResponse, error)
+	GetRequestLogs(ctx context.Context, req RequestLogsRequest) (*RequestLogsResponse, error)
+
+	// Endpoints without a wrapper
+	Do(ctx context.Context, method, path string, in, out interface{}, options ...RequestOption) error
+}
+
+var _ API = (*Client)(nil)
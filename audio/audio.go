@@ -0,0 +1,148 @@
+// Package audio converts synthesized speech into the formats used by
+// telephony stacks: 8kHz mono G.711 μ-law, A-law or linear PCM. It decodes
+// WAV, downmixes, resamples and encodes without external tools.
+package audio
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	vultrai "github.com/eqba1/vultrai"
+)
+
+// Encoding is the sample encoding of raw audio
+type Encoding int
+
+const (
+	// PCM16 is linear 16-bit little-endian PCM
+	PCM16 Encoding = iota
+	// MuLaw is G.711 μ-law, used by North American and Japanese networks
+	MuLaw
+	// ALaw is G.711 A-law, used by most other networks
+	ALaw
+)
+
+// Format describes raw audio
+type Format struct {
+	SampleRate int
+	Channels   int
+	Encoding   Encoding
+}
+
+// Telephony formats, 8kHz mono
+var (
+	Telephony     = Format{SampleRate: 8000, Channels: 1, Encoding: MuLaw}
+	TelephonyALaw = Format{SampleRate: 8000, Channels: 1, Encoding: ALaw}
+	TelephonyPCM  = Format{SampleRate: 8000, Channels: 1, Encoding: PCM16}
+)
+
+// PCM is linear 16-bit audio, interleaved when it has several channels
+type PCM struct {
+	Samples    []int16
+	SampleRate int
+	Channels   int
+}
+
+// Decode converts raw audio in format f to PCM
+func Decode(data []byte, f Format) *PCM {
+	pcm := &PCM{SampleRate: f.SampleRate, Channels: max(f.Channels, 1)}
+	switch f.Encoding {
+	case MuLaw:
+		pcm.Samples = DecodeMuLaw(data)
+	case ALaw:
+		pcm.Samples = DecodeALaw(data)
+	default:
+		pcm.Samples = make([]int16, len(data)/2)
+		for i := range pcm.Samples {
+			pcm.Samples[i] = int16(binary.LittleEndian.Uint16(data[2*i:]))
+		}
+	}
+	return pcm
+}
+
+// Encode returns the samples as raw audio with encoding e, without a
+// header
+func (p *PCM) Encode(e Encoding) []byte {
+	switch e {
+	case MuLaw:
+		return EncodeMuLaw(p.Samples)
+	case ALaw:
+		return EncodeALaw(p.Samples)
+	default:
+		data := make([]byte, 2*len(p.Samples))
+		for i, s := range p.Samples {
+			binary.LittleEndian.PutUint16(data[2*i:], uint16(s))
+		}
+		return data
+	}
+}
+
+// Mono mixes the channels down to one
+func (p *PCM) Mono() *PCM {
+	if p.Channels <= 1 {
+		return p
+	}
+	mono := &PCM{Samples: make([]int16, len(p.Samples)/p.Channels), SampleRate: p.SampleRate, Channels: 1}
+	for i := range mono.Samples {
+		sum := 0
+		for c := 0; c < p.Channels; c++ {
+			sum += int(p.Samples[i*p.Channels+c])
+		}
+		mono.Samples[i] = int16(sum / p.Channels)
+	}
+	return mono
+}
+
+// Duration returns the length of the audio in seconds
+func (p *PCM) Duration() float64 {
+	if p.SampleRate == 0 || p.Channels == 0 {
+		return 0
+	}
+	return float64(len(p.Samples)/p.Channels) / float64(p.SampleRate)
+}
+
+// Convert decodes a WAV file, such as speech from Client.CreateSpeech, and
+// returns its audio as headerless raw audio in format to, ready to be
+// streamed over RTP
+func Convert(wav []byte, to Format) ([]byte, error) {
+	pcm, err := DecodeWAV(wav)
+	if err != nil {
+		return nil, err
+	}
+	if to.Channels <= 1 {
+		pcm = pcm.Mono()
+	}
+	if to.SampleRate > 0 {
+		pcm = pcm.Resample(to.SampleRate)
+	}
+	return pcm.Encode(to.Encoding), nil
+}
+
+// ErrNotWAV is returned when speech is not in WAV format
+var ErrNotWAV = errors.New("audio is not a wav file")
+
+// CreateSpeech synthesizes req as WAV and converts it to format to
+func CreateSpeech(ctx context.Context, client *vultrai.Client, req vultrai.TTSRequest, to Format) ([]byte, error) {
+	if req.ResponseFormat == "" {
+		req.ResponseFormat = "wav"
+	}
+	speech, err := client.CreateSpeech(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := Convert(speech, to)
+	if err != nil {
+		return nil, fmt.Errorf("error converting speech: %w", err)
+	}
+	return data, nil
+}
+
+// PlayHook returns a VoiceHooks.BeforePlay hook converting the WAV speech
+// of a VoiceChat to format to. Set the VoiceChat's SpeechFormat to "wav".
+func PlayHook(to Format) func(ctx context.Context, audio []byte) ([]byte, error) {
+	return func(ctx context.Context, audio []byte) ([]byte, error) {
+		return Convert(audio, to)
+	}
+}
@@ -0,0 +1,113 @@
+package audio
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tone returns freq Hz at rate for the given number of samples
+func tone(freq float64, rate, n int) []int16 {
+	samples := make([]int16, n)
+	for i := range samples {
+		samples[i] = int16(10000 * math.Sin(2*math.Pi*freq*float64(i)/float64(rate)))
+	}
+	return samples
+}
+
+// rms is the root mean square of samples, ignoring the filter's edges
+func rms(samples []int16) float64 {
+	samples = samples[len(samples)/10 : len(samples)*9/10]
+	var sum float64
+	for _, s := range samples {
+		sum += float64(s) * float64(s)
+	}
+	return math.Sqrt(sum / float64(len(samples)))
+}
+
+func TestG711(t *testing.T) {
+	assert.Equal(t, []byte{0xFF}, EncodeMuLaw([]int16{0}))
+	assert.Equal(t, []byte{0xD5}, EncodeALaw([]int16{0}))
+
+	for _, v := range []int16{1, -1, 100, -100, 1000, -1000, 12345, -12345, 32767, -32768} {
+		// Quantization error grows with the magnitude, about 1/16
+		tolerance := math.Abs(float64(v))/16 + 16
+		assert.InDelta(t, v, DecodeMuLaw(EncodeMuLaw([]int16{v}))[0], tolerance, "mu-law %d", v)
+		assert.InDelta(t, v, DecodeALaw(EncodeALaw([]int16{v}))[0], tolerance, "a-law %d", v)
+	}
+
+	// Every code decodes to a value that encodes back to it
+	for code := 0; code < 256; code++ {
+		if code != 0x7F { // μ-law has two codes for zero
+			assert.Equal(t, byte(code), EncodeMuLaw(DecodeMuLaw([]byte{byte(code)}))[0], "mu-law code %#x", code)
+		}
+		assert.Equal(t, byte(code), EncodeALaw(DecodeALaw([]byte{byte(code)}))[0], "a-law code %#x", code)
+	}
+}
+
+func TestResample(t *testing.T) {
+	pcm := &PCM{Samples: tone(1000, 24000, 2400), SampleRate: 24000, Channels: 1}
+	down := pcm.Resample(8000)
+	assert.Len(t, down.Samples, 800)
+	assert.InDelta(t, rms(tone(1000, 8000, 800)), rms(down.Samples), 100)
+
+	// A tone above the new Nyquist limit is filtered out
+	high := &PCM{Samples: tone(6000, 24000, 2400), SampleRate: 24000, Channels: 1}
+	assert.Less(t, rms(high.Resample(8000).Samples), 500.0)
+
+	up := down.Resample(16000)
+	assert.Len(t, up.Samples, 1600)
+	assert.InDelta(t, rms(tone(1000, 16000, 1600)), rms(up.Samples), 200)
+}
+
+func TestConvert(t *testing.T) {
+	stereo := &PCM{SampleRate: 16000, Channels: 2}
+	for _, s := range tone(500, 16000, 1600) {
+		stereo.Samples = append(stereo.Samples, s, s)
+	}
+	wav := stereo.WAV(PCM16)
+
+	decoded, err := DecodeWAV(wav)
+	require.NoError(t, err)
+	assert.Equal(t, stereo, decoded)
+	assert.InDelta(t, 0.1, decoded.Duration(), 1e-9)
+
+	data, err := Convert(wav, Telephony)
+	require.NoError(t, err)
+	assert.Len(t, data, 800)
+	mono := Decode(data, Telephony)
+	assert.InDelta(t, rms(tone(500, 8000, 800)), rms(mono.Samples), 200)
+
+	// G.711 files decode too
+	decoded, err = DecodeWAV(mono.WAV(ALaw))
+	require.NoError(t, err)
+	assert.Equal(t, DecodeALaw(EncodeALaw(mono.Samples)), decoded.Samples)
+
+	_, err = Convert([]byte("ID3 mp3 data"), Telephony)
+	assert.ErrorIs(t, err, ErrNotWAV)
+}
+
+func TestCreateSpeech(t *testing.T) {
+	wav := (&PCM{Samples: tone(440, 24000, 2400), SampleRate: 24000, Channels: 1}).WAV(PCM16)
+	var format string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req vultrai.TTSRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		format = req.ResponseFormat
+		w.Write(wav)
+	}))
+	defer server.Close()
+
+	client := vultrai.NewClient("key", vultrai.WithBaseURL(server.URL))
+	data, err := CreateSpeech(context.Background(), client, vultrai.TTSRequest{Model: "tts", Input: "hi", Voice: "v"}, TelephonyPCM)
+	require.NoError(t, err)
+	assert.Equal(t, "wav", format)
+	assert.Len(t, data, 1600)
+}
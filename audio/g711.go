@@ -0,0 +1,90 @@
+package audio
+
+const (
+	muLawBias = 0x84
+	muLawClip = 32635
+)
+
+// EncodeMuLaw compresses linear samples to G.711 μ-law, one byte each
+func EncodeMuLaw(samples []int16) []byte {
+	out := make([]byte, len(samples))
+	for i, s := range samples {
+		v := int(s)
+		sign := 0
+		if v < 0 {
+			v = -v
+			sign = 0x80
+		}
+		v = min(v, muLawClip) + muLawBias
+
+		exponent := 7
+		for mask := 0x4000; v&mask == 0 && exponent > 0; mask >>= 1 {
+			exponent--
+		}
+		mantissa := (v >> (exponent + 3)) & 0x0F
+		out[i] = ^byte(sign | exponent<<4 | mantissa)
+	}
+	return out
+}
+
+// DecodeMuLaw expands G.711 μ-law to linear samples
+func DecodeMuLaw(data []byte) []int16 {
+	out := make([]int16, len(data))
+	for i, b := range data {
+		b = ^b
+		exponent := int(b>>4) & 0x07
+		mantissa := int(b) & 0x0F
+		v := ((mantissa<<3)+muLawBias)<<exponent - muLawBias
+		if b&0x80 != 0 {
+			v = -v
+		}
+		out[i] = int16(v)
+	}
+	return out
+}
+
+// EncodeALaw compresses linear samples to G.711 A-law, one byte each
+func EncodeALaw(samples []int16) []byte {
+	out := make([]byte, len(samples))
+	for i, s := range samples {
+		v := int(s)
+		sign := 0x80
+		if v < 0 {
+			v = -v - 1
+			sign = 0
+		}
+
+		var code int
+		if v < 256 {
+			code = v >> 4
+		} else {
+			exponent := 7
+			for mask := 0x4000; v&mask == 0 && exponent > 1; mask >>= 1 {
+				exponent--
+			}
+			code = exponent<<4 | (v>>(exponent+3))&0x0F
+		}
+		out[i] = byte(code|sign) ^ 0x55
+	}
+	return out
+}
+
+// DecodeALaw expands G.711 A-law to linear samples
+func DecodeALaw(data []byte) []int16 {
+	out := make([]int16, len(data))
+	for i, b := range data {
+		b ^= 0x55
+		exponent := int(b>>4) & 0x07
+		v := int(b&0x0F) << 4
+		if exponent == 0 {
+			v += 8
+		} else {
+			v = (v + 0x108) << (exponent - 1)
+		}
+		if b&0x80 == 0 {
+			v = -v
+		}
+		out[i] = int16(v)
+	}
+	return out
+}
@@ -0,0 +1,63 @@
+package audio
+
+import "math"
+
+// resampleTaps is the number of input samples each side of an output
+// sample used by the interpolation filter, at the input rate
+const resampleTaps = 16
+
+// Resample converts the audio to rate with windowed-sinc interpolation.
+// When downsampling, frequencies above the new Nyquist limit are filtered
+// out instead of folding back as noise.
+func (p *PCM) Resample(rate int) *PCM {
+	if rate <= 0 || p.SampleRate <= 0 || rate == p.SampleRate {
+		return p
+	}
+	channels := max(p.Channels, 1)
+	frames := len(p.Samples) / channels
+	outFrames := int(int64(frames) * int64(rate) / int64(p.SampleRate))
+	out := &PCM{Samples: make([]int16, outFrames*channels), SampleRate: rate, Channels: channels}
+
+	step := float64(p.SampleRate) / float64(rate)
+	// Cutoff relative to the input Nyquist frequency
+	cutoff := math.Min(1, float64(rate)/float64(p.SampleRate))
+	halfWidth := float64(resampleTaps) / cutoff
+
+	for i := 0; i < outFrames; i++ {
+		t := float64(i) * step
+		first := max(int(math.Ceil(t-halfWidth)), 0)
+		last := min(int(math.Floor(t+halfWidth)), frames-1)
+		for c := 0; c < channels; c++ {
+			var sum, weights float64
+			for k := first; k <= last; k++ {
+				w := lowPass(t-float64(k), cutoff, halfWidth)
+				sum += w * float64(p.Samples[k*channels+c])
+				weights += w
+			}
+			if weights != 0 {
+				// Normalize so that edges and DC keep their level
+				sum /= weights
+			}
+			out.Samples[i*channels+c] = clip16(sum)
+		}
+	}
+	return out
+}
+
+// lowPass is a Hann-windowed sinc filter with the given cutoff, evaluated
+// at offset d input samples
+func lowPass(d, cutoff, halfWidth float64) float64 {
+	if math.Abs(d) >= halfWidth {
+		return 0
+	}
+	window := 0.5 + 0.5*math.Cos(math.Pi*d/halfWidth)
+	x := math.Pi * d * cutoff
+	if x == 0 {
+		return window
+	}
+	return math.Sin(x) / x * window
+}
+
+func clip16(v float64) int16 {
+	return int16(math.Max(math.MinInt16, math.Min(math.MaxInt16, math.Round(v))))
+}
@@ -0,0 +1,113 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// WAV format codes
+const (
+	wavPCM        = 1
+	wavALaw       = 6
+	wavMuLaw      = 7
+	wavExtensible = 0xFFFE
+)
+
+// DecodeWAV decodes a WAV file holding 8 or 16-bit PCM, μ-law or A-law
+// audio
+func DecodeWAV(data []byte) (*PCM, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, ErrNotWAV
+	}
+
+	var format, channels, bits int
+	var rate int
+	var samples []byte
+	haveFormat := false
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		body := data[pos+8:]
+		// Streamed files may not know their data size
+		if size < 0 || size > len(body) {
+			size = len(body)
+		}
+		body = body[:size]
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, fmt.Errorf("wav format chunk too short")
+			}
+			format = int(binary.LittleEndian.Uint16(body))
+			channels = int(binary.LittleEndian.Uint16(body[2:]))
+			rate = int(binary.LittleEndian.Uint32(body[4:]))
+			bits = int(binary.LittleEndian.Uint16(body[14:]))
+			if format == wavExtensible && size >= 26 {
+				format = int(binary.LittleEndian.Uint16(body[24:]))
+			}
+			haveFormat = true
+		case "data":
+			samples = body
+		}
+		pos += 8 + size + size%2
+	}
+	if !haveFormat || samples == nil {
+		return nil, fmt.Errorf("wav file has no format or data")
+	}
+	if channels < 1 {
+		return nil, fmt.Errorf("wav file has %d channels", channels)
+	}
+
+	switch {
+	case format == wavPCM && bits == 16:
+		return Decode(samples, Format{SampleRate: rate, Channels: channels, Encoding: PCM16}), nil
+	case format == wavPCM && bits == 8:
+		pcm := &PCM{Samples: make([]int16, len(samples)), SampleRate: rate, Channels: channels}
+		for i, b := range samples {
+			pcm.Samples[i] = (int16(b) - 128) << 8
+		}
+		return pcm, nil
+	case format == wavMuLaw:
+		return Decode(samples, Format{SampleRate: rate, Channels: channels, Encoding: MuLaw}), nil
+	case format == wavALaw:
+		return Decode(samples, Format{SampleRate: rate, Channels: channels, Encoding: ALaw}), nil
+	default:
+		return nil, fmt.Errorf("unsupported wav format %d with %d bits", format, bits)
+	}
+}
+
+// WAV wraps the samples in a WAV file with encoding e
+func (p *PCM) WAV(e Encoding) []byte {
+	data := p.Encode(e)
+	format, bits := wavPCM, 16
+	switch e {
+	case MuLaw:
+		format, bits = wavMuLaw, 8
+	case ALaw:
+		format, bits = wavALaw, 8
+	}
+	channels := max(p.Channels, 1)
+	blockAlign := channels * bits / 8
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+len(data)))
+	buf.WriteString("WAVEfmt ")
+	for _, v := range []any{
+		uint32(16),
+		uint16(format),
+		uint16(channels),
+		uint32(p.SampleRate),
+		uint32(p.SampleRate * blockAlign),
+		uint16(blockAlign),
+		uint16(bits),
+	} {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+	return buf.Bytes()
+}
@@ -0,0 +1,55 @@
+package vultrai
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// RequestSigner is called on every request right before it is sent, after
+// all headers are set. It can add signatures or credentials that depend on
+// the request; req.GetBody returns a copy of the body when it is needed.
+// Uploads are streamed and have no GetBody.
+type RequestSigner func(req *http.Request) error
+
+// WithAuthHeader changes how the API key is sent, for gateways and proxies
+// that don't accept "Authorization: Bearer <key>". The key is sent in the
+// header named name, prefixed with scheme and a space unless scheme is
+// empty: WithAuthHeader("X-Api-Key", "") sends "X-Api-Key: <key>". An
+// empty name disables the header, e.g. when a signer handles
+// authentication.
+func WithAuthHeader(name, scheme string) ClientOption {
+	return func(c *Client) {
+		c.authHeader = name
+		c.authScheme = scheme
+	}
+}
+
+// WithRequestSigner sets a signer called on every request
+func WithRequestSigner(signer RequestSigner) ClientOption {
+	return func(c *Client) {
+		c.signer = signer
+	}
+}
+
+// setAuth sets the API key header on req
+func (c *Client) setAuth(req *http.Request) {
+	if c.authHeader == "" {
+		return
+	}
+	value := c.apiKey
+	if c.authScheme != "" {
+		value = c.authScheme + " " + c.apiKey
+	}
+	req.Header.Set(c.authHeader, value)
+}
+
+// sign runs the request signer, if any
+func (c *Client) sign(req *http.Request) error {
+	if c.signer == nil {
+		return nil
+	}
+	if err := c.signer(req); err != nil {
+		return fmt.Errorf("error signing request: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package vultrai
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthHeader(t *testing.T) {
+	client, transport := setupTestClient()
+	_, err := client.GetUsage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer test-api-key", transport.GetRequests()[0].Header.Get("Authorization"))
+
+	WithAuthHeader("X-Api-Key", "")(client)
+	_, err = client.GetUsage(context.Background())
+	require.NoError(t, err)
+	req := transport.GetRequests()[1]
+	assert.Equal(t, "test-api-key", req.Header.Get("X-Api-Key"))
+	assert.Empty(t, req.Header.Get("Authorization"))
+
+	WithAuthHeader("Authorization", "Token")(client)
+	_, err = client.CreateTranscription(context.Background(), TranscriptionRequest{Model: "m", Audio: strings.NewReader("RIFF")})
+	require.NoError(t, err)
+	assert.Equal(t, "Token test-api-key", transport.GetRequests()[2].Header.Get("Authorization"))
+}
+
+func TestRequestSigner(t *testing.T) {
+	client, transport := setupTestClient()
+	WithAuthHeader("", "")(client)
+	WithRequestSigner(func(req *http.Request) error {
+		body, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		data, _ := io.ReadAll(body)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(data)
+		req.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+		return nil
+	})(client)
+
+	_, err := client.CreateEmbeddings(context.Background(), EmbeddingRequest{Model: "m", Input: []string{"hi"}})
+	require.NoError(t, err)
+	req := transport.GetRequests()[0]
+	assert.Empty(t, req.Header.Get("Authorization"))
+	assert.Len(t, req.Header.Get("X-Signature"), 64)
+
+	failure := errors.New("no credentials")
+	WithRequestSigner(func(*http.Request) error { return failure })(client)
+	_, err = client.GetUsage(context.Background())
+	assert.ErrorIs(t, err, failure)
+	assert.Len(t, transport.GetRequests(), 1)
+}
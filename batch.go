@@ -0,0 +1,134 @@
+package vultrai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// DefaultBatchEndpoint is the endpoint batch requests are sent to
+	DefaultBatchEndpoint = "/v1/chat/completions"
+	// DefaultBatchCompletionWindow is the time a batch may take to finish
+	DefaultBatchCompletionWindow = "24h"
+)
+
+// BatchRequestLine is one request in a batch input file
+type BatchRequestLine struct {
+	CustomID string                `json:"custom_id"`
+	Method   string                `json:"method"`
+	URL      string                `json:"url"`
+	Body     ChatCompletionRequest `json:"body"`
+}
+
+// BatchResult is one line of a batch results file
+type BatchResult struct {
+	ID       string               `json:"id"`
+	CustomID string               `json:"custom_id"`
+	Response *BatchResultResponse `json:"response"`
+	Error    *Error               `json:"error"`
+}
+
+// BatchResultResponse is the API response to one batch request
+type BatchResultResponse struct {
+	StatusCode int                     `json:"status_code"`
+	Body       *ChatCompletionResponse `json:"body"`
+}
+
+// Completion returns the chat completion of a successful result, or an
+// error describing why the request failed
+func (r BatchResult) Completion() (*ChatCompletionResponse, error) {
+	if r.Error != nil {
+		return nil, fmt.Errorf("batch request %s failed: %s", r.CustomID, r.Error.Message)
+	}
+	if r.Response == nil || r.Response.Body == nil {
+		return nil, fmt.Errorf("batch request %s has no response", r.CustomID)
+	}
+	if r.Response.StatusCode < 200 || r.Response.StatusCode >= 300 {
+		return nil, fmt.Errorf("batch request %s failed with status %d", r.CustomID, r.Response.StatusCode)
+	}
+	return r.Response.Body, nil
+}
+
+// BatchCustomID is the custom_id BuildBatchJSONL gives the request at index i
+func BatchCustomID(i int) string {
+	return fmt.Sprintf("request-%d", i)
+}
+
+// BuildBatchJSONL encodes chat completion requests as a batch input file.
+// Request i is given the custom ID BatchCustomID(i).
+func BuildBatchJSONL(reqs []ChatCompletionRequest) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for i, req := range reqs {
+		line := BatchRequestLine{
+			CustomID: BatchCustomID(i),
+			Method:   "POST",
+			URL:      DefaultBatchEndpoint,
+			Body:     req,
+		}
+		if err := enc.Encode(line); err != nil {
+			return nil, fmt.Errorf("error encoding batch request %d: %w", i, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// ParseBatchResults decodes a JSONL batch results file
+func ParseBatchResults(r io.Reader) ([]BatchResult, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var results []BatchResult
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		var result BatchResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("error decoding batch result line %d: %w", line, err)
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading batch results: %w", err)
+	}
+	return results, nil
+}
+
+// BatchDone reports whether a batch status is final
+func BatchDone(status string) bool {
+	switch status {
+	case "completed", "failed", "expired", "cancelled":
+		return true
+	}
+	return false
+}
+
+// WaitForBatch polls a batch every interval until it reaches a final status
+// or ctx is done
+func (c *Client) WaitForBatch(ctx context.Context, batchID string, interval time.Duration) (*Batch, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		resp, err := c.GetBatch(ctx, batchID)
+		if err != nil {
+			return nil, err
+		}
+		if BatchDone(resp.Batch.Status) {
+			return &resp.Batch, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package vultrai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func textResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestBuildBatchJSONL(t *testing.T) {
+	data, err := BuildBatchJSONL([]ChatCompletionRequest{
+		{Model: "m", Messages: []Message{{Role: "user", Content: "one"}}},
+		{Model: "m", Messages: []Message{{Role: "user", Content: "two"}}},
+	})
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var line BatchRequestLine
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &line))
+	assert.Equal(t, BatchCustomID(1), line.CustomID)
+	assert.Equal(t, "POST", line.Method)
+	assert.Equal(t, DefaultBatchEndpoint, line.URL)
+	assert.Equal(t, "two", line.Body.Messages[0].Content)
+}
+
+func TestCreateBatch(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+	mockTransport.SetResponse("POST", "/batches", 200, BatchResponse{
+		Batch: Batch{ID: "batch-1", Status: "validating"},
+	})
+
+	input, err := BuildBatchJSONL([]ChatCompletionRequest{{Model: "m"}})
+	require.NoError(t, err)
+
+	resp, err := client.CreateBatch(context.Background(), CreateBatchRequest{Input: bytes.NewReader(input)})
+	require.NoError(t, err)
+	assert.Equal(t, "batch-1", resp.Batch.ID)
+
+	req := mockTransport.GetRequests()[0]
+	require.NoError(t, req.ParseMultipartForm(1<<20))
+	assert.Equal(t, DefaultBatchEndpoint, req.FormValue("endpoint"))
+	assert.Equal(t, "24h", req.FormValue("completion_window"))
+
+	file, header, err := req.FormFile("file")
+	require.NoError(t, err)
+	defer file.Close()
+	assert.Equal(t, "batch.jsonl", header.Filename)
+	uploaded, _ := io.ReadAll(file)
+	assert.Equal(t, input, uploaded)
+}
+
+func TestListBatches(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+	mockTransport.SetResponse("GET", "/batches", 200, ListBatchesResponse{
+		Batches: []Batch{{ID: "batch-1"}, {ID: "batch-2"}},
+	})
+
+	resp, err := client.ListBatches(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, resp.Batches, 2)
+}
+
+func TestWaitForBatchAndDownload(t *testing.T) {
+	statuses := []string{"validating", "in_progress", "completed"}
+	results := `{"id":"r1","custom_id":"request-0","response":{"status_code":200,"body":{"choices":[{"message":{"role":"assistant","content":"Hi"}}]}}}
+
+{"id":"r2","custom_id":"request-1","error":{"message":"bad request"}}
+`
+	var polls int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		switch req.URL.Path {
+		case "/batches/batch-1":
+			body, _ := json.Marshal(BatchResponse{Batch: Batch{ID: "batch-1", Status: statuses[polls]}})
+			polls++
+			return textResponse(200, string(body)), nil
+		case "/batches/batch-1/results":
+			assert.Equal(t, "application/jsonl", req.Header.Get("Accept"))
+			return textResponse(200, results), nil
+		}
+		return textResponse(404, `{"message":"not found"}`), nil
+	})
+	client := NewClient("test-api-key", WithBaseURL("https://api.test.local"), WithHTTPClient(&http.Client{Transport: transport}))
+
+	batch, err := client.WaitForBatch(context.Background(), "batch-1", time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, "completed", batch.Status)
+	assert.Equal(t, 3, polls)
+
+	got, err := client.DownloadBatchResults(context.Background(), "batch-1")
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	completion, err := got[0].Completion()
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", completion.Choices[0].Message.Content)
+
+	_, err = got[1].Completion()
+	assert.EqualError(t, err, "batch request request-1 failed: bad request")
+}
+
+func TestParseBatchResultsInvalid(t *testing.T) {
+	_, err := ParseBatchResults(strings.NewReader("{\"id\":\"r1\"}\nnot json\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
@@ -0,0 +1,98 @@
+package vultrai
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func benchmarkStreamData(chunks int) string {
+	var sb strings.Builder
+	for i := 0; i < chunks; i++ {
+		fmt.Fprintf(&sb, `data: {"id":"chat-123","created":1640995200,"model":"test-model","choices":[{"index":0,"delta":{"content":"token %d "}}]}`+"\n\n", i)
+	}
+	sb.WriteString("data: [DONE]\n\n")
+	return sb.String()
+}
+
+func benchmarkChunks(n int) []*StreamChatCompletion {
+	chunks := make([]*StreamChatCompletion, n)
+	for i := range chunks {
+		chunks[i] = &StreamChatCompletion{
+			ID:      "chat-123",
+			Choices: []StreamChoice{{Delta: StreamDelta{Content: "token "}}},
+		}
+	}
+	return chunks
+}
+
+func BenchmarkMarshalChatCompletionRequest(b *testing.B) {
+	req := ChatCompletionRequest{
+		Model: "test-model",
+		Messages: []Message{
+			CreateSystemMessage("You are a helpful assistant."),
+			CreateUserMessage(strings.Repeat("Tell me something interesting. ", 50)),
+		},
+		MaxTokens:   Int(256),
+		Temperature: Float64(0.7),
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalChatCompletionResponse(b *testing.B) {
+	data, _ := json.Marshal(ChatCompletionResponse{
+		ID:      "chat-123",
+		Model:   "test-model",
+		Choices: []Choice{{Message: Message{Role: "assistant", Content: strings.Repeat("word ", 500)}}},
+		Usage:   Usage{PromptTokens: 10, CompletionTokens: 500, TotalTokens: 510},
+	})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var resp ChatCompletionResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStreamReaderRecv(b *testing.B) {
+	data := benchmarkStreamData(100)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		reader := NewStreamReader(io.NopCloser(strings.NewReader(data)))
+		for {
+			if _, err := reader.Recv(); err != nil {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkAccumulateStreamContent(b *testing.B) {
+	chunks := benchmarkChunks(500)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		AccumulateStreamContent(chunks)
+	}
+}
+
+func BenchmarkStreamToComplete(b *testing.B) {
+	chunks := benchmarkChunks(500)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		StreamToComplete(chunks)
+	}
+}
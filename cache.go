@@ -0,0 +1,204 @@
+package vultrai
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Cache is a storage backend for cached chat completions
+type Cache interface {
+	// Get returns the cached value for key and whether it was found
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for the given TTL (zero means no expiry)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// WithCache enables caching of deterministic chat completions, so repeated
+// ones are served from cache, keyed by a hash of the request, e.g. a
+// MemoryCache. Only requests with a temperature of 0 or an explicit seed
+// are cached.
+func WithCache(cache Cache, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+// IsDeterministic reports whether a request is expected to produce a repeatable result
+func IsDeterministic(req ChatCompletionRequest) bool {
+	if req.Seed != nil {
+		return true
+	}
+	return req.Temperature != nil && *req.Temperature == 0
+}
+
+// CompletionCacheKey returns the cache key for a chat completion request.
+// The client extends it with the base URL and the request options of the
+// call, so responses aren't shared between endpoints or API keys.
+func CompletionCacheKey(req ChatCompletionRequest) (string, error) {
+	// Streaming does not change the result, so it must not change the key
+	req.Stream = nil
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// cachedCompletion looks up a cached response, treating any cache failure as a miss
+func (c *Client) cachedCompletion(ctx context.Context, key string) (*ChatCompletionResponse, bool) {
+	data, ok, err := c.cache.Get(ctx, storageKey(key))
+	if err != nil || !ok {
+		return nil, false
+	}
+
+	var resp ChatCompletionResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, false
+	}
+
+	return &resp, true
+}
+
+// storeCompletion writes a response to the cache; failures are ignored
+func (c *Client) storeCompletion(ctx context.Context, key string, resp *ChatCompletionResponse) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	_ = c.cache.Set(ctx, storageKey(key), data, c.cacheTTL)
+}
+
+// storageKey hashes a request key, which may hold header values such as
+// API keys, before it is handed to the cache
+func storageKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryCache is an in-memory LRU Cache with a bounded number of entries.
+// When full, the least recently used entry is evicted.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List
+}
+
+type memoryCacheEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCache creates an in-memory cache holding at most maxEntries values.
+// A maxEntries of zero or less means the cache is unbounded.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Get returns the cached value for key
+func (m *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := elem.Value.(*memoryCacheEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		m.removeElement(elem)
+		return nil, false, nil
+	}
+
+	m.order.MoveToBack(elem)
+	return entry.value, true, nil
+}
+
+// Set stores value under key
+func (m *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if elem, ok := m.entries[key]; ok {
+		m.removeElement(elem)
+	}
+
+	elem := m.order.PushBack(&memoryCacheEntry{key: key, value: value, expiresAt: expiresAt})
+	m.entries[key] = elem
+
+	for m.maxEntries > 0 && m.order.Len() > m.maxEntries {
+		m.removeElement(m.order.Front())
+	}
+
+	return nil
+}
+
+// Len returns the number of entries currently held
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+func (m *MemoryCache) removeElement(elem *list.Element) {
+	m.order.Remove(elem)
+	delete(m.entries, elem.Value.(*memoryCacheEntry).key)
+}
+
+// RedisClient is the subset of a Redis client used by RedisCache.
+// Get must return a nil slice and no error when the key does not exist.
+type RedisClient interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// RedisCache is a Cache backed by Redis
+type RedisCache struct {
+	client RedisClient
+	prefix string
+}
+
+// NewRedisCache creates a Redis-backed cache, namespacing keys with prefix
+func NewRedisCache(client RedisClient, prefix string) *RedisCache {
+	return &RedisCache{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Get returns the cached value for key
+func (r *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := r.client.Get(ctx, r.prefix+key)
+	if err != nil {
+		return nil, false, err
+	}
+	if value == nil {
+		return nil, false, nil
+	}
+	return value, true, nil
+}
+
+// Set stores value under key
+func (r *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, r.prefix+key, value, ttl)
+}
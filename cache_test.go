@@ -0,0 +1,118 @@
+package vultrai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletionCache(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+	WithCache(NewMemoryCache(10), time.Minute)(client)
+
+	mockTransport.SetResponse("POST", "/chat/completions", 200, &ChatCompletionResponse{
+		ID:      "chat-123",
+		Choices: []Choice{{Message: Message{Role: "assistant", Content: "cached"}}},
+	})
+
+	req := ChatCompletionRequest{
+		Model:       "test-model",
+		Messages:    []Message{{Role: "user", Content: "Hello"}},
+		Temperature: Float64(0),
+	}
+
+	first, err := client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+
+	second, err := client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, "cached", second.Choices[0].Message.Content)
+	assert.Len(t, mockTransport.GetRequests(), 1)
+}
+
+func TestCompletionCacheSkipsNonDeterministic(t *testing.T) {
+	client, mockTransport := setupTestClient()
+	WithCache(NewMemoryCache(10), time.Minute)(client)
+
+	req := ChatCompletionRequest{
+		Model:       "test-model",
+		Messages:    []Message{{Role: "user", Content: "Hello"}},
+		Temperature: Float64(0.7),
+	}
+
+	_, err := client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+	_, err = client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Len(t, mockTransport.GetRequests(), 2)
+}
+
+func TestCompletionCacheKey(t *testing.T) {
+	req := ChatCompletionRequest{Model: "m", Messages: []Message{{Role: "user", Content: "hi"}}}
+
+	key1, err := CompletionCacheKey(req)
+	require.NoError(t, err)
+
+	req.Stream = Bool(true)
+	key2, err := CompletionCacheKey(req)
+	require.NoError(t, err)
+	assert.Equal(t, key1, key2)
+
+	req.Messages[0].Content = "bye"
+	key3, err := CompletionCacheKey(req)
+	require.NoError(t, err)
+	assert.NotEqual(t, key1, key3)
+}
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(2)
+
+	require.NoError(t, cache.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, cache.Set(ctx, "b", []byte("2"), 0))
+	require.NoError(t, cache.Set(ctx, "c", []byte("3"), 0))
+
+	_, ok, _ := cache.Get(ctx, "a")
+	assert.False(t, ok, "oldest entry should be evicted")
+	assert.Equal(t, 2, cache.Len())
+
+	// Reading an entry keeps it over older ones
+	_, ok, _ = cache.Get(ctx, "b")
+	assert.True(t, ok)
+	require.NoError(t, cache.Set(ctx, "e", []byte("5"), 0))
+	_, ok, _ = cache.Get(ctx, "c")
+	assert.False(t, ok, "least recently used entry should be evicted")
+	_, ok, _ = cache.Get(ctx, "b")
+	assert.True(t, ok)
+
+	require.NoError(t, cache.Set(ctx, "d", []byte("4"), time.Nanosecond))
+	time.Sleep(time.Millisecond)
+	_, ok, _ = cache.Get(ctx, "d")
+	assert.False(t, ok, "expired entry should not be returned")
+}
+
+func TestCompletionCacheRequestOptions(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	WithCache(NewMemoryCache(10), time.Minute)(client)
+	req := ChatCompletionRequest{Model: "m", Messages: []Message{CreateUserMessage("hi")}, Temperature: Float64(0)}
+
+	for _, ctx := range []context.Context{
+		context.Background(),
+		ContextWithRequestOptions(context.Background(), WithRequestHeader("Authorization", "Bearer other-key")),
+		ContextWithRequestOptions(context.Background(), WithRequestBaseURL("https://eu.api.test.local")),
+		context.Background(),
+	} {
+		transport.SetResponse("POST", "/chat/completions", 200, ChatCompletionResponse{ID: "resp"})
+		_, err := client.CreateChatCompletion(ctx, req)
+		require.NoError(t, err)
+	}
+
+	// Calls with other headers or base URLs aren't served from the cache
+	assert.Len(t, transport.GetRequests(), 3)
+}
@@ -0,0 +1,109 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CaptionLength controls how much detail a caption carries
+type CaptionLength string
+
+const (
+	// CaptionShort is a single short sentence
+	CaptionShort CaptionLength = "short"
+	// CaptionMedium is one or two sentences
+	CaptionMedium CaptionLength = "medium"
+	// CaptionDetailed is a full paragraph
+	CaptionDetailed CaptionLength = "detailed"
+)
+
+// captionLengths maps lengths to the prompt wording and a token budget
+var captionLengths = map[CaptionLength]struct {
+	instruction string
+	maxTokens   int
+}{
+	CaptionShort:    {"a single short sentence of at most 15 words", 60},
+	CaptionMedium:   {"one or two sentences", 120},
+	CaptionDetailed: {"a detailed paragraph", 400},
+}
+
+// CaptionStyle configures CaptionImage
+type CaptionStyle struct {
+	// Length defaults to CaptionShort
+	Length CaptionLength
+	// Tone is free text such as "neutral", "playful" or "formal"
+	// (default neutral)
+	Tone string
+	// AltText writes alt text for screen readers: what the image shows
+	// and any text in it, without phrases like "image of"
+	AltText bool
+	// Language of the caption (default English)
+	Language string
+}
+
+// AltTextStyle is the style for short, accessible alt text
+var AltTextStyle = CaptionStyle{Length: CaptionShort, AltText: true}
+
+// CaptionImage describes an image with a vision-capable model, as a caption
+// or as alt text
+func (c *Client) CaptionImage(ctx context.Context, model string, image []byte, style CaptionStyle) (string, error) {
+	if len(image) == 0 {
+		return "", errors.New("image is empty")
+	}
+	if style.Length == "" {
+		style.Length = CaptionShort
+	}
+	length, ok := captionLengths[style.Length]
+	if !ok {
+		return "", fmt.Errorf("unknown caption length %q", style.Length)
+	}
+	tone := style.Tone
+	if tone == "" {
+		tone = "neutral"
+	}
+
+	var prompt strings.Builder
+	if style.AltText {
+		prompt.WriteString("Write alt text for this image for people using screen readers. ")
+		prompt.WriteString("Describe what matters in the image and include any important text it contains. ")
+		prompt.WriteString(`Do not start with "image of" or "picture of". `)
+	} else {
+		prompt.WriteString("Write a caption for this image. ")
+	}
+	fmt.Fprintf(&prompt, "Use %s in a %s tone. ", length.instruction, tone)
+	if style.Language != "" {
+		fmt.Fprintf(&prompt, "Write in %s. ", style.Language)
+	}
+	prompt.WriteString("Respond with the text only.")
+
+	resp, err := c.CreateChatCompletion(ctx, ChatCompletionRequest{
+		Model:     model,
+		Messages:  []Message{CreateImageMessage(prompt.String(), ImageDataURL(image))},
+		MaxTokens: Int(length.maxTokens),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error captioning image: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", errors.New("error captioning image: no choices returned")
+	}
+	return cleanCaption(resp.Choices[0].Message.Content), nil
+}
+
+// cleanCaption removes labels and quotes models tend to wrap captions in
+func cleanCaption(s string) string {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+	for _, label := range []string{"alt text:", "alt:", "caption:"} {
+		if strings.HasPrefix(lower, label) {
+			s = strings.TrimSpace(s[len(label):])
+			break
+		}
+	}
+	if len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '\'' && s[len(s)-1] == '\'') {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	return s
+}
@@ -0,0 +1,33 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptionImage(t *testing.T) {
+	client, transport := setupSequenceClient(`Alt text: "A red bicycle leaning on a brick wall."`, "A lazy Sunday ride.")
+	ctx := context.Background()
+
+	alt, err := client.CaptionImage(ctx, "vision-model", []byte("img"), AltTextStyle)
+	require.NoError(t, err)
+	assert.Equal(t, "A red bicycle leaning on a brick wall.", alt)
+
+	caption, err := client.CaptionImage(ctx, "vision-model", []byte("img"), CaptionStyle{Length: CaptionDetailed, Tone: "playful", Language: "French"})
+	require.NoError(t, err)
+	assert.Equal(t, "A lazy Sunday ride.", caption)
+
+	var req ChatCompletionRequest
+	require.NoError(t, json.NewDecoder(transport.requests[1].Body).Decode(&req))
+	prompt := req.Messages[0].Parts[0].Text
+	assert.Contains(t, prompt, "a detailed paragraph in a playful tone")
+	assert.Contains(t, prompt, "Write in French.")
+	assert.Equal(t, 400, *req.MaxTokens)
+
+	_, err = client.CaptionImage(ctx, "vision-model", []byte("img"), CaptionStyle{Length: "epic"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,280 @@
+// Package chunker splits long documents into vector store items on the
+// client. Unlike the server's AutoChunk, the chunks are deterministic: the
+// same text and settings always produce the same items, so they can be
+// diffed, cached and cited.
+package chunker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	vultrai "github.com/eqba1/vultrai"
+)
+
+// DefaultSize is the chunk size used when Chunker.Size is not set
+const DefaultSize = 2000
+
+// Boundary selects where chunks prefer to end
+type Boundary int
+
+const (
+	// Paragraph keeps paragraphs whole when they fit in a chunk, falling
+	// back to sentence boundaries for longer paragraphs
+	Paragraph Boundary = iota
+	// Sentence packs as many whole sentences as fit in each chunk,
+	// regardless of paragraphs
+	Sentence
+)
+
+// Chunker splits text into chunks of at most Size characters. Sentences
+// longer than Size are split between words, and words longer than Size
+// are split anywhere. The zero value splits at paragraphs into chunks of
+// DefaultSize characters without overlap.
+type Chunker struct {
+	Size     int      // maximum characters per chunk (default DefaultSize)
+	Overlap  int      // characters of whole trailing sentences repeated at the start of the next chunk
+	Boundary Boundary // preferred chunk boundary (default Paragraph)
+}
+
+// segment is a sentence, or a piece of one, that fits in a chunk
+type segment struct {
+	text string
+	size int
+	para bool // starts a paragraph
+}
+
+var paragraphBreak = regexp.MustCompile(`\n[ \t]*\n`)
+
+// Split splits text into chunks
+func (c Chunker) Split(text string) []string {
+	size := c.Size
+	if size <= 0 {
+		size = DefaultSize
+	}
+	overlap := c.Overlap
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+	return c.pack(segments(text, size), size, overlap)
+}
+
+// Items splits text into items ready for AddItem or AddItems. description
+// is numbered when there are several parts. AutoChunk is disabled so the
+// server keeps the chunks as they are.
+func (c Chunker) Items(text, description string) []vultrai.AddItemRequest {
+	parts := c.Split(text)
+	autoChunk := false
+	items := make([]vultrai.AddItemRequest, 0, len(parts))
+	for i, part := range parts {
+		desc := description
+		if len(parts) > 1 {
+			desc = strings.TrimSpace(fmt.Sprintf("%s (part %d of %d)", description, i+1, len(parts)))
+		}
+		items = append(items, vultrai.AddItemRequest{
+			Content:     part,
+			Description: desc,
+			AutoChunk:   &autoChunk,
+		})
+	}
+	return items
+}
+
+// pack joins segments greedily into chunks
+func (c Chunker) pack(segs []segment, size, overlap int) []string {
+	var chunks []string
+	var current []segment
+	currentLen := 0
+	fresh := false // current holds content not yet emitted
+
+	flush := func() {
+		chunks = append(chunks, join(current))
+
+		// Keep trailing segments that fit within the overlap window
+		start := len(current)
+		for start > 0 && joinedLen(current[start-1:]) <= overlap {
+			start--
+		}
+		current = append([]segment(nil), current[start:]...)
+		currentLen = joinedLen(current)
+		fresh = false
+	}
+
+	for i, seg := range segs {
+		if c.Boundary == Paragraph && seg.para && fresh {
+			// Start a paragraph in a new chunk unless it fits in this one,
+			// or is too long for any chunk
+			paraLen := paragraphLen(segs[i:])
+			if currentLen+2+paraLen > size && paraLen <= size {
+				flush()
+				for len(current) > 0 && currentLen+2+paraLen > size {
+					current = current[1:]
+					currentLen = joinedLen(current)
+				}
+			}
+		}
+
+		if currentLen > 0 && currentLen+separatorLen(seg)+seg.size > size {
+			if fresh {
+				flush()
+			}
+			// Drop overlap that would not leave room for the new segment
+			for len(current) > 0 && currentLen+separatorLen(seg)+seg.size > size {
+				current = current[1:]
+				currentLen = joinedLen(current)
+			}
+		}
+		if currentLen > 0 {
+			currentLen += separatorLen(seg)
+		}
+		current = append(current, seg)
+		currentLen += seg.size
+		fresh = true
+	}
+
+	if fresh {
+		chunks = append(chunks, join(current))
+	}
+	return chunks
+}
+
+// segments splits text into sentences no longer than size
+func segments(text string, size int) []segment {
+	var segs []segment
+	for _, para := range paragraphBreak.Split(text, -1) {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		first := true
+		for _, sentence := range sentences(para) {
+			for _, piece := range splitLong(sentence, size) {
+				segs = append(segs, segment{text: piece, size: utf8.RuneCountInString(piece), para: first})
+				first = false
+			}
+		}
+	}
+	return segs
+}
+
+// sentences splits a paragraph after sentence-ending punctuation followed
+// by whitespace
+func sentences(para string) []string {
+	var out []string
+	runes := []rune(para)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '.' && runes[i] != '!' && runes[i] != '?' {
+			continue
+		}
+		end := i + 1
+		// Closing quotes and brackets belong to the sentence
+		for end < len(runes) && strings.ContainsRune(`"')]”’`, runes[end]) {
+			end++
+		}
+		if end < len(runes) && !unicode.IsSpace(runes[end]) {
+			continue
+		}
+		if sentence := strings.TrimSpace(string(runes[start:end])); sentence != "" {
+			out = append(out, sentence)
+		}
+		start, i = end, end-1
+	}
+	if sentence := strings.TrimSpace(string(runes[start:])); sentence != "" {
+		out = append(out, sentence)
+	}
+	return out
+}
+
+// splitLong splits a sentence longer than size between words, and words
+// longer than size anywhere
+func splitLong(sentence string, size int) []string {
+	if utf8.RuneCountInString(sentence) <= size {
+		return []string{sentence}
+	}
+
+	var pieces []string
+	var current strings.Builder
+	currentLen := 0
+	for _, word := range strings.Fields(sentence) {
+		runes := []rune(word)
+		for len(runes) > size {
+			if currentLen > 0 {
+				pieces = append(pieces, current.String())
+				current.Reset()
+				currentLen = 0
+			}
+			pieces = append(pieces, string(runes[:size]))
+			runes = runes[size:]
+		}
+		if len(runes) == 0 {
+			continue
+		}
+		if currentLen > 0 && currentLen+1+len(runes) > size {
+			pieces = append(pieces, current.String())
+			current.Reset()
+			currentLen = 0
+		}
+		if currentLen > 0 {
+			current.WriteByte(' ')
+			currentLen++
+		}
+		current.WriteString(string(runes))
+		currentLen += len(runes)
+	}
+	if currentLen > 0 {
+		pieces = append(pieces, current.String())
+	}
+	return pieces
+}
+
+// separatorLen is the length of the separator written before seg
+func separatorLen(seg segment) int {
+	if seg.para {
+		return 2
+	}
+	return 1
+}
+
+// paragraphLen returns the joined length of the paragraph starting at
+// segs[0]
+func paragraphLen(segs []segment) int {
+	n := segs[0].size
+	for _, seg := range segs[1:] {
+		if seg.para {
+			break
+		}
+		n += 1 + seg.size
+	}
+	return n
+}
+
+func joinedLen(segs []segment) int {
+	n := 0
+	for i, seg := range segs {
+		if i > 0 {
+			n += separatorLen(seg)
+		}
+		n += seg.size
+	}
+	return n
+}
+
+// join writes segments separated by a blank line between paragraphs and a
+// space between sentences
+func join(segs []segment) string {
+	var sb strings.Builder
+	for i, seg := range segs {
+		if i > 0 {
+			if seg.para {
+				sb.WriteString("\n\n")
+			} else {
+				sb.WriteByte(' ')
+			}
+		}
+		sb.WriteString(seg.text)
+	}
+	return sb.String()
+}
@@ -0,0 +1,79 @@
+package chunker
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const document = `First paragraph opens here. It has a second sentence.
+
+Second paragraph is a little longer than the first one. It goes on for a while. Then it ends!
+
+Third.`
+
+func TestChunkerParagraphs(t *testing.T) {
+	chunks := Chunker{Size: 120}.Split(document)
+	assert.Equal(t, []string{
+		"First paragraph opens here. It has a second sentence.",
+		"Second paragraph is a little longer than the first one. It goes on for a while. Then it ends!\n\nThird.",
+	}, chunks)
+
+	chunks = Chunker{Size: 120, Boundary: Sentence}.Split(document)
+	assert.Equal(t, []string{
+		"First paragraph opens here. It has a second sentence.\n\nSecond paragraph is a little longer than the first one.",
+		"It goes on for a while. Then it ends!\n\nThird.",
+	}, chunks)
+
+	chunks = Chunker{}.Split(document)
+	require.Len(t, chunks, 1)
+	assert.Equal(t, strings.Count(document, "\n\n"), strings.Count(chunks[0], "\n\n"))
+}
+
+func TestChunkerSentences(t *testing.T) {
+	chunks := Chunker{Size: 80, Boundary: Sentence}.Split(document)
+	assert.Equal(t, []string{
+		"First paragraph opens here. It has a second sentence.",
+		"Second paragraph is a little longer than the first one. It goes on for a while.",
+		"Then it ends!\n\nThird.",
+	}, chunks)
+
+	for _, chunk := range (Chunker{Size: 30, Boundary: Sentence}).Split(`He said "stop." She didn't. Version 1.2 shipped?`) {
+		assert.LessOrEqual(t, utf8.RuneCountInString(chunk), 30)
+	}
+	assert.Equal(t, []string{`He said "stop."`, `She didn't.`, `Version 1.2 shipped?`},
+		sentences(`He said "stop." She didn't. Version 1.2 shipped?`))
+}
+
+func TestChunkerOverlap(t *testing.T) {
+	text := "One two. Three four. Five six. Seven eight. Nine ten."
+	chunks := Chunker{Size: 30, Overlap: 12, Boundary: Sentence}.Split(text)
+	assert.Equal(t, []string{
+		"One two. Three four. Five six.",
+		"Five six. Seven eight.",
+		"Seven eight. Nine ten.",
+	}, chunks)
+}
+
+func TestChunkerLongWords(t *testing.T) {
+	text := "short " + strings.Repeat("x", 25) + " tail words here"
+	chunks := Chunker{Size: 10}.Split(text)
+	assert.Equal(t, []string{"short", "xxxxxxxxxx", "xxxxxxxxxx", "xxxxx tail", "words here"}, chunks)
+	assert.Empty(t, Chunker{}.Split(" \n\n "))
+}
+
+func TestChunkerItems(t *testing.T) {
+	items := Chunker{Size: 80}.Items(document, "Guide")
+	require.Len(t, items, 3)
+	assert.Equal(t, "Guide (part 2 of 3)", items[1].Description)
+	require.NotNil(t, items[0].AutoChunk)
+	assert.False(t, *items[0].AutoChunk)
+	assert.Equal(t, items, Chunker{Size: 80}.Items(document, "Guide"))
+
+	items = Chunker{}.Items("Short note.", "Note")
+	require.Len(t, items, 1)
+	assert.Equal(t, "Note", items[0].Description)
+}
@@ -0,0 +1,181 @@
+package vultrai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Default minimum similarities for a sentence to be cited
+const (
+	DefaultFuzzyCitationThreshold     = 0.5
+	DefaultEmbeddingCitationThreshold = 0.75
+)
+
+// Citation links a sentence of an answer to the passage of a source that
+// best supports it. Offsets are in bytes, for highlighting in a UI.
+type Citation struct {
+	// Start and End delimit the sentence in the answer
+	Start, End int
+	Text       string
+	// Source is the index of the supporting source
+	Source   int
+	SourceID string
+	// SourceStart and SourceEnd delimit the supporting passage in the
+	// source content
+	SourceStart, SourceEnd int
+	// Score is the similarity between the sentence and the passage
+	Score float64
+}
+
+// CitationOptions configures Cite
+type CitationOptions struct {
+	// Embedder compares sentences by embedding similarity; without one
+	// they are compared by word overlap
+	Embedder Embedder
+	// Threshold is the minimum similarity for a citation (default
+	// DefaultEmbeddingCitationThreshold with an Embedder,
+	// DefaultFuzzyCitationThreshold otherwise)
+	Threshold float64
+}
+
+// span is a sentence located in a larger text
+type span struct {
+	start, end int
+	text       string
+}
+
+// passage is a sentence of a source
+type passage struct {
+	source int
+	span
+}
+
+// Cite aligns each sentence of answer with the most similar sentence among
+// sources, typically the results of the search that grounded the answer.
+// Sentences without a passage above the threshold are left uncited.
+func Cite(ctx context.Context, answer string, sources []SearchResult, opts CitationOptions) ([]Citation, error) {
+	sentences := splitSentences(answer)
+	var passages []passage
+	for i, source := range sources {
+		for _, s := range splitSentences(source.Content) {
+			passages = append(passages, passage{source: i, span: s})
+		}
+	}
+	if len(sentences) == 0 || len(passages) == 0 {
+		return nil, nil
+	}
+
+	threshold := opts.Threshold
+	var score func(i, j int) float64
+	if opts.Embedder != nil {
+		if threshold == 0 {
+			threshold = DefaultEmbeddingCitationThreshold
+		}
+		texts := make([]string, 0, len(sentences)+len(passages))
+		for _, s := range sentences {
+			texts = append(texts, s.text)
+		}
+		for _, p := range passages {
+			texts = append(texts, p.text)
+		}
+		vecs, err := opts.Embedder.Embed(ctx, texts)
+		if err != nil {
+			return nil, fmt.Errorf("error embedding citations: %w", err)
+		}
+		if len(vecs) != len(texts) {
+			return nil, fmt.Errorf("embedder returned %d vectors for %d texts", len(vecs), len(texts))
+		}
+		score = func(i, j int) float64 { return CosineSimilarity(vecs[i], vecs[len(sentences)+j]) }
+	} else {
+		if threshold == 0 {
+			threshold = DefaultFuzzyCitationThreshold
+		}
+		sentenceWords := make([][]string, len(sentences))
+		for i, s := range sentences {
+			sentenceWords[i] = splitWords(s.text)
+		}
+		passageWords := make([]map[string]bool, len(passages))
+		for j, p := range passages {
+			passageWords[j] = make(map[string]bool)
+			for _, w := range splitWords(p.text) {
+				passageWords[j][w] = true
+			}
+		}
+		score = func(i, j int) float64 { return wordCoverage(sentenceWords[i], passageWords[j]) }
+	}
+
+	var citations []Citation
+	for i, s := range sentences {
+		best, bestScore := -1, 0.0
+		for j := range passages {
+			if sc := score(i, j); sc > bestScore {
+				best, bestScore = j, sc
+			}
+		}
+		if best < 0 || bestScore < threshold {
+			continue
+		}
+		p := passages[best]
+		citations = append(citations, Citation{
+			Start:       s.start,
+			End:         s.end,
+			Text:        s.text,
+			Source:      p.source,
+			SourceID:    sources[p.source].ID,
+			SourceStart: p.start,
+			SourceEnd:   p.end,
+			Score:       bestScore,
+		})
+	}
+	return citations, nil
+}
+
+// wordCoverage is the share of the words of sentence found in passage,
+// ignoring words of one or two letters
+func wordCoverage(sentence []string, passage map[string]bool) float64 {
+	total, found := 0, 0
+	for _, w := range sentence {
+		if len(w) < 3 {
+			continue
+		}
+		total++
+		if passage[w] {
+			found++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(found) / float64(total)
+}
+
+// splitSentences locates the sentences of text, ending them at ., ! or ?
+// followed by a space and at line breaks
+func splitSentences(text string) []span {
+	var spans []span
+	start := 0
+	emit := func(end int) {
+		s := strings.TrimSpace(text[start:end])
+		if s != "" {
+			offset := start + strings.Index(text[start:end], s)
+			spans = append(spans, span{start: offset, end: offset + len(s), text: s})
+		}
+		start = end
+	}
+
+	for i, r := range text {
+		switch {
+		case r == '\n':
+			emit(i)
+		case r == '.' || r == '!' || r == '?':
+			next := i + 1
+			if next == len(text) || unicode.IsSpace(rune(text[next])) {
+				emit(next)
+			}
+		}
+	}
+	emit(len(text))
+	return spans
+}
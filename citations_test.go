@@ -0,0 +1,68 @@
+package vultrai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCite(t *testing.T) {
+	sources := []SearchResult{
+		{ID: "shipping", Content: "Orders ship from Berlin. Standard delivery takes three to five business days."},
+		{ID: "refunds", Content: "Refunds are issued to the original payment method within 14 days."},
+	}
+	answer := "Delivery usually takes three to five business days! Refunds go back to your original payment method within 14 days.\nLet me know if you need anything else."
+
+	citations, err := Cite(context.Background(), answer, sources, CitationOptions{})
+	require.NoError(t, err)
+	require.Len(t, citations, 2)
+
+	first := citations[0]
+	assert.Equal(t, "Delivery usually takes three to five business days!", answer[first.Start:first.End])
+	assert.Equal(t, first.Text, answer[first.Start:first.End])
+	assert.Equal(t, "shipping", first.SourceID)
+	assert.Equal(t, "Standard delivery takes three to five business days.", sources[0].Content[first.SourceStart:first.SourceEnd])
+
+	second := citations[1]
+	assert.Equal(t, 1, second.Source)
+	assert.Equal(t, "refunds", second.SourceID)
+	assert.InDelta(t, 0.75, second.Score, 1e-9)
+}
+
+func TestCiteEmbeddings(t *testing.T) {
+	// Embed by the presence of two topics so synonyms can align
+	embedder := EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+		vecs := make([][]float32, len(texts))
+		for i, text := range texts {
+			lower := strings.ToLower(text)
+			vecs[i] = []float32{0.01, 0, 0}
+			if strings.Contains(lower, "ship") || strings.Contains(lower, "deliver") {
+				vecs[i][1] = 1
+			}
+			if strings.Contains(lower, "refund") || strings.Contains(lower, "money back") {
+				vecs[i][2] = 1
+			}
+		}
+		return vecs, nil
+	})
+	sources := []SearchResult{{ID: "a", Content: "We ship worldwide."}, {ID: "b", Content: "Refunds take a week."}}
+
+	citations, err := Cite(context.Background(), "You get your money back. Hello there.", sources, CitationOptions{Embedder: embedder})
+	require.NoError(t, err)
+	require.Len(t, citations, 1)
+	assert.Equal(t, "You get your money back.", citations[0].Text)
+	assert.Equal(t, "b", citations[0].SourceID)
+}
+
+func TestSplitSentences(t *testing.T) {
+	text := "  Version 1.5 is out. Really?  Yes!\n\nNew line"
+	var got []string
+	for _, s := range splitSentences(text) {
+		assert.Equal(t, s.text, text[s.start:s.end])
+		got = append(got, s.text)
+	}
+	assert.Equal(t, []string{"Version 1.5 is out.", "Really?", "Yes!", "New line"}, got)
+}
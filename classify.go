@@ -0,0 +1,119 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrNoMatchingLabel is returned when the model does not answer with one of
+// the allowed labels
+var ErrNoMatchingLabel = errors.New("model did not return an allowed label")
+
+// Classification is the result of Classify
+type Classification struct {
+	Label string
+	// Confidence is the model's probability for the answer, derived from
+	// logprobs. It is zero when the model did not return logprobs.
+	Confidence float64
+	Usage      Usage
+}
+
+// Classify asks model to assign text exactly one of labels. The answer is
+// validated against the label set and the model is asked again once if it
+// strays. Confidence is taken from token logprobs when the model provides them.
+func Classify(ctx context.Context, client *Client, model, text string, labels []string) (*Classification, error) {
+	if len(labels) == 0 {
+		return nil, errors.New("at least one label is required")
+	}
+
+	longest := 0
+	for _, label := range labels {
+		if n := EstimateTokens(label); n > longest {
+			longest = n
+		}
+	}
+
+	messages := []Message{
+		CreateSystemMessage(fmt.Sprintf(
+			"Classify the user's text into exactly one of these labels: %s. Respond with the label only, exactly as written.",
+			strings.Join(labels, ", "),
+		)),
+		CreateUserMessage(text),
+	}
+
+	result := &Classification{}
+	for attempt := 0; attempt < 2; attempt++ {
+		resp, err := client.CreateChatCompletion(ctx, ChatCompletionRequest{
+			Model:       model,
+			Messages:    messages,
+			Temperature: Float64(0),
+			MaxTokens:   Int(longest + 8),
+			LogProbs:    Bool(true),
+			TopLogProbs: Int(5),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Choices) == 0 {
+			return nil, errors.New("error classifying: no choices returned")
+		}
+
+		result.Usage.PromptTokens += resp.Usage.PromptTokens
+		result.Usage.CompletionTokens += resp.Usage.CompletionTokens
+		result.Usage.TotalTokens += resp.Usage.TotalTokens
+
+		choice := resp.Choices[0]
+		if label, ok := matchLabel(choice.Message.Content, labels); ok {
+			result.Label = label
+			result.Confidence = sequenceProbability(choice.LogProbs)
+			return result, nil
+		}
+
+		messages = append(messages,
+			CreateAssistantMessage(choice.Message.Content),
+			CreateUserMessage("Answer with exactly one of: "+strings.Join(labels, ", ")),
+		)
+	}
+
+	return nil, ErrNoMatchingLabel
+}
+
+// matchLabel maps a model answer onto the allowed labels, tolerating case,
+// quoting and trailing punctuation, or an answer that mentions a single label
+func matchLabel(answer string, labels []string) (string, bool) {
+	normalized := strings.ToLower(strings.Trim(strings.TrimSpace(answer), "\"'`.*: \n"))
+	for _, label := range labels {
+		if normalized == strings.ToLower(label) {
+			return label, true
+		}
+	}
+
+	var found []string
+	lower := strings.ToLower(answer)
+	for _, label := range labels {
+		if strings.Contains(lower, strings.ToLower(label)) {
+			found = append(found, label)
+		}
+	}
+	if len(found) == 1 {
+		return found[0], true
+	}
+
+	return "", false
+}
+
+// sequenceProbability returns the joint probability of the generated tokens
+func sequenceProbability(logProbs *LogProbs) float64 {
+	if logProbs == nil || len(logProbs.Content) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, token := range logProbs.Content {
+		sum += token.LogProb
+	}
+	return math.Exp(sum)
+}
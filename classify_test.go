@@ -0,0 +1,51 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+	mockTransport.SetResponse("POST", "/chat/completions", 200, &ChatCompletionResponse{
+		Choices: []Choice{{
+			Message: Message{Role: "assistant", Content: "Negative."},
+			LogProbs: &LogProbs{Content: []LogProb{
+				{Token: "Neg", LogProb: math.Log(0.9)},
+				{Token: "ative", LogProb: 0},
+			}},
+		}},
+	})
+
+	result, err := Classify(context.Background(), client, "test-model", "This is awful", []string{"positive", "negative", "neutral"})
+	require.NoError(t, err)
+	assert.Equal(t, "negative", result.Label)
+	assert.InDelta(t, 0.9, result.Confidence, 1e-9)
+
+	var req ChatCompletionRequest
+	require.NoError(t, json.NewDecoder(mockTransport.GetRequests()[0].Body).Decode(&req))
+	assert.True(t, *req.LogProbs)
+	assert.Contains(t, req.Messages[0].Content, "positive, negative, neutral")
+}
+
+func TestClassifyRetriesInvalidLabel(t *testing.T) {
+	client, transport := setupSequenceClient("I think it's mixed", "neutral")
+
+	result, err := Classify(context.Background(), client, "test-model", "meh", []string{"positive", "negative", "neutral"})
+	require.NoError(t, err)
+	assert.Equal(t, "neutral", result.Label)
+	assert.Zero(t, result.Confidence)
+	assert.Len(t, transport.requests, 2)
+}
+
+func TestClassifyNoMatch(t *testing.T) {
+	client, _ := setupSequenceClient("positive or negative")
+
+	_, err := Classify(context.Background(), client, "test-model", "meh", []string{"positive", "negative"})
+	assert.ErrorIs(t, err, ErrNoMatchingLabel)
+}
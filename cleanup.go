@@ -0,0 +1,120 @@
+package vultrai
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// RetentionPolicy selects the items CleanupCollection deletes
+type RetentionPolicy struct {
+	// MaxAge deletes items created longer ago; zero keeps items of any age
+	MaxAge time.Duration
+	// MaxItems keeps only the newest items; zero keeps any number
+	MaxItems int
+	// DryRun reports the items to delete without deleting them
+	DryRun bool
+}
+
+// CleanupReport is the result of CleanupCollection
+type CleanupReport struct {
+	// Items is the number of items examined
+	Items int
+	// Expired holds the items older than MaxAge
+	Expired []CollectionItem
+	// Overflow holds the items beyond MaxItems that had not expired
+	Overflow []CollectionItem
+	// Deleted holds the IDs of removed items
+	Deleted []string
+	// Undated holds the IDs of items whose creation time could not be
+	// parsed; they are never deleted
+	Undated []string
+}
+
+// CleanupCollection deletes the items of a collection that are older than
+// policy.MaxAge or beyond the newest policy.MaxItems, so append-only
+// collections such as logs don't grow without bound
+func (c *Client) CleanupCollection(ctx context.Context, collectionID string, policy RetentionPolicy) (*CleanupReport, error) {
+	items, err := c.ListItemsPager(collectionID, ListOptions{}).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing items: %w", err)
+	}
+	report := &CleanupReport{Items: len(items)}
+
+	type datedItem struct {
+		item    CollectionItem
+		created time.Time
+	}
+	var dated []datedItem
+	for _, item := range items {
+		created, ok := parseCreated(item.Created)
+		if !ok {
+			report.Undated = append(report.Undated, item.ID)
+			continue
+		}
+		dated = append(dated, datedItem{item, created})
+	}
+	// Newest first, so the items beyond the cap are at the end
+	sort.SliceStable(dated, func(i, j int) bool { return dated[i].created.After(dated[j].created) })
+
+	now := time.Now()
+	for i, d := range dated {
+		switch {
+		case policy.MaxAge > 0 && now.Sub(d.created) > policy.MaxAge:
+			report.Expired = append(report.Expired, d.item)
+		case policy.MaxItems > 0 && i >= policy.MaxItems:
+			report.Overflow = append(report.Overflow, d.item)
+		}
+	}
+
+	if policy.DryRun {
+		return report, nil
+	}
+	stale := append(append([]CollectionItem{}, report.Expired...), report.Overflow...)
+	for _, item := range stale {
+		if err := c.DeleteItem(ctx, collectionID, item.ID); err != nil {
+			return report, fmt.Errorf("error deleting item %s: %w", item.ID, err)
+		}
+		report.Deleted = append(report.Deleted, item.ID)
+	}
+
+	return report, nil
+}
+
+// ScheduleCleanup runs CleanupCollection every interval until ctx is done,
+// passing each result to report, which may be nil. It blocks, so run it in
+// its own goroutine.
+func (c *Client) ScheduleCleanup(ctx context.Context, collectionID string, policy RetentionPolicy, interval time.Duration, report func(*CleanupReport, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := c.CleanupCollection(ctx, collectionID, policy)
+		if ctx.Err() != nil {
+			return
+		}
+		if report != nil {
+			report(result, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// createdLayouts are the timestamp formats accepted for item creation times
+var createdLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+// parseCreated parses the creation timestamp of an item
+func parseCreated(created string) (time.Time, bool) {
+	for _, layout := range createdLayouts {
+		if t, err := time.Parse(layout, created); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,83 @@
+package vultrai
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cleanupItems() []CollectionItem {
+	day := func(n int) string { return time.Now().AddDate(0, 0, -n).UTC().Format(time.RFC3339) }
+	return []CollectionItem{
+		{ID: "today", Created: day(0)},
+		{ID: "old", Created: day(40)},
+		{ID: "yesterday", Created: day(1)},
+		{ID: "week", Created: day(7)},
+		{ID: "undated", Created: "sometime"},
+		{ID: "ancient", Created: "2020-01-01"},
+	}
+}
+
+func itemIDList(items []CollectionItem) []string {
+	ids := []string{}
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+func TestCleanupCollection(t *testing.T) {
+	client, deleted := collectionServer(t, cleanupItems(), nil)
+
+	report, err := client.CleanupCollection(context.Background(), "kb", RetentionPolicy{MaxAge: 30 * 24 * time.Hour, MaxItems: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, report.Items)
+	assert.Equal(t, []string{"old", "ancient"}, itemIDList(report.Expired))
+	assert.Equal(t, []string{"week"}, itemIDList(report.Overflow))
+	assert.Equal(t, []string{"undated"}, report.Undated)
+	assert.Equal(t, []string{"old", "ancient", "week"}, report.Deleted)
+	assert.Equal(t, report.Deleted, *deleted)
+}
+
+func TestCleanupCollectionDryRun(t *testing.T) {
+	client, deleted := collectionServer(t, cleanupItems(), nil)
+
+	report, err := client.CleanupCollection(context.Background(), "kb", RetentionPolicy{MaxItems: 1, DryRun: true})
+	require.NoError(t, err)
+	assert.Empty(t, report.Expired)
+	assert.Equal(t, []string{"yesterday", "week", "old", "ancient"}, itemIDList(report.Overflow))
+	assert.Empty(t, report.Deleted)
+	assert.Empty(t, *deleted)
+}
+
+func TestScheduleCleanup(t *testing.T) {
+	client, _ := collectionServer(t, cleanupItems(), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var mu sync.Mutex
+	runs := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client.ScheduleCleanup(ctx, "kb", RetentionPolicy{DryRun: true}, 10*time.Millisecond, func(report *CleanupReport, err error) {
+			assert.NoError(t, err)
+			mu.Lock()
+			defer mu.Unlock()
+			if runs++; runs == 3 {
+				cancel()
+			}
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("cleanup did not stop")
+	}
+	assert.Equal(t, 3, runs)
+}
@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -27,6 +28,27 @@ type Client struct {
 	baseURL    string
 	apiKey     string
 	httpClient *http.Client
+	authHeader string
+	authScheme string
+	signer     RequestSigner
+
+	cache        Cache
+	cacheTTL     time.Duration
+	inflight     *callGroup
+	interceptors []ChatInterceptor
+	scheduler    *Scheduler
+
+	usageCallbacks []UsageCallback
+	tracer         Tracer
+	metrics        MetricsCollector
+	rateLimit      atomic.Pointer[RateLimitInfo]
+
+	endpoints             *endpointSet
+	endpointTimeouts      map[string]time.Duration
+	streamFirstByte       time.Duration
+	streamIdle            time.Duration
+	connectTimeout        time.Duration
+	responseHeaderTimeout time.Duration
 }
 
 // ClientOption represents a function to configure the client
@@ -49,16 +71,21 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 // NewClient creates a new Vultr Inference API client
 func NewClient(apiKey string, options ...ClientOption) *Client {
 	client := &Client{
-		baseURL: defaultBaseURL,
-		apiKey:  apiKey,
+		baseURL:    defaultBaseURL,
+		apiKey:     apiKey,
+		authHeader: "Authorization",
+		authScheme: "Bearer",
 		httpClient: &http.Client{
 			Timeout: defaultTimeout,
 		},
+		streamFirstByte: defaultTimeout,
+		streamIdle:      defaultTimeout,
 	}
 
 	for _, option := range options {
 		option(client)
 	}
+	client.applyTransportTimeouts()
 
 	return client
 }
@@ -75,13 +102,16 @@ func (c *Client) doRequest(ctx context.Context, method, endpoint string, body in
 		reqBody = bytes.NewBuffer(jsonBody)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+endpoint, reqBody)
+	if c.observing() {
+		ctx = withModel(ctx, requestModel(body))
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURLFor(ctx)+endpoint, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
 	// Set default headers
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	c.setAuth(req)
 	req.Header.Set("Content-Type", contentTypeJSON)
 	req.Header.Set("Accept", contentTypeJSON)
 
@@ -89,84 +119,185 @@ func (c *Client) doRequest(ctx context.Context, method, endpoint string, body in
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
+	applyRequestHeaders(req)
+
+	return c.send(ctx, req)
+}
+
+// doMultipartRequest performs a multipart POST. The form is streamed as
+// it is written, so large files are sent in constant memory; the request
+// therefore has no GetBody.
+func (c *Client) doMultipartRequest(ctx context.Context, endpoint string, fields map[string]string, file io.Reader, filename, contentType string) (*http.Response, error) {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	progress := requestOptions(ctx).progress
 
-	resp, err := c.httpClient.Do(req)
+	go func() {
+		pw.CloseWithError(writeMultipartForm(writer, fields, file, filename, contentType, progress))
+	}()
+
+	if c.observing() {
+		ctx = withModel(ctx, fields["model"])
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURLFor(ctx)+endpoint, pr)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
+		pr.CloseWithError(err)
+		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
-	// Check for HTTP errors
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		defer resp.Body.Close()
-		body, _ := io.ReadAll(resp.Body)
+	c.setAuth(req)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	applyRequestHeaders(req)
 
-		var apiError Error
-		if err := json.Unmarshal(body, &apiError); err != nil {
-			return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, apiError.Message)
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		// Unblock the writer if the body was not read to the end
+		pr.CloseWithError(err)
 	}
-
-	return resp, nil
+	return resp, err
 }
 
-// doMultipartRequest performs a multipart form request
-func (c *Client) doMultipartRequest(ctx context.Context, endpoint string, fields map[string]string, file io.Reader, filename string) (*http.Response, error) {
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
+// closeBody closes the body of a request that won't be sent
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
 
+// writeMultipartForm writes the fields and file of a multipart form and
+// closes it
+func writeMultipartForm(writer *multipart.Writer, fields map[string]string, file io.Reader, filename, contentType string, progress func(sent, total int64)) error {
 	// Add form fields
 	for key, value := range fields {
 		if err := writer.WriteField(key, value); err != nil {
-			return nil, fmt.Errorf("error writing field %s: %w", key, err)
+			return fmt.Errorf("error writing field %s: %w", key, err)
 		}
 	}
 
 	// Add file if provided
 	if file != nil && filename != "" {
-		part, err := writer.CreateFormFile("file", filename)
+		part, err := createFormFile(writer, filename, contentType)
 		if err != nil {
-			return nil, fmt.Errorf("error creating form file: %w", err)
+			return fmt.Errorf("error creating form file: %w", err)
 		}
 
+		if progress != nil {
+			file = &progressReader{reader: file, total: readerSize(file), progress: progress}
+		}
 		if _, err := io.Copy(part, file); err != nil {
-			return nil, fmt.Errorf("error copying file content: %w", err)
+			return fmt.Errorf("error copying file content: %w", err)
 		}
 	}
 
 	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("error closing multipart writer: %w", err)
+		return fmt.Errorf("error closing multipart writer: %w", err)
 	}
+	return nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+endpoint, &buf)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+// send performs req, failing over between endpoints when several are
+// configured
+func (c *Client) send(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if c.endpoints == nil || requestOptions(ctx).baseURL != "" {
+		return c.sendOnce(ctx, req)
 	}
+	return c.endpoints.send(ctx, req, c.baseURLFor(ctx), c.sendOnce)
+}
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+// sendOnce waits for the scheduler, performs req and converts error
+// statuses into an *APIError. Like http.Client.Do, it closes the request
+// body even when the request is never sent.
+func (c *Client) sendOnce(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if err := c.sign(req); err != nil {
+		closeBody(req)
+		return nil, err
+	}
 
-	resp, err := c.httpClient.Do(req)
+	release, err := c.acquire(ctx)
 	if err != nil {
+		closeBody(req)
+		return nil, err
+	}
+
+	req, obs := c.observe(req)
+	resp, err := c.httpClientFor(req).Do(req)
+	if err != nil {
+		release()
+		obs.end(err)
 		return nil, fmt.Errorf("error making request: %w", err)
 	}
+	obs.setStatus(resp.StatusCode)
 
+	// Check for HTTP errors
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer release()
 		defer resp.Body.Close()
 		body, _ := io.ReadAll(resp.Body)
-
-		var apiError Error
-		if err := json.Unmarshal(body, &apiError); err != nil {
-			return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, apiError.Message)
+		apiErr := newAPIError(resp.StatusCode, body)
+		apiErr.RequestID = requestID(resp.Header)
+		apiErr.RateLimit = c.recordRateLimit(resp.Header)
+		obs.end(apiErr)
+		return nil, apiErr
 	}
 
+	c.recordRateLimit(resp.Header)
+	if c.scheduler != nil {
+		resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+	}
+	if obs != nil {
+		resp.Request = req
+		resp.Body = &observedBody{ReadCloser: resp.Body, obs: obs}
+	}
 	return resp, nil
 }
 
 // CreateChatCompletion creates a chat completion
 func (c *Client) CreateChatCompletion(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
+	interceptors := c.requestInterceptors(ctx)
+	if len(interceptors) == 0 {
+		return c.completeChat(ctx, req)
+	}
+	return chainChat(interceptors, c.completeChat)(ctx, req)
+}
+
+// completeChat serves a chat completion from the cache, a coalesced
+// in-flight call or the API
+func (c *Client) completeChat(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
+	var key string
+	if (c.cache != nil || c.inflight != nil) && IsDeterministic(req) {
+		if reqKey, err := CompletionCacheKey(req); err == nil {
+			key = c.requestKey(ctx, reqKey)
+		}
+	}
+
+	if key != "" && c.cache != nil {
+		if cached, ok := c.cachedCompletion(ctx, key); ok {
+			return cached, nil
+		}
+	}
+
+	var chatResp *ChatCompletionResponse
+	var err error
+	if key != "" && c.inflight != nil {
+		chatResp, err = c.inflight.do(ctx, key, func(ctx context.Context) (*ChatCompletionResponse, error) {
+			return c.createChatCompletion(ctx, req)
+		})
+	} else {
+		chatResp, err = c.createChatCompletion(ctx, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if key != "" && c.cache != nil {
+		c.storeCompletion(ctx, key, chatResp)
+	}
+
+	return chatResp, nil
+}
+
+// createChatCompletion sends a chat completion request to the API
+func (c *Client) createChatCompletion(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
 	resp, err := c.doRequest(ctx, "POST", "/chat/completions", req, nil)
 	if err != nil {
 		return nil, err
@@ -178,6 +309,10 @@ func (c *Client) CreateChatCompletion(ctx context.Context, req ChatCompletionReq
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
+	if info := parseRateLimitInfo(resp.Header, time.Now()); info != nil {
+		chatResp.Meta = &ResponseMeta{RateLimit: info}
+	}
+	c.reportUsage(resp, "/chat/completions", modelOf(chatResp.Model, req.Model), chatResp.Usage)
 	return &chatResp, nil
 }
 
@@ -194,9 +329,30 @@ func (c *Client) CreateRAGChatCompletion(ctx context.Context, req RAGChatComplet
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
+	if info := parseRateLimitInfo(resp.Header, time.Now()); info != nil {
+		chatResp.Meta = &ResponseMeta{RateLimit: info}
+	}
+	c.reportUsage(resp, "/chat/completions/rag", modelOf(chatResp.Model, req.Model), chatResp.Usage)
 	return &chatResp, nil
 }
 
+// CreateEmbeddings creates embeddings for the input texts
+func (c *Client) CreateEmbeddings(ctx context.Context, req EmbeddingRequest) (*EmbeddingResponse, error) {
+	resp, err := c.doRequest(ctx, "POST", "/embeddings", req, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var embResp EmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embResp); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	c.reportUsage(resp, "/embeddings", modelOf(embResp.Model, req.Model), embResp.Usage)
+	return &embResp, nil
+}
+
 // CreateSpeech generates speech from text
 func (c *Client) CreateSpeech(ctx context.Context, req TTSRequest) ([]byte, error) {
 	resp, err := c.doRequest(ctx, "POST", "/audio/speech", req, nil)
@@ -213,6 +369,76 @@ func (c *Client) CreateSpeech(ctx context.Context, req TTSRequest) ([]byte, erro
 	return audio, nil
 }
 
+// OpenSpeech generates speech from text and returns the audio as it
+// arrives. The caller must close the reader.
+func (c *Client) OpenSpeech(ctx context.Context, req TTSRequest) (io.ReadCloser, error) {
+	resp, err := c.doRequest(ctx, "POST", "/audio/speech", req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// CreateSpeechStream generates speech from text and copies the audio to w
+// as it arrives, so long audio can be piped to a file or HTTP response
+// without buffering it. It returns the number of bytes written.
+func (c *Client) CreateSpeechStream(ctx context.Context, req TTSRequest, w io.Writer) (int64, error) {
+	audio, err := c.OpenSpeech(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	defer audio.Close()
+
+	n, err := io.Copy(w, audio)
+	if err != nil {
+		return n, fmt.Errorf("error streaming audio response: %w", err)
+	}
+	return n, nil
+}
+
+// CreateTranscription converts speech to text
+func (c *Client) CreateTranscription(ctx context.Context, req TranscriptionRequest) (*TranscriptionResponse, error) {
+	filename := req.Filename
+	if filename == "" {
+		filename = "audio.wav"
+	}
+	fields := map[string]string{"model": req.Model}
+	if req.Language != "" {
+		fields["language"] = req.Language
+	}
+	if req.ResponseFormat != "" {
+		fields["response_format"] = req.ResponseFormat
+	}
+	if req.Prompt != "" {
+		fields["prompt"] = req.Prompt
+	}
+	if req.Temperature != nil {
+		fields["temperature"] = strconv.FormatFloat(*req.Temperature, 'f', -1, 64)
+	}
+
+	resp, err := c.doMultipartRequest(ctx, "/audio/transcriptions", fields, req.Audio, filename, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch req.ResponseFormat {
+	case "text", "srt", "vtt":
+		text, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading response: %w", err)
+		}
+		return &TranscriptionResponse{Text: string(text)}, nil
+	}
+
+	var transcription TranscriptionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&transcription); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return &transcription, nil
+}
+
 // CreateCollection creates a new vector store collection
 func (c *Client) CreateCollection(ctx context.Context, req CreateCollectionRequest) (*CreateCollectionResponse, error) {
 	resp, err := c.doRequest(ctx, "POST", "/vector-stores/collections", req, nil)
@@ -229,6 +455,21 @@ func (c *Client) CreateCollection(ctx context.Context, req CreateCollectionReque
 	return &collResp, nil
 }
 
+// ListCollections lists vector store collections. Only the first page is
+// returned when there are many; use ListCollectionsPager for the rest.
+func (c *Client) ListCollections(ctx context.Context) (*ListCollectionsResponse, error) {
+	return c.listCollections(ctx, ListOptions{})
+}
+
+func (c *Client) listCollections(ctx context.Context, opts ListOptions) (*ListCollectionsResponse, error) {
+	var collResp ListCollectionsResponse
+	if err := c.getList(ctx, "/vector-stores/collections", nil, opts, &collResp); err != nil {
+		return nil, err
+	}
+
+	return &collResp, nil
+}
+
 // UpdateCollection updates a vector store collection
 func (c *Client) UpdateCollection(ctx context.Context, id string, req UpdateCollectionRequest) (*UpdateCollectionResponse, error) {
 	endpoint := fmt.Sprintf("/vector-stores/collections/%s", id)
@@ -246,7 +487,21 @@ func (c *Client) UpdateCollection(ctx context.Context, id string, req UpdateColl
 	return &collResp, nil
 }
 
-// SearchCollection searches items in a vector store collection
+// DeleteCollection deletes a vector store collection and its items
+func (c *Client) DeleteCollection(ctx context.Context, id string) error {
+	endpoint := fmt.Sprintf("/vector-stores/collections/%s", id)
+	resp, err := c.doRequest(ctx, "DELETE", endpoint, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// SearchCollection searches items in a vector store collection. Results
+// are ranked with RankSearchResults, so req.TopK and req.ScoreThreshold
+// are enforced even if the API ignores them.
 func (c *Client) SearchCollection(ctx context.Context, id string, req SearchRequest) (*SearchResponse, error) {
 	endpoint := fmt.Sprintf("/vector-stores/collections/%s/search", id)
 	resp, err := c.doRequest(ctx, "POST", endpoint, req, nil)
@@ -259,22 +514,23 @@ func (c *Client) SearchCollection(ctx context.Context, id string, req SearchRequ
 	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
+	searchResp.Results = RankSearchResults(req, searchResp.Results)
 
+	c.reportUsage(resp, endpoint, "", searchResp.Usage)
 	return &searchResp, nil
 }
 
-// ListItems lists items in a vector store collection
+// ListItems lists items in a vector store collection. Only the first page
+// is returned by paginated collections; use ListItemsPager for the rest.
 func (c *Client) ListItems(ctx context.Context, collectionID string) (*ListItemsResponse, error) {
-	endpoint := fmt.Sprintf("/vector-stores/collections/%s/items", collectionID)
-	resp, err := c.doRequest(ctx, "GET", endpoint, nil, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	return c.listItems(ctx, collectionID, ListOptions{})
+}
 
+func (c *Client) listItems(ctx context.Context, collectionID string, opts ListOptions) (*ListItemsResponse, error) {
+	endpoint := fmt.Sprintf("/vector-stores/collections/%s/items", collectionID)
 	var itemsResp ListItemsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&itemsResp); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+	if err := c.getList(ctx, endpoint, nil, opts, &itemsResp); err != nil {
+		return nil, err
 	}
 
 	return &itemsResp, nil
@@ -294,6 +550,7 @@ func (c *Client) AddItem(ctx context.Context, collectionID string, req AddItemRe
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
+	c.reportUsage(resp, endpoint, "", itemResp.Usage)
 	return &itemResp, nil
 }
 
@@ -331,18 +588,29 @@ func (c *Client) UpdateItem(ctx context.Context, collectionID, itemID string, re
 	return &itemResp, nil
 }
 
-// ListFiles lists files in a vector store collection
-func (c *Client) ListFiles(ctx context.Context, collectionID string) (*ListFilesResponse, error) {
-	endpoint := fmt.Sprintf("/vector-stores/collections/%s/files", collectionID)
-	resp, err := c.doRequest(ctx, "GET", endpoint, nil, nil)
+// DeleteItem deletes an item from a vector store collection
+func (c *Client) DeleteItem(ctx context.Context, collectionID, itemID string) error {
+	endpoint := fmt.Sprintf("/vector-stores/collections/%s/items/%s", collectionID, itemID)
+	resp, err := c.doRequest(ctx, "DELETE", endpoint, nil, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer resp.Body.Close()
+	resp.Body.Close()
+
+	return nil
+}
 
+// ListFiles lists files in a vector store collection. Only the first page
+// is returned by paginated collections; use ListFilesPager for the rest.
+func (c *Client) ListFiles(ctx context.Context, collectionID string) (*ListFilesResponse, error) {
+	return c.listFiles(ctx, collectionID, ListOptions{})
+}
+
+func (c *Client) listFiles(ctx context.Context, collectionID string, opts ListOptions) (*ListFilesResponse, error) {
+	endpoint := fmt.Sprintf("/vector-stores/collections/%s/files", collectionID)
 	var filesResp ListFilesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&filesResp); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+	if err := c.getList(ctx, endpoint, nil, opts, &filesResp); err != nil {
+		return nil, err
 	}
 
 	return &filesResp, nil
@@ -350,8 +618,12 @@ func (c *Client) ListFiles(ctx context.Context, collectionID string) (*ListFiles
 
 // AddFile adds a file to a vector store collection
 func (c *Client) AddFile(ctx context.Context, collectionID string, file io.Reader, filename string) (*AddFileResponse, error) {
+	return c.addFile(ctx, collectionID, file, filename, "")
+}
+
+func (c *Client) addFile(ctx context.Context, collectionID string, file io.Reader, filename, contentType string) (*AddFileResponse, error) {
 	endpoint := fmt.Sprintf("/vector-stores/collections/%s/files", collectionID)
-	resp, err := c.doMultipartRequest(ctx, endpoint, nil, file, filename)
+	resp, err := c.doMultipartRequest(ctx, endpoint, nil, file, filename, contentType)
 	if err != nil {
 		return nil, err
 	}
@@ -382,6 +654,96 @@ func (c *Client) GetFile(ctx context.Context, collectionID, fileID string) (*Get
 	return &fileResp, nil
 }
 
+// DeleteFile deletes a file from a vector store collection
+func (c *Client) DeleteFile(ctx context.Context, collectionID, fileID string) error {
+	endpoint := fmt.Sprintf("/vector-stores/collections/%s/files/%s", collectionID, fileID)
+	resp, err := c.doRequest(ctx, "DELETE", endpoint, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// CreateBatch submits a JSONL file of requests to run asynchronously
+func (c *Client) CreateBatch(ctx context.Context, req CreateBatchRequest) (*BatchResponse, error) {
+	filename := req.Filename
+	if filename == "" {
+		filename = "batch.jsonl"
+	}
+	fields := map[string]string{
+		"endpoint":          req.Endpoint,
+		"completion_window": req.CompletionWindow,
+	}
+	if fields["endpoint"] == "" {
+		fields["endpoint"] = DefaultBatchEndpoint
+	}
+	if fields["completion_window"] == "" {
+		fields["completion_window"] = DefaultBatchCompletionWindow
+	}
+
+	resp, err := c.doMultipartRequest(ctx, "/batches", fields, req.Input, filename, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var batchResp BatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batchResp); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return &batchResp, nil
+}
+
+// GetBatch retrieves a batch
+func (c *Client) GetBatch(ctx context.Context, batchID string) (*BatchResponse, error) {
+	endpoint := fmt.Sprintf("/batches/%s", batchID)
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var batchResp BatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batchResp); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return &batchResp, nil
+}
+
+// ListBatches lists batches
+func (c *Client) ListBatches(ctx context.Context) (*ListBatchesResponse, error) {
+	resp, err := c.doRequest(ctx, "GET", "/batches", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var batchesResp ListBatchesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batchesResp); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return &batchesResp, nil
+}
+
+// DownloadBatchResults downloads the results of a completed batch
+func (c *Client) DownloadBatchResults(ctx context.Context, batchID string) ([]BatchResult, error) {
+	endpoint := fmt.Sprintf("/batches/%s/results", batchID)
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil, map[string]string{
+		"Accept": "application/jsonl",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return ParseBatchResults(resp.Body)
+}
+
 // GenerateImage generates an image from a text prompt
 func (c *Client) GenerateImage(ctx context.Context, req ImageGenerationRequest) (*ImageGenerationResponse, error) {
 	resp, err := c.doRequest(ctx, "POST", "/images/generations", req, nil)
@@ -414,8 +776,14 @@ func (c *Client) GetUsage(ctx context.Context) (*UsageResponse, error) {
 	return &usageResp, nil
 }
 
-// GetRequestLogs retrieves API request logs
+// GetRequestLogs retrieves API request logs. Only the first page is
+// returned when there are many; use RequestLogsPager for the rest, or
+// RequestLogsIterator for ranges longer than an hour.
 func (c *Client) GetRequestLogs(ctx context.Context, req RequestLogsRequest) (*RequestLogsResponse, error) {
+	return c.getRequestLogs(ctx, req, ListOptions{})
+}
+
+func (c *Client) getRequestLogs(ctx context.Context, req RequestLogsRequest, opts ListOptions) (*RequestLogsResponse, error) {
 	// Build query parameters
 	params := url.Values{}
 	params.Set("period", strconv.Itoa(req.Period))
@@ -426,16 +794,9 @@ func (c *Client) GetRequestLogs(ctx context.Context, req RequestLogsRequest) (*R
 		params.Set("endpoint", req.Endpoint)
 	}
 
-	endpoint := "/request-logs?" + params.Encode()
-	resp, err := c.doRequest(ctx, "GET", endpoint, nil, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
 	var logsResp RequestLogsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&logsResp); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+	if err := c.getList(ctx, "/request-logs", params, opts, &logsResp); err != nil {
+		return nil, err
 	}
 
 	return &logsResp, nil
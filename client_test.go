@@ -3,6 +3,7 @@ package vultrai
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -27,6 +28,12 @@ func NewMockTransport() *MockTransport {
 }
 
 func (m *MockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Read the body like a real transport, since uploads are streamed
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
 	m.requests = append(m.requests, req)
 
 	key := req.Method + " " + req.URL.Path
@@ -71,6 +78,49 @@ func setupTestClient() (*Client, *MockTransport) {
 	return client, mockTransport
 }
 
+// setupLocalTestClient is setupTestClient with a base URL without a path, so
+// the mock sees requests under the endpoint paths themselves
+func setupLocalTestClient() (*Client, *MockTransport) {
+	client, transport := setupTestClient()
+	WithBaseURL("https://api.test.local")(client)
+	return client, transport
+}
+
+// sequenceTransport answers successive requests with chat completions whose
+// assistant content is taken from contents in order; the last one repeats
+type sequenceTransport struct {
+	contents []string
+	requests []*http.Request
+}
+
+func (s *sequenceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.requests = append(s.requests, req)
+
+	content := s.contents[0]
+	if len(s.contents) > 1 {
+		s.contents = s.contents[1:]
+	}
+
+	body, _ := json.Marshal(ChatCompletionResponse{
+		ID:      "chat-123",
+		Model:   "test-model",
+		Choices: []Choice{{Message: Message{Role: "assistant", Content: content}, FinishReason: "stop"}},
+	})
+	return &http.Response{
+		StatusCode: 200,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}, nil
+}
+
+func setupSequenceClient(contents ...string) (*Client, *sequenceTransport) {
+	transport := &sequenceTransport{contents: contents}
+	httpClient := &http.Client{Transport: transport}
+
+	client := NewClient("test-api-key", WithHTTPClient(httpClient))
+	return client, transport
+}
+
 func TestNewClient(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -108,7 +158,7 @@ func TestNewClient(t *testing.T) {
 }
 
 func TestCreateChatCompletion(t *testing.T) {
-	client, mockTransport := setupTestClient()
+	client, mockTransport := setupLocalTestClient()
 
 	expectedResp := &ChatCompletionResponse{
 		ID:      "chat-123",
@@ -156,7 +206,7 @@ func TestCreateChatCompletion(t *testing.T) {
 }
 
 func TestCreateRAGChatCompletion(t *testing.T) {
-	client, mockTransport := setupTestClient()
+	client, mockTransport := setupLocalTestClient()
 
 	expectedResp := &ChatCompletionResponse{
 		ID:      "rag-chat-123",
@@ -190,8 +240,42 @@ func TestCreateRAGChatCompletion(t *testing.T) {
 	assert.Equal(t, "Based on the documents, here's the answer...", resp.Choices[0].Message.Content)
 }
 
+func TestCreateEmbeddings(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+
+	// 1.5 and -2 as little-endian float32
+	encoded := base64.StdEncoding.EncodeToString([]byte{0, 0, 0xc0, 0x3f, 0, 0, 0, 0xc0})
+	mockTransport.SetResponse("POST", "/embeddings", 200, map[string]interface{}{
+		"model": "bge-large-en",
+		"data": []map[string]interface{}{
+			{"index": 0, "embedding": []float32{0.25, 0.5}},
+			{"index": 1, "embedding": encoded},
+		},
+		"usage": map[string]int{"prompt_tokens": 4, "total_tokens": 4},
+	})
+
+	resp, err := client.CreateEmbeddings(context.Background(), EmbeddingRequest{
+		Model:          "bge-large-en",
+		Input:          []string{"hello", "world"},
+		EncodingFormat: EncodingBase64,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 2)
+	assert.Equal(t, []float32{0.25, 0.5}, resp.Data[0].Embedding)
+	assert.Equal(t, []float32{1.5, -2}, resp.Data[1].Embedding)
+	assert.Equal(t, 4, resp.Usage.TotalTokens)
+
+	var sent map[string]interface{}
+	require.NoError(t, json.NewDecoder(mockTransport.GetRequests()[0].Body).Decode(&sent))
+	assert.Equal(t, "base64", sent["encoding_format"])
+	assert.NotContains(t, sent, "dimensions")
+
+	var bad Embedding
+	assert.Error(t, json.Unmarshal([]byte(`{"embedding": "AAA="}`), &bad))
+}
+
 func TestCreateSpeech(t *testing.T) {
-	client, mockTransport := setupTestClient()
+	client, mockTransport := setupLocalTestClient()
 
 	expectedAudio := []byte("fake-audio-data")
 	mockTransport.SetResponse("POST", "/audio/speech", 200, nil)
@@ -214,8 +298,86 @@ func TestCreateSpeech(t *testing.T) {
 	assert.Equal(t, expectedAudio, audio)
 }
 
+func TestCreateSpeechStream(t *testing.T) {
+	audio := bytes.Repeat([]byte("ID3-mp3-frame"), 1000)
+	client, mockTransport := setupLocalTestClient()
+	mockTransport.responses["POST /audio/speech"] = textResponse(200, string(audio))
+
+	var buf bytes.Buffer
+	n, err := client.CreateSpeechStream(context.Background(), TTSRequest{Model: "tts", Input: "hi", Voice: "v"}, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(audio)), n)
+	assert.Equal(t, audio, buf.Bytes())
+
+	mockTransport.SetResponse("POST", "/audio/speech", 400, Error{Message: "unknown voice"})
+	_, err = client.CreateSpeechStream(context.Background(), TTSRequest{Model: "tts", Input: "hi", Voice: "x"}, &buf)
+	assert.ErrorIs(t, err, ErrBadRequest)
+}
+
+func TestCreateTranscription(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+	mockTransport.SetResponse("POST", "/audio/transcriptions", 200, TranscriptionResponse{Text: "Hello world"})
+
+	resp, err := client.CreateTranscription(context.Background(), TranscriptionRequest{
+		Model:    "stt-model",
+		Audio:    strings.NewReader("fake-audio-data"),
+		Language: "en",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello world", resp.Text)
+
+	req := mockTransport.GetRequests()[0]
+	require.NoError(t, req.ParseMultipartForm(1<<20))
+	assert.Equal(t, "stt-model", req.FormValue("model"))
+	assert.Equal(t, "en", req.FormValue("language"))
+	assert.Equal(t, "audio.wav", req.MultipartForm.File["file"][0].Filename)
+}
+
+func TestCreateTranscriptionFormats(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+	mockTransport.SetResponse("POST", "/audio/transcriptions", 200, map[string]interface{}{
+		"text":     "Hello world",
+		"language": "english",
+		"duration": 1.5,
+		"segments": []map[string]interface{}{
+			{"id": 0, "start": 0.0, "end": 0.7, "text": "Hello"},
+			{"id": 1, "start": 0.7, "end": 1.5, "text": " world"},
+		},
+	})
+
+	resp, err := client.CreateTranscription(context.Background(), TranscriptionRequest{
+		Model:          "stt-model",
+		Audio:          strings.NewReader("fake-audio-data"),
+		Filename:       "call.mp3",
+		ResponseFormat: "verbose_json",
+		Prompt:         "Vultr",
+		Temperature:    Float64(0.2),
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Segments, 2)
+	assert.Equal(t, 0.7, resp.Segments[1].Start)
+	assert.Equal(t, 1.5, resp.Duration)
+
+	req := mockTransport.GetRequests()[0]
+	require.NoError(t, req.ParseMultipartForm(1<<20))
+	assert.Equal(t, "verbose_json", req.FormValue("response_format"))
+	assert.Equal(t, "Vultr", req.FormValue("prompt"))
+	assert.Equal(t, "0.2", req.FormValue("temperature"))
+	assert.Equal(t, "call.mp3", req.MultipartForm.File["file"][0].Filename)
+
+	vtt := "WEBVTT\n\n00:00.000 --> 00:01.500\nHello world\n"
+	mockTransport.responses["POST /audio/transcriptions"] = textResponse(200, vtt)
+	resp, err = client.CreateTranscription(context.Background(), TranscriptionRequest{
+		Model:          "stt-model",
+		Audio:          strings.NewReader("fake-audio-data"),
+		ResponseFormat: "vtt",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, vtt, resp.Text)
+}
+
 func TestCreateCollection(t *testing.T) {
-	client, mockTransport := setupTestClient()
+	client, mockTransport := setupLocalTestClient()
 
 	expectedResp := &CreateCollectionResponse{
 		Collection: VectorStoreCollection{
@@ -238,7 +400,7 @@ func TestCreateCollection(t *testing.T) {
 }
 
 func TestSearchCollection(t *testing.T) {
-	client, mockTransport := setupTestClient()
+	client, mockTransport := setupLocalTestClient()
 
 	expectedResp := &SearchResponse{
 		Results: []SearchResult{
@@ -266,8 +428,90 @@ func TestSearchCollection(t *testing.T) {
 	assert.Equal(t, "This is relevant content", resp.Results[0].Content)
 }
 
+func TestSearchCollectionRanking(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+
+	mockTransport.SetResponse("POST", "/vector-stores/collections/coll-123/search", 200, &SearchResponse{
+		Results: []SearchResult{
+			{ID: "r-1", Score: 0.4, ItemID: "item-1"},
+			{ID: "r-2", Score: 0.9, ItemID: "item-2", FileID: "file-2"},
+			{ID: "r-3", Score: 0.1},
+			{ID: "r-4", Score: 0.7},
+		},
+	})
+
+	resp, err := client.SearchCollection(context.Background(), "coll-123", SearchRequest{
+		Input:          "q",
+		TopK:           2,
+		ScoreThreshold: 0.3,
+		Filter:         map[string]string{"lang": "en"},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 2)
+	assert.Equal(t, "r-2", resp.Results[0].ID)
+	assert.Equal(t, "file-2", resp.Results[0].FileID)
+	assert.Equal(t, "r-4", resp.Results[1].ID)
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(mockTransport.GetRequests()[0].Body).Decode(&body))
+	assert.Equal(t, float64(2), body["top_k"])
+	assert.Equal(t, 0.3, body["score_threshold"])
+	assert.Equal(t, map[string]interface{}{"lang": "en"}, body["filter"])
+}
+
+func TestSearchPathsRankAlike(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+	results := []SearchResult{
+		{ID: "r-1", Score: 0.4},
+		{ID: "r-2"},
+		{ID: "r-3", Score: 0.9},
+		{ID: "r-4", Score: 0.4},
+		{ID: "r-5", Score: 0.7},
+		{ID: "r-6", Score: 0.2},
+	}
+	setResults := func() {
+		mockTransport.SetResponse("POST", "/vector-stores/collections/coll-123/search", 200, &SearchResponse{Results: results})
+	}
+	ids := func(results []SearchResult) []string {
+		var ids []string
+		for _, result := range results {
+			ids = append(ids, result.ID)
+		}
+		return ids
+	}
+
+	for _, req := range []SearchRequest{
+		{Input: "q"},
+		{Input: "q", ScoreThreshold: 0.3},
+		{Input: "q", TopK: 1},
+		{Input: "q", TopK: 2, ScoreThreshold: 0.3},
+		{Input: "q", TopK: 10},
+	} {
+		want := ids(RankSearchResults(req, append([]SearchResult(nil), results...)))
+
+		setResults()
+		resp, err := client.SearchCollection(context.Background(), "coll-123", req)
+		require.NoError(t, err)
+		assert.Equal(t, want, ids(resp.Results), "SearchCollection %+v", req)
+
+		setResults()
+		var streamed []SearchResult
+		_, err = client.SearchCollectionFunc(context.Background(), "coll-123", req, func(r SearchResult) error {
+			streamed = append(streamed, r)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, want, ids(streamed), "SearchCollectionFunc %+v", req)
+	}
+
+	assert.Equal(t, []string{"r-3", "r-5", "r-1", "r-4", "r-6", "r-2"},
+		ids(RankSearchResults(SearchRequest{}, append([]SearchResult(nil), results...))))
+	assert.Equal(t, []string{"r-3", "r-5"},
+		ids(RankSearchResults(SearchRequest{TopK: 2, ScoreThreshold: 0.3}, append([]SearchResult(nil), results...))))
+}
+
 func TestAddItem(t *testing.T) {
-	client, mockTransport := setupTestClient()
+	client, mockTransport := setupLocalTestClient()
 
 	expectedResp := &AddItemResponse{
 		Item: CollectionItem{
@@ -296,8 +540,44 @@ func TestAddItem(t *testing.T) {
 	assert.Equal(t, "This is test content", resp.Item.Content)
 }
 
+func TestDeleteItem(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+
+	mockTransport.SetResponse("DELETE", "/vector-stores/collections/coll-123/items/item-123", 204, nil)
+
+	err := client.DeleteItem(context.Background(), "coll-123", "item-123")
+	require.NoError(t, err)
+
+	requests := mockTransport.GetRequests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "DELETE", requests[0].Method)
+
+	mockTransport.SetResponse("DELETE", "/vector-stores/collections/coll-123/items/missing", 404, map[string]string{"message": "not found"})
+	err = client.DeleteItem(context.Background(), "coll-123", "missing")
+	assert.Error(t, err)
+}
+
+func TestDeleteCollectionAndFile(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+	mockTransport.SetResponse("DELETE", "/vector-stores/collections/coll-123", 204, nil)
+	mockTransport.SetResponse("DELETE", "/vector-stores/collections/coll-123/files/file-123", 204, nil)
+
+	require.NoError(t, client.DeleteCollection(context.Background(), "coll-123"))
+	require.NoError(t, client.DeleteFile(context.Background(), "coll-123", "file-123"))
+
+	requests := mockTransport.GetRequests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "DELETE", requests[0].Method)
+	assert.Equal(t, "/vector-stores/collections/coll-123", requests[0].URL.Path)
+	assert.Equal(t, "/vector-stores/collections/coll-123/files/file-123", requests[1].URL.Path)
+
+	mockTransport.SetResponse("DELETE", "/vector-stores/collections/missing", 404, map[string]string{"message": "not found"})
+	err := client.DeleteCollection(context.Background(), "missing")
+	assert.True(t, IsNotFound(err))
+}
+
 func TestGenerateImage(t *testing.T) {
-	client, mockTransport := setupTestClient()
+	client, mockTransport := setupLocalTestClient()
 
 	expectedResp := &ImageGenerationResponse{
 		Created: 1640995200,
@@ -326,7 +606,7 @@ func TestGenerateImage(t *testing.T) {
 }
 
 func TestGetUsage(t *testing.T) {
-	client, mockTransport := setupTestClient()
+	client, mockTransport := setupLocalTestClient()
 
 	expectedResp := &UsageResponse{
 		CurrentMonth: MonthlyUsage{
@@ -350,7 +630,7 @@ func TestGetUsage(t *testing.T) {
 }
 
 func TestErrorHandling(t *testing.T) {
-	client, mockTransport := setupTestClient()
+	client, mockTransport := setupLocalTestClient()
 
 	errorResp := Error{
 		Message: "Invalid request",
@@ -487,3 +767,31 @@ func TestImageOptions(t *testing.T) {
 	assert.Equal(t, "512x512", reqBody.Size)
 	assert.Equal(t, "url", reqBody.ResponseFormat)
 }
+
+func TestSpeechOptions(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+	mockTransport.responses["POST /audio/speech"] = textResponse(200, "audio")
+
+	audio, err := client.SimpleSpeech(context.Background(), "Hello")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("audio"), audio)
+
+	_, err = client.SimpleSpeech(context.Background(), "Bonjour",
+		WithSpeechModel("tts-hd"),
+		WithVoice("nova"),
+		WithSpeechSpeed(1.25),
+		WithSpeechFormat("wav"),
+		WithSpeechLanguage("fr"),
+	)
+	require.NoError(t, err)
+
+	requests := mockTransport.GetRequests()
+	require.Len(t, requests, 2)
+
+	var defaults, custom TTSRequest
+	require.NoError(t, json.NewDecoder(requests[0].Body).Decode(&defaults))
+	require.NoError(t, json.NewDecoder(requests[1].Body).Decode(&custom))
+
+	assert.Equal(t, TTSRequest{Model: DefaultTTSModel, Input: "Hello", Voice: DefaultTTSVoice, ResponseFormat: DefaultTTSFormat}, defaults)
+	assert.Equal(t, TTSRequest{Model: "tts-hd", Input: "Bonjour", Voice: "nova", ResponseFormat: "wav", Speed: Float64(1.25), Language: "fr"}, custom)
+}
@@ -0,0 +1,116 @@
+package vultrai
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// WithRequestCoalescing merges identical deterministic chat completion
+// requests that are in flight at the same time into a single API call.
+// Every caller receives its own copy of the shared result. A caller that
+// gives up doesn't cancel the call for the others, and requests with
+// different per-request options are never merged.
+func WithRequestCoalescing() ClientOption {
+	return func(c *Client) {
+		c.inflight = &callGroup{}
+	}
+}
+
+// inflightCall is a chat completion shared by concurrent callers
+type inflightCall struct {
+	done    chan struct{}
+	resp    *ChatCompletionResponse
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// callGroup deduplicates concurrent calls with the same key
+type callGroup struct {
+	mu    sync.Mutex
+	calls map[string]*inflightCall
+}
+
+// do runs fn once for all concurrent callers using key. fn runs on a
+// context that is not tied to any one caller: a caller whose context is
+// done stops waiting without affecting the others, and the shared call is
+// cancelled only once every caller has left.
+func (g *callGroup) do(ctx context.Context, key string, fn func(context.Context) (*ChatCompletionResponse, error)) (*ChatCompletionResponse, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*inflightCall)
+	}
+	call, ok := g.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &inflightCall{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = call
+		go func() {
+			defer cancel()
+			resp, err := fn(callCtx)
+
+			g.mu.Lock()
+			call.resp, call.err = resp, err
+			g.forget(key, call)
+			g.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return copyChatCompletionResponse(call.resp), call.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			call.cancel()
+			g.forget(key, call)
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// forget removes call so later callers start a new one. g.mu must be held.
+func (g *callGroup) forget(key string, call *inflightCall) {
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
+
+// requestKey extends a completion cache key with the base URL and request
+// options of ctx, so only calls that would send the same request share a
+// result
+func (c *Client) requestKey(ctx context.Context, key string) string {
+	cfg := requestOptions(ctx)
+
+	var sb strings.Builder
+	sb.WriteString(key)
+	fmt.Fprintf(&sb, "\x00%s\x00%s", c.baseURLFor(ctx), cfg.timeout)
+	names := make([]string, 0, len(cfg.headers))
+	for name := range cfg.headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&sb, "\x00%s=%s", name, strings.Join(cfg.headers[name], ","))
+	}
+	return sb.String()
+}
+
+// copyChatCompletionResponse returns a copy that callers can modify independently
+func copyChatCompletionResponse(resp *ChatCompletionResponse) *ChatCompletionResponse {
+	if resp == nil {
+		return nil
+	}
+
+	cp := *resp
+	cp.Choices = append([]Choice(nil), resp.Choices...)
+	return &cp
+}
@@ -0,0 +1,140 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestCoalescing(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(100 * time.Millisecond)
+		json.NewEncoder(w).Encode(ChatCompletionResponse{ID: "chat-123"})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-api-key", WithBaseURL(server.URL), WithRequestCoalescing())
+
+	req := ChatCompletionRequest{
+		Model:       "test-model",
+		Messages:    []Message{{Role: "user", Content: "Hello"}},
+		Temperature: Float64(0),
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*ChatCompletionResponse, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := client.CreateChatCompletion(context.Background(), req)
+			require.NoError(t, err)
+			results[i] = resp
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for _, resp := range results {
+		assert.Equal(t, "chat-123", resp.ID)
+	}
+	assert.NotSame(t, results[0], results[1])
+}
+
+func TestRequestCoalescingCallerCancel(t *testing.T) {
+	var calls int32
+	cancelled := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		// The server only notices a dropped connection once the body is read
+		io.Copy(io.Discard, r.Body)
+		select {
+		case <-time.After(100 * time.Millisecond):
+			json.NewEncoder(w).Encode(ChatCompletionResponse{ID: "chat-123"})
+		case <-r.Context().Done():
+			cancelled <- struct{}{}
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-api-key", WithBaseURL(server.URL), WithRequestCoalescing())
+	req := ChatCompletionRequest{
+		Model:       "test-model",
+		Messages:    []Message{{Role: "user", Content: "Hello"}},
+		Temperature: Float64(0),
+	}
+
+	// The first caller gives up; the second still gets the shared result
+	leaderCtx, cancelLeader := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancelLeader()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err := client.CreateChatCompletion(leaderCtx, req)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	resp, err := client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "chat-123", resp.ID)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// The call is cancelled once every caller has left
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.CreateChatCompletion(ctx, req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("shared call was not cancelled")
+	}
+}
+
+func TestRequestCoalescingRequestOptions(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(50 * time.Millisecond)
+		json.NewEncoder(w).Encode(ChatCompletionResponse{ID: r.Header.Get("X-Tenant")})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-api-key", WithBaseURL(server.URL), WithRequestCoalescing())
+	req := ChatCompletionRequest{
+		Model:       "test-model",
+		Messages:    []Message{{Role: "user", Content: "Hello"}},
+		Temperature: Float64(0),
+	}
+
+	tenants := []string{"a", "b"}
+	results := make([]string, len(tenants))
+	var wg sync.WaitGroup
+	for i, tenant := range tenants {
+		wg.Add(1)
+		go func(i int, tenant string) {
+			defer wg.Done()
+			ctx := ContextWithRequestOptions(context.Background(), WithRequestHeader("X-Tenant", tenant))
+			resp, err := client.CreateChatCompletion(ctx, req)
+			require.NoError(t, err)
+			results[i] = resp.ID
+		}(i, tenant)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, tenants, results)
+}
@@ -0,0 +1,120 @@
+package vultrai
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultCompletionReserve is the number of tokens kept free for the answer
+// when a request sets no max_tokens
+const defaultCompletionReserve = 1024
+
+// defaultListingRetry is how long a failed model listing is remembered
+// before the models are listed again
+const defaultListingRetry = 30 * time.Second
+
+// ContextWindowPolicy trims chat histories that would overflow the model's
+// context window before they are sent
+type ContextWindowPolicy struct {
+	// Trimmer reduces histories that don't fit (default DropOldestTrimmer).
+	// KeepRecentTrimmer, SummarizingTrimmer and NewSummarizeThenDropTrimmer
+	// are alternatives.
+	Trimmer HistoryTrimmer
+	// Limits maps model IDs to their context windows. Models missing from
+	// it are looked up with ListModels until a listing succeeds.
+	Limits map[string]int
+	// ListingRetry is how long a failed ListModels lookup falls back to
+	// DefaultLimit before it is retried (default 30s)
+	ListingRetry time.Duration
+	// DefaultLimit is the context window of models with none known; zero
+	// leaves their requests untouched
+	DefaultLimit int
+	// Reserve is the number of tokens kept free for the answer when the
+	// request sets no max_tokens (default 1024)
+	Reserve int
+}
+
+// WithContextWindowPolicy trims the messages of chat completions whose
+// estimated tokens, plus room for the answer, exceed the model's context
+// window
+func WithContextWindowPolicy(policy ContextWindowPolicy) ClientOption {
+	return func(c *Client) {
+		WithChatInterceptor(policy.interceptor(c))(c)
+	}
+}
+
+func (p ContextWindowPolicy) interceptor(c *Client) ChatInterceptor {
+	trimmer := p.Trimmer
+	if trimmer == nil {
+		trimmer = DropOldestTrimmer{}
+	}
+	reserve := p.Reserve
+	if reserve <= 0 {
+		reserve = defaultCompletionReserve
+	}
+
+	retry := p.ListingRetry
+	if retry <= 0 {
+		retry = defaultListingRetry
+	}
+
+	var mu sync.Mutex
+	var listed *ListModelsResponse
+	var retryAt time.Time
+	listModels := func(ctx context.Context) *ListModelsResponse {
+		mu.Lock()
+		models, failed := listed, time.Now().Before(retryAt)
+		mu.Unlock()
+		if models != nil || failed {
+			return models
+		}
+
+		// The lock isn't held while listing, so a slow lookup doesn't hold
+		// up requests whose limits are known
+		resp, err := c.ListModels(withoutRequestOptions(ctx))
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			// A caller giving up says nothing about the API, so only other
+			// failures are remembered
+			if ctx.Err() == nil {
+				retryAt = time.Now().Add(retry)
+			}
+			return listed
+		}
+		listed = resp
+		return resp
+	}
+	limitOf := func(ctx context.Context, model string) int {
+		if limit, ok := p.Limits[model]; ok {
+			return limit
+		}
+		if models := listModels(ctx); models != nil {
+			if m, ok := models.Find(model); ok && m.ContextWindow > 0 {
+				return m.ContextWindow
+			}
+		}
+		return p.DefaultLimit
+	}
+
+	return func(ctx context.Context, req ChatCompletionRequest, next ChatHandler) (*ChatCompletionResponse, error) {
+		limit := limitOf(ctx, req.Model)
+		if limit <= 0 {
+			return next(ctx, req)
+		}
+
+		budget := limit - reserve
+		if req.MaxTokens != nil {
+			budget = limit - *req.MaxTokens
+		}
+		if EstimateMessagesTokens(req.Messages) > budget {
+			trimmed, err := trimmer.Trim(ctx, req.Messages, budget)
+			if err != nil {
+				return nil, err
+			}
+			req.Messages = trimmed
+		}
+		return next(ctx, req)
+	}
+}
@@ -0,0 +1,123 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextWindowPolicy(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	WithContextWindowPolicy(ContextWindowPolicy{Trimmer: KeepRecentTrimmer{Recent: 1}})(client)
+
+	transport.SetResponse("GET", "/models", 200, ListModelsResponse{Data: []Model{{ID: "small", ContextWindow: 300}}})
+	long := strings.Repeat("word ", 100)
+	messages := []Message{
+		CreateSystemMessage("Be brief."),
+		CreateUserMessage(long),
+		CreateAssistantMessage(long),
+		CreateUserMessage("And now?"),
+	}
+
+	sent := func(i int) []Message {
+		var req ChatCompletionRequest
+		require.NoError(t, json.NewDecoder(transport.GetRequests()[i].Body).Decode(&req))
+		return req.Messages
+	}
+
+	// 300 tokens minus 100 for the answer can't hold the history
+	_, err := client.CreateChatCompletion(context.Background(), ChatCompletionRequest{Model: "small", Messages: messages, MaxTokens: Int(100)})
+	require.NoError(t, err)
+	require.Len(t, transport.GetRequests(), 2)
+	assert.Equal(t, "/models", transport.GetRequests()[0].URL.Path)
+	assert.Equal(t, []Message{messages[0], messages[3]}, sent(1))
+
+	// Models without a known window are left alone, and the models are
+	// listed once
+	_, err = client.CreateChatCompletion(context.Background(), ChatCompletionRequest{Model: "large", Messages: messages})
+	require.NoError(t, err)
+	require.Len(t, transport.GetRequests(), 3)
+	assert.Equal(t, messages, sent(2))
+}
+
+func TestContextWindowPolicyLimits(t *testing.T) {
+	client, transport := setupTestClient()
+	WithContextWindowPolicy(ContextWindowPolicy{Limits: map[string]int{"m": 1100}})(client)
+
+	messages := []Message{CreateUserMessage(strings.Repeat("word ", 100)), CreateUserMessage("Hi")}
+	_, err := client.CreateChatCompletion(context.Background(), ChatCompletionRequest{Model: "m", Messages: messages})
+	require.NoError(t, err)
+
+	// The default reserve of 1024 tokens leaves room for the last message
+	requests := transport.GetRequests()
+	require.Len(t, requests, 1)
+	var req ChatCompletionRequest
+	require.NoError(t, json.NewDecoder(requests[0].Body).Decode(&req))
+	assert.Equal(t, messages[1:], req.Messages)
+}
+
+func TestContextWindowPolicyListRetry(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	WithContextWindowPolicy(ContextWindowPolicy{Trimmer: KeepRecentTrimmer{Recent: 1}, ListingRetry: 50 * time.Millisecond})(client)
+	messages := []Message{CreateUserMessage(strings.Repeat("word ", 100)), CreateUserMessage("Hi")}
+	req := ChatCompletionRequest{Model: "small", Messages: messages, MaxTokens: Int(200)}
+	paths := func() []string {
+		var paths []string
+		for _, req := range transport.GetRequests() {
+			paths = append(paths, req.URL.Path)
+		}
+		return paths
+	}
+
+	// A failed listing leaves the request alone and isn't retried at once
+	transport.SetResponse("GET", "/models", 500, map[string]string{"error": "unavailable"})
+	_, err := client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+	_, err = client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/models", "/chat/completions", "/chat/completions"}, paths())
+
+	// Once the retry delay is over the models are listed again
+	time.Sleep(60 * time.Millisecond)
+	transport.SetResponse("GET", "/models", 200, ListModelsResponse{Data: []Model{{ID: "small", ContextWindow: 300}}})
+	_, err = client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/models", "/chat/completions", "/chat/completions", "/models", "/chat/completions"}, paths())
+	var sent ChatCompletionRequest
+	require.NoError(t, json.NewDecoder(transport.GetRequests()[4].Body).Decode(&sent))
+	assert.Equal(t, messages[1:], sent.Messages)
+}
+
+func TestContextWindowPolicyListCancelled(t *testing.T) {
+	var listings int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+		if req.URL.Path == "/models" {
+			listings++
+			return textResponse(200, `{"data":[{"id":"small","context_window":300}]}`), nil
+		}
+		return textResponse(200, `{"choices":[]}`), nil
+	})
+	client := NewClient("key", WithBaseURL("https://api.test.local"), WithHTTPClient(&http.Client{Transport: transport}))
+	WithContextWindowPolicy(ContextWindowPolicy{Trimmer: KeepRecentTrimmer{Recent: 1}})(client)
+	req := ChatCompletionRequest{Model: "small", Messages: []Message{CreateUserMessage("Hi")}}
+
+	// A caller that gave up doesn't stop the next one from listing
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.CreateChatCompletion(ctx, req)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, listings)
+
+	_, err = client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, listings)
+}
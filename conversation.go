@@ -0,0 +1,195 @@
+package vultrai
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Conversation holds the message history of a multi-turn chat. It can be
+// branched at any point to explore alternative continuations while the
+// original stays intact; branches record where they came from so they can
+// be compared later.
+type Conversation struct {
+	// ID identifies the conversation
+	ID string
+	// ParentID is the ID of the conversation this one was branched from
+	ParentID string
+	// BranchPoint is the number of messages inherited from the parent
+	BranchPoint int
+
+	// Model answers the conversation's turns
+	Model string
+	// Options apply to every request of the conversation
+	Options []ChatOption
+	// TokenBudget bounds the estimated tokens of the history sent with
+	// each turn; zero sends the whole history
+	TokenBudget int
+	// Trimmer reduces the history once it exceeds TokenBudget (default
+	// DropOldestTrimmer). The trimmed history replaces the stored one.
+	Trimmer HistoryTrimmer
+	// Store, when set, receives the conversation after every turn
+	Store ConversationStore
+	// Summary, when set, compresses older turns in the background
+	Summary *RollingSummary
+
+	client   *Client
+	mu       sync.Mutex
+	messages []Message
+	usage    Usage
+	// revision counts rewrites of the history, as opposed to appends, and
+	// appended counts appended messages, so Send can merge changes made
+	// while its request was in flight
+	revision int
+	appended int
+
+	summarizing bool
+	summaries   sync.WaitGroup
+}
+
+// NewConversation starts an empty conversation answered by model
+func NewConversation(client *Client, model string, options ...ChatOption) *Conversation {
+	return &Conversation{
+		ID:      newConversationID(),
+		Model:   model,
+		Options: options,
+		client:  client,
+	}
+}
+
+func newConversationID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return "conv_" + hex.EncodeToString(b[:])
+}
+
+// Add appends messages to the history without calling the model
+func (c *Conversation) Add(messages ...Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = append(c.messages, messages...)
+	c.appended += len(messages)
+}
+
+// Messages returns a copy of the history
+func (c *Conversation) Messages() []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Message(nil), c.messages...)
+}
+
+// Len returns the number of messages in the history
+func (c *Conversation) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.messages)
+}
+
+// Usage returns the token usage accumulated over the conversation's turns
+func (c *Conversation) Usage() Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.usage
+}
+
+// Send adds a user message, asks the model for a reply and adds the reply
+// to the history, trimming it first when it exceeds TokenBudget. The
+// history is left unchanged when the request fails. With a Store, the
+// conversation is saved after the turn; a failure to save is returned
+// along with the response.
+func (c *Conversation) Send(ctx context.Context, content string) (*ChatCompletionResponse, error) {
+	c.mu.Lock()
+	history := append([]Message(nil), c.messages...)
+	revision, appended := c.revision, c.appended
+	c.mu.Unlock()
+
+	userMessage := CreateUserMessage(content)
+	messages := append(history, userMessage)
+
+	if c.TokenBudget > 0 {
+		trimmer := c.Trimmer
+		if trimmer == nil {
+			trimmer = DropOldestTrimmer{}
+		}
+		trimmed, err := trimmer.Trim(ctx, messages, c.TokenBudget)
+		if err != nil {
+			return nil, err
+		}
+		messages = trimmed
+	}
+
+	resp, err := c.client.ChatWithMessages(ctx, c.Model, messages, c.Options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, errors.New("no choices returned")
+	}
+
+	c.mu.Lock()
+	// Keep messages added while the request was in flight, after this turn
+	split := len(c.messages) - min(c.appended-appended, len(c.messages))
+	added := c.messages[split:]
+	base := messages
+	if c.revision != revision {
+		// A summary rewrote the history in the meantime: build on it
+		// rather than on the stale copy sent with the request
+		base = append(append([]Message(nil), c.messages[:split]...), userMessage)
+	} else if len(messages) != len(history)+1 {
+		// The trimmed history replaces the stored one
+		c.revision++
+	}
+	c.messages = append(append(append([]Message(nil), base...), resp.Choices[0].Message), added...)
+	c.appended += 2
+	c.usage.PromptTokens += resp.Usage.PromptTokens
+	c.usage.CompletionTokens += resp.Usage.CompletionTokens
+	c.usage.TotalTokens += resp.Usage.TotalTokens
+	c.maybeSummarize()
+	c.mu.Unlock()
+
+	if c.Store != nil {
+		if err := c.Save(ctx, c.Store); err != nil {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// Clone returns a branch holding the whole history
+func (c *Conversation) Clone() *Conversation {
+	branch, _ := c.Branch(c.Len())
+	return branch
+}
+
+// Branch returns a new conversation holding the first at messages of the
+// history, answered by the same model with the same options plus options.
+// Change the branch's Model to continue with a different model.
+func (c *Conversation) Branch(at int, options ...ChatOption) (*Conversation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if at < 0 || at > len(c.messages) {
+		return nil, fmt.Errorf("branch point %d outside history of %d messages", at, len(c.messages))
+	}
+
+	branchOptions := make([]ChatOption, 0, len(c.Options)+len(options))
+	branchOptions = append(branchOptions, c.Options...)
+	branchOptions = append(branchOptions, options...)
+
+	return &Conversation{
+		ID:          newConversationID(),
+		ParentID:    c.ID,
+		BranchPoint: at,
+		Model:       c.Model,
+		Options:     branchOptions,
+		TokenBudget: c.TokenBudget,
+		Trimmer:     c.Trimmer,
+		Store:       c.Store,
+		Summary:     c.Summary,
+		client:      c.client,
+		messages:    append([]Message(nil), c.messages[:at]...),
+	}, nil
+}
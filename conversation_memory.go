@@ -0,0 +1,131 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// memoryMessagePrefix starts the system message holding a rolling summary
+const memoryMessagePrefix = "Memory of the earlier conversation:\n"
+
+// RollingSummary makes a Conversation compress its older turns in the
+// background once the history grows past Threshold messages. The turns are
+// summarized with SimpleChatCompletion and replaced with a single system
+// "memory" message, which later summaries fold in, so long-running chats
+// stay within budget while keeping salient facts.
+type RollingSummary struct {
+	// Model writes the summaries, typically a small, inexpensive model
+	Model string
+	// Threshold is the number of non-system messages that triggers a
+	// summary
+	Threshold int
+	// KeepRecent is the number of most recent messages kept verbatim
+	// (default 4)
+	KeepRecent int
+	// Prompt is the instruction used to summarize
+	Prompt string
+	// OnError receives the errors of background summaries, which otherwise
+	// leave the history unchanged
+	OnError func(error)
+}
+
+// WaitForSummary waits until a background summary in progress is done,
+// e.g. before saving or shutting down
+func (c *Conversation) WaitForSummary() {
+	c.summaries.Wait()
+}
+
+// maybeSummarize starts a background summary when the history is past the
+// threshold and none is running. c.mu must be held.
+func (c *Conversation) maybeSummarize() {
+	cfg := c.Summary
+	if cfg == nil || cfg.Threshold <= 0 || c.summarizing {
+		return
+	}
+	system, rest, _ := splitHistory(c.messages, 0)
+	if len(rest) <= cfg.Threshold {
+		return
+	}
+
+	keepRecent := cfg.KeepRecent
+	if keepRecent <= 0 {
+		keepRecent = defaultKeepRecent
+	}
+	_, older, _ := splitHistory(c.messages, keepRecent)
+	if len(older) == 0 {
+		return
+	}
+
+	// Copy the prefix being summarized so it can be checked later
+	snapshot := append([]Message(nil), c.messages[:len(system)+len(older)]...)
+	c.summarizing = true
+	c.summaries.Add(1)
+	go func() {
+		defer c.summaries.Done()
+		err := c.summarize(*cfg, snapshot, len(system))
+
+		c.mu.Lock()
+		c.summarizing = false
+		c.mu.Unlock()
+		if err != nil && cfg.OnError != nil {
+			cfg.OnError(err)
+		}
+	}()
+}
+
+// summarize replaces prefix, made of the system messages then older turns,
+// with the system messages and a memory message
+func (c *Conversation) summarize(cfg RollingSummary, prefix []Message, systemCount int) error {
+	var system []Message
+	var memory string
+	for _, msg := range prefix[:systemCount] {
+		if strings.HasPrefix(msg.Content, memoryMessagePrefix) {
+			memory = strings.TrimPrefix(msg.Content, memoryMessagePrefix)
+			continue
+		}
+		system = append(system, msg)
+	}
+
+	prompt := cfg.Prompt
+	if prompt == "" {
+		prompt = defaultSummaryPrompt
+	}
+	var sb strings.Builder
+	sb.WriteString(prompt)
+	if memory != "" {
+		sb.WriteString("\n\nSummary so far:\n")
+		sb.WriteString(memory)
+	}
+	sb.WriteString("\n\nConversation:\n")
+	sb.WriteString(FormatTranscript(prefix[systemCount:]))
+
+	resp, err := c.client.SimpleChatCompletion(context.Background(), cfg.Model, sb.String())
+	if err != nil {
+		return fmt.Errorf("error summarizing conversation: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return errors.New("error summarizing conversation: no choices returned")
+	}
+	summary := strings.TrimSpace(resp.Choices[0].Message.Content)
+
+	c.mu.Lock()
+	// The history may have been trimmed or replaced in the meantime
+	if len(c.messages) < len(prefix) || !reflect.DeepEqual(c.messages[:len(prefix)], prefix) {
+		c.mu.Unlock()
+		return nil
+	}
+	messages := make([]Message, 0, len(system)+1+len(c.messages)-len(prefix))
+	messages = append(messages, system...)
+	messages = append(messages, CreateSystemMessage(memoryMessagePrefix+summary))
+	c.messages = append(messages, c.messages[len(prefix):]...)
+	c.revision++
+	c.mu.Unlock()
+
+	if c.Store != nil {
+		return c.Save(context.Background(), c.Store)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrConversationNotFound is returned by ConversationStore.Load for unknown
+// IDs
+var ErrConversationNotFound = errors.New("conversation not found")
+
+// ConversationState is the stored form of a Conversation. Options,
+// TokenBudget and Trimmer aren't stored since they hold code; set them
+// again after loading.
+type ConversationState struct {
+	ID          string    `json:"id"`
+	ParentID    string    `json:"parent_id,omitempty"`
+	BranchPoint int       `json:"branch_point,omitempty"`
+	Model       string    `json:"model"`
+	Messages    []Message `json:"messages"`
+	Usage       Usage     `json:"usage"`
+}
+
+// ConversationStore persists conversations so they survive restarts and
+// can be shared between instances
+type ConversationStore interface {
+	// Load returns the conversation with the given ID, or
+	// ErrConversationNotFound
+	Load(ctx context.Context, id string) (*ConversationState, error)
+	// Save stores the conversation, replacing any with the same ID
+	Save(ctx context.Context, state *ConversationState) error
+	// List returns the IDs of the stored conversations, sorted
+	List(ctx context.Context) ([]string, error)
+}
+
+// State returns a snapshot of the conversation for storage
+func (c *Conversation) State() *ConversationState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &ConversationState{
+		ID:          c.ID,
+		ParentID:    c.ParentID,
+		BranchPoint: c.BranchPoint,
+		Model:       c.Model,
+		Messages:    append([]Message(nil), c.messages...),
+		Usage:       c.usage,
+	}
+}
+
+// Save stores the conversation in store
+func (c *Conversation) Save(ctx context.Context, store ConversationStore) error {
+	if err := store.Save(ctx, c.State()); err != nil {
+		return fmt.Errorf("error saving conversation: %w", err)
+	}
+	return nil
+}
+
+// LoadConversation restores the conversation with the given ID from store.
+// Its Store is set, so later turns are saved back.
+func LoadConversation(ctx context.Context, client *Client, store ConversationStore, id string, options ...ChatOption) (*Conversation, error) {
+	state, err := store.Load(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error loading conversation: %w", err)
+	}
+	return &Conversation{
+		ID:          state.ID,
+		ParentID:    state.ParentID,
+		BranchPoint: state.BranchPoint,
+		Model:       state.Model,
+		Options:     options,
+		Store:       store,
+		client:      client,
+		messages:    state.Messages,
+		usage:       state.Usage,
+	}, nil
+}
+
+// MemoryConversationStore keeps conversations in memory, e.g. for tests or
+// a single instance
+type MemoryConversationStore struct {
+	mu     sync.Mutex
+	states map[string]*ConversationState
+}
+
+// NewMemoryConversationStore creates an empty in-memory store
+func NewMemoryConversationStore() *MemoryConversationStore {
+	return &MemoryConversationStore{states: make(map[string]*ConversationState)}
+}
+
+// Load returns a copy of the stored conversation
+func (m *MemoryConversationStore) Load(ctx context.Context, id string) (*ConversationState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.states[id]
+	if !ok {
+		return nil, ErrConversationNotFound
+	}
+	return copyConversationState(state), nil
+}
+
+// Save stores a copy of state
+func (m *MemoryConversationStore) Save(ctx context.Context, state *ConversationState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[state.ID] = copyConversationState(state)
+	return nil
+}
+
+// List returns the stored IDs
+func (m *MemoryConversationStore) List(ctx context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ids := make([]string, 0, len(m.states))
+	for id := range m.states {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func copyConversationState(state *ConversationState) *ConversationState {
+	c := *state
+	c.Messages = append([]Message(nil), state.Messages...)
+	return &c
+}
+
+// FileConversationStore keeps each conversation in a JSON file named after
+// its ID in a directory, which may be shared between instances
+type FileConversationStore struct {
+	dir string
+}
+
+// NewFileConversationStore creates a store in dir, creating the directory
+// if needed
+func NewFileConversationStore(dir string) (*FileConversationStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating conversation directory: %w", err)
+	}
+	return &FileConversationStore{dir: dir}, nil
+}
+
+func (f *FileConversationStore) path(id string) (string, error) {
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") {
+		return "", fmt.Errorf("invalid conversation ID %q", id)
+	}
+	return filepath.Join(f.dir, id+".json"), nil
+}
+
+// Load reads the conversation's file
+func (f *FileConversationStore) Load(ctx context.Context, id string) (*ConversationState, error) {
+	path, err := f.path(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrConversationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading conversation: %w", err)
+	}
+
+	var state ConversationState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("error parsing conversation: %w", err)
+	}
+	return &state, nil
+}
+
+// Save writes the conversation's file atomically
+func (f *FileConversationStore) Save(ctx context.Context, state *ConversationState) error {
+	path, err := f.path(state.ID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("error encoding conversation: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(f.dir, ".conversation-*")
+	if err != nil {
+		return fmt.Errorf("error writing conversation: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing conversation: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing conversation: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing conversation: %w", err)
+	}
+	return nil
+}
+
+// List returns the IDs of the files in the directory
+func (f *FileConversationStore) List(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, fmt.Errorf("error listing conversations: %w", err)
+	}
+	var ids []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(name, ".json"))
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
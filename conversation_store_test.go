@@ -0,0 +1,54 @@
+package vultrai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversationStores(t *testing.T) {
+	fileStore, err := NewFileConversationStore(t.TempDir())
+	require.NoError(t, err)
+
+	for name, store := range map[string]ConversationStore{
+		"memory": NewMemoryConversationStore(),
+		"file":   fileStore,
+	} {
+		t.Run(name, func(t *testing.T) {
+			client, _ := setupSequenceClient("Hi there", "Paris")
+			ctx := context.Background()
+
+			conv := NewConversation(client, "model-a")
+			conv.Store = store
+			conv.Add(CreateSystemMessage("Be brief."))
+			_, err := conv.Send(ctx, "Hello")
+			require.NoError(t, err)
+
+			ids, err := store.List(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{conv.ID}, ids)
+
+			// A restarted instance picks up where the first left off
+			restored, err := LoadConversation(ctx, client, store, conv.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "model-a", restored.Model)
+			assert.Equal(t, conv.Messages(), restored.Messages())
+			assert.Equal(t, conv.Usage(), restored.Usage())
+
+			_, err = restored.Send(ctx, "Capital of France?")
+			require.NoError(t, err)
+			state, err := store.Load(ctx, conv.ID)
+			require.NoError(t, err)
+			require.Len(t, state.Messages, 5)
+			assert.Equal(t, "Paris", state.Messages[4].Content)
+
+			_, err = store.Load(ctx, "conv_missing")
+			assert.ErrorIs(t, err, ErrConversationNotFound)
+		})
+	}
+
+	_, err = fileStore.Load(context.Background(), "../escape")
+	assert.Error(t, err)
+}
@@ -0,0 +1,214 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversationBranching(t *testing.T) {
+	client, transport := setupSequenceClient("Hi there", "Paris", "Lyon")
+	ctx := context.Background()
+
+	conv := NewConversation(client, "model-a", WithMaxTokens(100))
+	conv.Add(CreateSystemMessage("Be brief."))
+
+	_, err := conv.Send(ctx, "Hello")
+	require.NoError(t, err)
+	_, err = conv.Send(ctx, "Capital of France?")
+	require.NoError(t, err)
+	require.Equal(t, 5, conv.Len())
+
+	// Branch before the second question and ask it differently
+	branch, err := conv.Branch(3, WithTemperature(1.2))
+	require.NoError(t, err)
+	branch.Model = "model-b"
+	assert.Equal(t, conv.ID, branch.ParentID)
+	assert.Equal(t, 3, branch.BranchPoint)
+	assert.NotEqual(t, conv.ID, branch.ID)
+
+	resp, err := branch.Send(ctx, "Second city of France?")
+	require.NoError(t, err)
+	assert.Equal(t, "Lyon", resp.Choices[0].Message.Content)
+
+	// The original is untouched
+	assert.Equal(t, 5, conv.Len())
+	assert.Equal(t, "Paris", conv.Messages()[4].Content)
+	assert.Equal(t, "Lyon", branch.Messages()[4].Content)
+
+	var last ChatCompletionRequest
+	require.NoError(t, json.NewDecoder(transport.requests[2].Body).Decode(&last))
+	assert.Equal(t, "model-b", last.Model)
+	assert.Equal(t, 100, *last.MaxTokens)
+	assert.Equal(t, 1.2, *last.Temperature)
+	assert.Len(t, last.Messages, 4)
+
+	clone := conv.Clone()
+	assert.Equal(t, conv.Messages(), clone.Messages())
+	assert.Equal(t, 5, clone.BranchPoint)
+
+	_, err = conv.Branch(6)
+	assert.Error(t, err)
+}
+
+func TestConversationUsageAndBudget(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	conv := NewConversation(client, "m")
+	conv.Add(CreateSystemMessage("Be brief."))
+	conv.TokenBudget = 30
+
+	long := strings.Repeat("word ", 20) // 25 tokens with framing
+	for i := 0; i < 3; i++ {
+		transport.SetResponse("POST", "/chat/completions", 200, ChatCompletionResponse{
+			Choices: []Choice{{Message: CreateAssistantMessage("ok")}},
+			Usage:   Usage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12},
+		})
+		_, err := conv.Send(context.Background(), long)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, Usage{PromptTokens: 30, CompletionTokens: 6, TotalTokens: 36}, conv.Usage())
+
+	// Older turns were dropped, the system message and the last turn kept
+	messages := conv.Messages()
+	require.Len(t, messages, 3)
+	assert.Equal(t, "system", messages[0].Role)
+	assert.Equal(t, long, messages[1].Content)
+	assert.Equal(t, "ok", messages[2].Content)
+
+	var sent ChatCompletionRequest
+	require.NoError(t, json.NewDecoder(transport.GetRequests()[2].Body).Decode(&sent))
+	assert.Len(t, sent.Messages, 2)
+}
+
+func TestDropOldestTrimmer(t *testing.T) {
+	messages := []Message{
+		CreateSystemMessage("sys"),
+		CreateUserMessage(strings.Repeat("a", 40)),
+		{Role: "assistant", ToolCalls: []ToolCall{{ID: "1", Function: Function{Name: "f"}}}},
+		CreateToolMessage("1", strings.Repeat("b", 40)),
+		CreateAssistantMessage("done"),
+		CreateUserMessage("next"),
+	}
+
+	trimmed, err := DropOldestTrimmer{}.Trim(context.Background(), messages, 20)
+	require.NoError(t, err)
+	assert.Equal(t, []Message{messages[0], messages[4], messages[5]}, trimmed)
+
+	same, err := DropOldestTrimmer{}.Trim(context.Background(), messages, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, messages, same)
+}
+
+func TestConversationRollingSummary(t *testing.T) {
+	var prompts []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var body ChatCompletionRequest
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		content := "ok"
+		if body.Model == "small" {
+			prompts = append(prompts, body.Messages[0].Content)
+			content = fmt.Sprintf("summary %d", len(prompts))
+		}
+		data, _ := json.Marshal(ChatCompletionResponse{Choices: []Choice{{Message: CreateAssistantMessage(content)}}})
+		return textResponse(200, string(data)), nil
+	})
+	client := NewClient("key", WithBaseURL("https://api.test.local"), WithHTTPClient(&http.Client{Transport: transport}))
+	ctx := context.Background()
+
+	conv := NewConversation(client, "large")
+	conv.Summary = &RollingSummary{Model: "small", Threshold: 4, KeepRecent: 2}
+	conv.Add(CreateSystemMessage("Be brief."))
+
+	send := func(content string) {
+		_, err := conv.Send(ctx, content)
+		require.NoError(t, err)
+		conv.WaitForSummary()
+	}
+	send("one")
+	send("two")
+	assert.Empty(t, prompts)
+
+	// Past four turns, all but the last two are summarized
+	send("three")
+	require.Len(t, prompts, 1)
+	assert.Contains(t, prompts[0], "user: one")
+	assert.NotContains(t, prompts[0], "user: three")
+	messages := conv.Messages()
+	require.Len(t, messages, 4)
+	assert.Equal(t, "Be brief.", messages[0].Content)
+	assert.Equal(t, memoryMessagePrefix+"summary 1", messages[1].Content)
+	assert.Equal(t, "three", messages[2].Content)
+
+	// Later summaries fold in the previous memory
+	send("four")
+	send("five")
+	require.Len(t, prompts, 2)
+	assert.Contains(t, prompts[1], "Summary so far:\nsummary 1")
+	messages = conv.Messages()
+	require.Len(t, messages, 4)
+	assert.Equal(t, memoryMessagePrefix+"summary 2", messages[1].Content)
+	assert.Equal(t, "five", messages[2].Content)
+}
+
+func TestConversationMergesInFlightChanges(t *testing.T) {
+	summaryStarted := make(chan struct{})
+	releaseSummary := make(chan struct{})
+	chatStarted := make(chan struct{}, 1)
+	releaseChat := make(chan struct{})
+	var turns int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var body ChatCompletionRequest
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		content := "ok"
+		if body.Model == "small" {
+			close(summaryStarted)
+			<-releaseSummary
+			content = "summary"
+		} else {
+			turns++
+			if turns == 4 {
+				chatStarted <- struct{}{}
+				<-releaseChat
+			}
+		}
+		data, _ := json.Marshal(ChatCompletionResponse{Choices: []Choice{{Message: CreateAssistantMessage(content)}}})
+		return textResponse(200, string(data)), nil
+	})
+	client := NewClient("key", WithBaseURL("https://api.test.local"), WithHTTPClient(&http.Client{Transport: transport}))
+	ctx := context.Background()
+
+	conv := NewConversation(client, "large")
+	conv.Summary = &RollingSummary{Model: "small", Threshold: 4, KeepRecent: 2}
+	for _, content := range []string{"one", "two", "three"} {
+		_, err := conv.Send(ctx, content)
+		require.NoError(t, err)
+	}
+	<-summaryStarted
+
+	// The summary lands and a message is added while "four" is in flight
+	done := make(chan error)
+	go func() {
+		_, err := conv.Send(ctx, "four")
+		done <- err
+	}()
+	<-chatStarted
+	close(releaseSummary)
+	conv.WaitForSummary()
+	conv.Summary = nil // no further summaries
+	conv.Add(CreateUserMessage("aside"))
+	close(releaseChat)
+	require.NoError(t, <-done)
+
+	var contents []string
+	for _, msg := range conv.Messages() {
+		contents = append(contents, msg.Content)
+	}
+	assert.Equal(t, []string{memoryMessagePrefix + "summary", "three", "ok", "four", "ok", "aside"}, contents)
+}
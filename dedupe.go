@@ -0,0 +1,164 @@
+package vultrai
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Duplicate is an item found to repeat an item that is kept
+type Duplicate struct {
+	Item CollectionItem
+	// Of is the ID of the kept item it duplicates
+	Of         string
+	Similarity float64
+}
+
+// DedupeReport is the result of DeduplicateCollection
+type DedupeReport struct {
+	// Items is the number of items compared
+	Items      int
+	Duplicates []Duplicate
+	// Deleted holds the IDs of removed duplicates when deletion is enabled
+	Deleted []string
+	Usage   Usage
+}
+
+// DedupeOption configures DeduplicateCollection
+type DedupeOption func(*dedupeConfig)
+
+type dedupeConfig struct {
+	model  string
+	delete bool
+}
+
+// WithDedupeModel compares items by the embeddings of model instead of by
+// their words
+func WithDedupeModel(model string) DedupeOption {
+	return func(c *dedupeConfig) {
+		c.model = model
+	}
+}
+
+// WithDuplicateDeletion deletes the duplicates found
+func WithDuplicateDeletion() DedupeOption {
+	return func(c *dedupeConfig) {
+		c.delete = true
+	}
+}
+
+// DeduplicateCollection finds items in a collection whose content is at
+// least threshold similar (0 to 1) to an older item. Items are compared by
+// the cosine similarity of their embeddings when WithDedupeModel is given,
+// or of their word counts otherwise. The oldest item of each group is kept;
+// the others are reported and, with WithDuplicateDeletion, deleted.
+func (c *Client) DeduplicateCollection(ctx context.Context, collectionID string, threshold float64, options ...DedupeOption) (*DedupeReport, error) {
+	var cfg dedupeConfig
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	list, err := c.ListItems(ctx, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing items: %w", err)
+	}
+	items := list.Items
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Created < items[j].Created })
+
+	texts := make([]string, len(items))
+	for i, item := range items {
+		if item.Content == "" {
+			got, err := c.GetItem(ctx, collectionID, item.ID)
+			if err != nil {
+				return nil, fmt.Errorf("error getting item %s: %w", item.ID, err)
+			}
+			items[i].Content = got.Item.Content
+		}
+		texts[i] = items[i].Content
+	}
+
+	report := &DedupeReport{Items: len(items)}
+	similarity, err := c.dedupeSimilarity(ctx, cfg.model, texts, &report.Usage)
+	if err != nil {
+		return nil, err
+	}
+
+	var kept []int
+	for i := range items {
+		best, bestScore := -1, 0.0
+		for _, k := range kept {
+			if score := similarity(i, k); score >= threshold && score > bestScore {
+				best, bestScore = k, score
+			}
+		}
+		if best < 0 {
+			kept = append(kept, i)
+			continue
+		}
+		report.Duplicates = append(report.Duplicates, Duplicate{Item: items[i], Of: items[best].ID, Similarity: bestScore})
+	}
+
+	if cfg.delete {
+		for _, dup := range report.Duplicates {
+			if err := c.DeleteItem(ctx, collectionID, dup.Item.ID); err != nil {
+				return report, fmt.Errorf("error deleting item %s: %w", dup.Item.ID, err)
+			}
+			report.Deleted = append(report.Deleted, dup.Item.ID)
+		}
+	}
+
+	return report, nil
+}
+
+// dedupeSimilarity returns a function scoring the similarity of texts i and j
+func (c *Client) dedupeSimilarity(ctx context.Context, model string, texts []string, usage *Usage) (func(i, j int) float64, error) {
+	if model != "" {
+		vectors, embedUsage, err := c.embed(ctx, model, texts)
+		if err != nil {
+			return nil, fmt.Errorf("error embedding items: %w", err)
+		}
+		*usage = embedUsage
+		return func(i, j int) float64 { return CosineSimilarity(vectors[i], vectors[j]) }, nil
+	}
+
+	counts := make([]map[string]float64, len(texts))
+	for i, text := range texts {
+		counts[i] = wordCounts(text)
+	}
+	return func(i, j int) float64 { return termCosine(counts[i], counts[j]) }, nil
+}
+
+// splitWords returns the lowercased words of text
+func splitWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// wordCounts counts the lowercased words of text
+func wordCounts(text string) map[string]float64 {
+	counts := make(map[string]float64)
+	for _, word := range splitWords(text) {
+		counts[word]++
+	}
+	return counts
+}
+
+// termCosine is the cosine similarity of two sparse vectors
+func termCosine(a, b map[string]float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for term, x := range a {
+		normA += x * x
+		dot += x * b[term]
+	}
+	for _, y := range b {
+		normB += y * y
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
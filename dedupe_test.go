@@ -0,0 +1,128 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collectionServer serves a vector store collection and embeddings for
+// deduplication tests
+func collectionServer(t *testing.T, items []CollectionItem, vectors map[string][]float32) (*Client, *[]string) {
+	var deleted []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		const prefix = "/vector-stores/collections/kb/items"
+		var body interface{}
+		switch {
+		case req.Method == "GET" && req.URL.Path == prefix:
+			listed := make([]CollectionItem, len(items))
+			for i, item := range items {
+				listed[i] = CollectionItem{ID: item.ID, Created: item.Created}
+			}
+			body = ListItemsResponse{Items: listed}
+		case req.Method == "GET" && strings.HasPrefix(req.URL.Path, prefix+"/"):
+			id := strings.TrimPrefix(req.URL.Path, prefix+"/")
+			for _, item := range items {
+				if item.ID == id {
+					body = GetItemResponse{Item: item}
+				}
+			}
+		case req.Method == "DELETE":
+			deleted = append(deleted, strings.TrimPrefix(req.URL.Path, prefix+"/"))
+			body = struct{}{}
+		case req.URL.Path == "/embeddings":
+			var embReq EmbeddingRequest
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&embReq))
+			resp := EmbeddingResponse{Usage: Usage{PromptTokens: len(embReq.Input), TotalTokens: len(embReq.Input)}}
+			for i, input := range embReq.Input {
+				resp.Data = append(resp.Data, Embedding{Index: i, Embedding: vectors[input]})
+			}
+			body = resp
+		}
+		data, _ := json.Marshal(body)
+		return textResponse(200, string(data)), nil
+	})
+
+	client := NewClient("test-api-key", WithBaseURL("https://api.test.local"), WithHTTPClient(&http.Client{Transport: transport}))
+	return client, &deleted
+}
+
+func TestDeduplicateCollection(t *testing.T) {
+	items := []CollectionItem{
+		{ID: "newer", Created: "2024-03-01", Content: "Refunds are issued within 14 days of purchase."},
+		{ID: "original", Created: "2024-01-01", Content: "Refunds are issued within 14 days of a purchase."},
+		{ID: "other", Created: "2024-02-01", Content: "Shipping takes three to five business days."},
+	}
+	client, deleted := collectionServer(t, items, nil)
+
+	report, err := client.DeduplicateCollection(context.Background(), "kb", 0.9)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Items)
+	require.Len(t, report.Duplicates, 1)
+	assert.Equal(t, "newer", report.Duplicates[0].Item.ID)
+	assert.Equal(t, "original", report.Duplicates[0].Of)
+	assert.Greater(t, report.Duplicates[0].Similarity, 0.9)
+	assert.Empty(t, report.Deleted)
+	assert.Empty(t, *deleted)
+}
+
+func TestDeduplicateCollectionEmbeddings(t *testing.T) {
+	items := []CollectionItem{
+		{ID: "a", Created: "1", Content: "cats"},
+		{ID: "b", Created: "2", Content: "felines"},
+		{ID: "c", Created: "3", Content: "cars"},
+	}
+	vectors := map[string][]float32{
+		"cats":    {1, 0.1, 0},
+		"felines": {0.98, 0.12, 0},
+		"cars":    {0, 0, 1},
+	}
+	client, deleted := collectionServer(t, items, vectors)
+
+	report, err := client.DeduplicateCollection(context.Background(), "kb", 0.95,
+		WithDedupeModel("embed-model"), WithDuplicateDeletion())
+	require.NoError(t, err)
+	require.Len(t, report.Duplicates, 1)
+	assert.Equal(t, "b", report.Duplicates[0].Item.ID)
+	assert.Equal(t, "a", report.Duplicates[0].Of)
+	assert.Equal(t, []string{"b"}, report.Deleted)
+	assert.Equal(t, []string{"b"}, *deleted)
+	assert.Equal(t, 3, report.Usage.PromptTokens)
+}
+
+func TestEmbedBatches(t *testing.T) {
+	var sizes []int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var embReq EmbeddingRequest
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&embReq))
+		sizes = append(sizes, len(embReq.Input))
+
+		// Answer out of order to check results are placed by index
+		var resp EmbeddingResponse
+		for i := len(embReq.Input) - 1; i >= 0; i-- {
+			resp.Data = append(resp.Data, Embedding{Index: i, Embedding: []float32{float32(len(embReq.Input[i]))}})
+		}
+		data, _ := json.Marshal(resp)
+		return textResponse(200, string(data)), nil
+	})
+	client := NewClient("test-api-key", WithBaseURL("https://api.test.local"), WithHTTPClient(&http.Client{Transport: transport}))
+
+	texts := make([]string, embedBatchSize+2)
+	for i := range texts {
+		texts[i] = strings.Repeat("x", i+1)
+	}
+	vectors, err := client.Embed(context.Background(), "embed-model", texts)
+	require.NoError(t, err)
+	assert.Equal(t, []int{embedBatchSize, 2}, sizes)
+	for i, v := range vectors {
+		assert.Equal(t, []float32{float32(i + 1)}, v)
+	}
+
+	assert.InDelta(t, 1.0, CosineSimilarity([]float32{1, 2}, []float32{2, 4}), 1e-9)
+	assert.Zero(t, CosineSimilarity([]float32{1}, []float32{1, 2}))
+}
@@ -0,0 +1,38 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Do calls an endpoint the SDK has no wrapper for yet, with the client's
+// authentication, scheduling, failover and error handling. path is relative
+// to the base URL and may carry a query. in is sent as the JSON body when
+// not nil; the JSON response is decoded into out when not nil. Error
+// statuses are returned as *APIError.
+func (c *Client) Do(ctx context.Context, method, path string, in, out interface{}, options ...RequestOption) error {
+	if len(options) > 0 {
+		ctx = ContextWithRequestOptions(ctx, options...)
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	resp, err := c.doRequest(ctx, method, path, in, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && err != io.EOF {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	return nil
+}
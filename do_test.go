@@ -0,0 +1,39 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	transport.SetResponse("POST", "/fine-tunes", 200, map[string]string{"id": "ft-1"})
+	transport.SetResponse("DELETE", "/fine-tunes/ft-1", 204, nil)
+	transport.SetResponse("GET", "/fine-tunes/ft-2", 404, Error{Message: "missing"})
+
+	var out struct {
+		ID string `json:"id"`
+	}
+	err := client.Do(context.Background(), "POST", "fine-tunes", map[string]string{"model": "m"}, &out, WithRequestHeader("X-Beta", "1"))
+	require.NoError(t, err)
+	assert.Equal(t, "ft-1", out.ID)
+
+	require.NoError(t, client.Do(context.Background(), "DELETE", "/fine-tunes/ft-1", nil, &out))
+	err = client.Do(context.Background(), "GET", "/fine-tunes/ft-2", nil, nil)
+	assert.True(t, IsNotFound(err))
+
+	requests := transport.GetRequests()
+	require.Len(t, requests, 3)
+	assert.Equal(t, "1", requests[0].Header.Get("X-Beta"))
+	assert.Equal(t, "Bearer test-api-key", requests[0].Header.Get("Authorization"))
+	body, _ := io.ReadAll(requests[0].Body)
+	var in map[string]string
+	require.NoError(t, json.Unmarshal(body, &in))
+	assert.Equal(t, "m", in["model"])
+	assert.Empty(t, requests[1].Header.Get("X-Beta"))
+}
@@ -0,0 +1,231 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBatcherClosed is returned by EmbeddingBatcher.Embed once the batcher
+// has been closed
+var ErrBatcherClosed = errors.New("embedding batcher closed")
+
+// DefaultBatcherMaxWait is how long an EmbeddingBatcher waits for a batch
+// to fill by default
+const DefaultBatcherMaxWait = 10 * time.Millisecond
+
+// EmbeddingBatcherConfig configures an EmbeddingBatcher
+type EmbeddingBatcherConfig struct {
+	// MaxBatchSize is the maximum number of texts per request (default 64).
+	// A single call with more texts is sent on its own.
+	MaxBatchSize int
+	// MaxWait is how long to wait for more calls once one is pending
+	// (default DefaultBatcherMaxWait)
+	MaxWait time.Duration
+	// Limits caps the rate and concurrency of the requests. While a batch
+	// waits for the limit it keeps filling with new calls.
+	Limits SchedulerConfig
+}
+
+// EmbeddingBatcher collects Embed calls from many goroutines and sends
+// them to an underlying Embedder in batches, under a rate limit. It smooths
+// spiky workloads such as indexing documents concurrently: callers embed
+// one text at a time while the API sees few, full requests.
+type EmbeddingBatcher struct {
+	embedder  Embedder
+	cfg       EmbeddingBatcherConfig
+	scheduler *Scheduler
+	calls     chan *embedCall
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+var _ Embedder = (*EmbeddingBatcher)(nil)
+
+// embedCall is a pending Embed call; done receives exactly one result
+type embedCall struct {
+	ctx   context.Context
+	texts []string
+	done  chan embedResult
+}
+
+type embedResult struct {
+	vectors [][]float32
+	err     error
+}
+
+// NewEmbeddingBatcher starts a batcher sending to embedder, typically
+// Client.Embedder. Close it to stop its background goroutine.
+func NewEmbeddingBatcher(embedder Embedder, cfg EmbeddingBatcherConfig) *EmbeddingBatcher {
+	if cfg.MaxBatchSize <= 0 {
+		cfg.MaxBatchSize = embedBatchSize
+	}
+	if cfg.MaxWait <= 0 {
+		cfg.MaxWait = DefaultBatcherMaxWait
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &EmbeddingBatcher{
+		embedder:  embedder,
+		cfg:       cfg,
+		scheduler: NewScheduler(cfg.Limits),
+		calls:     make(chan *embedCall),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	b.wg.Add(1)
+	go b.run()
+	return b
+}
+
+// Embed queues texts for the next batch and waits for their vectors
+func (b *EmbeddingBatcher) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	call := &embedCall{ctx: ctx, texts: texts, done: make(chan embedResult, 1)}
+	select {
+	case b.calls <- call:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-b.ctx.Done():
+		return nil, ErrBatcherClosed
+	}
+
+	select {
+	case res := <-call.done:
+		return res.vectors, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close stops the batcher, failing calls that have not been sent yet, and
+// waits for batches in flight
+func (b *EmbeddingBatcher) Close() error {
+	b.closeOnce.Do(b.cancel)
+	b.wg.Wait()
+	return nil
+}
+
+// run groups incoming calls into batches and dispatches them
+func (b *EmbeddingBatcher) run() {
+	defer b.wg.Done()
+
+	var carry *embedCall
+	for {
+		var batch []*embedCall
+		size := 0
+		// add appends c to the batch, or keeps it for the next batch when
+		// it does not fit
+		add := func(c *embedCall) {
+			if size > 0 && size+len(c.texts) > b.cfg.MaxBatchSize {
+				carry = c
+				return
+			}
+			batch = append(batch, c)
+			size += len(c.texts)
+		}
+
+		first := carry
+		carry = nil
+		if first == nil {
+			select {
+			case first = <-b.calls:
+			case <-b.ctx.Done():
+				return
+			}
+		}
+		add(first)
+
+		timer := time.NewTimer(b.cfg.MaxWait)
+	collect:
+		for size < b.cfg.MaxBatchSize && carry == nil {
+			select {
+			case c := <-b.calls:
+				add(c)
+			case <-timer.C:
+				break collect
+			case <-b.ctx.Done():
+				timer.Stop()
+				failCalls(batch)
+				return
+			}
+		}
+		timer.Stop()
+
+		priority := PriorityBatch
+		for _, c := range batch {
+			if p := PriorityFromContext(c.ctx); p > priority {
+				priority = p
+			}
+		}
+		release, err := b.scheduler.Acquire(b.ctx, priority)
+		if err != nil {
+			failCalls(append(batch, carry))
+			return
+		}
+
+		// Top up with calls that arrived while waiting for the limit
+	topUp:
+		for size < b.cfg.MaxBatchSize && carry == nil {
+			select {
+			case c := <-b.calls:
+				add(c)
+			default:
+				break topUp
+			}
+		}
+
+		b.wg.Add(1)
+		go b.dispatch(batch, release)
+	}
+}
+
+// dispatch embeds the texts of a batch in one call and resolves each
+// caller with its share of the vectors
+func (b *EmbeddingBatcher) dispatch(batch []*embedCall, release func()) {
+	defer b.wg.Done()
+	defer release()
+
+	var live []*embedCall
+	var texts []string
+	for _, c := range batch {
+		if c.ctx.Err() != nil {
+			// The caller has given up
+			continue
+		}
+		live = append(live, c)
+		texts = append(texts, c.texts...)
+	}
+	if len(live) == 0 {
+		return
+	}
+
+	vectors, err := b.embedder.Embed(b.ctx, texts)
+	if err == nil && len(vectors) != len(texts) {
+		err = fmt.Errorf("embedder returned %d vectors for %d texts", len(vectors), len(texts))
+	}
+	for _, c := range live {
+		if err != nil {
+			c.done <- embedResult{err: err}
+			continue
+		}
+		n := len(c.texts)
+		c.done <- embedResult{vectors: vectors[:n:n]}
+		vectors = vectors[n:]
+	}
+}
+
+// failCalls resolves calls that will never be sent
+func failCalls(calls []*embedCall) {
+	for _, c := range calls {
+		if c != nil {
+			c.done <- embedResult{err: ErrBatcherClosed}
+		}
+	}
+}
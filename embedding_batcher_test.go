@@ -0,0 +1,101 @@
+package vultrai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddingBatcher(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+	embedder := EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+		mu.Lock()
+		sizes = append(sizes, len(texts))
+		mu.Unlock()
+		vecs := make([][]float32, len(texts))
+		for i, text := range texts {
+			vecs[i] = []float32{float32(len(text))}
+		}
+		return vecs, nil
+	})
+
+	batcher := NewEmbeddingBatcher(embedder, EmbeddingBatcherConfig{MaxBatchSize: 8, MaxWait: 50 * time.Millisecond})
+	defer batcher.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			text := fmt.Sprintf("%*s", i+1, "x")
+			vecs, err := batcher.Embed(context.Background(), []string{text})
+			if assert.NoError(t, err) && assert.Len(t, vecs, 1) {
+				assert.Equal(t, []float32{float32(i + 1)}, vecs[0])
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	total := 0
+	for _, size := range sizes {
+		assert.LessOrEqual(t, size, 8)
+		total += size
+	}
+	assert.Equal(t, 20, total)
+	assert.Less(t, len(sizes), 20)
+}
+
+func TestEmbeddingBatcherRateLimit(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+	embedder := EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+		mu.Lock()
+		sizes = append(sizes, len(texts))
+		mu.Unlock()
+		return make([][]float32, len(texts)), nil
+	})
+
+	batcher := NewEmbeddingBatcher(embedder, EmbeddingBatcherConfig{
+		MaxWait: time.Millisecond,
+		Limits:  SchedulerConfig{RequestsPerSecond: 5, Burst: 1},
+	})
+	defer batcher.Close()
+
+	_, err := batcher.Embed(context.Background(), []string{"first"})
+	require.NoError(t, err)
+
+	// These arrive while the batcher waits for the rate limit and share a
+	// request
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := batcher.Embed(context.Background(), []string{"text"})
+			assert.NoError(t, err)
+		}()
+		time.Sleep(5 * time.Millisecond)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, sizes[0])
+	assert.Less(t, len(sizes), 5)
+}
+
+func TestEmbeddingBatcherClosed(t *testing.T) {
+	batcher := NewEmbeddingBatcher(EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+		return nil, nil
+	}), EmbeddingBatcherConfig{})
+	require.NoError(t, batcher.Close())
+
+	_, err := batcher.Embed(context.Background(), []string{"text"})
+	assert.ErrorIs(t, err, ErrBatcherClosed)
+}
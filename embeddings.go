@@ -0,0 +1,132 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Embedder turns texts into vectors, one per text and in order
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EmbedderFunc adapts a function to the Embedder interface
+type EmbedderFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
+// Embed calls f
+func (f EmbedderFunc) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return f(ctx, texts)
+}
+
+// Embedder returns an Embedder using model
+func (c *Client) Embedder(model string) Embedder {
+	return EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+		return c.Embed(ctx, model, texts)
+	})
+}
+
+// embedBatchSize is the number of inputs sent per embeddings request
+const embedBatchSize = 64
+
+// Embed returns one embedding per text, in order, splitting large inputs
+// across several requests
+func (c *Client) Embed(ctx context.Context, model string, texts []string) ([][]float32, error) {
+	vectors, _, err := c.embed(ctx, model, texts)
+	return vectors, err
+}
+
+// embed is Embed, also returning the combined usage of the requests
+func (c *Client) embed(ctx context.Context, model string, texts []string) ([][]float32, Usage, error) {
+	var usage Usage
+	vectors := make([][]float32, len(texts))
+	for start := 0; start < len(texts); start += embedBatchSize {
+		end := start + embedBatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+
+		resp, err := c.CreateEmbeddings(ctx, EmbeddingRequest{Model: model, Input: texts[start:end]})
+		if err != nil {
+			return nil, usage, err
+		}
+		usage.PromptTokens += resp.Usage.PromptTokens
+		usage.TotalTokens += resp.Usage.TotalTokens
+		for _, data := range resp.Data {
+			if data.Index < 0 || start+data.Index >= end {
+				return nil, usage, fmt.Errorf("embedding index %d out of range", data.Index)
+			}
+			vectors[start+data.Index] = data.Embedding
+		}
+	}
+
+	for i, v := range vectors {
+		if v == nil {
+			return nil, usage, fmt.Errorf("no embedding returned for input %d", i)
+		}
+	}
+	return vectors, usage, nil
+}
+
+// CosineSimilarity returns the cosine of the angle between a and b, or 0
+// when either is empty, zero or their lengths differ
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		dot += x * y
+		normA += x * x
+		normB += y * y
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// Embedding encoding formats
+const (
+	EncodingFloat  = "float"
+	EncodingBase64 = "base64"
+)
+
+// UnmarshalJSON decodes the vector from a JSON array or, for the base64
+// encoding format, from a string of little-endian float32 values
+func (e *Embedding) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Index     int             `json:"index"`
+		Embedding json.RawMessage `json:"embedding"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	e.Index = raw.Index
+	e.Embedding = nil
+
+	if len(raw.Embedding) == 0 || raw.Embedding[0] != '"' {
+		return json.Unmarshal(raw.Embedding, &e.Embedding)
+	}
+
+	var encoded string
+	if err := json.Unmarshal(raw.Embedding, &encoded); err != nil {
+		return err
+	}
+	bytes, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("error decoding base64 embedding: %w", err)
+	}
+	if len(bytes)%4 != 0 {
+		return fmt.Errorf("error decoding base64 embedding: %d bytes is not a whole number of float32 values", len(bytes))
+	}
+	e.Embedding = make([]float32, len(bytes)/4)
+	for i := range e.Embedding {
+		e.Embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32(bytes[i*4:]))
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package vultrai
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WithEndpointTimeouts sets the overall timeout of requests per endpoint,
+// e.g. {"/images/generations": 2 * time.Minute, "/usage": 5 * time.Second}.
+// Keys are paths relative to the base URL and also match the endpoints
+// below them; the longest match wins. Other endpoints keep the HTTP
+// client's Timeout. Streams are exempt unless stream timeouts are disabled.
+func WithEndpointTimeouts(timeouts map[string]time.Duration) ClientOption {
+	return func(c *Client) {
+		c.endpointTimeouts = make(map[string]time.Duration, len(timeouts))
+		for endpoint, timeout := range timeouts {
+			c.endpointTimeouts["/"+strings.Trim(endpoint, "/")] = timeout
+		}
+	}
+}
+
+// endpointTimeout returns the timeout configured for the endpoint of req
+func (c *Client) endpointTimeout(req *http.Request) (time.Duration, bool) {
+	if len(c.endpointTimeouts) == 0 {
+		return 0, false
+	}
+	endpoint := c.endpointOf(req)
+
+	var timeout time.Duration
+	match := ""
+	for prefix, t := range c.endpointTimeouts {
+		if len(prefix) <= len(match) {
+			continue
+		}
+		if endpoint == prefix || strings.HasPrefix(endpoint, prefix+"/") {
+			match, timeout = prefix, t
+		}
+	}
+	return timeout, match != ""
+}
@@ -0,0 +1,67 @@
+package vultrai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointTimeouts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{Timeout: 50 * time.Millisecond}
+	client := NewClient("key", WithBaseURL(server.URL+"/v1"), WithHTTPClient(httpClient), WithEndpointTimeouts(map[string]time.Duration{
+		"images/generations": time.Second,
+		"/usage":             20 * time.Millisecond,
+	}))
+
+	_, err := client.GenerateImage(context.Background(), ImageGenerationRequest{Prompt: "a cat"})
+	require.NoError(t, err)
+
+	_, err = client.GetUsage(context.Background())
+	assert.Error(t, err)
+
+	_, err = client.ListBatches(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 50*time.Millisecond, httpClient.Timeout)
+}
+
+func TestEndpointTimeoutMatching(t *testing.T) {
+	client := NewClient("key", WithBaseURL("https://api.test.local/v1"), WithEndpointTimeouts(map[string]time.Duration{
+		"/chat/completions":     time.Minute,
+		"/chat/completions/rag": 2 * time.Minute,
+		"/usage":                time.Second,
+	}))
+
+	tests := []struct {
+		path    string
+		timeout time.Duration
+		ok      bool
+	}{
+		{"/chat/completions", time.Minute, true},
+		{"/chat/completions/rag", 2 * time.Minute, true},
+		{"/usage?period=15", time.Second, true},
+		{"/usage-report", 0, false},
+		{"/embeddings", 0, false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "https://api.test.local/v1"+tt.path, nil)
+		timeout, ok := client.endpointTimeout(req)
+		assert.Equal(t, tt.ok, ok, tt.path)
+		assert.Equal(t, tt.timeout, timeout, tt.path)
+	}
+}
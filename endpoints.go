@@ -0,0 +1,164 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EndpointPolicy selects which endpoint serves a request
+type EndpointPolicy int
+
+const (
+	// EndpointPrimaryBackup sends requests to the first healthy endpoint in
+	// the configured order
+	EndpointPrimaryBackup EndpointPolicy = iota
+	// EndpointRoundRobin spreads requests over the healthy endpoints in turn
+	EndpointRoundRobin
+)
+
+// DefaultEndpointCooldown is how long a failed endpoint is skipped
+const DefaultEndpointCooldown = 30 * time.Second
+
+// EndpointConfig lists the base URLs the client may send requests to, e.g.
+// the Vultr API and a self-hosted OpenAI-compatible gateway
+type EndpointConfig struct {
+	URLs   []string
+	Policy EndpointPolicy
+	// Cooldown is how long an endpoint that failed is skipped while others
+	// are healthy (default DefaultEndpointCooldown)
+	Cooldown time.Duration
+}
+
+// WithEndpoints sends requests to several base URLs. When an endpoint is
+// unreachable or fails with an error IsRetryable accepts, such as a 429,
+// 500 or 503, the request is retried on the next one and the failed endpoint is skipped for the cooldown. When all
+// endpoints are cooling down, they are tried anyway. A base URL set with
+// WithRequestBaseURL bypasses the endpoints.
+func WithEndpoints(cfg EndpointConfig) ClientOption {
+	return func(c *Client) {
+		if len(cfg.URLs) == 0 {
+			return
+		}
+		if cfg.Cooldown <= 0 {
+			cfg.Cooldown = DefaultEndpointCooldown
+		}
+		set := &endpointSet{policy: cfg.Policy, cooldown: cfg.Cooldown, down: make(map[string]time.Time)}
+		for _, u := range cfg.URLs {
+			set.urls = append(set.urls, strings.TrimSuffix(u, "/"))
+		}
+		c.baseURL = set.urls[0]
+		c.endpoints = set
+	}
+}
+
+type endpointKey struct{}
+
+// endpointSet tracks the health of the configured endpoints
+type endpointSet struct {
+	urls     []string
+	policy   EndpointPolicy
+	cooldown time.Duration
+
+	mu   sync.Mutex
+	next int
+	down map[string]time.Time
+}
+
+// order returns the endpoints to try, healthy ones first
+func (s *endpointSet) order() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := 0
+	if s.policy == EndpointRoundRobin {
+		start = s.next
+		s.next = (s.next + 1) % len(s.urls)
+	}
+
+	now := time.Now()
+	var healthy, cooling []string
+	for i := range s.urls {
+		u := s.urls[(start+i)%len(s.urls)]
+		if until, ok := s.down[u]; ok && now.Before(until) {
+			cooling = append(cooling, u)
+		} else {
+			healthy = append(healthy, u)
+		}
+	}
+	return append(healthy, cooling...)
+}
+
+func (s *endpointSet) mark(u string, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if failed {
+		s.down[u] = time.Now().Add(s.cooldown)
+	} else {
+		delete(s.down, u)
+	}
+}
+
+// send performs req, built on base, against each endpoint in turn until
+// one is reachable
+func (s *endpointSet) send(ctx context.Context, req *http.Request, base string, send func(context.Context, *http.Request) (*http.Response, error)) (*http.Response, error) {
+	path := strings.TrimPrefix(req.URL.String(), base)
+
+	var lastErr error
+	for i, base := range s.order() {
+		if i > 0 && req.Body != nil && req.GetBody == nil {
+			break
+		}
+		attempt, err := endpointRequest(req, base, path)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := send(attempt.Context(), attempt)
+		if err == nil || !endpointFailed(ctx, err) {
+			s.mark(base, false)
+			return resp, err
+		}
+		s.mark(base, true)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// endpointRequest returns a copy of req sent to base
+func endpointRequest(req *http.Request, base, path string) (*http.Request, error) {
+	target, err := url.Parse(base + path)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	attempt := req.Clone(context.WithValue(req.Context(), endpointKey{}, base))
+	attempt.URL = target
+	attempt.Host = ""
+	if req.GetBody != nil {
+		if attempt.Body, err = req.GetBody(); err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+	}
+	return attempt, nil
+}
+
+// endpointFailed reports whether err means the endpoint itself failed,
+// rather than the request: the endpoint couldn't be reached or answered
+// with an error IsRetryable accepts. Errors raised before the request is
+// sent, such as signer and scheduler errors, and cancellation don't count.
+func endpointFailed(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	var urlErr *url.Error
+	if !errors.As(err, &apiErr) && !errors.As(err, &urlErr) {
+		return false
+	}
+	return IsRetryable(err)
+}
@@ -0,0 +1,150 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hostTransport answers by host: unreachable hosts fail to connect, others
+// return their status
+type hostTransport struct {
+	status      map[string]int
+	unreachable map[string]bool
+	hosts       []string
+	bodies      []string
+}
+
+func (h *hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	h.hosts = append(h.hosts, req.URL.Host+req.URL.Path)
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		h.bodies = append(h.bodies, string(body))
+	}
+	if h.unreachable[req.URL.Host] {
+		return nil, errors.New("connection refused")
+	}
+	status := http.StatusOK
+	if code, ok := h.status[req.URL.Host]; ok {
+		status = code
+	}
+	return textResponse(status, `{}`), nil
+}
+
+func endpointClient(transport *hostTransport, policy EndpointPolicy) *Client {
+	return NewClient("key", WithHTTPClient(&http.Client{Transport: transport}), WithEndpoints(EndpointConfig{
+		URLs:   []string{"https://primary.test/v1", "https://backup.test/v1/"},
+		Policy: policy,
+	}))
+}
+
+func TestEndpointFailover(t *testing.T) {
+	transport := &hostTransport{unreachable: map[string]bool{"primary.test": true}}
+	client := endpointClient(transport, EndpointPrimaryBackup)
+
+	_, err := client.CreateEmbeddings(context.Background(), EmbeddingRequest{Model: "m", Input: []string{"hi"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"primary.test/v1/embeddings", "backup.test/v1/embeddings"}, transport.hosts)
+	require.Len(t, transport.bodies, 2)
+	assert.Equal(t, transport.bodies[0], transport.bodies[1])
+	var req EmbeddingRequest
+	require.NoError(t, json.Unmarshal([]byte(transport.bodies[1]), &req))
+	assert.Equal(t, []string{"hi"}, req.Input)
+
+	// The primary cools down
+	transport.hosts = nil
+	_, err = client.GetUsage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"backup.test/v1/usage"}, transport.hosts)
+}
+
+func TestEndpointFailoverStatus(t *testing.T) {
+	transport := &hostTransport{status: map[string]int{"primary.test": 503, "backup.test": 400}}
+	client := endpointClient(transport, EndpointPrimaryBackup)
+
+	_, err := client.GetUsage(context.Background())
+	assert.ErrorIs(t, err, ErrBadRequest)
+	assert.Equal(t, []string{"primary.test/v1/usage", "backup.test/v1/usage"}, transport.hosts)
+
+	// Failover follows IsRetryable
+	for status, failover := range map[int]bool{429: true, 500: true, 504: true, 404: false, 501: false} {
+		transport = &hostTransport{status: map[string]int{"primary.test": status}}
+		client = endpointClient(transport, EndpointPrimaryBackup)
+		_, err = client.GetUsage(context.Background())
+		if failover {
+			assert.NoError(t, err, status)
+			assert.Len(t, transport.hosts, 2, status)
+		} else {
+			assert.Error(t, err, status)
+			assert.Len(t, transport.hosts, 1, status)
+		}
+	}
+}
+
+func TestEndpointRoundRobin(t *testing.T) {
+	transport := &hostTransport{}
+	client := endpointClient(transport, EndpointRoundRobin)
+
+	for i := 0; i < 3; i++ {
+		_, err := client.GetUsage(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"primary.test/v1/usage", "backup.test/v1/usage", "primary.test/v1/usage"}, transport.hosts)
+
+	// A per-request base URL bypasses the endpoints
+	transport.hosts = nil
+	ctx := ContextWithRequestOptions(context.Background(), WithRequestBaseURL("https://other.test"))
+	_, err := client.GetUsage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other.test/usage"}, transport.hosts)
+}
+
+func TestEndpointFailoverRequestErrors(t *testing.T) {
+	// Errors raised before sending leave the endpoints alone
+	transport := &hostTransport{}
+	client := endpointClient(transport, EndpointPrimaryBackup)
+	WithRequestSigner(func(req *http.Request) error {
+		return errors.New("no credentials")
+	})(client)
+	_, err := client.GetUsage(context.Background())
+	require.ErrorContains(t, err, "no credentials")
+	assert.Empty(t, transport.hosts)
+	assert.Empty(t, client.endpoints.down)
+
+	// So does the caller giving up
+	transport = &hostTransport{}
+	client = endpointClient(transport, EndpointPrimaryBackup)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.GetUsage(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, client.endpoints.down)
+}
+
+func TestEndpointsAfterBaseURL(t *testing.T) {
+	transport := &hostTransport{unreachable: map[string]bool{"primary.test": true}}
+	client := endpointClient(transport, EndpointPrimaryBackup)
+	WithBaseURL("https://api.test.local")(client)
+
+	_, err := client.GetUsage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"primary.test/v1/usage", "backup.test/v1/usage"}, transport.hosts)
+}
+
+func TestEndpointOrder(t *testing.T) {
+	set := &endpointSet{urls: []string{"a", "b", "c"}, cooldown: time.Minute, down: map[string]time.Time{}}
+	set.mark("a", true)
+	assert.Equal(t, []string{"b", "c", "a"}, set.order())
+	set.mark("a", false)
+	assert.Equal(t, []string{"a", "b", "c"}, set.order())
+}
@@ -0,0 +1,202 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors matched by *APIError with errors.Is, so callers can
+// branch on the kind of failure without inspecting status codes
+var (
+	// ErrBadRequest matches 400 and 422 responses
+	ErrBadRequest = errors.New("bad request")
+	// ErrAuth matches 401 and 403 responses
+	ErrAuth = errors.New("authentication failed")
+	// ErrNotFound matches 404 responses
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited matches 429 responses
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServer matches 5xx responses
+	ErrServer = errors.New("server error")
+)
+
+// APIError is returned when the API responds with a non-2xx status
+type APIError struct {
+	StatusCode int
+	Message    string
+	Type       string
+	Code       string
+	// Body is the raw response body
+	Body []byte
+	// RequestID is the request ID reported by the API, to quote when
+	// contacting support
+	RequestID string
+	// RateLimit is the rate limit state reported with the error, including
+	// Retry-After
+	RateLimit *RateLimitInfo
+
+	// raw is set when the body was not a JSON error and Message holds it as-is
+	raw bool
+}
+
+func (e *APIError) Error() string {
+	if e.raw {
+		return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+}
+
+// Is matches the sentinel error for the status code
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == 400 || e.StatusCode == 422
+	case ErrAuth:
+		return e.StatusCode == 401 || e.StatusCode == 403
+	case ErrNotFound:
+		return e.StatusCode == 404
+	case ErrRateLimited:
+		return e.StatusCode == 429
+	case ErrServer:
+		return e.StatusCode >= 500 && e.StatusCode < 600
+	}
+	return false
+}
+
+// IsRateLimited reports whether err is a 429 response
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsAuthError reports whether err is a 401 or 403 response
+func IsAuthError(err error) bool {
+	return errors.Is(err, ErrAuth)
+}
+
+// IsNotFound reports whether err is a 404 response
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// requestIDHeaders are the headers the request ID may be reported in
+var requestIDHeaders = []string{"X-Request-Id", "Request-Id", "X-Amzn-Requestid"}
+
+// requestID returns the request ID reported in header
+func requestID(header http.Header) string {
+	for _, name := range requestIDHeaders {
+		if id := header.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// newAPIError builds an *APIError from an error response body
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiError, ok := decodeError(body)
+	if !ok {
+		return &APIError{StatusCode: statusCode, Message: string(body), Body: body, raw: true}
+	}
+	return &APIError{
+		StatusCode: statusCode,
+		Message:    apiError.Message,
+		Type:       apiError.Type,
+		Code:       apiError.Code,
+		Body:       body,
+	}
+}
+
+// decodeError parses an error body, either a flat Error object or one
+// wrapped as {"error": {...}}
+func decodeError(data []byte) (Error, bool) {
+	var wrapped struct {
+		Error *Error `json:"error"`
+	}
+	if json.Unmarshal(data, &wrapped) == nil && wrapped.Error != nil {
+		return *wrapped.Error, true
+	}
+	var apiError Error
+	if json.Unmarshal(data, &apiError) != nil {
+		return Error{}, false
+	}
+	return apiError, true
+}
+
+// Error codes that are never worth retrying, whatever the status code
+var permanentErrorCodes = map[string]bool{
+	"insufficient_quota":         true,
+	"billing_hard_limit_reached": true,
+	"invalid_api_key":            true,
+	"context_length_exceeded":    true,
+	"content_policy_violation":   true,
+	"model_not_found":            true,
+}
+
+// Error codes reporting load on the API, whatever the status code
+var temporaryErrorCodes = map[string]bool{
+	"rate_limit_exceeded": true,
+	"rate_limited":        true,
+	"overloaded":          true,
+	"server_overloaded":   true,
+}
+
+// Temporary reports whether the error is caused by load or availability
+// and is expected to clear by itself:
+//
+//   - 408 Request Timeout, 425 Too Early and 429 Too Many Requests
+//   - 502 Bad Gateway, 503 Service Unavailable, 504 Gateway Timeout and
+//     529 Overloaded
+//   - the rate_limit_exceeded, rate_limited, overloaded and
+//     server_overloaded codes with any status
+//
+// Codes such as insufficient_quota are never temporary, even with a 429.
+func (e *APIError) Temporary() bool {
+	code := strings.ToLower(e.Code)
+	if permanentErrorCodes[code] {
+		return false
+	}
+	if temporaryErrorCodes[code] {
+		return true
+	}
+	switch e.StatusCode {
+	case 408, 425, 429, 502, 503, 504, 529:
+		return true
+	}
+	return false
+}
+
+// Retryable reports whether sending the same request again may succeed.
+// Temporary errors are retryable, and so is 500 Internal Server Error.
+// Other 4xx errors, such as 400 validation errors, 401, 403 and 404, are
+// never retryable: the request has to change first.
+func (e *APIError) Retryable() bool {
+	if e.Temporary() {
+		return true
+	}
+	return e.StatusCode == 500 && !permanentErrorCodes[strings.ToLower(e.Code)]
+}
+
+// IsRetryable reports whether the request that failed with err may succeed
+// if sent again. API errors follow APIError.Retryable; stream timeouts,
+// HTTP timeouts and network errors are retryable, cancellation is not.
+// Retry loops still have to stop once their own context is done. Endpoint
+// failover and ClassifyFallback use the same classification.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, ErrStreamTimeout) {
+		return true
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,132 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIErrorRetryability(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       *APIError
+		temporary bool
+		retryable bool
+	}{
+		{"validation", &APIError{StatusCode: 400, Code: "invalid_request"}, false, false},
+		{"unauthorized", &APIError{StatusCode: 401}, false, false},
+		{"not found", &APIError{StatusCode: 404}, false, false},
+		{"timeout", &APIError{StatusCode: 408}, true, true},
+		{"rate limit", &APIError{StatusCode: 429}, true, true},
+		{"quota", &APIError{StatusCode: 429, Code: "insufficient_quota"}, false, false},
+		{"internal", &APIError{StatusCode: 500}, false, true},
+		{"internal context length", &APIError{StatusCode: 500, Code: "context_length_exceeded"}, false, false},
+		{"bad gateway", &APIError{StatusCode: 502}, true, true},
+		{"unavailable", &APIError{StatusCode: 503}, true, true},
+		{"overloaded", &APIError{StatusCode: 529}, true, true},
+		{"overloaded code", &APIError{StatusCode: 400, Code: "Overloaded"}, true, true},
+		{"not implemented", &APIError{StatusCode: 501}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.temporary, tt.err.Temporary())
+			assert.Equal(t, tt.retryable, tt.err.Retryable())
+			assert.Equal(t, tt.retryable, IsRetryable(fmt.Errorf("wrapped: %w", tt.err)))
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.False(t, IsRetryable(nil))
+	assert.False(t, IsRetryable(errors.New("error decoding response")))
+	assert.False(t, IsRetryable(context.Canceled))
+	assert.True(t, IsRetryable(fmt.Errorf("%w: stream idle", ErrStreamTimeout)))
+	assert.True(t, IsRetryable(&net.OpError{Op: "dial", Err: errors.New("connection refused")}))
+
+	// Timeouts of the HTTP client are retried, cancellation is not
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("key", WithBaseURL(server.URL), WithHTTPClient(&http.Client{Timeout: 20 * time.Millisecond}))
+	_, err := client.GetUsage(context.Background())
+	require.Error(t, err)
+	assert.True(t, IsRetryable(err))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.GetUsage(ctx)
+	require.Error(t, err)
+	assert.False(t, IsRetryable(err))
+}
+
+func TestAPIErrorSentinels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"message": "slow down", "code": "rate_limited"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient("key", WithBaseURL(server.URL)).GetUsage(context.Background())
+	require.Error(t, err)
+	assert.True(t, IsRateLimited(err))
+	assert.False(t, IsAuthError(err))
+	assert.ErrorIs(t, err, ErrRateLimited)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "req-123", apiErr.RequestID)
+	assert.JSONEq(t, `{"message": "slow down", "code": "rate_limited"}`, string(apiErr.Body))
+
+	tests := []struct {
+		status   int
+		sentinel error
+	}{
+		{400, ErrBadRequest},
+		{422, ErrBadRequest},
+		{401, ErrAuth},
+		{403, ErrAuth},
+		{404, ErrNotFound},
+		{429, ErrRateLimited},
+		{500, ErrServer},
+		{503, ErrServer},
+	}
+	sentinels := []error{ErrBadRequest, ErrAuth, ErrNotFound, ErrRateLimited, ErrServer}
+	for _, tt := range tests {
+		err := fmt.Errorf("wrapped: %w", newAPIError(tt.status, []byte("oops")))
+		for _, sentinel := range sentinels {
+			assert.Equal(t, sentinel == tt.sentinel, errors.Is(err, sentinel), "%d %v", tt.status, sentinel)
+		}
+	}
+	assert.True(t, IsAuthError(&APIError{StatusCode: 401}))
+	assert.True(t, IsNotFound(&APIError{StatusCode: 404}))
+}
+
+func TestNewAPIErrorBodyShapes(t *testing.T) {
+	flat := newAPIError(400, []byte(`{"message":"bad model","type":"invalid_request_error","code":"model_not_found"}`))
+	assert.Equal(t, "bad model", flat.Message)
+	assert.Equal(t, "invalid_request_error", flat.Type)
+	assert.Equal(t, "model_not_found", flat.Code)
+
+	wrapped := newAPIError(429, []byte(`{"error":{"message":"quota exceeded","type":"rate_limit","code":"insufficient_quota"}}`))
+	assert.Equal(t, "quota exceeded", wrapped.Message)
+	assert.Equal(t, "rate_limit", wrapped.Type)
+	assert.Equal(t, "insufficient_quota", wrapped.Code)
+
+	raw := newAPIError(502, []byte("Bad Gateway"))
+	assert.Equal(t, "Bad Gateway", raw.Message)
+}
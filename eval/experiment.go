@@ -0,0 +1,314 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	vultrai "github.com/eqba1/vultrai"
+)
+
+// CostFunc returns the cost of a request given the model and its usage
+type CostFunc func(model string, usage vultrai.Usage) float64
+
+// Variant is one prompt and parameter combination under test
+type Variant struct {
+	Name  string
+	Model string
+	// System is an optional system prompt
+	System string
+	// Prompt is a text/template rendered with the input as its data, for
+	// example "Summarize in one sentence:\n{{.}}". When empty the input is
+	// sent verbatim.
+	Prompt string
+	// Options customize the request, e.g. vultrai.WithTemperature
+	Options []vultrai.ChatOption
+}
+
+// Experiment runs every variant over the same inputs
+type Experiment struct {
+	Variants []Variant
+	Inputs   []string
+	// Repeats is the number of runs per variant and input (default 1)
+	Repeats int
+	// Concurrency is the number of requests in flight (default 4)
+	Concurrency int
+	// Seed is passed to every request and also fixes the shuffled execution
+	// order, so runs are reproducible and no variant systematically gets
+	// the warmest or coldest server
+	Seed int
+	// Cost prices each request; costs are zero when nil
+	Cost CostFunc
+	// Judge, when set, grades every output with the input as the question
+	Judge *Judge
+	// Rubric is passed to the judge for every output
+	Rubric string
+}
+
+// RunResult is a single variant run on one input
+type RunResult struct {
+	Variant string
+	Input   int
+	Repeat  int
+	Output  string
+	Latency time.Duration
+	Usage   vultrai.Usage
+	Cost    float64
+	// Score is the normalized judge score, or -1 when not graded
+	Score float64
+	Err   error
+}
+
+// VariantSummary aggregates the runs of one variant
+type VariantSummary struct {
+	Name             string
+	Runs             int
+	Errors           int
+	MeanLatency      time.Duration
+	P50              time.Duration
+	P95              time.Duration
+	PromptTokens     int
+	CompletionTokens int
+	TotalCost        float64
+	// CostPerRun is TotalCost divided by successful runs
+	CostPerRun float64
+	// MeanScore is the mean judge score over graded runs
+	MeanScore float64
+	Graded    int
+}
+
+// ExperimentReport collects all runs and per-variant summaries in the
+// order the variants were declared
+type ExperimentReport struct {
+	Runs     []RunResult
+	Variants []VariantSummary
+	Duration time.Duration
+}
+
+type experimentJob struct {
+	variant int
+	input   int
+	repeat  int
+}
+
+// Run executes the experiment. Individual failures are recorded in the
+// runs; an error is returned only for invalid configuration.
+func (e *Experiment) Run(ctx context.Context, client Completer) (*ExperimentReport, error) {
+	if len(e.Variants) == 0 || len(e.Inputs) == 0 {
+		return nil, errors.New("at least one variant and one input are required")
+	}
+
+	templates := make([]*template.Template, len(e.Variants))
+	order := make(map[string]int)
+	for i, v := range e.Variants {
+		if _, dup := order[v.Name]; v.Name == "" || dup {
+			return nil, fmt.Errorf("variant %d needs a unique name", i)
+		}
+		order[v.Name] = i
+		if v.Prompt != "" {
+			tmpl, err := template.New(v.Name).Parse(v.Prompt)
+			if err != nil {
+				return nil, fmt.Errorf("invalid prompt template for %s: %w", v.Name, err)
+			}
+			templates[i] = tmpl
+		}
+	}
+
+	repeats := max(e.Repeats, 1)
+	concurrency := e.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	var jobs []experimentJob
+	for input := range e.Inputs {
+		for repeat := 0; repeat < repeats; repeat++ {
+			for variant := range e.Variants {
+				jobs = append(jobs, experimentJob{variant: variant, input: input, repeat: repeat})
+			}
+		}
+	}
+	rand.New(rand.NewSource(int64(e.Seed))).Shuffle(len(jobs), func(i, j int) {
+		jobs[i], jobs[j] = jobs[j], jobs[i]
+	})
+
+	results := make([]RunResult, len(jobs))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				results[i] = e.runJob(ctx, client, jobs[i], templates[jobs[i].variant])
+			}
+		}()
+	}
+
+	start := time.Now()
+	for i := range jobs {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Input != b.Input {
+			return a.Input < b.Input
+		}
+		if a.Repeat != b.Repeat {
+			return a.Repeat < b.Repeat
+		}
+		return order[a.Variant] < order[b.Variant]
+	})
+
+	report := &ExperimentReport{Runs: results, Duration: time.Since(start)}
+	for _, v := range e.Variants {
+		report.Variants = append(report.Variants, summarize(v.Name, results))
+	}
+	return report, nil
+}
+
+func (e *Experiment) runJob(ctx context.Context, client Completer, job experimentJob, tmpl *template.Template) RunResult {
+	v := e.Variants[job.variant]
+	input := e.Inputs[job.input]
+	result := RunResult{Variant: v.Name, Input: job.input, Repeat: job.repeat, Score: -1}
+
+	if err := ctx.Err(); err != nil {
+		result.Err = err
+		return result
+	}
+
+	prompt := input
+	if tmpl != nil {
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, input); err != nil {
+			result.Err = fmt.Errorf("error rendering prompt: %w", err)
+			return result
+		}
+		prompt = sb.String()
+	}
+
+	req := vultrai.ChatCompletionRequest{Model: v.Model}
+	if v.System != "" {
+		req.Messages = append(req.Messages, vultrai.CreateSystemMessage(v.System))
+	}
+	req.Messages = append(req.Messages, vultrai.CreateUserMessage(prompt))
+	seed := e.Seed + job.repeat
+	req.Seed = &seed
+	for _, opt := range v.Options {
+		opt(&req)
+	}
+
+	start := time.Now()
+	resp, err := client.CreateChatCompletion(ctx, req)
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Usage = resp.Usage
+	if len(resp.Choices) > 0 {
+		result.Output = resp.Choices[0].Message.Content
+	}
+	if e.Cost != nil {
+		result.Cost = e.Cost(v.Model, resp.Usage)
+	}
+
+	if e.Judge != nil {
+		grade, err := e.Judge.Grade(ctx, Case{Question: input, Answer: result.Output, Rubric: e.Rubric})
+		if err == nil {
+			result.Score = grade.Score
+		}
+	}
+	return result
+}
+
+func summarize(name string, runs []RunResult) VariantSummary {
+	summary := VariantSummary{Name: name}
+
+	var latencies []time.Duration
+	var total time.Duration
+	scoreSum := 0.0
+	for _, r := range runs {
+		if r.Variant != name {
+			continue
+		}
+		summary.Runs++
+		if r.Err != nil {
+			summary.Errors++
+			continue
+		}
+		latencies = append(latencies, r.Latency)
+		total += r.Latency
+		summary.PromptTokens += r.Usage.PromptTokens
+		summary.CompletionTokens += r.Usage.CompletionTokens
+		summary.TotalCost += r.Cost
+		if r.Score >= 0 {
+			summary.Graded++
+			scoreSum += r.Score
+		}
+	}
+
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		summary.MeanLatency = total / time.Duration(len(latencies))
+		summary.P50 = durationPercentile(latencies, 50)
+		summary.P95 = durationPercentile(latencies, 95)
+		summary.CostPerRun = summary.TotalCost / float64(len(latencies))
+	}
+	if summary.Graded > 0 {
+		summary.MeanScore = scoreSum / float64(summary.Graded)
+	}
+	return summary
+}
+
+// durationPercentile returns the nearest-rank percentile of sorted durations
+func durationPercentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Outputs returns the outputs for one input keyed by variant name, using
+// the first repeat, for side-by-side review
+func (r *ExperimentReport) Outputs(input int) map[string]string {
+	outputs := make(map[string]string)
+	for _, run := range r.Runs {
+		if run.Input == input && run.Repeat == 0 {
+			outputs[run.Variant] = run.Output
+		}
+	}
+	return outputs
+}
+
+// String formats the per-variant comparison as a table
+func (r *ExperimentReport) String() string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VARIANT\tRUNS\tERRORS\tMEAN\tP50\tP95\tTOKENS IN/OUT\tCOST/RUN\tSCORE")
+	for _, v := range r.Variants {
+		score := "-"
+		if v.Graded > 0 {
+			score = fmt.Sprintf("%.3f", v.MeanScore)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%d/%d\t%.6f\t%s\n",
+			v.Name, v.Runs, v.Errors,
+			v.MeanLatency.Round(time.Millisecond), v.P50.Round(time.Millisecond), v.P95.Round(time.Millisecond),
+			v.PromptTokens, v.CompletionTokens, v.CostPerRun, score)
+	}
+	w.Flush()
+	fmt.Fprintf(&sb, "Duration: %s\n", r.Duration.Round(time.Millisecond))
+	return sb.String()
+}
@@ -0,0 +1,119 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/eqba1/vultrai/vultraitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExperimentRun(t *testing.T) {
+	var mu sync.Mutex
+	var seeds []int
+	client := &vultraitest.MockClient{
+		CreateChatCompletionFunc: func(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.ChatCompletionResponse, error) {
+			mu.Lock()
+			seeds = append(seeds, *req.Seed)
+			mu.Unlock()
+
+			prompt := req.Messages[len(req.Messages)-1].Content
+			if strings.Contains(prompt, "fail") {
+				return nil, errors.New("boom")
+			}
+			resp := vultraitest.ChatResponse(req.Model + ": " + prompt)
+			resp.Usage = vultrai.Usage{PromptTokens: 10, CompletionTokens: len(prompt), TotalTokens: 10 + len(prompt)}
+			return resp, nil
+		},
+	}
+
+	exp := &Experiment{
+		Variants: []Variant{
+			{Name: "terse", Model: "small", Prompt: "Short: {{.}}"},
+			{Name: "verbose", Model: "large", System: "Be thorough.", Prompt: "Explain in detail: {{.}}", Options: []vultrai.ChatOption{vultrai.WithTemperature(0.2)}},
+		},
+		Inputs:  []string{"tides", "fail"},
+		Repeats: 2,
+		Seed:    42,
+		Cost: func(model string, usage vultrai.Usage) float64 {
+			if model == "large" {
+				return float64(usage.TotalTokens) * 0.002
+			}
+			return float64(usage.TotalTokens) * 0.001
+		},
+	}
+
+	report, err := exp.Run(context.Background(), client)
+	require.NoError(t, err)
+
+	require.Len(t, report.Runs, 8)
+	assert.Equal(t, "terse", report.Runs[0].Variant)
+	assert.Equal(t, "verbose", report.Runs[1].Variant)
+	assert.Equal(t, "small: Short: tides", report.Runs[0].Output)
+	assert.ElementsMatch(t, []int{42, 42, 42, 42, 43, 43, 43, 43}, seeds)
+
+	require.Len(t, report.Variants, 2)
+	terse, verbose := report.Variants[0], report.Variants[1]
+	assert.Equal(t, 4, terse.Runs)
+	assert.Equal(t, 2, terse.Errors)
+	assert.Equal(t, 2*len("Short: tides"), terse.CompletionTokens)
+	assert.InDelta(t, 0.001*float64(10+len("Short: tides")), terse.CostPerRun, 1e-9)
+	assert.Greater(t, verbose.CostPerRun, terse.CostPerRun)
+
+	assert.Equal(t, map[string]string{
+		"terse":   "small: Short: tides",
+		"verbose": "large: Explain in detail: tides",
+	}, report.Outputs(0))
+	assert.Contains(t, report.String(), "verbose")
+}
+
+func TestExperimentWithJudge(t *testing.T) {
+	client := &vultraitest.MockClient{
+		CreateChatCompletionFunc: func(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.ChatCompletionResponse, error) {
+			if req.Model == "judge" {
+				if strings.Contains(req.Messages[1].Content, "good answer") {
+					return vultraitest.ChatResponse(`{"rationale": "", "score": 5}`), nil
+				}
+				return vultraitest.ChatResponse(`{"rationale": "", "score": 1}`), nil
+			}
+			return vultraitest.ChatResponse(req.Messages[0].Content), nil
+		},
+	}
+
+	exp := &Experiment{
+		Variants: []Variant{
+			{Name: "a", Model: "m", Prompt: "good answer"},
+			{Name: "b", Model: "m", Prompt: "poor answer"},
+		},
+		Inputs: []string{"q"},
+		Judge:  NewJudge(client, "judge"),
+	}
+
+	report, err := exp.Run(context.Background(), client)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, report.Variants[0].MeanScore)
+	assert.Equal(t, 0.0, report.Variants[1].MeanScore)
+	assert.Equal(t, 1, report.Variants[1].Graded)
+}
+
+func TestExperimentValidation(t *testing.T) {
+	_, err := (&Experiment{Inputs: []string{"x"}}).Run(context.Background(), &vultraitest.MockClient{})
+	assert.Error(t, err)
+
+	_, err = (&Experiment{
+		Variants: []Variant{{Name: "a"}, {Name: "a"}},
+		Inputs:   []string{"x"},
+	}).Run(context.Background(), &vultraitest.MockClient{})
+	assert.Error(t, err)
+
+	_, err = (&Experiment{
+		Variants: []Variant{{Name: "a", Prompt: "{{"}},
+		Inputs:   []string{"x"},
+	}).Run(context.Background(), &vultraitest.MockClient{})
+	assert.Error(t, err)
+}
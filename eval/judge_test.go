@@ -0,0 +1,123 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/eqba1/vultrai/vultraitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// judgeStub answers judge requests with fn applied to the user prompt
+func judgeStub(fn func(prompt string) string) *vultraitest.MockClient {
+	return &vultraitest.MockClient{
+		CreateChatCompletionFunc: func(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.ChatCompletionResponse, error) {
+			resp := vultraitest.ChatResponse(fn(req.Messages[1].Content))
+			resp.Usage = vultrai.Usage{TotalTokens: 10}
+			return resp, nil
+		},
+	}
+}
+
+func TestJudgeGrade(t *testing.T) {
+	client := judgeStub(func(prompt string) string {
+		return "```json\n{\"rationale\": \"Matches the reference.\", \"score\": 4}\n```"
+	})
+
+	judge := NewJudge(client, "judge-model", WithRubric("Must mention Paris"))
+	grade, err := judge.Grade(context.Background(), Case{
+		ID:        "q1",
+		Question:  "Capital of France?",
+		Answer:    "Paris",
+		Reference: "Paris",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, grade.RawScore)
+	assert.Equal(t, 0.75, grade.Score)
+	assert.Equal(t, "Matches the reference.", grade.Rationale)
+
+	req := client.Calls()[0].Args[0].(vultrai.ChatCompletionRequest)
+	assert.Contains(t, req.Messages[0].Content, "from 1 (unacceptable) to 5")
+	assert.Contains(t, req.Messages[1].Content, "RUBRIC:\nMust mention Paris")
+	assert.Contains(t, req.Messages[1].Content, "ANSWER TO GRADE:\nParis")
+	assert.Equal(t, 0.0, *req.Temperature)
+}
+
+func TestJudgeGradeInvalidScore(t *testing.T) {
+	client := judgeStub(func(string) string { return `{"rationale": "", "score": 9}` })
+
+	_, err := NewJudge(client, "judge-model").Grade(context.Background(), Case{Answer: "x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "outside scale")
+}
+
+func TestJudgeGradeAll(t *testing.T) {
+	client := judgeStub(func(prompt string) string {
+		switch {
+		case strings.Contains(prompt, "good"):
+			return `{"rationale": "ok", "score": 5}`
+		case strings.Contains(prompt, "bad"):
+			return `{"rationale": "wrong", "score": 1}`
+		default:
+			return "I cannot grade this."
+		}
+	})
+
+	report := NewJudge(client, "judge-model", WithConcurrency(2)).GradeAll(context.Background(), []Case{
+		{ID: "1", Answer: "good"},
+		{ID: "2", Answer: "good"},
+		{ID: "3", Answer: "bad"},
+		{ID: "4", Answer: "???"},
+	})
+
+	assert.Equal(t, 3, report.Graded)
+	assert.Equal(t, 1, report.Errors)
+	assert.InDelta(t, 2.0/3.0, report.Mean, 1e-9)
+	assert.Equal(t, 1.0, report.Median)
+	assert.InDelta(t, 2.0/3.0, report.PassRate, 1e-9)
+	assert.Equal(t, map[int]int{5: 2, 1: 1}, report.Distribution)
+	assert.Equal(t, 40, report.Usage.TotalTokens)
+	assert.Error(t, report.Grades[3].Err)
+	assert.Contains(t, report.String(), "3 graded, 1 errors")
+}
+
+func TestJudgeCompare(t *testing.T) {
+	// A consistent judge prefers the answer mentioning Paris in both orders
+	consistent := judgeStub(func(prompt string) string {
+		first := strings.Index(prompt, "ANSWER 1")
+		second := strings.Index(prompt, "ANSWER 2")
+		if strings.Index(prompt[first:], "Paris") < second-first {
+			return `{"rationale": "correct", "winner": "1"}`
+		}
+		return `{"rationale": "correct", "winner": "2"}`
+	})
+
+	cmp, err := NewJudge(consistent, "judge-model").Compare(context.Background(), Case{Question: "Capital of France?"}, "Lyon", "Paris")
+	require.NoError(t, err)
+	assert.Equal(t, "B", cmp.Winner)
+	assert.True(t, cmp.Consistent)
+	assert.Equal(t, 2, consistent.CallCount("CreateChatCompletion"))
+
+	// A judge that always prefers the first position is detected
+	biased := judgeStub(func(string) string { return `{"rationale": "first", "winner": "1"}` })
+	cmp, err = NewJudge(biased, "judge-model").Compare(context.Background(), Case{}, "Lyon", "Paris")
+	require.NoError(t, err)
+	assert.Equal(t, "tie", cmp.Winner)
+	assert.False(t, cmp.Consistent)
+}
+
+func TestJudgeCompareError(t *testing.T) {
+	client := &vultraitest.MockClient{
+		CreateChatCompletionFunc: func(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.ChatCompletionResponse, error) {
+			return nil, errors.New("unavailable")
+		},
+	}
+
+	_, err := NewJudge(client, "judge-model").Compare(context.Background(), Case{}, "a", "b")
+	assert.Error(t, err)
+}
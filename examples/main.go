@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	vultrai "github.com/eqba1/vultrai"
 )
@@ -266,6 +267,23 @@ func ExampleClient_GetRequestLogs() {
 	}
 }
 
+func ExampleContextWithRequestOptions() {
+	client := vultrai.NewClient(os.Getenv("API_KEY"))
+
+	// Override the client's defaults for this call only
+	ctx := vultrai.ContextWithRequestOptions(context.Background(),
+		vultrai.WithRequestTimeout(10*time.Second),
+		vultrai.WithRequestHeader("X-Request-Source", "nightly-report"),
+	)
+
+	response, err := client.SimpleChatCompletion(ctx, DefaultModel, "Summarize today's usage in one sentence.")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(response.Choices[0].Message.Content)
+}
+
 // func ExampleValidation() {
 // 	// Validate parameters before making requests
 // 	temperature := 0.8
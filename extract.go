@@ -0,0 +1,104 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const extractSystemPrompt = "You are a data extraction engine. Extract the requested information from the user's document. " +
+	"Respond with a single JSON value that conforms to this JSON Schema:\n%s\n" +
+	"Respond with JSON only: no prose, no markdown. Use null for information that is not present."
+
+// Extract asks model to pull structured data out of document and decodes the
+// result into T. The JSON Schema of T is set as the response format and
+// included in the prompt for models that ignore it. The answer is decoded
+// like with CreateChatCompletionInto, and the model gets one chance to
+// correct output that is invalid or lacks required fields.
+func Extract[T any](ctx context.Context, client API, model, document string) (T, error) {
+	var result extraction[T]
+
+	schema := JSONSchemaOf(result.value)
+	text, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return result.value, fmt.Errorf("error building schema: %w", err)
+	}
+
+	req := ChatCompletionRequest{
+		Model: model,
+		Messages: []Message{
+			CreateSystemMessage(fmt.Sprintf(extractSystemPrompt, text)),
+			CreateUserMessage(document),
+		},
+		Temperature: Float64(0),
+	}
+	WithJSONSchema("extraction", schema, false)(&req)
+
+	if _, err := createChatCompletionInto(ctx, client, req, &result); err != nil {
+		return result.value, fmt.Errorf("error extracting: %w", err)
+	}
+	return result.value, nil
+}
+
+// extraction decodes a T and fails when fields its schema requires are
+// missing, so that the model is asked to correct them
+type extraction[T any] struct {
+	value T
+}
+
+func (e *extraction[T]) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &e.value); err != nil {
+		return err
+	}
+
+	required, _ := JSONSchemaOf(e.value)["required"].([]string)
+	if len(required) == 0 {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	var missing []string
+	for _, name := range required {
+		if _, ok := fields[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required fields: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// extractJSONText strips markdown fences and surrounding prose from model
+// output, returning the outermost JSON object or array it contains
+func extractJSONText(s string) string {
+	s = strings.TrimSpace(s)
+
+	if strings.HasPrefix(s, "```") {
+		s = strings.TrimPrefix(s, "```")
+		if nl := strings.IndexByte(s, '\n'); nl >= 0 {
+			s = s[nl+1:]
+		}
+		if end := strings.LastIndex(s, "```"); end >= 0 {
+			s = s[:end]
+		}
+		s = strings.TrimSpace(s)
+	}
+
+	start := strings.IndexAny(s, "{[")
+	if start < 0 {
+		return s
+	}
+	closer := byte('}')
+	if s[start] == '[' {
+		closer = ']'
+	}
+	end := strings.LastIndexByte(s, closer)
+	if end < start {
+		return s[start:]
+	}
+	return s[start : end+1]
+}
@@ -0,0 +1,86 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type extractInvoice struct {
+	Number   string        `json:"number" description:"Invoice number"`
+	Total    float64       `json:"total"`
+	Currency string        `json:"currency" enum:"USD,EUR"`
+	Lines    []extractLine `json:"lines,omitempty"`
+	Due      *time.Time    `json:"due"`
+}
+
+type extractLine struct {
+	Item string `json:"item"`
+	Qty  int    `json:"qty"`
+}
+
+func TestJSONSchemaOf(t *testing.T) {
+	schema := JSONSchemaOf(extractInvoice{})
+
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, []string{"number", "total", "currency"}, schema["required"])
+
+	props := schema["properties"].(map[string]interface{})
+	assert.Equal(t, "Invoice number", props["number"].(map[string]interface{})["description"])
+	assert.Equal(t, []interface{}{"USD", "EUR"}, props["currency"].(map[string]interface{})["enum"])
+	assert.Equal(t, "date-time", props["due"].(map[string]interface{})["format"])
+
+	lines := props["lines"].(map[string]interface{})
+	assert.Equal(t, "array", lines["type"])
+	assert.Equal(t, "integer", lines["items"].(map[string]interface{})["properties"].(map[string]interface{})["qty"].(map[string]interface{})["type"])
+}
+
+func TestExtractJSONText(t *testing.T) {
+	assert.Equal(t, `{"a":1}`, extractJSONText("```json\n{\"a\":1}\n```"))
+	assert.Equal(t, `{"a":1}`, extractJSONText(`Here you go: {"a":1} Hope that helps!`))
+	assert.Equal(t, `[1,2]`, extractJSONText(" [1,2] "))
+}
+
+func TestExtract(t *testing.T) {
+	client, transport := setupSequenceClient(
+		"```json\n{\"number\":\"INV-1\",\"total\":42.5,\"currency\":\"EUR\"}\n```",
+	)
+
+	invoice, err := Extract[extractInvoice](context.Background(), client, "test-model", "Invoice INV-1, total 42.50 EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "INV-1", invoice.Number)
+	assert.Equal(t, 42.5, invoice.Total)
+	require.Len(t, transport.requests, 1)
+
+	// The schema of the result is the response format
+	var req ChatCompletionRequest
+	require.NoError(t, json.NewDecoder(transport.requests[0].Body).Decode(&req))
+	require.NotNil(t, req.ResponseFormat)
+	assert.Equal(t, ResponseFormatJSONSchema, req.ResponseFormat.Type)
+	assert.Equal(t, "extraction", req.ResponseFormat.JSONSchema.Name)
+}
+
+func TestExtractRepairsOutput(t *testing.T) {
+	client, transport := setupSequenceClient(`{"number":"INV-2","total":3,"currency":"EUR",}`)
+
+	invoice, err := Extract[extractInvoice](context.Background(), client, "test-model", "doc")
+	require.NoError(t, err)
+	assert.Equal(t, "INV-2", invoice.Number)
+	assert.Len(t, transport.requests, 1)
+}
+
+func TestExtractRetriesInvalidOutput(t *testing.T) {
+	client, transport := setupSequenceClient(
+		`{"number":"INV-1"}`,
+		`{"number":"INV-1","total":10,"currency":"USD"}`,
+	)
+
+	invoice, err := Extract[extractInvoice](context.Background(), client, "test-model", "doc")
+	require.NoError(t, err)
+	assert.Equal(t, "USD", invoice.Currency)
+	assert.Len(t, transport.requests, 2)
+}
@@ -0,0 +1,168 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// FallbackReason is the kind of failure that moves a request to the next
+// model
+type FallbackReason string
+
+const (
+	// FallbackOverloaded covers the errors APIError.Temporary reports, such
+	// as 429, 502, 503, 504 and 529 responses, and errors the API reports
+	// as out of capacity
+	FallbackOverloaded FallbackReason = "overloaded"
+	// FallbackContentFilter covers content_filter finish reasons, content
+	// policy errors and rejections by an OutputFilter
+	FallbackContentFilter FallbackReason = "content_filter"
+	// FallbackEmptyOutput covers responses with no text or tool calls
+	FallbackEmptyOutput FallbackReason = "empty_output"
+	// FallbackServerError covers the other errors APIError.Retryable
+	// reports, i.e. 500 responses. Policies only fall back on it when it is
+	// listed in Reasons.
+	FallbackServerError FallbackReason = "server_error"
+)
+
+// FallbackAttempt records a failed attempt on one model
+type FallbackAttempt struct {
+	Model  string
+	Reason FallbackReason
+	// Err is the error returned by the attempt, nil for responses that were
+	// rejected for their content
+	Err error
+}
+
+// FallbackPolicy retries a chat completion on alternate models when the
+// requested model fails in a way another model may not
+type FallbackPolicy struct {
+	// Models are tried in order after the requested model
+	Models []string
+	// Reasons limits which failures fall back (default all but
+	// FallbackServerError)
+	Reasons []FallbackReason
+	// Classify overrides the built-in failure detection. It returns the
+	// reason and true when the attempt should fall back.
+	Classify func(resp *ChatCompletionResponse, err error) (FallbackReason, bool)
+}
+
+// WithModelFallback retries chat completions on policy.Models when the
+// requested model is overloaded, filters the output or returns nothing, or
+// for the failures listed in policy.Reasons. The answering model is
+// recorded in the response Meta. Retries are sent with the request options
+// of the original call.
+func WithModelFallback(policy FallbackPolicy) ClientOption {
+	return WithChatInterceptor(policy.Interceptor())
+}
+
+// WithFallbackModels retries chat completions on models, in order, when the
+// requested model is overloaded or fails with a server error. The
+// answering model is recorded in the response Meta.
+func WithFallbackModels(models ...string) ClientOption {
+	return WithModelFallback(capacityFallback(models))
+}
+
+// ContextWithFallbackModels returns a context whose chat completions are
+// retried on models like with WithFallbackModels
+func ContextWithFallbackModels(ctx context.Context, models ...string) context.Context {
+	return ContextWithChatInterceptors(ctx, capacityFallback(models).Interceptor())
+}
+
+func capacityFallback(models []string) FallbackPolicy {
+	return FallbackPolicy{Models: models, Reasons: []FallbackReason{FallbackOverloaded, FallbackServerError}}
+}
+
+// Interceptor returns a ChatInterceptor applying the policy
+func (p FallbackPolicy) Interceptor() ChatInterceptor {
+	return func(ctx context.Context, req ChatCompletionRequest, next ChatHandler) (*ChatCompletionResponse, error) {
+		model := req.Model
+		var attempts []FallbackAttempt
+		for i := 0; ; i++ {
+			attemptReq := req
+			attemptReq.Model = model
+			resp, err := next(ctx, attemptReq)
+
+			reason, fallback := p.classify(resp, err)
+			if !fallback || i == len(p.Models) || ctx.Err() != nil {
+				if err != nil || resp == nil {
+					return resp, err
+				}
+				// Copy so responses shared through coalescing are not mutated
+				annotated := *resp
+				meta := ResponseMeta{}
+				if resp.Meta != nil {
+					meta = *resp.Meta
+				}
+				meta.Model, meta.Fallbacks = model, attempts
+				annotated.Meta = &meta
+				return &annotated, nil
+			}
+			attempts = append(attempts, FallbackAttempt{Model: model, Reason: reason, Err: err})
+			model = p.Models[i]
+		}
+	}
+}
+
+func (p FallbackPolicy) classify(resp *ChatCompletionResponse, err error) (FallbackReason, bool) {
+	classify := p.Classify
+	if classify == nil {
+		classify = ClassifyFallback
+	}
+	reason, ok := classify(resp, err)
+	if !ok {
+		return "", false
+	}
+	if len(p.Reasons) == 0 {
+		return reason, reason != FallbackServerError
+	}
+	for _, allowed := range p.Reasons {
+		if allowed == reason {
+			return reason, true
+		}
+	}
+	return "", false
+}
+
+// ClassifyFallback is the default failure detection used by FallbackPolicy
+func ClassifyFallback(resp *ChatCompletionResponse, err error) (FallbackReason, bool) {
+	if err != nil {
+		if errors.Is(err, ErrOutputRejected) {
+			return FallbackContentFilter, true
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			return "", false
+		}
+		detail := strings.ToLower(apiErr.Type + " " + apiErr.Code + " " + apiErr.Message)
+		switch {
+		case strings.Contains(detail, "content_filter") || strings.Contains(detail, "content_policy"):
+			return FallbackContentFilter, true
+		case !apiErr.Retryable():
+			// The request fails the same way on any model
+			return "", false
+		case apiErr.Temporary() || strings.Contains(detail, "capacity"):
+			return FallbackOverloaded, true
+		default:
+			return FallbackServerError, true
+		}
+	}
+
+	if resp == nil {
+		return FallbackEmptyOutput, true
+	}
+	empty := true
+	for _, choice := range resp.Choices {
+		if choice.FinishReason == "content_filter" {
+			return FallbackContentFilter, true
+		}
+		if strings.TrimSpace(choice.Message.Content) != "" || len(choice.Message.ToolCalls) > 0 {
+			empty = false
+		}
+	}
+	if empty {
+		return FallbackEmptyOutput, true
+	}
+	return "", false
+}
@@ -0,0 +1,202 @@
+package vultrai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// modelTransport answers chat completions according to the requested model
+type modelTransport struct {
+	status  map[string]int
+	content map[string]string
+	models  []string
+}
+
+func (m *modelTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var chatReq ChatCompletionRequest
+	_ = json.NewDecoder(req.Body).Decode(&chatReq)
+	m.models = append(m.models, chatReq.Model)
+
+	var body []byte
+	status := http.StatusOK
+	if code, ok := m.status[chatReq.Model]; ok {
+		status = code
+		body, _ = json.Marshal(Error{Message: "model is overloaded", Type: "server_error"})
+	} else {
+		body, _ = json.Marshal(ChatCompletionResponse{
+			Model:   chatReq.Model,
+			Choices: []Choice{{Message: Message{Role: "assistant", Content: m.content[chatReq.Model]}, FinishReason: "stop"}},
+		})
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}, nil
+}
+
+func TestModelFallback(t *testing.T) {
+	transport := &modelTransport{
+		status:  map[string]int{"primary": http.StatusServiceUnavailable},
+		content: map[string]string{"secondary": "", "tertiary": "Hello"},
+	}
+	client := NewClient("test-api-key",
+		WithBaseURL("https://api.test.local"),
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithModelFallback(FallbackPolicy{Models: []string{"secondary", "tertiary"}}),
+	)
+
+	resp, err := client.CreateChatCompletion(context.Background(), ChatCompletionRequest{
+		Model:    "primary",
+		Messages: []Message{{Role: "user", Content: "Hi"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"primary", "secondary", "tertiary"}, transport.models)
+	assert.Equal(t, "Hello", resp.Choices[0].Message.Content)
+
+	require.NotNil(t, resp.Meta)
+	assert.Equal(t, "tertiary", resp.Meta.Model)
+	require.Len(t, resp.Meta.Fallbacks, 2)
+	assert.Equal(t, FallbackOverloaded, resp.Meta.Fallbacks[0].Reason)
+	assert.Equal(t, "primary", resp.Meta.Fallbacks[0].Model)
+
+	var apiErr *APIError
+	require.True(t, errors.As(resp.Meta.Fallbacks[0].Err, &apiErr))
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, FallbackEmptyOutput, resp.Meta.Fallbacks[1].Reason)
+	assert.NoError(t, resp.Meta.Fallbacks[1].Err)
+}
+
+func TestModelFallbackExhausted(t *testing.T) {
+	policy := FallbackPolicy{Models: []string{"backup"}}
+	overloaded := &APIError{StatusCode: 529, Message: "overloaded"}
+
+	var models []string
+	_, err := policy.Interceptor()(context.Background(), ChatCompletionRequest{Model: "primary"},
+		func(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
+			models = append(models, req.Model)
+			return nil, overloaded
+		})
+	assert.ErrorIs(t, err, overloaded)
+	assert.Equal(t, []string{"primary", "backup"}, models)
+}
+
+func TestModelFallbackReasons(t *testing.T) {
+	policy := FallbackPolicy{Models: []string{"backup"}, Reasons: []FallbackReason{FallbackOverloaded}}
+
+	calls := 0
+	resp, err := policy.Interceptor()(context.Background(), ChatCompletionRequest{Model: "primary"},
+		func(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
+			calls++
+			return &ChatCompletionResponse{Choices: []Choice{{FinishReason: "content_filter"}}}, nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "primary", resp.Meta.Model)
+	assert.Empty(t, resp.Meta.Fallbacks)
+
+	// Server errors only fall back when listed
+	serverError := &APIError{StatusCode: http.StatusInternalServerError}
+	failing := func(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
+		if req.Model == "primary" {
+			return nil, serverError
+		}
+		return &ChatCompletionResponse{Choices: []Choice{{Message: CreateAssistantMessage("ok")}}}, nil
+	}
+	_, err = FallbackPolicy{Models: []string{"backup"}}.Interceptor()(context.Background(), ChatCompletionRequest{Model: "primary"}, failing)
+	assert.ErrorIs(t, err, serverError)
+
+	policy.Reasons = []FallbackReason{FallbackServerError}
+	resp, err = policy.Interceptor()(context.Background(), ChatCompletionRequest{Model: "primary"}, failing)
+	require.NoError(t, err)
+	assert.Equal(t, "backup", resp.Meta.Model)
+}
+
+func TestFallbackModels(t *testing.T) {
+	transport := &modelTransport{
+		status:  map[string]int{"primary": http.StatusBadGateway, "secondary": http.StatusServiceUnavailable},
+		content: map[string]string{"tertiary": "Hello", "other": ""},
+	}
+	client := NewClient("test-api-key",
+		WithBaseURL("https://api.test.local"),
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithFallbackModels("secondary", "tertiary"),
+	)
+
+	resp, err := client.CreateChatCompletion(context.Background(), ChatCompletionRequest{Model: "primary"})
+	require.NoError(t, err)
+	assert.Equal(t, "tertiary", resp.Meta.Model)
+	require.Len(t, resp.Meta.Fallbacks, 2)
+	assert.Equal(t, "primary", resp.Meta.Fallbacks[0].Model)
+	assert.Equal(t, "secondary", resp.Meta.Fallbacks[1].Model)
+
+	// Empty output is not a capacity problem
+	transport.models = nil
+	ctx := ContextWithFallbackModels(context.Background(), "tertiary")
+	resp, err = NewClient("test-api-key", WithBaseURL("https://api.test.local"), WithHTTPClient(&http.Client{Transport: transport})).
+		CreateChatCompletion(ctx, ChatCompletionRequest{Model: "other"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other"}, transport.models)
+	assert.Equal(t, "other", resp.Meta.Model)
+}
+
+func TestClassifyFallback(t *testing.T) {
+	text := &ChatCompletionResponse{Choices: []Choice{{Message: Message{Content: "ok"}}}}
+	toolCall := &ChatCompletionResponse{Choices: []Choice{{Message: Message{ToolCalls: []ToolCall{{ID: "call_1"}}}}}}
+
+	tests := []struct {
+		name   string
+		resp   *ChatCompletionResponse
+		err    error
+		reason FallbackReason
+		ok     bool
+	}{
+		{"text", text, nil, "", false},
+		{"tool call", toolCall, nil, "", false},
+		{"blank", &ChatCompletionResponse{Choices: []Choice{{Message: Message{Content: " \n"}}}}, nil, FallbackEmptyOutput, true},
+		{"no choices", &ChatCompletionResponse{}, nil, FallbackEmptyOutput, true},
+		{"finish reason", &ChatCompletionResponse{Choices: []Choice{{Message: Message{Content: "partial"}, FinishReason: "content_filter"}}}, nil, FallbackContentFilter, true},
+		{"policy error", nil, &APIError{StatusCode: 400, Code: "content_policy_violation"}, FallbackContentFilter, true},
+		{"output filter", nil, &FilterError{Reason: "blocklist", Detail: "x"}, FallbackContentFilter, true},
+		{"unavailable", nil, &APIError{StatusCode: 503}, FallbackOverloaded, true},
+		{"capacity", nil, &APIError{StatusCode: 500, Message: "No capacity available"}, FallbackOverloaded, true},
+		{"bad gateway", nil, &APIError{StatusCode: 502, Message: "upstream failed"}, FallbackOverloaded, true},
+		{"rate limited", nil, &APIError{StatusCode: 429, Code: "rate_limited"}, FallbackOverloaded, true},
+		{"quota", nil, &APIError{StatusCode: 429, Code: "insufficient_quota"}, "", false},
+		{"internal", nil, &APIError{StatusCode: 500, Message: "oops"}, FallbackServerError, true},
+		{"not implemented", nil, &APIError{StatusCode: 501}, "", false},
+		{"bad request", nil, &APIError{StatusCode: 400, Message: "invalid"}, "", false},
+		{"transport", nil, errors.New("connection reset"), "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, ok := ClassifyFallback(tt.resp, tt.err)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+	mockTransport.SetResponse("POST", "/chat/completions", 429, Error{Message: "slow down", Type: "rate_limit", Code: "rate_limited"})
+
+	_, err := client.CreateChatCompletion(context.Background(), ChatCompletionRequest{Model: "test-model"})
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 429, apiErr.StatusCode)
+	assert.Equal(t, "rate_limited", apiErr.Code)
+	assert.Equal(t, "API error 429: slow down", err.Error())
+
+	raw := newAPIError(502, []byte("<html>Bad Gateway</html>"))
+	assert.Equal(t, "HTTP 502: <html>Bad Gateway</html>", raw.Error())
+}
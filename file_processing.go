@@ -0,0 +1,94 @@
+package vultrai
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Statuses of a file added to a collection
+const (
+	FileStatusEnqueued   = "enqueued"
+	FileStatusProcessing = "processing"
+	FileStatusCompleted  = "completed"
+	FileStatusFailed     = "failed"
+)
+
+// Defaults of PollOptions
+const (
+	DefaultPollInterval    = time.Second
+	DefaultMaxPollInterval = 30 * time.Second
+	DefaultPollTimeout     = 30 * time.Minute
+)
+
+// PollOptions configures how a helper polls for a long-running operation.
+// The wait between polls starts at Interval and doubles up to MaxInterval.
+// Polling stops after Timeout or when the context is done, whichever comes
+// first.
+type PollOptions struct {
+	// Interval is the first wait between polls (default DefaultPollInterval)
+	Interval time.Duration
+	// MaxInterval bounds the wait between polls (default
+	// DefaultMaxPollInterval)
+	MaxInterval time.Duration
+	// Timeout bounds the total wait (default DefaultPollTimeout)
+	Timeout time.Duration
+}
+
+// FileProcessingError is returned when a file fails processing
+type FileProcessingError struct {
+	File CollectionFile
+}
+
+func (e *FileProcessingError) Error() string {
+	if e.File.Error == "" {
+		return "file " + e.File.ID + " failed processing"
+	}
+	return "file " + e.File.ID + " failed processing: " + e.File.Error
+}
+
+// WaitForFileProcessing polls a file added to a collection until it is
+// processed and returns it. A file that failed yields a
+// *FileProcessingError, and a status other than the FileStatus values an
+// error rather than more polling.
+func (c *Client) WaitForFileProcessing(ctx context.Context, collectionID, fileID string, opts PollOptions) (*CollectionFile, error) {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	maxInterval := opts.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultMaxPollInterval
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultPollTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		resp, err := c.GetFile(ctx, collectionID, fileID)
+		if err != nil {
+			return nil, err
+		}
+		switch resp.File.Status {
+		case FileStatusCompleted:
+			return &resp.File, nil
+		case FileStatusFailed:
+			return nil, &FileProcessingError{File: resp.File}
+		case FileStatusEnqueued, FileStatusProcessing:
+		default:
+			return nil, fmt.Errorf("error waiting for file %s: unexpected status %q", fileID, resp.File.Status)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		interval = min(interval*2, maxInterval)
+	}
+}
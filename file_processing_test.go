@@ -0,0 +1,55 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForFileProcessing(t *testing.T) {
+	statuses := []string{FileStatusEnqueued, FileStatusProcessing, FileStatusCompleted}
+	polls := 0
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/vector-stores/collections/col/files/file-1", req.URL.Path)
+		file := CollectionFile{ID: "file-1", Status: statuses[min(polls, len(statuses)-1)], Items: 3}
+		polls++
+		body, _ := json.Marshal(GetFileResponse{File: file})
+		return textResponse(200, string(body)), nil
+	})
+	client := NewClient("key", WithBaseURL("https://api.test.local"), WithHTTPClient(&http.Client{Transport: transport}))
+	opts := PollOptions{Interval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+
+	file, err := client.WaitForFileProcessing(context.Background(), "col", "file-1", opts)
+	require.NoError(t, err)
+	assert.Equal(t, 3, file.Items)
+	assert.Equal(t, 3, polls)
+
+	statuses = []string{FileStatusFailed}
+	_, err = client.WaitForFileProcessing(context.Background(), "col", "file-1", opts)
+	var processingErr *FileProcessingError
+	require.True(t, errors.As(err, &processingErr))
+	assert.Equal(t, "file-1", processingErr.File.ID)
+
+	statuses = []string{FileStatusProcessing, "cancelled"}
+	polls = 0
+	_, err = client.WaitForFileProcessing(context.Background(), "col", "file-1", opts)
+	assert.ErrorContains(t, err, `unexpected status "cancelled"`)
+	assert.Equal(t, 2, polls)
+
+	statuses = []string{FileStatusProcessing}
+	opts.Timeout = 10 * time.Millisecond
+	_, err = client.WaitForFileProcessing(context.Background(), "col", "file-1", opts)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	opts.Timeout = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.WaitForFileProcessing(ctx, "col", "file-1", opts)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
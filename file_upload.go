@@ -0,0 +1,104 @@
+package vultrai
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// sniffLen is the number of bytes http.DetectContentType looks at
+const sniffLen = 512
+
+// AddFileFromPath uploads the file at path to a vector store collection,
+// named after its base name. The content type is taken from the extension,
+// or sniffed from the content when the extension is unknown.
+func (c *Client) AddFileFromPath(ctx context.Context, collectionID, path string) (*AddFileResponse, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer f.Close()
+
+	var size int64 = -1
+	if info, err := f.Stat(); err == nil {
+		size = info.Size()
+	}
+
+	reader := bufio.NewReaderSize(f, sniffLen)
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		head, _ := reader.Peek(sniffLen)
+		contentType = http.DetectContentType(head)
+	}
+
+	return c.addFile(ctx, collectionID, sizedReader{reader, size}, filepath.Base(path), contentType)
+}
+
+// sizedReader is a reader whose size is known
+type sizedReader struct {
+	io.Reader
+	size int64
+}
+
+func (s sizedReader) Size() int64 { return s.size }
+
+// readerSize returns the number of bytes left in r, or -1 when unknown
+func readerSize(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case interface{ Size() int64 }:
+		return r.Size()
+	case *os.File:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+	return -1
+}
+
+// progressReader reports the bytes read from reader
+type progressReader struct {
+	reader   io.Reader
+	sent     int64
+	total    int64
+	progress func(sent, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.reader.Read(b)
+	if n > 0 {
+		p.sent += int64(n)
+		p.progress(p.sent, p.total)
+	}
+	return n, err
+}
+
+// createFormFile creates the "file" part of a multipart form, with
+// contentType or application/octet-stream when empty
+func createFormFile(writer *multipart.Writer, filename, contentType string) (io.Writer, error) {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(filename)))
+	header.Set("Content-Type", contentType)
+	return writer.CreatePart(header)
+}
+
+// quoteEscaper escapes filenames like multipart.Writer.CreateFormFile
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
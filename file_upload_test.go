@@ -0,0 +1,113 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddFileFromPath(t *testing.T) {
+	dir := t.TempDir()
+	named := filepath.Join(dir, "report.pdf")
+	bare := filepath.Join(dir, "scan")
+	require.NoError(t, os.WriteFile(named, []byte("%PDF-1.7 named"), 0o600))
+	require.NoError(t, os.WriteFile(bare, []byte("%PDF-1.4 sniffed"), 0o600))
+
+	client, transport := setupLocalTestClient()
+	for _, path := range []string{named, bare} {
+		_, err := client.AddFileFromPath(context.Background(), "col", path)
+		require.NoError(t, err)
+	}
+
+	requests := transport.GetRequests()
+	require.Len(t, requests, 2)
+	for i, want := range []struct{ name, content string }{{"report.pdf", "%PDF-1.7 named"}, {"scan", "%PDF-1.4 sniffed"}} {
+		req := requests[i]
+		assert.Equal(t, "/vector-stores/collections/col/files", req.URL.Path)
+		_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		part, err := multipart.NewReader(req.Body, params["boundary"]).NextPart()
+		require.NoError(t, err)
+		assert.Equal(t, want.name, part.FileName())
+		assert.Equal(t, "application/pdf", part.Header.Get("Content-Type"))
+		content, _ := io.ReadAll(part)
+		assert.Equal(t, want.content, string(content))
+	}
+
+	_, err := client.AddFileFromPath(context.Background(), "col", filepath.Join(dir, "missing.pdf"))
+	assert.Error(t, err)
+}
+
+func TestStreamedUpload(t *testing.T) {
+	var received int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		received = len(data)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := NewClient("key", WithBaseURL(server.URL))
+
+	content := strings.Repeat("x", 200*1024)
+	var sent, total int64
+	ctx := ContextWithRequestOptions(context.Background(), WithUploadProgress(func(s, t int64) {
+		sent, total = s, t
+	}))
+	_, err := client.AddFile(ctx, "col", strings.NewReader(content), "big.txt")
+	require.NoError(t, err)
+	assert.Equal(t, len(content), received)
+	assert.Equal(t, int64(len(content)), sent)
+	assert.Equal(t, int64(len(content)), total)
+
+	// Read errors abort the upload
+	failing := io.MultiReader(strings.NewReader("partial"), iotest.ErrReader(errors.New("disk failure")))
+	_, err = client.AddFile(context.Background(), "col", failing, "broken.txt")
+	assert.ErrorContains(t, err, "disk failure")
+}
+
+func TestStreamedUploadNotSent(t *testing.T) {
+	client := NewClient("key", WithBaseURL("https://api.test.local"), WithRequestSigner(func(req *http.Request) error {
+		return errors.New("no credentials")
+	}))
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 20; i++ {
+		_, err := client.AddFile(context.Background(), "col", strings.NewReader(strings.Repeat("x", 64*1024)), "big.txt")
+		require.ErrorContains(t, err, "no credentials")
+	}
+	// The form writers must exit once the request fails
+	waitForGoroutines(t, before)
+}
+
+// waitForGoroutines fails t unless the goroutine count drops to n within a
+// second. It polls in the test's own goroutine, since assert.Eventually
+// runs its condition in another one.
+func waitForGoroutines(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines still running, want at most %d", runtime.NumGoroutine(), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
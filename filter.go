@@ -0,0 +1,211 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ErrOutputRejected is matched by errors returned when an output filter
+// rejects a response
+var ErrOutputRejected = errors.New("output rejected by content filter")
+
+// FilterAction is what an OutputFilter does with offending output
+type FilterAction int
+
+const (
+	// FilterRedact replaces offending text and returns the response
+	FilterRedact FilterAction = iota
+	// FilterReject fails the request with a *FilterError
+	FilterReject
+)
+
+// FilterError describes why an output was rejected
+type FilterError struct {
+	// Reason names the check that failed: "blocklist", "pattern" or "moderation"
+	Reason string
+	// Detail is the matched term, pattern or moderator explanation
+	Detail string
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", ErrOutputRejected.Error(), e.Reason, e.Detail)
+}
+
+// Is reports whether target is ErrOutputRejected
+func (e *FilterError) Is(target error) bool {
+	return target == ErrOutputRejected
+}
+
+// Moderator classifies text as acceptable or not
+type Moderator interface {
+	Moderate(ctx context.Context, text string) (flagged bool, reason string, err error)
+}
+
+// ModeratorFunc adapts a function to the Moderator interface
+type ModeratorFunc func(ctx context.Context, text string) (bool, string, error)
+
+// Moderate calls f
+func (f ModeratorFunc) Moderate(ctx context.Context, text string) (bool, string, error) {
+	return f(ctx, text)
+}
+
+// OutputFilter checks assistant output against blocklisted terms, regular
+// expressions and an optional moderator before it reaches the application.
+// Blocklist and pattern matches are redacted or rejected according to
+// Action; a moderator flag always rejects, since flagged text cannot be
+// redacted meaningfully.
+type OutputFilter struct {
+	// Blocklist holds terms matched case-insensitively on word boundaries
+	Blocklist []string
+	// Patterns are matched against the output as-is
+	Patterns []*regexp.Regexp
+	// Action applies to blocklist and pattern matches
+	Action FilterAction
+	// Replacement substitutes redacted text (default "[REDACTED]")
+	Replacement string
+	// Moderator is consulted after blocklist and pattern checks
+	Moderator Moderator
+
+	once      sync.Once
+	blocklist *regexp.Regexp
+}
+
+func (f *OutputFilter) compiled() *regexp.Regexp {
+	f.once.Do(func() {
+		if len(f.Blocklist) == 0 {
+			return
+		}
+		terms := make([]string, len(f.Blocklist))
+		for i, term := range f.Blocklist {
+			terms[i] = regexp.QuoteMeta(term)
+		}
+		f.blocklist = regexp.MustCompile(`(?i)\b(?:` + strings.Join(terms, "|") + `)\b`)
+	})
+	return f.blocklist
+}
+
+// Apply checks text and returns it, possibly redacted, or a *FilterError
+func (f *OutputFilter) Apply(ctx context.Context, text string) (string, error) {
+	var err error
+	if re := f.compiled(); re != nil {
+		if text, err = f.screen(text, re, "blocklist"); err != nil {
+			return "", err
+		}
+	}
+	for _, re := range f.Patterns {
+		if text, err = f.screen(text, re, "pattern"); err != nil {
+			return "", err
+		}
+	}
+
+	if f.Moderator != nil {
+		flagged, reason, err := f.Moderator.Moderate(ctx, text)
+		if err != nil {
+			return "", fmt.Errorf("error moderating output: %w", err)
+		}
+		if flagged {
+			return "", &FilterError{Reason: "moderation", Detail: reason}
+		}
+	}
+
+	return text, nil
+}
+
+// screen redacts or rejects matches of re in text
+func (f *OutputFilter) screen(text string, re *regexp.Regexp, reason string) (string, error) {
+	match := re.FindString(text)
+	if match == "" {
+		return text, nil
+	}
+
+	if f.Action == FilterReject {
+		detail := match
+		if reason == "pattern" {
+			detail = re.String()
+		}
+		return "", &FilterError{Reason: reason, Detail: detail}
+	}
+
+	replacement := f.Replacement
+	if replacement == "" {
+		replacement = "[REDACTED]"
+	}
+	return re.ReplaceAllLiteralString(text, replacement), nil
+}
+
+// Interceptor returns a ChatInterceptor applying the filter to every choice
+func (f *OutputFilter) Interceptor() ChatInterceptor {
+	return func(ctx context.Context, req ChatCompletionRequest, next ChatHandler) (*ChatCompletionResponse, error) {
+		resp, err := next(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		filtered := *resp
+		filtered.Choices = make([]Choice, len(resp.Choices))
+		for i, choice := range resp.Choices {
+			content, err := f.Apply(ctx, choice.Message.Content)
+			if err != nil {
+				return nil, err
+			}
+			choice.Message.Content = content
+			filtered.Choices[i] = choice
+		}
+		return &filtered, nil
+	}
+}
+
+// WithOutputFilter filters the output of every chat completion made by the
+// client. Use ContextWithChatInterceptors(ctx, filter.Interceptor()) to
+// filter individual requests.
+func WithOutputFilter(filter *OutputFilter) ClientOption {
+	return WithChatInterceptor(filter.Interceptor())
+}
+
+// DefaultModerationPolicy is the system prompt used by ModelModerator
+const DefaultModerationPolicy = `You are a content moderator. Decide whether the text provided by the user is safe to show to end users of a general-purpose application.
+Unsafe content includes hate, harassment, sexual content involving minors, instructions for violence or weapons, self-harm encouragement and disclosure of personal data.
+Reply with "safe", or with "unsafe" followed by a short reason on the same line.`
+
+// ModelModerator returns a Moderator that asks a chat model, such as a
+// guard model, to classify text. The model must reply starting with "safe"
+// or "unsafe". An empty policy uses DefaultModerationPolicy.
+func ModelModerator(client *Client, model, policy string) Moderator {
+	if policy == "" {
+		policy = DefaultModerationPolicy
+	}
+	return ModeratorFunc(func(ctx context.Context, text string) (bool, string, error) {
+		// Use the unintercepted path so moderation calls are not filtered
+		// or redacted themselves
+		resp, err := client.completeChat(ctx, ChatCompletionRequest{
+			Model: model,
+			Messages: []Message{
+				CreateSystemMessage(policy),
+				CreateUserMessage(text),
+			},
+			Temperature: Float64(0),
+		})
+		if err != nil {
+			return false, "", err
+		}
+		if len(resp.Choices) == 0 {
+			return false, "", errors.New("moderation model returned no choices")
+		}
+
+		verdict := strings.TrimSpace(resp.Choices[0].Message.Content)
+		lower := strings.ToLower(verdict)
+		switch {
+		case strings.HasPrefix(lower, "unsafe"):
+			reason := strings.TrimSpace(strings.TrimLeft(verdict[len("unsafe"):], ":-\n "))
+			return true, reason, nil
+		case strings.HasPrefix(lower, "safe"):
+			return false, "", nil
+		default:
+			return false, "", fmt.Errorf("unexpected moderation verdict %q", verdict)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputFilterRedact(t *testing.T) {
+	f := &OutputFilter{
+		Blocklist: []string{"darn", "project falcon"},
+		Patterns:  []*regexp.Regexp{regexp.MustCompile(`sk-[A-Za-z0-9]{8,}`)},
+	}
+
+	out, err := f.Apply(context.Background(), "Darn, Project Falcon leaked key sk-abcdef123456 (darnation is fine)")
+	require.NoError(t, err)
+	assert.Equal(t, "[REDACTED], [REDACTED] leaked key [REDACTED] (darnation is fine)", out)
+}
+
+func TestOutputFilterReject(t *testing.T) {
+	f := &OutputFilter{Blocklist: []string{"secret"}, Action: FilterReject}
+
+	_, err := f.Apply(context.Background(), "The Secret is out")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrOutputRejected))
+
+	var filterErr *FilterError
+	require.True(t, errors.As(err, &filterErr))
+	assert.Equal(t, "blocklist", filterErr.Reason)
+	assert.Equal(t, "Secret", filterErr.Detail)
+}
+
+func TestOutputFilterModerator(t *testing.T) {
+	f := &OutputFilter{
+		Moderator: ModeratorFunc(func(ctx context.Context, text string) (bool, string, error) {
+			return text == "bad", "violence", nil
+		}),
+	}
+
+	out, err := f.Apply(context.Background(), "fine")
+	require.NoError(t, err)
+	assert.Equal(t, "fine", out)
+
+	_, err = f.Apply(context.Background(), "bad")
+	assert.ErrorIs(t, err, ErrOutputRejected)
+	assert.Contains(t, err.Error(), "violence")
+}
+
+func TestModelModerator(t *testing.T) {
+	client, transport := setupSequenceClient("unsafe: S1 violent content", "safe", "maybe")
+	moderator := ModelModerator(client, "guard-model", "")
+
+	flagged, reason, err := moderator.Moderate(context.Background(), "text")
+	require.NoError(t, err)
+	assert.True(t, flagged)
+	assert.Equal(t, "S1 violent content", reason)
+
+	flagged, _, err = moderator.Moderate(context.Background(), "text")
+	require.NoError(t, err)
+	assert.False(t, flagged)
+
+	_, _, err = moderator.Moderate(context.Background(), "text")
+	assert.Error(t, err)
+	assert.Len(t, transport.requests, 3)
+}
+
+func TestOutputFilterPerClientAndRequest(t *testing.T) {
+	client, _ := setupSequenceClient("The password is hunter2")
+	WithOutputFilter(&OutputFilter{Blocklist: []string{"hunter2"}})(client)
+
+	resp, err := client.SimpleChatCompletion(context.Background(), "test-model", "hi")
+	require.NoError(t, err)
+	assert.Equal(t, "The password is [REDACTED]", resp.Choices[0].Message.Content)
+
+	strict := &OutputFilter{Blocklist: []string{"password"}, Action: FilterReject}
+	ctx := ContextWithChatInterceptors(context.Background(), strict.Interceptor())
+	_, err = client.SimpleChatCompletion(ctx, "test-model", "hi")
+	assert.ErrorIs(t, err, ErrOutputRejected)
+}
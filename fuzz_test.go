@@ -0,0 +1,55 @@
+package vultrai
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func FuzzStreamReaderRecv(f *testing.F) {
+	f.Add("data: {\"id\":\"chat-123\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hello\"}}]}\n\ndata: [DONE]\n\n")
+	f.Add("data: {\"id\":\"chat-123\",\"choices\":[{\"delta\":{\"content\":\"trunc")
+	f.Add(": keep-alive comment\n\ndata: {\"choices\":[]}\r\n\r\n")
+	f.Add("event: error\ndata: {\"message\":\"overloaded\"}\n\n")
+	f.Add("data: \xff\xfe\xfd\n\n")
+	f.Add("data: " + strings.Repeat("x", 70*1024) + "\n\n")
+	f.Add("data:\ndata: [DONE]\n")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		reader := NewStreamReader(io.NopCloser(strings.NewReader(input)))
+		defer reader.Close()
+
+		// Every stream must terminate with EOF or an error, never loop or panic
+		for i := 0; i <= len(input)+1; i++ {
+			chunk, err := reader.Recv()
+			if err != nil {
+				return
+			}
+			if chunk == nil {
+				t.Fatal("nil chunk returned without error")
+			}
+		}
+		t.Fatal("stream did not terminate")
+	})
+}
+
+func FuzzRepairJSON(f *testing.F) {
+	f.Add(`{"a": [1, 2, ], 'b': True}`)
+	f.Add("```json\n{\"a\": \"unterminated")
+	f.Add(`{name: "x" "y": {"z": [1 2 3`)
+	f.Add(`{"quote": "He said "hi" to me"} trailing`)
+	f.Add(`[/* c */ +1, .5, -, e, "\q"]`)
+	f.Add("[\"\\\x1e")
+	f.Add("{[")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		repaired := RepairJSON(input)
+		// Whenever a container was found the result must be valid JSON
+		if strings.HasPrefix(repaired, "{") || strings.HasPrefix(repaired, "[") {
+			if !json.Valid([]byte(repaired)) {
+				t.Fatalf("RepairJSON(%q) = %q is not valid JSON", input, repaired)
+			}
+		}
+	})
+}
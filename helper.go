@@ -75,6 +75,14 @@ func WithStream(stream bool) ChatOption {
 	}
 }
 
+// WithStreamIncludeUsage asks for a final stream chunk carrying the token
+// usage of the completion. It only applies to streaming requests.
+func WithStreamIncludeUsage(include bool) ChatOption {
+	return func(req *ChatCompletionRequest) {
+		req.StreamOptions = &StreamOptions{IncludeUsage: include}
+	}
+}
+
 // WithFrequencyPenalty sets the frequency penalty
 func WithFrequencyPenalty(penalty float64) ChatOption {
 	return func(req *ChatCompletionRequest) {
@@ -160,6 +168,69 @@ func WithImageFormat(format string) ImageOption {
 	}
 }
 
+// Defaults used by SimpleSpeech
+const (
+	DefaultTTSModel  = "tts-1"
+	DefaultTTSVoice  = "alloy"
+	DefaultTTSFormat = "mp3"
+)
+
+// SimpleSpeech is a helper function for text-to-speech with the default
+// model, voice and format, which options can override
+func (c *Client) SimpleSpeech(ctx context.Context, text string, options ...TTSOption) ([]byte, error) {
+	req := TTSRequest{
+		Model:          DefaultTTSModel,
+		Input:          text,
+		Voice:          DefaultTTSVoice,
+		ResponseFormat: DefaultTTSFormat,
+	}
+
+	// Apply options
+	for _, option := range options {
+		option(&req)
+	}
+
+	return c.CreateSpeech(ctx, req)
+}
+
+// TTSOption represents a function to configure text-to-speech requests
+type TTSOption func(*TTSRequest)
+
+// WithSpeechModel sets the model for text-to-speech
+func WithSpeechModel(model string) TTSOption {
+	return func(req *TTSRequest) {
+		req.Model = model
+	}
+}
+
+// WithVoice sets the voice
+func WithVoice(voice string) TTSOption {
+	return func(req *TTSRequest) {
+		req.Voice = voice
+	}
+}
+
+// WithSpeechSpeed sets the speaking rate, 1.0 being normal
+func WithSpeechSpeed(speed float64) TTSOption {
+	return func(req *TTSRequest) {
+		req.Speed = &speed
+	}
+}
+
+// WithSpeechFormat sets the audio format: mp3, wav, ogg or pcm
+func WithSpeechFormat(format string) TTSOption {
+	return func(req *TTSRequest) {
+		req.ResponseFormat = format
+	}
+}
+
+// WithSpeechLanguage sets the language of the input text
+func WithSpeechLanguage(language string) TTSOption {
+	return func(req *TTSRequest) {
+		req.Language = language
+	}
+}
+
 // CreateSystemMessage creates a system message
 func CreateSystemMessage(content string) Message {
 	return Message{
@@ -0,0 +1,225 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	defaultKeepRecent    = 4
+	defaultSummaryPrompt = "Summarize the following conversation so it can replace the original turns. " +
+		"Preserve facts, names, numbers, decisions and open questions. Be concise."
+	summaryMessagePrefix = "Summary of the earlier conversation:\n"
+)
+
+// HistoryTrimmer reduces a message history so it fits within a token budget
+type HistoryTrimmer interface {
+	Trim(ctx context.Context, messages []Message, budget int) ([]Message, error)
+}
+
+// SummarizingTrimmer is a HistoryTrimmer that, once the history exceeds the
+// budget, summarizes older turns with a chat completion and replaces them
+// with a single system message. System messages and the most recent turns
+// are kept verbatim; a summary left by a previous trim is folded into the
+// new one.
+type SummarizingTrimmer struct {
+	client *Client
+	model  string
+
+	// KeepRecent is the number of most recent messages kept verbatim
+	KeepRecent int
+	// Prompt is the instruction used to summarize older turns
+	Prompt string
+	// MaxSummaryTokens bounds the length of the generated summary
+	MaxSummaryTokens int
+}
+
+// NewSummarizingTrimmer creates a trimmer that summarizes with model, which
+// is typically a small, inexpensive model
+func NewSummarizingTrimmer(client *Client, model string) *SummarizingTrimmer {
+	return &SummarizingTrimmer{
+		client:           client,
+		model:            model,
+		KeepRecent:       defaultKeepRecent,
+		Prompt:           defaultSummaryPrompt,
+		MaxSummaryTokens: 256,
+	}
+}
+
+// Trim summarizes older turns when messages exceed budget tokens
+func (s *SummarizingTrimmer) Trim(ctx context.Context, messages []Message, budget int) ([]Message, error) {
+	if EstimateMessagesTokens(messages) <= budget {
+		return messages, nil
+	}
+
+	leading, older, recent := splitHistory(messages, s.KeepRecent)
+	if len(older) == 0 {
+		return messages, nil
+	}
+
+	var system []Message
+	var previous string
+	for _, msg := range leading {
+		if strings.HasPrefix(msg.Content, summaryMessagePrefix) {
+			previous = strings.TrimPrefix(msg.Content, summaryMessagePrefix)
+			continue
+		}
+		system = append(system, msg)
+	}
+
+	summary, err := s.summarize(ctx, previous, older)
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := make([]Message, 0, len(system)+1+len(recent))
+	trimmed = append(trimmed, system...)
+	trimmed = append(trimmed, CreateSystemMessage(summaryMessagePrefix+summary))
+	trimmed = append(trimmed, recent...)
+	return trimmed, nil
+}
+
+// DropOldestTrimmer is a HistoryTrimmer that drops the oldest turns until
+// the history fits. System messages and the last message are always kept.
+type DropOldestTrimmer struct{}
+
+// Trim drops the oldest non-system messages while messages exceed budget
+// tokens, along with tool results orphaned by dropping their call
+func (DropOldestTrimmer) Trim(ctx context.Context, messages []Message, budget int) ([]Message, error) {
+	system, rest, _ := splitHistory(messages, 0)
+	total := EstimateMessagesTokens(messages)
+
+	drop := 0
+	for drop < len(rest)-1 && (total > budget || rest[drop].Role == "tool") {
+		total -= EstimateMessagesTokens(rest[drop : drop+1])
+		drop++
+	}
+	if drop == 0 {
+		return messages, nil
+	}
+
+	trimmed := make([]Message, 0, len(system)+len(rest)-drop)
+	trimmed = append(trimmed, system...)
+	return append(trimmed, rest[drop:]...), nil
+}
+
+// KeepRecentTrimmer is a HistoryTrimmer that, once the history exceeds the
+// budget, keeps only the system messages and the Recent most recent
+// messages
+type KeepRecentTrimmer struct {
+	// Recent is the number of messages kept besides system messages
+	// (default 4)
+	Recent int
+}
+
+// Trim drops everything but system messages and the most recent turns when
+// messages exceed budget tokens
+func (k KeepRecentTrimmer) Trim(ctx context.Context, messages []Message, budget int) ([]Message, error) {
+	if EstimateMessagesTokens(messages) <= budget {
+		return messages, nil
+	}
+	recentCount := k.Recent
+	if recentCount <= 0 {
+		recentCount = defaultKeepRecent
+	}
+
+	system, older, recent := splitHistory(messages, recentCount)
+	if len(older) == 0 {
+		return messages, nil
+	}
+	trimmed := make([]Message, 0, len(system)+len(recent))
+	trimmed = append(trimmed, system...)
+	return append(trimmed, recent...), nil
+}
+
+// TrimmerChain is a HistoryTrimmer applying its trimmers in turn until the
+// history fits the budget
+type TrimmerChain []HistoryTrimmer
+
+// Trim runs each trimmer on the result of the previous one, stopping once
+// messages fit within budget tokens
+func (t TrimmerChain) Trim(ctx context.Context, messages []Message, budget int) ([]Message, error) {
+	for _, trimmer := range t {
+		if EstimateMessagesTokens(messages) <= budget {
+			break
+		}
+		trimmed, err := trimmer.Trim(ctx, messages, budget)
+		if err != nil {
+			return nil, err
+		}
+		messages = trimmed
+	}
+	return messages, nil
+}
+
+// NewSummarizeThenDropTrimmer creates a trimmer that summarizes older turns
+// with model, then drops the oldest remaining turns if the history still
+// doesn't fit
+func NewSummarizeThenDropTrimmer(client *Client, model string) TrimmerChain {
+	return TrimmerChain{NewSummarizingTrimmer(client, model), DropOldestTrimmer{}}
+}
+
+func (s *SummarizingTrimmer) summarize(ctx context.Context, previous string, messages []Message) (string, error) {
+	transcript := FormatTranscript(messages)
+	if previous != "" {
+		transcript = "Summary so far:\n" + previous + "\n\nConversation:\n" + transcript
+	}
+	req := ChatCompletionRequest{
+		Model: s.model,
+		Messages: []Message{
+			CreateSystemMessage(s.Prompt),
+			CreateUserMessage(transcript),
+		},
+		Temperature: Float64(0),
+	}
+	if s.MaxSummaryTokens > 0 {
+		req.MaxTokens = Int(s.MaxSummaryTokens)
+	}
+
+	resp, err := s.client.CreateChatCompletion(withoutRequestOptions(ctx), req)
+	if err != nil {
+		return "", fmt.Errorf("error summarizing history: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", errors.New("error summarizing history: no choices returned")
+	}
+
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
+
+// splitHistory separates leading system messages, older turns and the most
+// recent keepRecent messages. The recent window never starts with a tool
+// result, since that would orphan it from its tool call.
+func splitHistory(messages []Message, keepRecent int) (system, older, recent []Message) {
+	start := 0
+	for start < len(messages) && messages[start].Role == "system" {
+		start++
+	}
+	system = messages[:start]
+	rest := messages[start:]
+
+	cut := len(rest) - keepRecent
+	if cut < 0 {
+		cut = 0
+	}
+	for cut > 0 && cut < len(rest) && rest[cut].Role == "tool" {
+		cut--
+	}
+
+	return system, rest[:cut], rest[cut:]
+}
+
+// FormatTranscript renders messages as a plain "role: content" transcript
+func FormatTranscript(messages []Message) string {
+	var sb strings.Builder
+	for _, msg := range messages {
+		content := msg.Content
+		for _, call := range msg.ToolCalls {
+			content += fmt.Sprintf(" [called %s(%s)]", call.Function.Name, call.Function.Arguments)
+		}
+		fmt.Fprintf(&sb, "%s: %s\n", msg.Role, strings.TrimSpace(content))
+	}
+	return sb.String()
+}
@@ -0,0 +1,159 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, EstimateTokens(""))
+	assert.Equal(t, 1, EstimateTokens("abc"))
+	assert.Equal(t, 3, EstimateTokens("hello world!"))
+	assert.Equal(t, 3, EstimateTokens("سلام دنیا"))
+
+	messages := []Message{CreateUserMessage("hello world!")}
+	assert.Equal(t, 3+messageTokenOverhead, EstimateMessagesTokens(messages))
+}
+
+func TestSummarizingTrimmer(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+	mockTransport.SetResponse("POST", "/chat/completions", 200, &ChatCompletionResponse{
+		Choices: []Choice{{Message: Message{Role: "assistant", Content: "User's name is Sam."}}},
+	})
+
+	long := strings.Repeat("word ", 100)
+	messages := []Message{
+		CreateSystemMessage("You are helpful."),
+		CreateUserMessage("My name is Sam. " + long),
+		CreateAssistantMessage("Nice to meet you. " + long),
+		CreateUserMessage("Tell me a joke"),
+		CreateAssistantMessage("Why did the gopher cross the road?"),
+	}
+
+	trimmer := NewSummarizingTrimmer(client, "small-model")
+	trimmer.KeepRecent = 2
+
+	trimmed, err := trimmer.Trim(context.Background(), messages, 50)
+	require.NoError(t, err)
+
+	require.Len(t, trimmed, 4)
+	assert.Equal(t, "You are helpful.", trimmed[0].Content)
+	assert.Equal(t, "system", trimmed[1].Role)
+	assert.Contains(t, trimmed[1].Content, "User's name is Sam.")
+	assert.Equal(t, messages[3:], trimmed[2:])
+
+	requests := mockTransport.GetRequests()
+	require.Len(t, requests, 1)
+	var req ChatCompletionRequest
+	require.NoError(t, json.NewDecoder(requests[0].Body).Decode(&req))
+	assert.Equal(t, "small-model", req.Model)
+	assert.Contains(t, req.Messages[1].Content, "user: My name is Sam.")
+}
+
+func TestSummarizingTrimmerFoldsPreviousSummary(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+	trimmer := NewSummarizingTrimmer(client, "small-model")
+	trimmer.KeepRecent = 2
+	long := strings.Repeat("word ", 100)
+
+	messages := []Message{CreateSystemMessage("You are helpful.")}
+	for i, fact := range []string{"Sam", "Alex", "Kim"} {
+		mockTransport.SetResponse("POST", "/chat/completions", 200, &ChatCompletionResponse{
+			Choices: []Choice{{Message: CreateAssistantMessage(fmt.Sprintf("summary %d", i+1))}},
+		})
+		messages = append(messages,
+			CreateUserMessage("I met "+fact+". "+long),
+			CreateAssistantMessage("Noted. "+long),
+			CreateUserMessage("ok"),
+			CreateAssistantMessage("ok"),
+		)
+		trimmed, err := trimmer.Trim(context.Background(), messages, 50)
+		require.NoError(t, err)
+		messages = trimmed
+	}
+
+	// One summary message remains, built on the previous ones
+	require.Len(t, messages, 4)
+	assert.Equal(t, "You are helpful.", messages[0].Content)
+	assert.Equal(t, summaryMessagePrefix+"summary 3", messages[1].Content)
+
+	requests := mockTransport.GetRequests()
+	require.Len(t, requests, 3)
+	var req ChatCompletionRequest
+	require.NoError(t, json.NewDecoder(requests[2].Body).Decode(&req))
+	assert.Contains(t, req.Messages[1].Content, "Summary so far:\nsummary 2")
+	assert.Contains(t, req.Messages[1].Content, "user: I met Kim.")
+	assert.NotContains(t, req.Messages[1].Content, summaryMessagePrefix)
+}
+
+func TestSummarizingTrimmerUnderBudget(t *testing.T) {
+	client, mockTransport := setupTestClient()
+
+	messages := []Message{CreateUserMessage("hi")}
+	trimmed, err := NewSummarizingTrimmer(client, "small-model").Trim(context.Background(), messages, 100)
+	require.NoError(t, err)
+	assert.Equal(t, messages, trimmed)
+	assert.Empty(t, mockTransport.GetRequests())
+}
+
+func TestSplitHistoryKeepsToolResultsWithCalls(t *testing.T) {
+	messages := []Message{
+		CreateUserMessage("q"),
+		{Role: "assistant", ToolCalls: []ToolCall{{ID: "1"}}},
+		{Role: "tool", Content: "r", ToolCallID: "1"},
+		CreateAssistantMessage("a"),
+	}
+
+	_, older, recent := splitHistory(messages, 2)
+	assert.Len(t, older, 1)
+	assert.Equal(t, "assistant", recent[0].Role)
+}
+
+func TestKeepRecentTrimmer(t *testing.T) {
+	long := strings.Repeat("word ", 100)
+	messages := []Message{
+		CreateSystemMessage("You are helpful."),
+		CreateUserMessage(long),
+		CreateAssistantMessage(long),
+		CreateUserMessage("Tell me a joke"),
+	}
+
+	trimmed, err := KeepRecentTrimmer{Recent: 1}.Trim(context.Background(), messages, 50)
+	require.NoError(t, err)
+	assert.Equal(t, []Message{messages[0], messages[3]}, trimmed)
+
+	trimmed, err = KeepRecentTrimmer{Recent: 1}.Trim(context.Background(), messages, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, messages, trimmed)
+}
+
+func TestSummarizeThenDropTrimmer(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+	mockTransport.SetResponse("POST", "/chat/completions", 200, &ChatCompletionResponse{
+		Choices: []Choice{{Message: Message{Role: "assistant", Content: "Small talk."}}},
+	})
+
+	long := strings.Repeat("word ", 100)
+	messages := []Message{
+		CreateUserMessage("Hello"),
+		CreateAssistantMessage("Hi"),
+		CreateUserMessage(long),
+		CreateAssistantMessage(long),
+		CreateUserMessage("Tell me a joke"),
+	}
+
+	// The recent turns kept by the summary are still too long, so the
+	// oldest are dropped afterwards
+	trimmed, err := NewSummarizeThenDropTrimmer(client, "small-model").Trim(context.Background(), messages, 50)
+	require.NoError(t, err)
+	require.Len(t, mockTransport.GetRequests(), 1)
+	require.Len(t, trimmed, 2)
+	assert.Contains(t, trimmed[0].Content, "Small talk.")
+	assert.Equal(t, messages[4], trimmed[1])
+}
@@ -0,0 +1,176 @@
+package vultrai
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Event types sent by HTMLStreamFormatter for htmx
+const (
+	// HTMLBlockEvent carries a complete block to append to the answer
+	HTMLBlockEvent = "block"
+	// HTMLPendingEvent carries a preview of the incomplete block to swap
+	// into a placeholder
+	HTMLPendingEvent = "pending"
+	// HTMLDoneEvent marks the end of the answer
+	HTMLDoneEvent = "done"
+)
+
+// HTMLStreamFormatter converts streamed markdown into sanitized HTML
+// fragments for server-rendered pages. Complete blocks are sent once and
+// never change; the incomplete tail is sent as an escaped plain-text
+// preview that is replaced as it grows.
+//
+// For htmx's SSE extension, swap HTMLBlockEvent before the end of the
+// answer element and HTMLPendingEvent into a placeholder after it. With
+// Turbo set, every event is a <turbo-stream> message appending to the
+// element with id Target and updating the element with id Target+"-pending".
+type HTMLStreamFormatter struct {
+	Target string
+	Turbo  bool
+
+	md      *MarkdownStream
+	blocks  []string
+	pending string
+}
+
+// NewHTMLStreamFormatter creates a formatter for the answer element with
+// id target
+func NewHTMLStreamFormatter(target string, turbo bool) *HTMLStreamFormatter {
+	f := &HTMLStreamFormatter{Target: target, Turbo: turbo}
+	f.md = NewMarkdownStream(func(e MarkdownEvent) error {
+		f.blocks = append(f.blocks, e.HTML())
+		return nil
+	})
+	return f
+}
+
+// Format renders the blocks completed by chunk and the new preview
+func (f *HTMLStreamFormatter) Format(chunk *StreamChatCompletion) ([]SSEEvent, error) {
+	if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+		return nil, nil
+	}
+	if err := f.md.Write(chunk.Choices[0].Delta.Content); err != nil {
+		return nil, err
+	}
+	return f.events(), nil
+}
+
+// Finish renders the last block and clears the preview
+func (f *HTMLStreamFormatter) Finish() ([]SSEEvent, error) {
+	if err := f.md.Flush(); err != nil {
+		return nil, err
+	}
+	events := f.events()
+	if f.Turbo {
+		return events, nil
+	}
+	return append(events, SSEEvent{Event: HTMLDoneEvent}), nil
+}
+
+// events drains the rendered blocks and adds the preview if it changed
+func (f *HTMLStreamFormatter) events() []SSEEvent {
+	var events []SSEEvent
+	for _, block := range f.blocks {
+		if f.Turbo {
+			events = append(events, SSEEvent{Data: turboStream("append", f.Target, block)})
+		} else {
+			events = append(events, SSEEvent{Event: HTMLBlockEvent, Data: block})
+		}
+	}
+	f.blocks = f.blocks[:0]
+
+	pending := strings.TrimSpace(f.md.Pending())
+	if pending == f.pending {
+		return events
+	}
+	f.pending = pending
+	preview := ""
+	if pending != "" {
+		preview = `<p class="pending">` + strings.ReplaceAll(html.EscapeString(pending), "\n", "<br>") + "</p>"
+	}
+	if f.Turbo {
+		events = append(events, SSEEvent{Data: turboStream("update", f.Target+"-pending", preview)})
+	} else {
+		events = append(events, SSEEvent{Event: HTMLPendingEvent, Data: preview})
+	}
+	return events
+}
+
+func turboStream(action, target, content string) string {
+	return fmt.Sprintf(`<turbo-stream action="%s" target="%s"><template>%s</template></turbo-stream>`,
+		action, html.EscapeString(target), content)
+}
+
+var codeLanguage = regexp.MustCompile(`^[A-Za-z0-9_+#.-]+$`)
+
+// HTML renders the block as sanitized HTML. All text is escaped; inline
+// code, emphasis and links with http, https or mailto URLs are rendered.
+// List items are rendered as single-item lists so each block stands alone.
+func (e MarkdownEvent) HTML() string {
+	switch e.Type {
+	case MarkdownHeading:
+		return fmt.Sprintf("<h%d>%s</h%d>", e.Level, renderInline(e.Text), e.Level)
+	case MarkdownListItem:
+		class := ""
+		if e.Level > 0 {
+			class = fmt.Sprintf(` class="level-%d"`, e.Level)
+		}
+		item := "<li>" + renderInline(e.Text) + "</li>"
+		if e.Ordered {
+			return fmt.Sprintf(`<ol start="%d"%s>%s</ol>`, e.Number, class, item)
+		}
+		return "<ul" + class + ">" + item + "</ul>"
+	case MarkdownCodeBlock:
+		class := ""
+		if lang, _, _ := strings.Cut(e.Language, " "); codeLanguage.MatchString(lang) {
+			class = ` class="language-` + lang + `"`
+		}
+		return "<pre><code" + class + ">" + html.EscapeString(e.Text) + "</code></pre>"
+	case MarkdownQuote:
+		return "<blockquote><p>" + renderInline(e.Text) + "</p></blockquote>"
+	case MarkdownRule:
+		return "<hr>"
+	default:
+		return "<p>" + renderInline(e.Text) + "</p>"
+	}
+}
+
+var (
+	inlineCode   = regexp.MustCompile("`([^`]+)`")
+	inlineStrong = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	inlineEm     = regexp.MustCompile(`\*([^*]+)\*|\b_([^_]+)_\b`)
+	inlineLink   = regexp.MustCompile(`\[([^\]]+)\]\(([^()\s]+)\)`)
+	safeURL      = regexp.MustCompile(`(?i)^(?:https?://|mailto:)`)
+)
+
+// renderInline escapes text and renders inline markdown. Code spans are
+// rendered first so their content is left alone.
+func renderInline(text string) string {
+	var sb strings.Builder
+	last := 0
+	for _, m := range inlineCode.FindAllStringSubmatchIndex(text, -1) {
+		sb.WriteString(renderSpans(text[last:m[0]]))
+		sb.WriteString("<code>" + html.EscapeString(text[m[2]:m[3]]) + "</code>")
+		last = m[1]
+	}
+	sb.WriteString(renderSpans(text[last:]))
+	return strings.ReplaceAll(sb.String(), "\n", "<br>")
+}
+
+// renderSpans renders emphasis and links in text without code spans
+func renderSpans(text string) string {
+	s := html.EscapeString(text)
+	s = inlineLink.ReplaceAllStringFunc(s, func(m string) string {
+		parts := inlineLink.FindStringSubmatch(m)
+		if !safeURL.MatchString(html.UnescapeString(parts[2])) {
+			return parts[1]
+		}
+		return `<a href="` + parts[2] + `" rel="noopener noreferrer">` + parts[1] + "</a>"
+	})
+	s = inlineStrong.ReplaceAllString(s, "<strong>$1$2</strong>")
+	s = inlineEm.ReplaceAllString(s, "<em>$1$2</em>")
+	return s
+}
@@ -0,0 +1,97 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// ErrNoImageData is returned when an ImageData has neither b64_json data
+// nor a URL
+var ErrNoImageData = errors.New("image has no data")
+
+// Decode returns the image bytes of a b64_json result
+func (d ImageData) Decode() ([]byte, error) {
+	if d.B64JSON == "" {
+		if d.URL != "" {
+			return nil, errors.New("image is a URL result; use Download")
+		}
+		return nil, ErrNoImageData
+	}
+	data, err := base64.StdEncoding.DecodeString(d.B64JSON)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %w", err)
+	}
+	return data, nil
+}
+
+// imageDownloadRoute is the route image downloads are traced and measured
+// under
+const imageDownloadRoute = "images/download"
+
+// Download fetches the image of a URL result through the HTTP client of
+// client, so its transport, timeouts, tracing and metrics apply, or with a
+// client using the default timeout when nil. The API key isn't sent.
+// b64_json results are decoded without a request.
+func (d ImageData) Download(ctx context.Context, client *Client) ([]byte, error) {
+	if d.URL == "" {
+		return d.Decode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", d.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	var resp *http.Response
+	if client == nil {
+		resp, err = (&http.Client{Timeout: defaultTimeout}).Do(req)
+	} else {
+		resp, err = client.download(req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error downloading image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("error downloading image: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading image: %w", err)
+	}
+	return data, nil
+}
+
+// download performs req, which doesn't target the API, with the HTTP
+// client of c. Unlike sendOnce it doesn't sign req.
+func (c *Client) download(req *http.Request) (*http.Response, error) {
+	req, obs := c.observeRoute(req, imageDownloadRoute)
+	resp, err := c.httpClientFor(req).Do(req)
+	if err != nil {
+		obs.end(err)
+		return nil, err
+	}
+	obs.setStatus(resp.StatusCode)
+	if obs != nil {
+		resp.Body = &observedBody{ReadCloser: resp.Body, obs: obs}
+	}
+	return resp, nil
+}
+
+// SaveToFile writes the image to path, decoding b64_json results and
+// downloading URL results with the default timeout
+func (d ImageData) SaveToFile(path string) error {
+	data, err := d.Download(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error saving image: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageDataDecode(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\nfake")
+	data, err := ImageData{B64JSON: base64.StdEncoding.EncodeToString(png)}.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, png, data)
+
+	_, err = ImageData{B64JSON: "not base64!"}.Decode()
+	assert.Error(t, err)
+	_, err = ImageData{URL: "https://example.com/a.png"}.Decode()
+	assert.ErrorContains(t, err, "Download")
+	_, err = ImageData{}.Decode()
+	assert.ErrorIs(t, err, ErrNoImageData)
+}
+
+func TestImageDataDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+		if r.URL.Path != "/image.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("png-bytes"))
+	}))
+	defer server.Close()
+
+	// Downloads go through the client without its API key
+	tracer := &recordingTracer{}
+	client := NewClient("key", WithHTTPClient(server.Client()), WithTracer(tracer))
+	data, err := ImageData{URL: server.URL + "/image.png"}.Download(context.Background(), client)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("png-bytes"), data)
+	require.Len(t, tracer.spans, 1)
+	assert.Equal(t, "GET images/download", tracer.spans[0].name)
+	assert.Equal(t, 200, tracer.spans[0].attrs[AttrStatusCode])
+	assert.True(t, tracer.spans[0].ended)
+
+	_, err = ImageData{URL: server.URL + "/missing.png"}.Download(context.Background(), nil)
+	assert.ErrorContains(t, err, "HTTP 404")
+
+	path := filepath.Join(t.TempDir(), "out.png")
+	require.NoError(t, ImageData{URL: server.URL + "/image.png"}.SaveToFile(path))
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("png-bytes"), saved)
+
+	require.NoError(t, ImageData{B64JSON: base64.StdEncoding.EncodeToString([]byte("b64"))}.SaveToFile(path))
+	saved, _ = os.ReadFile(path)
+	assert.Equal(t, []byte("b64"), saved)
+}
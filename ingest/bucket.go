@@ -0,0 +1,173 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/eqba1/vultrai/loaders"
+)
+
+// BucketSyncOptions configures SyncBucket. The embedded Options control
+// chunking; HTTP settings come from the S3Client.
+type BucketSyncOptions struct {
+	Options
+
+	// Prefix limits the sync to keys starting with it
+	Prefix string
+	// Manifest records what has been ingested. When nil it is loaded from
+	// ManifestPath, or starts empty.
+	Manifest *Manifest
+	// ManifestPath, when set, is where the manifest is saved after every
+	// object, so an interrupted sync resumes where it stopped
+	ManifestPath string
+	// KeepDeleted leaves items of objects removed from the bucket in the
+	// collection instead of deleting them
+	KeepDeleted bool
+	// Progress is called after each object is processed
+	Progress func(Progress)
+}
+
+// SyncResult summarizes an incremental sync
+type SyncResult struct {
+	// Added lists sources ingested for the first time
+	Added []string
+	// Updated lists sources re-ingested because their content changed
+	Updated []string
+	// Removed lists sources whose items were deleted because the source
+	// no longer exists
+	Removed []string
+	// Unchanged counts sources whose content matched the manifest
+	Unchanged int
+	// Skipped counts folders and files without a matching loader
+	Skipped int
+	Errors  map[string]error
+}
+
+func (r *SyncResult) record(source string, change Change, err error) {
+	if err != nil {
+		r.Errors[source] = err
+	}
+	switch change {
+	case Added:
+		r.Added = append(r.Added, source)
+	case Updated:
+		r.Updated = append(r.Updated, source)
+	default:
+		if err == nil {
+			r.Unchanged++
+		}
+	}
+}
+
+func resolveManifest(manifest *Manifest, manifestPath string) (*Manifest, error) {
+	if manifest != nil {
+		return manifest, nil
+	}
+	if manifestPath != "" {
+		return LoadManifest(manifestPath)
+	}
+	return NewManifest(), nil
+}
+
+// SyncBucket makes the collection mirror the supported documents in a
+// bucket. Objects are identified by their s3:// URI. Objects whose ETag
+// matches the manifest are not downloaded; the rest are compared by content
+// hash, so only real changes are re-ingested. Objects deleted from the
+// bucket have their items removed. Failed objects are recorded in
+// SyncResult.Errors and retried on the next run. opts may be nil.
+func SyncBucket(ctx context.Context, client ItemManager, collectionID string, bucket *S3Client, opts *BucketSyncOptions) (*SyncResult, error) {
+	var o BucketSyncOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	manifest, err := resolveManifest(o.Manifest, o.ManifestPath)
+	if err != nil {
+		return nil, err
+	}
+	indexer := NewIndexer(client, collectionID, manifest, &o.Options)
+
+	objects, err := bucket.ListObjects(ctx, o.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{Errors: make(map[string]error)}
+	present := make(map[string]bool, len(objects))
+	for i, obj := range objects {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		uri := bucket.ObjectURI(obj.Key)
+		if _, ok := loaders.ForFile(path.Base(obj.Key)); !ok || strings.HasSuffix(obj.Key, "/") {
+			result.Skipped++
+			continue
+		}
+		present[uri] = true
+
+		if previous, seen := manifest.Get(uri); seen && previous.ETag != "" && previous.ETag == obj.ETag {
+			result.Unchanged++
+			continue
+		}
+
+		change, err := syncObject(ctx, indexer, bucket, obj, indexer.opts.MaxBytes)
+		result.record(uri, change, err)
+		if o.ManifestPath != "" && change != Unchanged {
+			if err := manifest.Save(o.ManifestPath); err != nil {
+				return result, err
+			}
+		}
+
+		if o.Progress != nil {
+			o.Progress(Progress{
+				URL:    uri,
+				Done:   i + 1,
+				Queued: len(objects) - i - 1,
+				Err:    err,
+			})
+		}
+	}
+
+	if !o.KeepDeleted {
+		removed, err := indexer.Prune(ctx, bucket.ObjectURI(o.Prefix), present)
+		result.Removed = removed
+		if err != nil {
+			result.Errors[bucket.ObjectURI(o.Prefix)] = err
+		}
+	}
+	if o.ManifestPath != "" {
+		if err := manifest.Save(o.ManifestPath); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+func syncObject(ctx context.Context, indexer *Indexer, bucket *S3Client, obj S3Object, maxBytes int64) (Change, error) {
+	body, err := bucket.GetObject(ctx, obj.Key)
+	if err != nil {
+		return Unchanged, fmt.Errorf("error downloading %s: %w", obj.Key, err)
+	}
+	defer body.Close()
+
+	doc, err := loaders.Load(ctx, obj.Key, io.LimitReader(body, maxBytes))
+	if err != nil {
+		return Unchanged, err
+	}
+	doc.Metadata[loaders.MetaSource] = bucket.ObjectURI(obj.Key)
+
+	return indexer.Upsert(ctx, doc, obj.ETag)
+}
+
+func itemIDs(res *Result) []string {
+	ids := make([]string, 0, len(res.Items))
+	for _, item := range res.Items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
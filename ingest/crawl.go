@@ -0,0 +1,438 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Default crawl limits applied when CrawlOptions fields are left zero
+const (
+	DefaultMaxPages = 100
+	DefaultMaxDepth = 3
+)
+
+// CrawlOptions bounds a crawl. The embedded Options control fetching and
+// chunking of each page.
+type CrawlOptions struct {
+	Options
+
+	// MaxPages caps the number of pages fetched, including failures
+	MaxPages int
+	// MaxDepth is the number of links followed from the start page.
+	// Use a negative value to ingest only the start page.
+	MaxDepth int
+	// Include lists regular expressions matched against page URLs. When
+	// set, only URLs matching at least one are crawled.
+	Include []string
+	// Exclude lists regular expressions for URLs that are never crawled
+	Exclude []string
+	// IgnoreRobots skips robots.txt checks
+	IgnoreRobots bool
+	// Delay is the pause between requests. A larger Crawl-delay from
+	// robots.txt takes precedence.
+	Delay time.Duration
+	// Progress is called after each page is processed
+	Progress func(Progress)
+}
+
+// Progress reports the outcome of one crawled page
+type Progress struct {
+	URL   string
+	Depth int
+	// Done is the number of pages processed so far, including this one
+	Done int
+	// Queued is the number of pages waiting to be fetched
+	Queued int
+	// Result is set when the page was ingested
+	Result *Result
+	// Err is set when fetching or ingesting the page failed
+	Err error
+}
+
+// CrawlResult summarizes a crawl
+type CrawlResult struct {
+	Pages  []*Result
+	Errors map[string]error
+	// Skipped counts discovered URLs excluded by patterns or robots.txt
+	Skipped int
+}
+
+// ItemCount returns the total number of collection items created
+func (r *CrawlResult) ItemCount() int {
+	n := 0
+	for _, p := range r.Pages {
+		n += len(p.Items)
+	}
+	return n
+}
+
+type crawlTarget struct {
+	url   *neturl.URL
+	depth int
+}
+
+type crawler struct {
+	client       ItemAdder
+	collectionID string
+	opts         CrawlOptions
+	include      []*regexp.Regexp
+	exclude      []*regexp.Regexp
+	robots       map[string]*robots
+	result       *CrawlResult
+	done         int
+	lastFetch    time.Time
+}
+
+func newCrawler(client ItemAdder, collectionID string, opts *CrawlOptions) (*crawler, error) {
+	var o CrawlOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.Options = o.Options.withDefaults()
+	if o.MaxPages <= 0 {
+		o.MaxPages = DefaultMaxPages
+	}
+	if o.MaxDepth == 0 {
+		o.MaxDepth = DefaultMaxDepth
+	}
+
+	c := &crawler{
+		client:       client,
+		collectionID: collectionID,
+		opts:         o,
+		robots:       make(map[string]*robots),
+		result:       &CrawlResult{Errors: make(map[string]error)},
+	}
+
+	var err error
+	if c.include, err = compilePatterns(o.Include); err != nil {
+		return nil, err
+	}
+	if c.exclude, err = compilePatterns(o.Exclude); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// Crawl walks a website breadth-first from startURL, following links on
+// the same host up to MaxDepth and MaxPages, and ingests every page into
+// the collection. Failed pages are recorded in CrawlResult.Errors and do not
+// stop the crawl; only context cancellation does. opts may be nil.
+func Crawl(ctx context.Context, client ItemAdder, collectionID, startURL string, opts *CrawlOptions) (*CrawlResult, error) {
+	c, err := newCrawler(client, collectionID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := neturl.Parse(startURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start URL: %w", err)
+	}
+	start.Fragment = ""
+
+	queue := []crawlTarget{{url: start}}
+	seen := map[string]bool{start.String(): true}
+
+	for len(queue) > 0 && c.done < c.opts.MaxPages {
+		if err := ctx.Err(); err != nil {
+			return c.result, err
+		}
+
+		target := queue[0]
+		queue = queue[1:]
+
+		if !c.permitted(ctx, target.url) {
+			c.result.Skipped++
+			continue
+		}
+
+		p, res, err := c.ingest(ctx, target.url)
+		if p != nil && err == nil && target.depth < c.opts.MaxDepth && p.isHTML() {
+			for _, link := range extractLinks(p) {
+				key := link.String()
+				if seen[key] || link.Host != start.Host {
+					continue
+				}
+				seen[key] = true
+				queue = append(queue, crawlTarget{url: link, depth: target.depth + 1})
+			}
+		}
+		c.report(target, len(queue), res, err)
+	}
+
+	return c.result, ctx.Err()
+}
+
+// CrawlSitemap ingests the pages listed in a sitemap.xml, following nested
+// sitemap indexes. Include, Exclude, robots.txt and MaxPages apply; link
+// depth does not. opts may be nil.
+func CrawlSitemap(ctx context.Context, client ItemAdder, collectionID, sitemapURL string, opts *CrawlOptions) (*CrawlResult, error) {
+	c, err := newCrawler(client, collectionID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	urls, err := c.sitemapURLs(ctx, sitemapURL, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, u := range urls {
+		if c.done >= c.opts.MaxPages {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return c.result, err
+		}
+		if !c.permitted(ctx, u) {
+			c.result.Skipped++
+			continue
+		}
+
+		_, res, err := c.ingest(ctx, u)
+		c.report(crawlTarget{url: u}, len(urls)-i-1, res, err)
+	}
+
+	return c.result, ctx.Err()
+}
+
+// permitted applies include/exclude patterns and robots.txt to u
+func (c *crawler) permitted(ctx context.Context, u *neturl.URL) bool {
+	raw := u.String()
+	for _, re := range c.exclude {
+		if re.MatchString(raw) {
+			return false
+		}
+	}
+	if len(c.include) > 0 {
+		matched := false
+		for _, re := range c.include {
+			if re.MatchString(raw) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if c.opts.IgnoreRobots {
+		return true
+	}
+	return c.robotsFor(ctx, u).allowed(u)
+}
+
+func (c *crawler) robotsFor(ctx context.Context, u *neturl.URL) *robots {
+	key := u.Scheme + "://" + u.Host
+	if r, ok := c.robots[key]; ok {
+		return r
+	}
+	r := fetchRobots(ctx, u, c.opts.Options)
+	c.robots[key] = r
+	return r
+}
+
+// wait enforces the delay between requests to the same site
+func (c *crawler) wait(ctx context.Context, u *neturl.URL) error {
+	delay := c.opts.Delay
+	if !c.opts.IgnoreRobots {
+		if r := c.robotsFor(ctx, u); r.crawlDelay > delay {
+			delay = r.crawlDelay
+		}
+	}
+	if c.lastFetch.IsZero() || delay <= 0 {
+		c.lastFetch = time.Now()
+		return nil
+	}
+
+	timer := time.NewTimer(time.Until(c.lastFetch.Add(delay)))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+	}
+	c.lastFetch = time.Now()
+	return nil
+}
+
+// ingest fetches u and adds it to the collection
+func (c *crawler) ingest(ctx context.Context, u *neturl.URL) (*page, *Result, error) {
+	if err := c.wait(ctx, u); err != nil {
+		return nil, nil, err
+	}
+
+	p, err := fetchPage(ctx, u.String(), c.opts.Options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc, err := p.document(ctx, c.opts.FullPage)
+	if err != nil {
+		return p, nil, err
+	}
+
+	res, err := addDocument(ctx, c.client, c.collectionID, doc, c.opts.Options)
+	return p, res, err
+}
+
+func (c *crawler) report(target crawlTarget, queued int, res *Result, err error) {
+	c.done++
+	if err != nil {
+		c.result.Errors[target.url.String()] = err
+	} else {
+		c.result.Pages = append(c.result.Pages, res)
+	}
+
+	if c.opts.Progress != nil {
+		c.opts.Progress(Progress{
+			URL:    target.url.String(),
+			Depth:  target.depth,
+			Done:   c.done,
+			Queued: queued,
+			Result: res,
+			Err:    err,
+		})
+	}
+}
+
+// skippedExtensions are links that never lead to ingestible documents
+var skippedExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true,
+	".webp": true, ".ico": true, ".css": true, ".js": true, ".zip": true,
+	".gz": true, ".tar": true, ".mp3": true, ".mp4": true, ".woff": true,
+	".woff2": true, ".ttf": true, ".exe": true, ".dmg": true,
+}
+
+// extractLinks returns the absolute http(s) links of an HTML page without
+// fragments, honoring a <base href> element
+func extractLinks(p *page) []*neturl.URL {
+	root, err := html.Parse(bytes.NewReader(p.body))
+	if err != nil {
+		return nil
+	}
+
+	base := p.url
+	var links []*neturl.URL
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Base:
+				if href := attr(n, "href"); href != "" {
+					if u, err := p.url.Parse(href); err == nil {
+						base = u
+					}
+				}
+			case atom.A:
+				if strings.Contains(attr(n, "rel"), "nofollow") {
+					break
+				}
+				if u, err := base.Parse(strings.TrimSpace(attr(n, "href"))); err == nil {
+					u.Fragment = ""
+					u.RawFragment = ""
+					if (u.Scheme == "http" || u.Scheme == "https") && !skippedExtensions[strings.ToLower(path.Ext(u.Path))] {
+						links = append(links, u)
+					}
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	return links
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// maxSitemapDepth limits how deeply sitemap indexes may nest
+const maxSitemapDepth = 3
+
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+func (c *crawler) sitemapURLs(ctx context.Context, sitemapURL string, depth int) ([]*neturl.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", sitemapURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.opts.UserAgent)
+
+	resp, err := c.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching sitemap %s: %w", sitemapURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching sitemap %s: status %d", sitemapURL, resp.StatusCode)
+	}
+
+	var doc sitemapDocument
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, c.opts.MaxBytes)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("error parsing sitemap %s: %w", sitemapURL, err)
+	}
+
+	var urls []*neturl.URL
+	for _, loc := range doc.URLs {
+		if u, err := neturl.Parse(strings.TrimSpace(loc.Loc)); err == nil && u.Host != "" {
+			urls = append(urls, u)
+		}
+	}
+
+	if depth < maxSitemapDepth {
+		for _, nested := range doc.Sitemaps {
+			if len(urls) >= c.opts.MaxPages {
+				break
+			}
+			more, err := c.sitemapURLs(ctx, strings.TrimSpace(nested.Loc), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			urls = append(urls, more...)
+		}
+	}
+
+	return urls, nil
+}
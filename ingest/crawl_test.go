@@ -0,0 +1,154 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSite(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	pages := map[string]string{
+		"/":          `<a href="/docs/a">A</a> <a href="/docs/b#top">B</a> <a href="/private/x">X</a> <a href="/logo.png">logo</a> <a href="https://other.example/">out</a>`,
+		"/docs/a":    `<a href="b">B again</a> <a href="/docs/c">C</a>`,
+		"/docs/b":    `<a href="/">home</a>`,
+		"/docs/c":    `<a href="/docs/d">D</a>`,
+		"/docs/d":    `deep`,
+		"/private/x": `secret`,
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private/\n")
+			return
+		case "/sitemap.xml":
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprintf(w, `<?xml version="1.0"?><sitemapindex><sitemap><loc>%s/pages.xml</loc></sitemap></sitemapindex>`, server.URL)
+			return
+		case "/pages.xml":
+			fmt.Fprintf(w, `<urlset><url><loc>%[1]s/docs/a</loc></url><url><loc>%[1]s/private/x</loc></url><url><loc>%[1]s/docs/d</loc></url></urlset>`, server.URL)
+			return
+		}
+
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "<html><head><title>Page %s</title></head><body><p>Content of %s.</p>%s</body></html>", r.URL.Path, r.URL.Path, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func crawledPaths(t *testing.T, result *CrawlResult) []string {
+	var paths []string
+	for _, p := range result.Pages {
+		u, err := neturl.Parse(p.URL)
+		require.NoError(t, err)
+		paths = append(paths, u.Path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestCrawl(t *testing.T) {
+	server := newTestSite(t)
+	client := newRecordingClient()
+
+	var progress []Progress
+	result, err := Crawl(context.Background(), client, "coll-123", server.URL+"/", &CrawlOptions{
+		MaxDepth: 2,
+		Progress: func(p Progress) { progress = append(progress, p) },
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/", "/docs/a", "/docs/b", "/docs/c"}, crawledPaths(t, result))
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Equal(t, 4, result.ItemCount())
+
+	require.Len(t, progress, 4)
+	assert.Equal(t, 4, progress[3].Done)
+	assert.Equal(t, 0, progress[3].Queued)
+	assert.Equal(t, 0, progress[0].Depth)
+}
+
+func TestCrawlLimits(t *testing.T) {
+	server := newTestSite(t)
+
+	result, err := Crawl(context.Background(), newRecordingClient(), "coll-123", server.URL+"/", &CrawlOptions{MaxPages: 2})
+	require.NoError(t, err)
+	assert.Len(t, result.Pages, 2)
+
+	result, err = Crawl(context.Background(), newRecordingClient(), "coll-123", server.URL+"/", &CrawlOptions{MaxDepth: -1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/"}, crawledPaths(t, result))
+
+	result, err = Crawl(context.Background(), newRecordingClient(), "coll-123", server.URL+"/", &CrawlOptions{
+		Exclude:      []string{`/docs/b`},
+		IgnoreRobots: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/", "/docs/a", "/docs/c", "/docs/d", "/private/x"}, crawledPaths(t, result))
+
+	_, err = Crawl(context.Background(), newRecordingClient(), "coll-123", server.URL, &CrawlOptions{Include: []string{"("}})
+	assert.Error(t, err)
+}
+
+func TestCrawlSitemap(t *testing.T) {
+	server := newTestSite(t)
+
+	result, err := CrawlSitemap(context.Background(), newRecordingClient(), "coll-123", server.URL+"/sitemap.xml", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/docs/a", "/docs/d"}, crawledPaths(t, result))
+	assert.Equal(t, 1, result.Skipped)
+}
+
+func TestParseRobots(t *testing.T) {
+	txt := `# comment
+User-agent: otherbot
+Disallow: /
+
+User-agent: vultrai-ingest
+User-agent: friendly
+Disallow: /admin
+Allow: /admin/public
+Disallow: /*.json$
+Crawl-delay: 2
+
+User-agent: *
+Disallow: /
+`
+	r := parseRobots(strings.NewReader(txt), "vultrai-ingest/1.0")
+
+	check := func(raw string) bool {
+		u, err := neturl.Parse(raw)
+		require.NoError(t, err)
+		return r.allowed(u)
+	}
+
+	assert.True(t, check("https://example.com/docs"))
+	assert.False(t, check("https://example.com/admin/settings"))
+	assert.True(t, check("https://example.com/admin/public/page"))
+	assert.False(t, check("https://example.com/data/file.json"))
+	assert.True(t, check("https://example.com/data/file.json?x=1"))
+	assert.Equal(t, "2s", r.crawlDelay.String())
+
+	wildcard := parseRobots(strings.NewReader(txt), "SomeBot/2.0")
+	u, _ := neturl.Parse("https://example.com/docs")
+	assert.False(t, wildcard.allowed(u))
+}
@@ -0,0 +1,94 @@
+package ingest
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/eqba1/vultrai/loaders"
+)
+
+// DirSyncOptions configures SyncDir
+type DirSyncOptions struct {
+	Options
+
+	// Manifest records what has been ingested. When nil it is loaded from
+	// ManifestPath, or starts empty.
+	Manifest *Manifest
+	// ManifestPath, when set, is where the manifest is saved after the sync
+	ManifestPath string
+	// KeepDeleted leaves items of deleted files in the collection
+	KeepDeleted bool
+}
+
+// SyncDir makes the collection mirror the supported documents under dir.
+// Files are identified by their absolute path and compared by content hash
+// against the manifest: unchanged files are skipped, changed ones are
+// re-ingested and deleted ones have their items removed. opts may be nil.
+func SyncDir(ctx context.Context, client ItemManager, collectionID, dir string, opts *DirSyncOptions) (*SyncResult, error) {
+	var o DirSyncOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := resolveManifest(o.Manifest, o.ManifestPath)
+	if err != nil {
+		return nil, err
+	}
+	indexer := NewIndexer(client, collectionID, manifest, &o.Options)
+
+	result := &SyncResult{Errors: make(map[string]error)}
+	present := make(map[string]bool)
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if _, ok := loaders.ForFile(path); !ok {
+			result.Skipped++
+			return nil
+		}
+		present[path] = true
+
+		doc, err := loaders.LoadFile(ctx, path)
+		if err != nil {
+			result.Errors[path] = err
+			return nil
+		}
+		doc.Metadata[loaders.MetaSource] = path
+
+		change, err := indexer.Upsert(ctx, doc, "")
+		result.record(path, change, err)
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if !o.KeepDeleted {
+		removed, err := indexer.Prune(ctx, root+string(os.PathSeparator), present)
+		result.Removed = removed
+		if err != nil {
+			result.Errors[root] = err
+		}
+	}
+	if o.ManifestPath != "" {
+		if err := manifest.Save(o.ManifestPath); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,156 @@
+package ingest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/eqba1/vultrai/loaders"
+)
+
+// ItemManager adds and deletes collection items. *vultrai.Client satisfies it.
+type ItemManager interface {
+	ItemAdder
+	DeleteItem(ctx context.Context, collectionID, itemID string) error
+}
+
+var _ ItemManager = (*vultrai.Client)(nil)
+
+// Change describes what Indexer.Upsert did with a document
+type Change int
+
+const (
+	// Unchanged means the document matched the manifest and was skipped
+	Unchanged Change = iota
+	// Added means the document was ingested for the first time
+	Added
+	// Updated means the document changed and its items were replaced
+	Updated
+)
+
+// ContentHash returns the SHA-256 of a document's text, used to detect changes
+func ContentHash(doc *loaders.Document) string {
+	sum := sha256.Sum256([]byte(doc.Text))
+	return hex.EncodeToString(sum[:])
+}
+
+// Indexer keeps a collection in sync with a set of documents, using a
+// manifest of content hashes so that re-running ingestion is idempotent:
+// unchanged documents are skipped, changed ones have their items replaced
+// and removed ones can be pruned.
+type Indexer struct {
+	client       ItemManager
+	collectionID string
+	manifest     *Manifest
+	opts         Options
+}
+
+// NewIndexer creates an indexer for the collection. A nil manifest starts
+// empty; opts may be nil.
+func NewIndexer(client ItemManager, collectionID string, manifest *Manifest, opts *Options) *Indexer {
+	if manifest == nil {
+		manifest = NewManifest()
+	}
+	return &Indexer{
+		client:       client,
+		collectionID: collectionID,
+		manifest:     manifest,
+		opts:         opts.withDefaults(),
+	}
+}
+
+// Manifest returns the manifest the indexer maintains
+func (ix *Indexer) Manifest() *Manifest {
+	return ix.manifest
+}
+
+// Upsert ingests doc, keyed by its source metadata, unless its content hash
+// matches the manifest. For changed documents the new items are added
+// before the previous ones are deleted, so the content never disappears
+// from search. etag is stored alongside the hash and may be empty.
+func (ix *Indexer) Upsert(ctx context.Context, doc *loaders.Document, etag string) (Change, error) {
+	source := doc.Metadata[loaders.MetaSource]
+	if source == "" {
+		return Unchanged, fmt.Errorf("document has no source")
+	}
+
+	hash := ContentHash(doc)
+	previous, seen := ix.manifest.Get(source)
+	if seen && previous.Hash == hash {
+		if previous.ETag != etag {
+			previous.ETag = etag
+			ix.manifest.Set(source, previous)
+		}
+		return Unchanged, nil
+	}
+
+	res, err := addDocument(ctx, ix.client, ix.collectionID, doc, ix.opts)
+	if err != nil {
+		// Roll back the chunks that were added so a retry starts clean
+		if res != nil {
+			ix.deleteItems(ctx, itemIDs(res))
+		}
+		return Unchanged, err
+	}
+
+	ix.manifest.Set(source, ManifestEntry{
+		Hash:      hash,
+		ETag:      etag,
+		ItemIDs:   itemIDs(res),
+		UpdatedAt: time.Now().UTC(),
+	})
+
+	if !seen {
+		return Added, nil
+	}
+	if err := ix.deleteItems(ctx, previous.ItemIDs); err != nil {
+		return Updated, fmt.Errorf("error removing previous items of %s: %w", source, err)
+	}
+	return Updated, nil
+}
+
+// Remove deletes the items ingested for source and drops it from the manifest
+func (ix *Indexer) Remove(ctx context.Context, source string) error {
+	entry, ok := ix.manifest.Get(source)
+	if !ok {
+		return nil
+	}
+	if err := ix.deleteItems(ctx, entry.ItemIDs); err != nil {
+		return fmt.Errorf("error removing %s: %w", source, err)
+	}
+	ix.manifest.Delete(source)
+	return nil
+}
+
+// Prune removes every source in the manifest that starts with prefix but is
+// not in keep, returning the removed sources
+func (ix *Indexer) Prune(ctx context.Context, prefix string, keep map[string]bool) ([]string, error) {
+	var removed []string
+	var errs []error
+	for _, source := range ix.manifest.Sources() {
+		if !strings.HasPrefix(source, prefix) || keep[source] {
+			continue
+		}
+		if err := ix.Remove(ctx, source); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed = append(removed, source)
+	}
+	return removed, errors.Join(errs...)
+}
+
+func (ix *Indexer) deleteItems(ctx context.Context, ids []string) error {
+	var errs []error
+	for _, id := range ids {
+		if err := ix.client.DeleteItem(ctx, ix.collectionID, id); err != nil {
+			errs = append(errs, fmt.Errorf("item %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,105 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/eqba1/vultrai/loaders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexerUpsert(t *testing.T) {
+	client := newRecordingClient()
+	indexer := NewIndexer(client, "coll-123", nil, nil)
+
+	doc := &loaders.Document{Text: "version one", Metadata: map[string]string{loaders.MetaSource: "doc.txt"}}
+	change, err := indexer.Upsert(context.Background(), doc, "")
+	require.NoError(t, err)
+	assert.Equal(t, Added, change)
+
+	change, err = indexer.Upsert(context.Background(), doc, "")
+	require.NoError(t, err)
+	assert.Equal(t, Unchanged, change)
+	assert.Equal(t, 1, client.CallCount("AddItem"))
+
+	first, _ := indexer.Manifest().Get("doc.txt")
+
+	doc.Text = "version two"
+	change, err = indexer.Upsert(context.Background(), doc, "")
+	require.NoError(t, err)
+	assert.Equal(t, Updated, change)
+
+	second, _ := indexer.Manifest().Get("doc.txt")
+	assert.NotEqual(t, first.Hash, second.Hash)
+	assert.NotEqual(t, first.ItemIDs, second.ItemIDs)
+
+	calls := client.Calls()
+	last := calls[len(calls)-1]
+	assert.Equal(t, "DeleteItem", last.Method)
+	assert.Equal(t, first.ItemIDs[0], last.Args[1])
+}
+
+func TestIndexerUpsertRollsBackOnFailure(t *testing.T) {
+	client := newRecordingClient()
+	add := client.AddItemFunc
+	client.AddItemFunc = func(ctx context.Context, collectionID string, req vultrai.AddItemRequest) (*vultrai.AddItemResponse, error) {
+		if client.CallCount("AddItem") > 1 {
+			return nil, errors.New("quota exceeded")
+		}
+		return add(ctx, collectionID, req)
+	}
+
+	indexer := NewIndexer(client, "coll-123", nil, &Options{ChunkSize: 20, Overlap: -1})
+	doc := &loaders.Document{Text: "one two three four five six seven eight", Metadata: map[string]string{loaders.MetaSource: "doc.txt"}}
+
+	_, err := indexer.Upsert(context.Background(), doc, "")
+	require.Error(t, err)
+	assert.Equal(t, 1, client.CallCount("DeleteItem"))
+	assert.Equal(t, 0, indexer.Manifest().Len())
+}
+
+func TestSyncDir(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(t.TempDir(), "manifest.json")
+	write := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	write("a.txt", "Alpha")
+	write("nested/b.md", "# Beta\n\nBeta text")
+	write("image.png", "binary")
+
+	client := newRecordingClient()
+	opts := &DirSyncOptions{ManifestPath: manifestPath}
+
+	result, err := SyncDir(context.Background(), client, "coll-123", dir, opts)
+	require.NoError(t, err)
+	assert.Len(t, result.Added, 2)
+	assert.Equal(t, 1, result.Skipped)
+
+	// Re-running without changes is a no-op
+	result, err = SyncDir(context.Background(), client, "coll-123", dir, opts)
+	require.NoError(t, err)
+	assert.Empty(t, result.Added)
+	assert.Equal(t, 2, result.Unchanged)
+	assert.Equal(t, 2, client.CallCount("AddItem"))
+
+	write("a.txt", "Alpha, revised")
+	require.NoError(t, os.Remove(filepath.Join(dir, "nested", "b.md")))
+
+	result, err = SyncDir(context.Background(), client, "coll-123", dir, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "a.txt")}, result.Updated)
+	assert.Equal(t, []string{filepath.Join(dir, "nested", "b.md")}, result.Removed)
+	assert.Equal(t, 2, client.CallCount("DeleteItem"))
+
+	manifest, err := LoadManifest(manifestPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "a.txt")}, manifest.Sources())
+}
@@ -0,0 +1,214 @@
+// Package ingest fetches documents from the web and adds them to vector
+// store collections, covering the "index this page" and "index our docs
+// site" workflows in a single call.
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"path"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/eqba1/vultrai/loaders"
+)
+
+// Default settings applied when Options fields are left zero
+const (
+	DefaultChunkSize = 2000
+	DefaultOverlap   = 200
+	DefaultMaxBytes  = 10 << 20
+	DefaultUserAgent = "vultrai-ingest/1.0"
+)
+
+// ItemAdder adds items to a collection. *vultrai.Client satisfies it.
+type ItemAdder interface {
+	AddItem(ctx context.Context, collectionID string, req vultrai.AddItemRequest) (*vultrai.AddItemResponse, error)
+}
+
+var _ ItemAdder = (*vultrai.Client)(nil)
+
+// Options configures fetching and chunking
+type Options struct {
+	// HTTPClient fetches pages (default http.DefaultClient)
+	HTTPClient *http.Client
+	// UserAgent is sent with every request
+	UserAgent string
+	// ChunkSize is the maximum number of characters per collection item
+	ChunkSize int
+	// Overlap is the number of characters shared by adjacent chunks
+	Overlap int
+	// MaxBytes caps the size of a fetched document
+	MaxBytes int64
+	// FullPage keeps all visible text of HTML pages instead of extracting
+	// the main article content
+	FullPage bool
+}
+
+func (o *Options) withDefaults() Options {
+	var opts Options
+	if o != nil {
+		opts = *o
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultUserAgent
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultChunkSize
+	}
+	if opts.Overlap < 0 {
+		opts.Overlap = 0
+	} else if opts.Overlap == 0 {
+		opts.Overlap = DefaultOverlap
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultMaxBytes
+	}
+	return opts
+}
+
+// Result describes an ingested document
+type Result struct {
+	// URL is the final URL of the document after redirects
+	URL   string
+	Title string
+	// Items are the collection items created from the document's chunks
+	Items []vultrai.CollectionItem
+}
+
+// IngestURL fetches a web page or document, extracts its main text, splits
+// it into chunks and adds each chunk to the collection. Item descriptions
+// carry the document title and URL so search results can be attributed.
+// opts may be nil.
+func IngestURL(ctx context.Context, client ItemAdder, collectionID, url string, opts *Options) (*Result, error) {
+	o := opts.withDefaults()
+
+	doc, err := Fetch(ctx, url, &o)
+	if err != nil {
+		return nil, err
+	}
+
+	return addDocument(ctx, client, collectionID, doc, o)
+}
+
+// Fetch downloads url and converts it to a document with the loader
+// matching its content type. The document source is the final URL.
+func Fetch(ctx context.Context, url string, opts *Options) (*loaders.Document, error) {
+	o := opts.withDefaults()
+
+	page, err := fetchPage(ctx, url, o)
+	if err != nil {
+		return nil, err
+	}
+	return page.document(ctx, o.FullPage)
+}
+
+// page is a downloaded document before conversion
+type page struct {
+	url         *neturl.URL
+	contentType string
+	body        []byte
+}
+
+func fetchPage(ctx context.Context, url string, o Options) (*page, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", o.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain,text/markdown,application/pdf;q=0.9,*/*;q=0.5")
+
+	resp, err := o.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("error fetching %s: status %d", url, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, o.MaxBytes))
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", url, err)
+	}
+
+	return &page{
+		url:         resp.Request.URL,
+		contentType: resp.Header.Get("Content-Type"),
+		body:        body,
+	}, nil
+}
+
+func (p *page) loader(fullPage bool) (loaders.Loader, error) {
+	loader, ok := loaders.ForMediaType(p.contentType)
+	if !ok {
+		loader, ok = loaders.ForFile(path.Base(p.url.Path))
+	}
+	if !ok {
+		return nil, fmt.Errorf("unsupported content type %q", p.contentType)
+	}
+
+	if _, isHTML := loader.(loaders.HTMLLoader); isHTML && !fullPage {
+		loader = loaders.ArticleLoader{}
+	}
+	return loader, nil
+}
+
+func (p *page) isHTML() bool {
+	loader, err := p.loader(true)
+	if err != nil {
+		return false
+	}
+	_, ok := loader.(loaders.HTMLLoader)
+	return ok
+}
+
+func (p *page) document(ctx context.Context, fullPage bool) (*loaders.Document, error) {
+	source := p.url.String()
+
+	loader, err := p.loader(fullPage)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s: %w", source, err)
+	}
+
+	doc, err := loader.Load(ctx, bytes.NewReader(p.body))
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s: %w", source, err)
+	}
+	if doc.Metadata == nil {
+		doc.Metadata = make(map[string]string)
+	}
+	doc.Metadata[loaders.MetaSource] = source
+	return doc, nil
+}
+
+// addDocument chunks doc and adds each chunk to the collection
+func addDocument(ctx context.Context, client ItemAdder, collectionID string, doc *loaders.Document, o Options) (*Result, error) {
+	source := doc.Metadata[loaders.MetaSource]
+	result := &Result{URL: source, Title: doc.Metadata[loaders.MetaTitle]}
+
+	if doc.Text == "" {
+		return nil, fmt.Errorf("error ingesting %s: no text content", source)
+	}
+
+	for _, item := range doc.Chunks(o.ChunkSize, o.Overlap) {
+		if result.Title != "" && source != "" {
+			item.Description = fmt.Sprintf("%s - %s", item.Description, source)
+		}
+
+		resp, err := client.AddItem(ctx, collectionID, item)
+		if err != nil {
+			return result, fmt.Errorf("error adding chunk %d of %s: %w", len(result.Items)+1, source, err)
+		}
+		result.Items = append(result.Items, resp.Item)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/eqba1/vultrai/vultraitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const articlePage = `<html><head><title>Deploying Models</title></head><body>
+<nav><a href="/">Home</a> <a href="/docs">Docs</a></nav>
+<div class="sidebar"><a href="/a">Related one</a><a href="/b">Related two</a></div>
+<article class="post-content">
+<h1>Deploying Models</h1>
+<p>Deploying a model involves packaging the weights, choosing an instance type, and configuring autoscaling for traffic.</p>
+<p>Once deployed, requests are routed to the nearest region, which keeps latency low for users around the world.</p>
+</article>
+<footer>Copyright 2024, Example Inc.</footer>
+</body></html>`
+
+func newRecordingClient() *vultraitest.MockClient {
+	mock := &vultraitest.MockClient{}
+	mock.AddItemFunc = func(ctx context.Context, collectionID string, req vultrai.AddItemRequest) (*vultrai.AddItemResponse, error) {
+		return &vultrai.AddItemResponse{Item: vultrai.CollectionItem{
+			ID:          fmt.Sprintf("item-%d", mock.CallCount("AddItem")),
+			Description: req.Description,
+			Content:     req.Content,
+		}}, nil
+	}
+	mock.DeleteItemFunc = func(ctx context.Context, collectionID, itemID string) error {
+		return nil
+	}
+	return mock
+}
+
+func TestIngestURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, DefaultUserAgent, r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, articlePage)
+	}))
+	defer server.Close()
+
+	client := newRecordingClient()
+	result, err := IngestURL(context.Background(), client, "coll-123", server.URL+"/guide", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Deploying Models", result.Title)
+	require.Len(t, result.Items, 1)
+
+	item := result.Items[0]
+	assert.Equal(t, "Deploying Models - "+server.URL+"/guide", item.Description)
+	assert.Contains(t, item.Content, "configuring autoscaling")
+	assert.Contains(t, item.Content, "nearest region")
+	assert.NotContains(t, item.Content, "Related one")
+	assert.NotContains(t, item.Content, "Copyright")
+}
+
+func TestIngestURLChunks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, strings.Repeat("A sentence about vector search. ", 30))
+	}))
+	defer server.Close()
+
+	client := newRecordingClient()
+	result, err := IngestURL(context.Background(), client, "coll-123", server.URL+"/notes.txt", &Options{ChunkSize: 300, Overlap: 30})
+	require.NoError(t, err)
+
+	assert.Greater(t, len(result.Items), 1)
+	assert.Equal(t, len(result.Items), client.CallCount("AddItem"))
+	assert.Contains(t, result.Items[0].Description, "(part 1 of")
+}
+
+func TestIngestURLErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		default:
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte{0x89, 'P', 'N', 'G'})
+		}
+	}))
+	defer server.Close()
+
+	client := newRecordingClient()
+
+	_, err := IngestURL(context.Background(), client, "coll-123", server.URL+"/missing", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 404")
+
+	_, err = IngestURL(context.Background(), client, "coll-123", server.URL+"/logo", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported content type")
+
+	assert.Equal(t, 0, client.CallCount("AddItem"))
+}
@@ -0,0 +1,125 @@
+package ingest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ManifestEntry records what was ingested for one source document
+type ManifestEntry struct {
+	// Hash is the SHA-256 of the ingested text
+	Hash string `json:"hash,omitempty"`
+	// ETag is the version identifier reported by the source, if any
+	ETag string `json:"etag,omitempty"`
+	// ItemIDs are the collection items created from the document
+	ItemIDs   []string  `json:"item_ids"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Manifest tracks ingested documents by source so repeated runs can pick
+// up where they left off. It is safe for concurrent use.
+type Manifest struct {
+	mu      sync.Mutex
+	entries map[string]ManifestEntry
+}
+
+// NewManifest returns an empty manifest
+func NewManifest() *Manifest {
+	return &Manifest{entries: make(map[string]ManifestEntry)}
+}
+
+// LoadManifest reads a manifest saved with Save. A missing file yields an
+// empty manifest.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewManifest(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest: %w", err)
+	}
+
+	m := NewManifest()
+	if err := json.Unmarshal(data, &m.entries); err != nil {
+		return nil, fmt.Errorf("error parsing manifest: %w", err)
+	}
+	if m.entries == nil {
+		m.entries = make(map[string]ManifestEntry)
+	}
+	return m, nil
+}
+
+// Save writes the manifest to path atomically
+func (m *Manifest) Save(path string) error {
+	m.mu.Lock()
+	data, err := json.MarshalIndent(m.entries, "", "  ")
+	m.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("error encoding manifest: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".manifest-*")
+	if err != nil {
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+	return nil
+}
+
+// Get returns the entry for source
+func (m *Manifest) Get(source string) (ManifestEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[source]
+	return entry, ok
+}
+
+// Set records the entry for source
+func (m *Manifest) Set(source string, entry ManifestEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[source] = entry
+}
+
+// Delete removes source from the manifest
+func (m *Manifest) Delete(source string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, source)
+}
+
+// Sources returns all recorded sources in sorted order
+func (m *Manifest) Sources() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sources := make([]string, 0, len(m.entries))
+	for source := range m.entries {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+// Len returns the number of recorded sources
+func (m *Manifest) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
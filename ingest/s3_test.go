@@ -0,0 +1,147 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignV4(t *testing.T) {
+	// get-vanilla from the AWS Signature Version 4 test suite
+	req, err := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	require.NoError(t, err)
+
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	signV4(req, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1", "service", emptyPayloadHash, now)
+
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t,
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		req.Header.Get("Authorization"))
+}
+
+func TestURIEncode(t *testing.T) {
+	assert.Equal(t, "/docs/my%20file%2B1.txt", uriEncode("/docs/my file+1.txt", false))
+	assert.Equal(t, "a%2Fb", uriEncode("a/b", true))
+}
+
+// fakeBucket serves a minimal S3 ListObjectsV2/GetObject API
+type fakeBucket struct {
+	mu      sync.Mutex
+	objects map[string]string
+	etags   map[string]string
+	keys    []string
+	gets    []string
+}
+
+func (b *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/kb")
+	if key == "" || key == "/" {
+		// One object per page to exercise continuation tokens
+		keys := b.keys
+		if keys == nil {
+			keys = []string{"docs/", "docs/a.md", "docs/b.txt", "docs/image.png"}
+		}
+		start := 0
+		if token := r.URL.Query().Get("continuation-token"); token != "" {
+			fmt.Sscanf(token, "%d", &start)
+		}
+		fmt.Fprint(w, "<ListBucketResult>")
+		k := keys[start]
+		fmt.Fprintf(w, `<Contents><Key>%s</Key><ETag>"%s"</ETag><Size>%d</Size></Contents>`, k, b.etags[k], len(b.objects[k]))
+		if start+1 < len(keys) {
+			fmt.Fprintf(w, "<IsTruncated>true</IsTruncated><NextContinuationToken>%d</NextContinuationToken>", start+1)
+		}
+		fmt.Fprint(w, "</ListBucketResult>")
+		return
+	}
+
+	key = strings.TrimPrefix(key, "/")
+	b.gets = append(b.gets, key)
+	fmt.Fprint(w, b.objects[key])
+}
+
+func TestSyncBucket(t *testing.T) {
+	bucket := &fakeBucket{
+		objects: map[string]string{"docs/a.md": "# Alpha\n\nFirst document.", "docs/b.txt": "Second document."},
+		etags:   map[string]string{"docs/a.md": "e1", "docs/b.txt": "e2"},
+	}
+	server := httptest.NewServer(bucket)
+	defer server.Close()
+
+	s3, err := NewS3Client(S3Config{Endpoint: server.URL, Bucket: "kb", AccessKey: "key", SecretKey: "secret"})
+	require.NoError(t, err)
+
+	manifestPath := filepath.Join(t.TempDir(), "manifest.json")
+	client := newRecordingClient()
+
+	result, err := SyncBucket(context.Background(), client, "coll-123", s3, &BucketSyncOptions{ManifestPath: manifestPath})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s3://kb/docs/a.md", "s3://kb/docs/b.txt"}, result.Added)
+	assert.Equal(t, 2, result.Skipped)
+	assert.Empty(t, result.Errors)
+
+	manifest, err := LoadManifest(manifestPath)
+	require.NoError(t, err)
+	entry, ok := manifest.Get("s3://kb/docs/a.md")
+	require.True(t, ok)
+	assert.Equal(t, "e1", entry.ETag)
+	assert.Len(t, entry.ItemIDs, 1)
+
+	// Second run only downloads the changed object
+	bucket.gets = nil
+	bucket.etags["docs/b.txt"] = "e3"
+	bucket.objects["docs/b.txt"] = "Second document, edited."
+	result, err = SyncBucket(context.Background(), client, "coll-123", s3, &BucketSyncOptions{ManifestPath: manifestPath})
+	require.NoError(t, err)
+	assert.Empty(t, result.Added)
+	assert.Equal(t, []string{"s3://kb/docs/b.txt"}, result.Updated)
+	assert.Equal(t, 1, result.Unchanged)
+	assert.Equal(t, []string{"docs/b.txt"}, bucket.gets)
+
+	// A new ETag with identical content is not re-ingested, and objects
+	// removed from the bucket are pruned
+	bucket.etags["docs/a.md"] = "e4"
+	delete(bucket.etags, "docs/b.txt")
+	delete(bucket.objects, "docs/b.txt")
+	bucket.keys = []string{"docs/a.md"}
+	result, err = SyncBucket(context.Background(), client, "coll-123", s3, &BucketSyncOptions{ManifestPath: manifestPath})
+	require.NoError(t, err)
+	assert.Empty(t, result.Updated)
+	assert.Equal(t, 1, result.Unchanged)
+	assert.Equal(t, []string{"s3://kb/docs/b.txt"}, result.Removed)
+	assert.Equal(t, 2, client.CallCount("DeleteItem"))
+}
+
+func TestS3ClientErrors(t *testing.T) {
+	server := httptest.NewServer(&fakeBucket{})
+	defer server.Close()
+
+	_, err := NewS3Client(S3Config{Endpoint: server.URL})
+	assert.Error(t, err)
+
+	s3, err := NewS3Client(S3Config{Endpoint: server.URL, Bucket: "kb", AccessKey: "wrong"})
+	require.NoError(t, err)
+
+	_, err = s3.ListObjects(context.Background(), "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AccessDenied")
+}
@@ -0,0 +1,10 @@
+// Package integration contains a live test suite that exercises every SDK
+// endpoint against the real Vultr Inference API.
+//
+// The tests are excluded from normal builds. Run them with:
+//
+//	VULTRAI_API_KEY=... go test -tags integration ./integration/...
+//
+// VULTRAI_TEST_MODEL overrides the chat model, VULTRAI_TEST_TTS_MODEL and
+// VULTRAI_TEST_IMAGE_MODEL enable the speech and image tests.
+package integration
@@ -0,0 +1,187 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient(t *testing.T) *vultrai.Client {
+	t.Helper()
+
+	apiKey := os.Getenv("VULTRAI_API_KEY")
+	if apiKey == "" {
+		t.Skip("VULTRAI_API_KEY not set")
+	}
+
+	var options []vultrai.ClientOption
+	if baseURL := os.Getenv("VULTRAI_BASE_URL"); baseURL != "" {
+		options = append(options, vultrai.WithBaseURL(baseURL))
+	}
+
+	return vultrai.NewClient(apiKey, options...)
+}
+
+func chatModel() string {
+	if model := os.Getenv("VULTRAI_TEST_MODEL"); model != "" {
+		return model
+	}
+	return vultrai.Qwen25_32bInstruct
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func tinyRequest() vultrai.ChatCompletionRequest {
+	return vultrai.ChatCompletionRequest{
+		Model:       chatModel(),
+		Messages:    []vultrai.Message{vultrai.CreateUserMessage("Reply with the single word: pong")},
+		MaxTokens:   vultrai.Int(8),
+		Temperature: vultrai.Float64(0),
+	}
+}
+
+func TestChatCompletion(t *testing.T) {
+	client := newClient(t)
+
+	resp, err := client.CreateChatCompletion(testContext(t), tinyRequest())
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Choices)
+	assert.NotEmpty(t, resp.Choices[0].Message.Content)
+	assert.Greater(t, resp.Usage.TotalTokens, 0)
+}
+
+func TestChatCompletionStream(t *testing.T) {
+	client := newClient(t)
+
+	var chunks []*vultrai.StreamChatCompletion
+	err := client.StreamChatCompletion(testContext(t), tinyRequest(), func(chunk *vultrai.StreamChatCompletion) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, chunks)
+	assert.NotEmpty(t, vultrai.AccumulateStreamContent(chunks))
+}
+
+func TestVectorStoreLifecycle(t *testing.T) {
+	client := newClient(t)
+	ctx := testContext(t)
+
+	name := fmt.Sprintf("vultrai-it-%d", time.Now().UnixNano())
+	created, err := client.CreateCollection(ctx, vultrai.CreateCollectionRequest{Name: name})
+	require.NoError(t, err)
+	collectionID := created.Collection.ID
+	require.NotEmpty(t, collectionID)
+
+	updated, err := client.UpdateCollection(ctx, collectionID, vultrai.UpdateCollectionRequest{Name: name + "-renamed"})
+	require.NoError(t, err)
+	assert.Equal(t, name+"-renamed", updated.Collection.Name)
+
+	added, err := client.AddItem(ctx, collectionID, vultrai.AddItemRequest{
+		Content:     "The Go gopher was designed by Renee French.",
+		Description: "gopher fact",
+	})
+	require.NoError(t, err)
+	itemID := added.Item.ID
+
+	item, err := client.GetItem(ctx, collectionID, itemID)
+	require.NoError(t, err)
+	assert.Equal(t, itemID, item.Item.ID)
+
+	_, err = client.UpdateItem(ctx, collectionID, itemID, vultrai.UpdateItemRequest{Description: "updated fact"})
+	require.NoError(t, err)
+
+	items, err := client.ListItems(ctx, collectionID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, items.Items)
+
+	search, err := client.SearchCollection(ctx, collectionID, vultrai.SearchRequest{Input: "Who designed the gopher?"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, search.Results)
+
+	file, err := client.AddFile(ctx, collectionID, strings.NewReader("Integration test document."), "it.txt")
+	require.NoError(t, err)
+
+	got, err := client.GetFile(ctx, collectionID, file.File.ID)
+	require.NoError(t, err)
+	assert.Equal(t, file.File.ID, got.File.ID)
+
+	files, err := client.ListFiles(ctx, collectionID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, files.Files)
+
+	rag, err := client.CreateRAGChatCompletion(ctx, vultrai.RAGChatCompletionRequest{
+		Collection: collectionID,
+		Model:      chatModel(),
+		Messages:   []vultrai.Message{vultrai.CreateUserMessage("Who designed the Go gopher?")},
+		MaxTokens:  vultrai.Int(32),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, rag.Choices)
+}
+
+func TestSpeech(t *testing.T) {
+	client := newClient(t)
+
+	model := os.Getenv("VULTRAI_TEST_TTS_MODEL")
+	if model == "" {
+		t.Skip("VULTRAI_TEST_TTS_MODEL not set")
+	}
+
+	audio, err := client.CreateSpeech(testContext(t), vultrai.TTSRequest{
+		Model: model,
+		Input: "Hi.",
+		Voice: os.Getenv("VULTRAI_TEST_TTS_VOICE"),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, audio)
+}
+
+func TestImageGeneration(t *testing.T) {
+	client := newClient(t)
+
+	model := os.Getenv("VULTRAI_TEST_IMAGE_MODEL")
+	if model == "" {
+		t.Skip("VULTRAI_TEST_IMAGE_MODEL not set")
+	}
+
+	resp, err := client.GenerateImageWithOptions(testContext(t), "a small red square",
+		vultrai.WithImageModel(model),
+		vultrai.WithImageCount(1),
+		vultrai.WithImageSize("256x256"),
+	)
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Data)
+}
+
+func TestUsageAndLogs(t *testing.T) {
+	client := newClient(t)
+	ctx := testContext(t)
+
+	_, err := client.GetUsage(ctx)
+	require.NoError(t, err)
+
+	_, err = client.GetRequestLogs(ctx, vultrai.RequestLogsRequest{Period: 15})
+	require.NoError(t, err)
+}
+
+func TestInvalidAPIKey(t *testing.T) {
+	newClient(t)
+
+	client := vultrai.NewClient("invalid-key")
+	_, err := client.CreateChatCompletion(testContext(t), tinyRequest())
+	require.Error(t, err)
+}
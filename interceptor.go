@@ -0,0 +1,58 @@
+package vultrai
+
+import "context"
+
+// ChatHandler performs a chat completion
+type ChatHandler func(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error)
+
+// ChatInterceptor wraps chat completions. It may rewrite the request before
+// calling next, inspect or modify the response, or return an error without
+// calling next at all. Interceptors run for CreateChatCompletion and every
+// helper built on it, before caching and request coalescing; streaming and
+// RAG requests are not intercepted.
+type ChatInterceptor func(ctx context.Context, req ChatCompletionRequest, next ChatHandler) (*ChatCompletionResponse, error)
+
+// WithChatInterceptor adds interceptors to the client. The first interceptor
+// added is the outermost, so it sees the request first and the response last.
+func WithChatInterceptor(interceptors ...ChatInterceptor) ClientOption {
+	return func(c *Client) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+type interceptorsKey struct{}
+
+// ContextWithChatInterceptors returns a context that applies interceptors to
+// chat completions made with it, in addition to the client's own. Request
+// interceptors run inside the client interceptors.
+func ContextWithChatInterceptors(ctx context.Context, interceptors ...ChatInterceptor) context.Context {
+	existing, _ := ctx.Value(interceptorsKey{}).([]ChatInterceptor)
+	combined := make([]ChatInterceptor, 0, len(existing)+len(interceptors))
+	combined = append(combined, existing...)
+	combined = append(combined, interceptors...)
+	return context.WithValue(ctx, interceptorsKey{}, combined)
+}
+
+// requestInterceptors returns the client interceptors followed by those
+// attached to ctx
+func (c *Client) requestInterceptors(ctx context.Context) []ChatInterceptor {
+	perRequest, _ := ctx.Value(interceptorsKey{}).([]ChatInterceptor)
+	if len(perRequest) == 0 {
+		return c.interceptors
+	}
+	all := make([]ChatInterceptor, 0, len(c.interceptors)+len(perRequest))
+	all = append(all, c.interceptors...)
+	return append(all, perRequest...)
+}
+
+// chainChat composes interceptors around final
+func chainChat(interceptors []ChatInterceptor, final ChatHandler) ChatHandler {
+	handler := final
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
+			return interceptor(ctx, req, next)
+		}
+	}
+	return handler
+}
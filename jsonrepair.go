@@ -0,0 +1,415 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RepairJSON fixes the JSON defects language models commonly produce so the
+// result can be decoded strictly. It strips markdown fences and surrounding
+// prose, converts single-quoted strings, quotes bare keys, drops trailing
+// commas and comments, inserts missing commas, escapes raw control
+// characters and unescaped inner quotes, maps Python and JavaScript
+// literals (True, None, undefined, NaN) and closes truncated strings,
+// objects and arrays. Well-formed JSON passes through unchanged apart from
+// whitespace inside containers.
+func RepairJSON(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "```") {
+		s = strings.TrimPrefix(s, "```")
+		if nl := strings.IndexByte(s, '\n'); nl >= 0 {
+			s = s[nl+1:]
+		}
+		if end := strings.LastIndex(s, "```"); end >= 0 {
+			s = s[:end]
+		}
+		s = strings.TrimSpace(s)
+	}
+
+	start := strings.IndexAny(s, "{[")
+	if start < 0 {
+		return s
+	}
+
+	r := &jsonRepairer{src: s[start:]}
+	r.run()
+	return r.out.String()
+}
+
+// DecodeModelJSON decodes model output into T, repairing it with RepairJSON
+// when strict decoding fails
+func DecodeModelJSON[T any](content string) (T, error) {
+	var result T
+	err := decodeModelJSONInto(content, &result)
+	return result, err
+}
+
+// DecodeModelJSONWithReask decodes content like DecodeModelJSON. If the
+// output cannot be repaired, the model is shown its answer and the decoding
+// error and asked once to respond again; req is the request that produced
+// content.
+func DecodeModelJSONWithReask[T any](ctx context.Context, client *Client, req ChatCompletionRequest, content string) (T, error) {
+	result, err := DecodeModelJSON[T](content)
+	if err == nil {
+		return result, nil
+	}
+
+	req.Messages = append(append([]Message(nil), req.Messages...),
+		CreateAssistantMessage(content),
+		CreateUserMessage(fmt.Sprintf("That response was not valid JSON (%v). Respond again with corrected JSON only.", err)),
+	)
+
+	resp, reaskErr := client.CreateChatCompletion(ctx, req)
+	if reaskErr != nil {
+		return result, reaskErr
+	}
+	if len(resp.Choices) == 0 {
+		return result, errors.New("error decoding model JSON: no choices returned")
+	}
+	return DecodeModelJSON[T](resp.Choices[0].Message.Content)
+}
+
+// Parser states for containers in jsonRepairer
+const (
+	expectKey   = iota // after { or a comma in an object
+	expectColon        // after an object key
+	expectValue        // after [ , a comma in an array, or a colon
+	afterValue         // after a complete value, wanting a comma or closer
+)
+
+type jsonFrame struct {
+	object bool
+	state  int
+}
+
+// jsonRepairer rewrites a JSON-like token stream into valid JSON
+type jsonRepairer struct {
+	src     string
+	pos     int
+	out     strings.Builder
+	stack   []jsonFrame
+	pending bool // a comma was seen and is emitted before the next member
+	done    bool // the root value is complete
+}
+
+func (r *jsonRepairer) run() {
+	for !r.done {
+		r.skipSpaceAndComments()
+		if r.pos >= len(r.src) {
+			break
+		}
+
+		c := r.src[r.pos]
+		switch c {
+		case '{', '[':
+			r.pos++
+			r.beginValue()
+			r.out.WriteByte(c)
+			frame := jsonFrame{object: c == '{', state: expectValue}
+			if frame.object {
+				frame.state = expectKey
+			}
+			r.stack = append(r.stack, frame)
+		case '}', ']':
+			r.pos++
+			r.closeTo(c == '}')
+		case ':':
+			r.pos++
+			if top := r.top(); top != nil && top.object && top.state == expectColon {
+				r.out.WriteByte(':')
+				top.state = expectValue
+			}
+		case ',':
+			r.pos++
+			if top := r.top(); top != nil && top.state == afterValue {
+				r.pending = true
+				top.state = expectValue
+				if top.object {
+					top.state = expectKey
+				}
+			}
+		case '"', '\'':
+			r.pos++
+			str := r.readString(c)
+			if top := r.top(); top != nil && top.object && (top.state == expectKey || top.state == afterValue) {
+				r.beginKey()
+				r.out.WriteString(str)
+				top.state = expectColon
+			} else {
+				r.beginValue()
+				r.out.WriteString(str)
+				r.endValue()
+			}
+		default:
+			word := r.readWord()
+			if word == "" {
+				r.pos++
+				continue
+			}
+			if top := r.top(); top != nil && top.object && (top.state == expectKey || top.state == afterValue) {
+				r.beginKey()
+				r.out.WriteString(quoteJSON(word))
+				top.state = expectColon
+			} else {
+				r.beginValue()
+				r.out.WriteString(bareValue(word))
+				r.endValue()
+			}
+		}
+	}
+
+	// Close whatever the input left open
+	for len(r.stack) > 0 {
+		r.closeTo(r.top().object)
+	}
+}
+
+func (r *jsonRepairer) top() *jsonFrame {
+	if len(r.stack) == 0 {
+		return nil
+	}
+	return &r.stack[len(r.stack)-1]
+}
+
+// beginKey emits a pending or missing comma before an object key
+func (r *jsonRepairer) beginKey() {
+	if top := r.top(); top.state == afterValue || r.pending {
+		r.out.WriteByte(',')
+	}
+	r.pending = false
+}
+
+// beginValue emits a pending or missing comma before an array element, or
+// a missing colon after an object key
+func (r *jsonRepairer) beginValue() {
+	top := r.top()
+	switch {
+	case top == nil:
+	case top.object && (top.state == expectKey || top.state == afterValue):
+		// A value where a key belongs gets an empty key
+		if top.state == afterValue || r.pending {
+			r.out.WriteByte(',')
+		}
+		r.out.WriteString(`"":`)
+		top.state = expectValue
+	case top.object && top.state == expectColon:
+		r.out.WriteByte(':')
+		top.state = expectValue
+	case top.state == afterValue || r.pending:
+		r.out.WriteByte(',')
+	}
+	r.pending = false
+}
+
+func (r *jsonRepairer) endValue() {
+	if top := r.top(); top != nil {
+		top.state = afterValue
+	} else {
+		r.done = true
+	}
+}
+
+// closeTo closes containers up to and including the innermost one of the
+// requested kind, completing dangling keys and dropping trailing commas
+func (r *jsonRepairer) closeTo(object bool) {
+	found := false
+	for _, f := range r.stack {
+		if f.object == object {
+			found = true
+		}
+	}
+	if !found {
+		return
+	}
+
+	for len(r.stack) > 0 {
+		top := r.stack[len(r.stack)-1]
+		if top.object {
+			switch top.state {
+			case expectColon:
+				r.out.WriteString(":null")
+			case expectValue:
+				r.out.WriteString("null")
+			}
+			r.out.WriteByte('}')
+		} else {
+			r.out.WriteByte(']')
+		}
+		r.pending = false
+		r.stack = r.stack[:len(r.stack)-1]
+		r.endValue()
+		if top.object == object {
+			return
+		}
+	}
+}
+
+func (r *jsonRepairer) skipSpaceAndComments() {
+	for r.pos < len(r.src) {
+		c := r.src[r.pos]
+		switch {
+		case c == ' ' || c == '\n' || c == '\r' || c == '\t':
+			r.pos++
+		case strings.HasPrefix(r.src[r.pos:], "//"):
+			for r.pos < len(r.src) && r.src[r.pos] != '\n' {
+				r.pos++
+			}
+		case strings.HasPrefix(r.src[r.pos:], "/*"):
+			end := strings.Index(r.src[r.pos+2:], "*/")
+			if end < 0 {
+				r.pos = len(r.src)
+			} else {
+				r.pos += end + 4
+			}
+		default:
+			return
+		}
+	}
+}
+
+// readString reads a string body after its opening quote and returns it as
+// a valid double-quoted JSON string. A quote only terminates the string when
+// followed by a structural character, so unescaped inner quotes survive.
+func (r *jsonRepairer) readString(quote byte) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+
+	for r.pos < len(r.src) {
+		c := r.src[r.pos]
+		r.pos++
+
+		switch {
+		case c == '\\':
+			if r.pos >= len(r.src) {
+				break
+			}
+			next := r.src[r.pos]
+			r.pos++
+			switch {
+			case strings.IndexByte(`"\\/bfnrt`, next) >= 0:
+				sb.WriteByte('\\')
+				sb.WriteByte(next)
+			case next == 'u' && r.pos+4 <= len(r.src) && isHex4(r.src[r.pos:r.pos+4]):
+				sb.WriteString(`\u`)
+			case next == '\'':
+				sb.WriteByte('\'')
+			default:
+				// Invalid escape: keep the backslash literally and handle the
+				// following character normally
+				sb.WriteString(`\\`)
+				r.pos--
+			}
+		case c == quote && r.closesString():
+			sb.WriteByte('"')
+			return sb.String()
+		case c == '"':
+			sb.WriteString(`\"`)
+		case c == '\n':
+			sb.WriteString(`\n`)
+		case c == '\r':
+			sb.WriteString(`\r`)
+		case c == '\t':
+			sb.WriteString(`\t`)
+		case c < 0x20:
+			fmt.Fprintf(&sb, `\u%04x`, c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	// Truncated string
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// closesString reports whether the quote just read ends the string: it must
+// be followed by a structural character, the end of input, or (when a comma
+// is missing) a line break or the next object key
+func (r *jsonRepairer) closesString() bool {
+	newline := false
+	for i := r.pos; i < len(r.src); i++ {
+		switch c := r.src[i]; c {
+		case '\n':
+			newline = true
+		case ' ', '\r', '\t':
+		case ',', ':', '}', ']':
+			return true
+		case '"', '\'':
+			if newline {
+				return true
+			}
+			end := strings.IndexByte(r.src[i+1:], c)
+			if end < 0 {
+				return false
+			}
+			rest := strings.TrimLeft(r.src[i+end+2:], " \r\n\t")
+			return strings.HasPrefix(rest, ":")
+		default:
+			return newline
+		}
+	}
+	return true
+}
+
+func (r *jsonRepairer) readWord() string {
+	start := r.pos
+	for r.pos < len(r.src) && !strings.ContainsRune(" \n\r\t{}[]:,\"'", rune(r.src[r.pos])) {
+		r.pos++
+	}
+	return r.src[start:r.pos]
+}
+
+var jsonNumber = regexp.MustCompile(`^-?(?:0|[1-9]\d*)(?:\.\d+)?(?:[eE][+-]?\d+)?$`)
+
+// bareValue converts an unquoted token to a JSON literal, number or string
+func bareValue(word string) string {
+	switch strings.ToLower(word) {
+	case "true":
+		return "true"
+	case "false":
+		return "false"
+	case "null", "none", "nil", "undefined", "nan", "infinity", "-infinity":
+		return "null"
+	}
+
+	number := strings.TrimPrefix(word, "+")
+	number = strings.TrimRight(number, ".eE+-")
+	if strings.HasPrefix(number, ".") {
+		number = "0" + number
+	} else if strings.HasPrefix(number, "-.") {
+		number = "-0" + number[1:]
+	}
+	if jsonNumber.MatchString(number) {
+		return number
+	}
+
+	// Truncated literals
+	for _, literal := range []string{"true", "false", "null"} {
+		if strings.HasPrefix(literal, strings.ToLower(word)) {
+			return literal
+		}
+	}
+	return quoteJSON(word)
+}
+
+func isHex4(s string) bool {
+	for i := 0; i < 4; i++ {
+		if !isHexByte(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isHexByte(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func quoteJSON(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
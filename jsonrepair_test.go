@@ -0,0 +1,75 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"valid", `{"a": 1, "b": [true, null]}`, `{"a":1,"b":[true,null]}`},
+		{"fences and prose", "Sure! Here it is:\n```json\n{\"a\": 1}\n```\nLet me know.", `{"a":1}`},
+		{"trailing prose", `{"a": 1} I hope this helps!`, `{"a":1}`},
+		{"trailing commas", `{"a": [1, 2, ], "b": 3, }`, `{"a":[1,2],"b":3}`},
+		{"single quotes", `{'name': 'O\'Brien', 'tags': ['x']}`, `{"name":"O'Brien","tags":["x"]}`},
+		{"apostrophe in single quotes", `{'text': 'it's fine'}`, `{"text":"it's fine"}`},
+		{"bare keys", `{name: "Ada", age: 36}`, `{"name":"Ada","age":36}`},
+		{"python literals", `{"ok": True, "missing": None, "bad": NaN}`, `{"ok":true,"missing":null,"bad":null}`},
+		{"comments", "{\n  // the id\n  \"id\": 7, /* inline */ \"x\": 1\n}", `{"id":7,"x":1}`},
+		{"missing commas", "{\"a\": 1 \"b\": \"two\"\n\"c\": [1 2]}", `{"a":1,"b":"two","c":[1,2]}`},
+		{"missing colon", "{\"a\"\n  1}", `{"a":1}`},
+		{"inner quotes", `{"quote": "He said "hi" to me"}`, `{"quote":"He said \"hi\" to me"}`},
+		{"raw newlines", "{\"text\": \"line one\nline two\"}", `{"text":"line one\nline two"}`},
+		{"truncated string", `{"items": ["alpha", "bet`, `{"items":["alpha","bet"]}`},
+		{"truncated after colon", `{"a": 1, "b":`, `{"a":1,"b":null}`},
+		{"truncated key", `{"a": 1, "b`, `{"a":1,"b":null}`},
+		{"truncated literal", `[1, 2.5, tr`, `[1,2.5,true]`},
+		{"truncated number", `{"n": 12.`, `{"n":12}`},
+		{"numbers", `[+1, .5, -.25]`, `[1,0.5,-0.25]`},
+		{"mismatched closer", `{"a": [1, 2}`, `{"a":[1,2]}`},
+		{"array root", `[{"a": 1}, {"a": 2},]`, `[{"a":1},{"a":2}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RepairJSON(tt.input)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, json.Valid([]byte(got)), "invalid JSON: %s", got)
+		})
+	}
+}
+
+func TestDecodeModelJSON(t *testing.T) {
+	type person struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+
+	p, err := DecodeModelJSON[person]("```json\n{'name': 'Ada', 'tags': ['math', 'code',],}\n```")
+	require.NoError(t, err)
+	assert.Equal(t, person{Name: "Ada", Tags: []string{"math", "code"}}, p)
+
+	_, err = DecodeModelJSON[person](`{"name": 42}`)
+	assert.Error(t, err)
+}
+
+func TestDecodeModelJSONWithReask(t *testing.T) {
+	client, transport := setupSequenceClient(`{"count": 3}`)
+
+	req := ChatCompletionRequest{Model: "test-model", Messages: []Message{CreateUserMessage("count")}}
+	result, err := DecodeModelJSONWithReask[struct {
+		Count int `json:"count"`
+	}](context.Background(), client, req, `{"count": "three"}`)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Count)
+	assert.Len(t, transport.requests, 1)
+	assert.Len(t, req.Messages, 1)
+}
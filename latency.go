@@ -0,0 +1,70 @@
+package vultrai
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// CompletionLatency holds timing measurements for a single streamed completion
+type CompletionLatency struct {
+	// TimeToFirstToken is the time from sending the request to the first content delta
+	TimeToFirstToken time.Duration
+	// Total is the time from sending the request to the end of the stream
+	Total time.Duration
+	// Tokens is the number of content chunks received, which approximates
+	// the number of generated tokens
+	Tokens int
+	// TokensPerSecond is the generation rate measured after the first token
+	TokensPerSecond float64
+	// Response is the assembled completion
+	Response *ChatCompletionResponse
+}
+
+// MeasureCompletion runs a streaming chat completion and reports latency
+// figures, which makes it easy to compare models or track regressions
+func (c *Client) MeasureCompletion(ctx context.Context, req ChatCompletionRequest) (*CompletionLatency, error) {
+	start := time.Now()
+
+	stream, err := c.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var latency CompletionLatency
+	var chunks []*StreamChatCompletion
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		chunks = append(chunks, chunk)
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		if latency.Tokens == 0 {
+			latency.TimeToFirstToken = time.Since(start)
+		}
+		latency.Tokens++
+	}
+
+	latency.Total = time.Since(start)
+	latency.Response = StreamToComplete(chunks)
+
+	generation := latency.Total - latency.TimeToFirstToken
+	if generation <= 0 {
+		generation = latency.Total
+	}
+	if latency.Tokens > 0 && generation > 0 {
+		latency.TokensPerSecond = float64(latency.Tokens) / generation.Seconds()
+	}
+
+	return &latency, nil
+}
@@ -0,0 +1,32 @@
+package vultrai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeasureCompletion(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+
+	mockTransport.responses["POST /chat/completions"] = &http.Response{
+		StatusCode: 200,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(benchmarkStreamData(3))),
+	}
+
+	latency, err := client.MeasureCompletion(context.Background(), ChatCompletionRequest{
+		Model:    "test-model",
+		Messages: []Message{{Role: "user", Content: "Hi"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, latency.Tokens)
+	assert.True(t, latency.Total >= latency.TimeToFirstToken)
+	assert.Equal(t, "token 0 token 1 token 2 ", latency.Response.Choices[0].Message.Content)
+}
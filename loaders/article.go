@@ -0,0 +1,269 @@
+package loaders
+
+import (
+	"context"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	unlikelyCandidate = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|cookie|disqus|footer|header|menu|modal|navbar|popup|related|remark|share|shoutbox|sidebar|skip|social|sponsor|subscribe|newsletter|advert`)
+	maybeCandidate    = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveHint      = regexp.MustCompile(`(?i)article|body|content|entry|hentry|main|page|post|text|blog|story`)
+	negativeHint      = regexp.MustCompile(`(?i)comment|contact|foot|footnote|masthead|meta|promo|related|scroll|shoutbox|sidebar|sponsor|shopping|tags|widget|nav|menu`)
+)
+
+// boilerplateElements hold navigation and chrome rather than content
+var boilerplateElements = map[atom.Atom]bool{
+	atom.Nav:    true,
+	atom.Aside:  true,
+	atom.Footer: true,
+	atom.Header: true,
+	atom.Form:   true,
+	atom.Button: true,
+}
+
+// ArticleLoader loads HTML like HTMLLoader but keeps only the main content
+// of the page, dropping navigation, sidebars, footers and other
+// boilerplate. Candidate containers are scored by the amount of paragraph
+// text they hold, their link density and hints in their class and id, in
+// the spirit of Mozilla's Readability. When no convincing candidate is
+// found the whole page text is used.
+type ArticleLoader struct {
+	// MinLength is the minimum number of characters the extracted article
+	// must have to be preferred over the full page text (default 200)
+	MinLength int
+}
+
+// Load parses r as HTML and extracts its main content
+func (l ArticleLoader) Load(ctx context.Context, r io.Reader) (*Document, error) {
+	root, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Metadata: map[string]string{MetaFormat: "html"}}
+	collectHTMLMetadata(root, doc.Metadata)
+
+	minLength := l.MinLength
+	if minLength <= 0 {
+		minLength = 200
+	}
+
+	var sb strings.Builder
+	for _, n := range articleNodes(root) {
+		writeArticleText(&sb, n)
+	}
+	doc.Text = CleanText(sb.String())
+
+	if utf8.RuneCountInString(doc.Text) < minLength {
+		sb.Reset()
+		writeHTMLText(&sb, root)
+		if full := CleanText(sb.String()); len(full) > len(doc.Text) {
+			doc.Text = full
+		}
+	}
+	return doc, nil
+}
+
+// articleNodes returns the best scoring content container followed by any
+// siblings that look like part of the same article
+func articleNodes(root *html.Node) []*html.Node {
+	scores := make(map[*html.Node]float64)
+	var order []*html.Node
+
+	addScore := func(n *html.Node, score float64) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(n)
+			order = append(order, n)
+		}
+		scores[n] += score
+	}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if skippedElements[n.DataAtom] || boilerplateElements[n.DataAtom] || isUnlikely(n) {
+				return
+			}
+			if isParagraph(n) {
+				text := strings.TrimSpace(nodeText(n))
+				if length := utf8.RuneCountInString(text); length >= 25 {
+					score := 1 + float64(strings.Count(text, ",")) + float64(min(length/100, 3))
+					addScore(n.Parent, score)
+					if n.Parent != nil {
+						addScore(n.Parent.Parent, score/2)
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	var top *html.Node
+	best := 0.0
+	for _, n := range order {
+		scores[n] *= 1 - linkDensity(n)
+		if scores[n] > best {
+			top, best = n, scores[n]
+		}
+	}
+	if top == nil {
+		return nil
+	}
+	if top.Parent == nil {
+		return []*html.Node{top}
+	}
+
+	// Pull in siblings that scored well or are substantial paragraphs,
+	// since articles are often split across several containers
+	threshold := max(10, best*0.2)
+	var nodes []*html.Node
+	for s := top.Parent.FirstChild; s != nil; s = s.NextSibling {
+		if s == top {
+			nodes = append(nodes, s)
+			continue
+		}
+		if s.Type != html.ElementNode {
+			continue
+		}
+		if score, ok := scores[s]; ok && score >= threshold {
+			nodes = append(nodes, s)
+			continue
+		}
+		if s.DataAtom == atom.P {
+			text := strings.TrimSpace(nodeText(s))
+			if utf8.RuneCountInString(text) > 80 && linkDensity(s) < 0.25 {
+				nodes = append(nodes, s)
+			}
+		}
+	}
+	return nodes
+}
+
+func initialScore(n *html.Node) float64 {
+	score := classWeight(n)
+	switch n.DataAtom {
+	case atom.Article, atom.Main:
+		score += 10
+	case atom.Div:
+		score += 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score += 3
+	case atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li, atom.Form:
+		score -= 3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score -= 5
+	}
+	return score
+}
+
+func classWeight(n *html.Node) float64 {
+	hints := attr(n, "class") + " " + attr(n, "id")
+	weight := 0.0
+	if negativeHint.MatchString(hints) {
+		weight -= 25
+	}
+	if positiveHint.MatchString(hints) {
+		weight += 25
+	}
+	return weight
+}
+
+func isUnlikely(n *html.Node) bool {
+	if n.DataAtom == atom.Body || n.DataAtom == atom.Article || n.DataAtom == atom.Main {
+		return false
+	}
+	hints := attr(n, "class") + " " + attr(n, "id")
+	return unlikelyCandidate.MatchString(hints) && !maybeCandidate.MatchString(hints)
+}
+
+// isParagraph reports whether n holds a run of prose: paragraphs,
+// preformatted blocks, table cells and divs without block children
+func isParagraph(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.P, atom.Pre, atom.Td, atom.Blockquote:
+		return true
+	case atom.Div:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && blockElements[c.DataAtom] && c.DataAtom != atom.Br {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// linkDensity is the share of n's text that sits inside links
+func linkDensity(n *html.Node) float64 {
+	total := utf8.RuneCountInString(strings.TrimSpace(nodeText(n)))
+	if total == 0 {
+		return 0
+	}
+
+	links := 0
+	var walk func(*html.Node)
+	walk = func(c *html.Node) {
+		if c.Type == html.ElementNode && c.DataAtom == atom.A {
+			links += utf8.RuneCountInString(strings.TrimSpace(nodeText(c)))
+			return
+		}
+		for child := c.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+
+	return float64(links) / float64(total)
+}
+
+// writeArticleText writes the text of n, leaving out nested boilerplate
+// such as share bars and link lists that survived candidate selection
+func writeArticleText(sb *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		sb.WriteString(n.Data)
+		return
+	case html.CommentNode:
+		return
+	case html.ElementNode:
+		if skippedElements[n.DataAtom] || boilerplateElements[n.DataAtom] || isUnlikely(n) {
+			return
+		}
+		if n.DataAtom == atom.Img {
+			writeHTMLText(sb, n)
+			return
+		}
+		if classWeight(n) < 0 && linkDensity(n) > 0.5 {
+			return
+		}
+		if (n.DataAtom == atom.Ul || n.DataAtom == atom.Ol) && linkDensity(n) > 0.8 {
+			return
+		}
+	}
+
+	block := n.Type == html.ElementNode && blockElements[n.DataAtom]
+	if block {
+		sb.WriteString("\n\n")
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeArticleText(sb, c)
+	}
+	if block {
+		sb.WriteString("\n\n")
+	} else if n.Type == html.ElementNode && (n.DataAtom == atom.Td || n.DataAtom == atom.Th) {
+		sb.WriteString(" ")
+	}
+}
@@ -0,0 +1,139 @@
+package loaders
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// skippedElements never contribute text
+var skippedElements = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Svg:      true,
+	atom.Iframe:   true,
+	atom.Head:     true,
+}
+
+// blockElements are separated from their neighbours by line breaks
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true,
+	atom.Header: true, atom.Footer: true, atom.Nav: true, atom.Aside: true,
+	atom.Main: true, atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true,
+	atom.H5: true, atom.H6: true, atom.Ul: true, atom.Ol: true, atom.Li: true,
+	atom.Table: true, atom.Tr: true, atom.Blockquote: true, atom.Pre: true,
+	atom.Br: true, atom.Hr: true, atom.Dl: true, atom.Dt: true, atom.Dd: true,
+	atom.Figure: true, atom.Figcaption: true, atom.Form: true,
+}
+
+// HTMLLoader loads HTML, extracting visible text and the page title and
+// description
+type HTMLLoader struct{}
+
+// Load parses r as HTML
+func (HTMLLoader) Load(ctx context.Context, r io.Reader) (*Document, error) {
+	root, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Metadata: map[string]string{MetaFormat: "html"}}
+	collectHTMLMetadata(root, doc.Metadata)
+
+	var sb strings.Builder
+	writeHTMLText(&sb, root)
+	doc.Text = CleanText(sb.String())
+	return doc, nil
+}
+
+func collectHTMLMetadata(n *html.Node, meta map[string]string) {
+	if n.Type == html.ElementNode {
+		switch n.DataAtom {
+		case atom.Title:
+			if meta[MetaTitle] == "" {
+				meta[MetaTitle] = strings.TrimSpace(nodeText(n))
+			}
+		case atom.Meta:
+			name := strings.ToLower(attr(n, "name"))
+			if name == "" {
+				name = strings.ToLower(attr(n, "property"))
+			}
+			switch name {
+			case "description", "og:description":
+				if meta["description"] == "" {
+					meta["description"] = attr(n, "content")
+				}
+			case "og:title":
+				if meta[MetaTitle] == "" {
+					meta[MetaTitle] = attr(n, "content")
+				}
+			}
+		case atom.Html:
+			if lang := attr(n, "lang"); lang != "" {
+				meta["language"] = lang
+			}
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		collectHTMLMetadata(c, meta)
+	}
+}
+
+func writeHTMLText(sb *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		sb.WriteString(n.Data)
+		return
+	case html.ElementNode:
+		if skippedElements[n.DataAtom] {
+			return
+		}
+		if n.DataAtom == atom.Img {
+			if alt := attr(n, "alt"); alt != "" {
+				sb.WriteString(alt)
+			}
+			return
+		}
+	case html.CommentNode:
+		return
+	}
+
+	block := n.Type == html.ElementNode && blockElements[n.DataAtom]
+	if block {
+		sb.WriteString("\n\n")
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeHTMLText(sb, c)
+	}
+	if block {
+		sb.WriteString("\n\n")
+	} else if n.Type == html.ElementNode && (n.DataAtom == atom.Td || n.DataAtom == atom.Th) {
+		sb.WriteString(" ")
+	}
+}
+
+func nodeText(n *html.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+		} else {
+			sb.WriteString(nodeText(c))
+		}
+	}
+	return sb.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package loaders
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	vultrai "github.com/eqba1/vultrai"
+)
+
+// ImageLoader reads the text of images, such as scanned pages, with a
+// vision model. It needs a client so it is not registered by default:
+//
+//	loaders.Register(".png", loaders.ImageLoader{Client: client, Model: model})
+type ImageLoader struct {
+	Client  *vultrai.Client
+	Model   string
+	Options []vultrai.OCROption
+}
+
+// Load sends the image in r to the model and returns its text
+func (l ImageLoader) Load(ctx context.Context, r io.Reader) (*Document, error) {
+	if l.Client == nil {
+		return nil, errors.New("image loader has no client")
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := l.Client.ExtractTextFromImage(ctx, l.Model, data, l.Options...)
+	if err != nil {
+		return nil, err
+	}
+	return &Document{
+		Text:     CleanText(result.Text),
+		Metadata: map[string]string{MetaFormat: "image"},
+	}, nil
+}
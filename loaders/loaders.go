@@ -0,0 +1,208 @@
+// Package loaders converts documents in common formats into clean text and
+// metadata ready for ingestion into a vector store collection. Loaders for
+// plain text, Markdown, HTML and PDF are built in; more formats can be added
+// with Register.
+package loaders
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	vultrai "github.com/eqba1/vultrai"
+)
+
+// Metadata keys set by the built-in loaders
+const (
+	MetaTitle  = "title"
+	MetaSource = "source"
+	MetaFormat = "format"
+)
+
+// Document is the text content of a loaded file with its metadata
+type Document struct {
+	Text     string
+	Metadata map[string]string
+}
+
+// Title returns the document title, falling back to its source
+func (d *Document) Title() string {
+	if title := d.Metadata[MetaTitle]; title != "" {
+		return title
+	}
+	return d.Metadata[MetaSource]
+}
+
+// AddItemRequest converts the whole document into a single collection item
+func (d *Document) AddItemRequest() vultrai.AddItemRequest {
+	return vultrai.AddItemRequest{
+		Content:     d.Text,
+		Description: d.Title(),
+	}
+}
+
+// Chunks splits the document into collection items of at most chunkSize
+// characters with the given overlap. Each item is described by the document
+// title and its chunk position.
+func (d *Document) Chunks(chunkSize, overlap int) []vultrai.AddItemRequest {
+	parts := vultrai.SplitText(d.Text, chunkSize, overlap)
+	items := make([]vultrai.AddItemRequest, 0, len(parts))
+	for i, part := range parts {
+		description := d.Title()
+		if len(parts) > 1 {
+			description = fmt.Sprintf("%s (part %d of %d)", description, i+1, len(parts))
+		}
+		items = append(items, vultrai.AddItemRequest{
+			Content:     part,
+			Description: strings.TrimSpace(description),
+		})
+	}
+	return items
+}
+
+// Loader converts a document into text and metadata
+type Loader interface {
+	Load(ctx context.Context, r io.Reader) (*Document, error)
+}
+
+// LoaderFunc adapts a function to the Loader interface
+type LoaderFunc func(ctx context.Context, r io.Reader) (*Document, error)
+
+// Load calls f
+func (f LoaderFunc) Load(ctx context.Context, r io.Reader) (*Document, error) {
+	return f(ctx, r)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Loader{
+		".txt":      TextLoader{},
+		".text":     TextLoader{},
+		".md":       MarkdownLoader{},
+		".markdown": MarkdownLoader{},
+		".html":     HTMLLoader{},
+		".htm":      HTMLLoader{},
+		".pdf":      PDFLoader{},
+	}
+)
+
+// Register makes a loader available for files with the given extension,
+// replacing any existing loader for it
+func Register(ext string, loader Loader) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[normalizeExt(ext)] = loader
+}
+
+// ForFile returns the loader registered for name's extension
+func ForFile(name string) (Loader, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	loader, ok := registry[normalizeExt(filepath.Ext(name))]
+	return loader, ok
+}
+
+// mediaTypes maps MIME types to the extension of the loader handling them
+var mediaTypes = map[string]string{
+	"text/plain":            ".txt",
+	"text/markdown":         ".md",
+	"text/x-markdown":       ".md",
+	"text/html":             ".html",
+	"application/xhtml+xml": ".html",
+	"application/pdf":       ".pdf",
+	"image/png":             ".png",
+	"image/jpeg":            ".jpg",
+	"image/webp":            ".webp",
+}
+
+// ForMediaType returns the loader for a MIME type such as a Content-Type
+// header value. Parameters like charset are ignored.
+func ForMediaType(contentType string) (Loader, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	ext, ok := mediaTypes[mediaType]
+	if !ok {
+		return nil, false
+	}
+	return ForFile("file" + ext)
+}
+
+// Load reads r with the loader registered for name and records name as the
+// document source
+func Load(ctx context.Context, name string, r io.Reader) (*Document, error) {
+	loader, ok := ForFile(name)
+	if !ok {
+		return nil, fmt.Errorf("no loader registered for %q", filepath.Ext(name))
+	}
+
+	doc, err := loader.Load(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s: %w", name, err)
+	}
+	if doc.Metadata == nil {
+		doc.Metadata = make(map[string]string)
+	}
+	if doc.Metadata[MetaSource] == "" {
+		doc.Metadata[MetaSource] = name
+	}
+	return doc, nil
+}
+
+// LoadFile opens and loads the file at path
+func LoadFile(ctx context.Context, path string) (*Document, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Load(ctx, path, f)
+}
+
+func normalizeExt(ext string) string {
+	ext = strings.ToLower(ext)
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+var (
+	horizontalSpace = regexp.MustCompile(`[ \t\f\v\r\x{00a0}]+`)
+	spaceAroundLine = regexp.MustCompile(` *\n *`)
+	blankLines      = regexp.MustCompile(`\n{3,}`)
+)
+
+// CleanText normalizes whitespace: runs of spaces collapse to one, lines
+// are trimmed and at most one blank line separates paragraphs
+func CleanText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = horizontalSpace.ReplaceAllString(s, " ")
+	s = spaceAroundLine.ReplaceAllString(s, "\n")
+	s = blankLines.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
+
+// TextLoader loads plain text
+type TextLoader struct{}
+
+// Load reads r as UTF-8 text
+func (TextLoader) Load(ctx context.Context, r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Document{
+		Text:     CleanText(string(data)),
+		Metadata: map[string]string{MetaFormat: "text"},
+	}, nil
+}
@@ -0,0 +1,231 @@
+package loaders
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextLoader(t *testing.T) {
+	doc, err := Load(context.Background(), "notes.txt", strings.NewReader("Hello   world\r\n\n\n\nSecond  paragraph  "))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Hello world\n\nSecond paragraph", doc.Text)
+	assert.Equal(t, "notes.txt", doc.Metadata[MetaSource])
+	assert.Equal(t, "notes.txt", doc.Title())
+}
+
+func TestMarkdownLoader(t *testing.T) {
+	input := `---
+title: Release Notes
+author: Jane
+---
+
+# Version 2
+
+Some **bold** and _italic_ text with a [link](https://example.com).
+
+- first item
+- second item
+
+` + "```go\nfmt.Println(\"hi\")\n```\n"
+
+	doc, err := MarkdownLoader{}.Load(context.Background(), strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Release Notes", doc.Metadata[MetaTitle])
+	assert.Equal(t, "Jane", doc.Metadata["author"])
+	assert.Contains(t, doc.Text, "Version 2")
+	assert.Contains(t, doc.Text, "Some bold and italic text with a link.")
+	assert.Contains(t, doc.Text, "first item\nsecond item")
+	assert.Contains(t, doc.Text, `fmt.Println("hi")`)
+	assert.NotContains(t, doc.Text, "**")
+	assert.NotContains(t, doc.Text, "```")
+}
+
+func TestHTMLLoader(t *testing.T) {
+	input := `<html lang="en"><head><title>Docs Page</title>
+<meta name="description" content="A page about things">
+<style>body { color: red }</style></head>
+<body><nav>Home</nav><h1>Heading</h1><p>First   paragraph.</p>
+<script>alert("x")</script><p>Second <b>paragraph</b>.</p></body></html>`
+
+	doc, err := HTMLLoader{}.Load(context.Background(), strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Docs Page", doc.Metadata[MetaTitle])
+	assert.Equal(t, "A page about things", doc.Metadata["description"])
+	assert.Equal(t, "en", doc.Metadata["language"])
+	assert.Contains(t, doc.Text, "Heading\n\nFirst paragraph.")
+	assert.Contains(t, doc.Text, "Second paragraph.")
+	assert.NotContains(t, doc.Text, "alert")
+	assert.NotContains(t, doc.Text, "color")
+}
+
+func buildPDF(t *testing.T, content string, compress bool) []byte {
+	t.Helper()
+
+	stream := []byte(content)
+	filter := ""
+	if compress {
+		var buf bytes.Buffer
+		w := zlib.NewWriter(&buf)
+		_, err := w.Write(stream)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		stream = buf.Bytes()
+		filter = " /Filter /FlateDecode"
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	pdf.WriteString("1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	pdf.WriteString("2 0 obj\n<< /Type /Pages /Kids [3 0 R] /Count 1 >>\nendobj\n")
+	pdf.WriteString("3 0 obj\n<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>\nendobj\n")
+	fmt.Fprintf(&pdf, "4 0 obj\n<< /Length %d%s >>\nstream\n", len(stream), filter)
+	pdf.Write(stream)
+	pdf.WriteString("\nendstream\nendobj\n")
+	pdf.WriteString("5 0 obj\n<< /Title (Quarterly \\(Q3\\) Report) /Author (Finance) >>\nendobj\n")
+	pdf.WriteString("trailer\n<< /Root 1 0 R /Info 5 0 R >>\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+func TestPDFLoader(t *testing.T) {
+	content := "BT /F1 12 Tf 72 720 Td (Revenue grew) Tj 0 -14 Td [(by ten) -250 (percent.)] TJ ET"
+
+	for _, compress := range []bool{false, true} {
+		doc, err := PDFLoader{}.Load(context.Background(), bytes.NewReader(buildPDF(t, content, compress)))
+		require.NoError(t, err)
+
+		assert.Equal(t, "Revenue grew\nby ten percent.", doc.Text)
+		assert.Equal(t, "Quarterly (Q3) Report", doc.Metadata[MetaTitle])
+		assert.Equal(t, "Finance", doc.Metadata["author"])
+	}
+}
+
+func TestPDFLoaderErrors(t *testing.T) {
+	_, err := PDFLoader{}.Load(context.Background(), strings.NewReader("plain text"))
+	assert.Error(t, err)
+
+	_, err = PDFLoader{}.Load(context.Background(), bytes.NewReader(buildPDF(t, "0 0 m 10 10 l S", false)))
+	assert.ErrorIs(t, err, ErrNoPDFText)
+
+	encrypted := bytes.Replace(buildPDF(t, "BT (x) Tj ET", false), []byte("/Info 5 0 R"), []byte("/Info 5 0 R /Encrypt 6 0 R"), 1)
+	_, err = PDFLoader{}.Load(context.Background(), bytes.NewReader(encrypted))
+	assert.ErrorIs(t, err, ErrEncryptedPDF)
+
+	// Only the trailer decides whether a PDF is encrypted
+	doc, err := PDFLoader{}.Load(context.Background(), bytes.NewReader(buildPDF(t, "BT (/Encrypt) Tj ET", false)))
+	require.NoError(t, err)
+	assert.Equal(t, "/Encrypt", doc.Text)
+
+	// Composite fonts are refused rather than decoded as Latin-1
+	for _, compress := range []bool{false, true} {
+		cid := buildPDF(t, "BT /F1 12 Tf <0024004C> Tj ET", compress)
+		cid = bytes.Replace(cid, []byte("/Contents 4 0 R"), []byte("/Contents 4 0 R /Resources << /Font << /F1 6 0 R >> >>"), 1)
+		cid = bytes.Replace(cid, []byte("trailer"), []byte("6 0 obj\n<< /Type /Font /Subtype /Type0 /BaseFont /ABCDEF+Arial /Encoding /Identity-H >>\nendobj\ntrailer"), 1)
+		_, err = PDFLoader{}.Load(context.Background(), bytes.NewReader(cid))
+		assert.ErrorIs(t, err, ErrUnsupportedPDFFont)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	_, ok := ForFile("data.csv")
+	assert.False(t, ok)
+
+	Register("CSV", LoaderFunc(func(ctx context.Context, r io.Reader) (*Document, error) {
+		data, _ := io.ReadAll(r)
+		return &Document{Text: strings.ReplaceAll(string(data), ",", " ")}, nil
+	}))
+	defer func() {
+		registryMu.Lock()
+		delete(registry, ".csv")
+		registryMu.Unlock()
+	}()
+
+	doc, err := Load(context.Background(), "data.csv", strings.NewReader("a,b,c"))
+	require.NoError(t, err)
+	assert.Equal(t, "a b c", doc.Text)
+	assert.Equal(t, "data.csv", doc.Metadata[MetaSource])
+
+	_, err = Load(context.Background(), "image.png", strings.NewReader(""))
+	assert.Error(t, err)
+}
+
+func TestDocumentChunks(t *testing.T) {
+	doc := &Document{
+		Text:     strings.Repeat("Sentence number one. ", 20),
+		Metadata: map[string]string{MetaTitle: "Guide"},
+	}
+
+	items := doc.Chunks(100, 10)
+	require.Greater(t, len(items), 1)
+	assert.Equal(t, fmt.Sprintf("Guide (part 1 of %d)", len(items)), items[0].Description)
+	for _, item := range items {
+		assert.LessOrEqual(t, len(item.Content), 100)
+	}
+
+	single := doc.AddItemRequest()
+	assert.Equal(t, "Guide", single.Description)
+}
+
+func TestArticleLoader(t *testing.T) {
+	input := `<html><head><title>Guide</title></head><body>
+<header class="site-header"><a href="/">Home</a></header>
+<div id="sidebar"><ul><li><a href="/x">Popular post</a></li><li><a href="/y">Another post</a></li></ul></div>
+<div class="content">
+<p>The first paragraph explains the feature in detail, with enough words to count as prose.</p>
+<p>The second paragraph continues, adding examples, caveats, and a short summary for readers.</p>
+<div class="share-buttons"><a href="/tw">Tweet</a> <a href="/fb">Share</a></div>
+</div>
+<div class="comments"><p>Great post, thanks for writing this up, it really helped me a lot!</p></div>
+</body></html>`
+
+	doc, err := ArticleLoader{MinLength: 50}.Load(context.Background(), strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Guide", doc.Metadata[MetaTitle])
+	assert.Contains(t, doc.Text, "The first paragraph")
+	assert.Contains(t, doc.Text, "The second paragraph")
+	assert.NotContains(t, doc.Text, "Popular post")
+	assert.NotContains(t, doc.Text, "Tweet")
+	assert.NotContains(t, doc.Text, "Great post")
+}
+
+func TestArticleLoaderFallsBackToFullPage(t *testing.T) {
+	doc, err := ArticleLoader{}.Load(context.Background(), strings.NewReader(`<html><body><span>Short page</span></body></html>`))
+	require.NoError(t, err)
+	assert.Equal(t, "Short page", doc.Text)
+}
+
+func TestForMediaType(t *testing.T) {
+	loader, ok := ForMediaType("text/html; charset=utf-8")
+	require.True(t, ok)
+	assert.IsType(t, HTMLLoader{}, loader)
+
+	_, ok = ForMediaType("image/png")
+	assert.False(t, ok)
+}
+
+func TestImageLoader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"Scanned   page\n\n\n\ntext"}}]}`)
+	}))
+	defer server.Close()
+
+	loader := ImageLoader{Client: vultrai.NewClient("key", vultrai.WithBaseURL(server.URL)), Model: "vision"}
+	doc, err := loader.Load(context.Background(), strings.NewReader("image bytes"))
+	require.NoError(t, err)
+	assert.Equal(t, "Scanned page\n\ntext", doc.Text)
+	assert.Equal(t, "image", doc.Metadata[MetaFormat])
+}
@@ -0,0 +1,116 @@
+package loaders
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	mdImage      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink       = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	mdRefLink    = regexp.MustCompile(`\[([^\]]+)\]\[[^\]]*\]`)
+	mdBold       = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	mdItalic     = regexp.MustCompile(`(^|[^\w*])[*_]([^*_\n]+)[*_]`)
+	mdInlineCode = regexp.MustCompile("`([^`]+)`")
+	mdHeading    = regexp.MustCompile(`^#{1,6}\s+`)
+	mdListMarker = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+`)
+	mdQuote      = regexp.MustCompile(`^\s*>\s?`)
+	mdRule       = regexp.MustCompile(`^\s*([-*_]\s*){3,}$`)
+	mdRefDef     = regexp.MustCompile(`^\s*\[[^\]]+\]:\s+\S+`)
+	mdTableSep   = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+)
+
+// MarkdownLoader loads Markdown, stripping formatting syntax while keeping
+// the text of headings, lists, links and code blocks. YAML front matter
+// fields become metadata and the first heading becomes the title.
+type MarkdownLoader struct{}
+
+// Load reads r as Markdown
+func (MarkdownLoader) Load(ctx context.Context, r io.Reader) (*Document, error) {
+	doc := &Document{Metadata: map[string]string{MetaFormat: "markdown"}}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	var out strings.Builder
+	inFence := false
+	inFrontMatter := false
+	first := true
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if first {
+			first = false
+			if strings.TrimSpace(line) == "---" {
+				inFrontMatter = true
+				continue
+			}
+		}
+		if inFrontMatter {
+			if strings.TrimSpace(line) == "---" {
+				inFrontMatter = false
+				continue
+			}
+			if key, value, ok := strings.Cut(line, ":"); ok {
+				doc.Metadata[strings.ToLower(strings.TrimSpace(key))] = strings.Trim(strings.TrimSpace(value), `"'`)
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			out.WriteString(line)
+			out.WriteString("\n")
+			continue
+		}
+
+		if mdRule.MatchString(line) || mdRefDef.MatchString(line) || mdTableSep.MatchString(line) && strings.Contains(line, "-") {
+			continue
+		}
+
+		if mdHeading.MatchString(trimmed) {
+			heading := stripInlineMarkdown(mdHeading.ReplaceAllString(trimmed, ""))
+			if doc.Metadata[MetaTitle] == "" {
+				doc.Metadata[MetaTitle] = heading
+			}
+			out.WriteString("\n")
+			out.WriteString(heading)
+			out.WriteString("\n\n")
+			continue
+		}
+
+		line = mdQuote.ReplaceAllString(line, "")
+		line = mdListMarker.ReplaceAllString(line, "$1")
+		if strings.HasPrefix(strings.TrimSpace(line), "|") {
+			line = strings.Trim(strings.TrimSpace(line), "|")
+			line = strings.Join(strings.Fields(strings.ReplaceAll(line, "|", " ")), " ")
+		}
+
+		out.WriteString(stripInlineMarkdown(line))
+		out.WriteString("\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	doc.Text = CleanText(out.String())
+	return doc, nil
+}
+
+func stripInlineMarkdown(s string) string {
+	s = mdImage.ReplaceAllString(s, "$1")
+	s = mdLink.ReplaceAllString(s, "$1")
+	s = mdRefLink.ReplaceAllString(s, "$1")
+	s = mdInlineCode.ReplaceAllString(s, "$1")
+	s = mdBold.ReplaceAllString(s, "$2")
+	s = mdItalic.ReplaceAllString(s, "$1$2")
+	return s
+}
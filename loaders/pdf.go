@@ -0,0 +1,512 @@
+package loaders
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+)
+
+var (
+	// ErrEncryptedPDF is returned for password-protected PDFs
+	ErrEncryptedPDF = errors.New("pdf is encrypted")
+	// ErrNoPDFText is returned when a PDF has no extractable text, which
+	// usually means it is a scan and needs OCR
+	ErrNoPDFText = errors.New("pdf contains no extractable text")
+	// ErrUnsupportedPDFFont is returned for PDFs using composite (CID)
+	// fonts, whose text can't be decoded without their CMaps
+	ErrUnsupportedPDFFont = errors.New("pdf uses composite fonts")
+)
+
+// cidFont matches the font dictionaries of composite fonts
+var cidFont = regexp.MustCompile(`/Subtype\s*/Type0\b`)
+
+// PDFLoader extracts text from PDFs without external dependencies. It reads
+// uncompressed and Flate-compressed content streams drawn with simple fonts
+// in Latin-1 compatible encodings, and text strings marked as UTF-16.
+// Composite (CID) fonts, which many PDFs embed for their text, are rejected
+// with ErrUnsupportedPDFFont rather than decoded into garbage. Such PDFs and
+// scans need a dedicated loader, which can be plugged in with
+// Register(".pdf", ...).
+type PDFLoader struct{}
+
+// Load extracts the text of r
+func (PDFLoader) Load(ctx context.Context, r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("%PDF")) {
+		return nil, errors.New("not a pdf file")
+	}
+	if pdfEncrypted(data) {
+		return nil, ErrEncryptedPDF
+	}
+
+	if cidFont.Match(data) {
+		return nil, ErrUnsupportedPDFFont
+	}
+
+	var sb strings.Builder
+	for _, stream := range pdfStreams(data) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// Font dictionaries may sit in compressed object streams
+		if cidFont.Match(stream) {
+			return nil, ErrUnsupportedPDFFont
+		}
+		if bytes.Contains(stream, []byte("BT")) {
+			sb.WriteString(pdfContentText(stream))
+			sb.WriteString("\n\n")
+		}
+	}
+
+	text := CleanText(sb.String())
+	if text == "" {
+		return nil, ErrNoPDFText
+	}
+
+	doc := &Document{
+		Text:     text,
+		Metadata: map[string]string{MetaFormat: "pdf"},
+	}
+	if title := pdfInfoString(data, "/Title"); title != "" {
+		doc.Metadata[MetaTitle] = title
+	}
+	if author := pdfInfoString(data, "/Author"); author != "" {
+		doc.Metadata["author"] = author
+	}
+	return doc, nil
+}
+
+// pdfEncrypted reports whether a trailer dictionary, or the dictionary of
+// a cross-reference stream standing in for one, has an /Encrypt entry
+func pdfEncrypted(data []byte) bool {
+	for offset := 0; ; {
+		idx := bytes.Index(data[offset:], []byte("trailer"))
+		if idx < 0 {
+			break
+		}
+		offset += idx + len("trailer")
+		if pdfHasKey(pdfDict(data[offset:]), "/Encrypt") {
+			return true
+		}
+	}
+
+	for offset := 0; ; {
+		idx := bytes.Index(data[offset:], []byte("/XRef"))
+		if idx < 0 {
+			break
+		}
+		at := offset + idx
+		offset = at + len("/XRef")
+		if objStart := bytes.LastIndex(data[:at], []byte("obj")); objStart >= 0 {
+			if pdfHasKey(pdfDict(data[objStart:]), "/Encrypt") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pdfDict returns the first dictionary in data, nested ones included
+func pdfDict(data []byte) []byte {
+	start := bytes.Index(data, []byte("<<"))
+	if start < 0 {
+		return nil
+	}
+	depth := 0
+	for i := start; i+1 < len(data); i++ {
+		switch {
+		case data[i] == '<' && data[i+1] == '<':
+			depth++
+			i++
+		case data[i] == '>' && data[i+1] == '>':
+			depth--
+			i++
+			if depth == 0 {
+				return data[start : i+1]
+			}
+		}
+	}
+	return nil
+}
+
+// pdfHasKey reports whether dict holds the name key, ignoring strings
+func pdfHasKey(dict []byte, key string) bool {
+	lex := &pdfLexer{data: dict}
+	for {
+		tok, ok := lex.next()
+		if !ok {
+			return false
+		}
+		if tok.kind == pdfOther && tok.text == key {
+			return true
+		}
+	}
+}
+
+// pdfStreams returns the decoded contents of every stream object that is
+// not an image, font or other binary payload
+func pdfStreams(data []byte) [][]byte {
+	var streams [][]byte
+
+	offset := 0
+	for {
+		idx := bytes.Index(data[offset:], []byte("stream"))
+		if idx < 0 {
+			break
+		}
+		start := offset + idx
+		offset = start + len("stream")
+
+		// Skip "endstream" and keywords that merely contain "stream"
+		if start >= 3 && string(data[start-3:start]) == "end" {
+			continue
+		}
+
+		bodyStart := offset
+		if bodyStart < len(data) && data[bodyStart] == '\r' {
+			bodyStart++
+		}
+		if bodyStart < len(data) && data[bodyStart] == '\n' {
+			bodyStart++
+		}
+
+		end := bytes.Index(data[bodyStart:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		body := bytes.TrimRight(data[bodyStart:bodyStart+end], "\r\n")
+		offset = bodyStart + end
+
+		dict := data[:start]
+		if objStart := bytes.LastIndex(dict, []byte("obj")); objStart >= 0 {
+			dict = dict[objStart:]
+		}
+		if skipPDFStream(dict) {
+			continue
+		}
+
+		if bytes.Contains(dict, []byte("/FlateDecode")) {
+			decoded, err := inflate(body)
+			if err != nil {
+				continue
+			}
+			body = decoded
+		} else if bytes.Contains(dict, []byte("/Filter")) {
+			// Other filters (DCT, LZW, ASCII85...) are not supported
+			continue
+		}
+
+		streams = append(streams, body)
+	}
+
+	return streams
+}
+
+func skipPDFStream(dict []byte) bool {
+	for _, marker := range []string{"/Image", "/FontFile", "/Length1", "/XRef", "/Metadata", "/ICCBased", "/EmbeddedFile"} {
+		if bytes.Contains(dict, []byte(marker)) {
+			return true
+		}
+	}
+	return false
+}
+
+func inflate(data []byte) ([]byte, error) {
+	if r, err := zlib.NewReader(bytes.NewReader(data)); err == nil {
+		defer r.Close()
+		if out, err := io.ReadAll(r); err == nil || len(out) > 0 {
+			return out, nil
+		}
+	}
+
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// pdfContentText interprets the text operators of a content stream
+func pdfContentText(content []byte) string {
+	var sb strings.Builder
+	lex := &pdfLexer{data: content}
+	var operands []pdfToken
+
+	for {
+		tok, ok := lex.next()
+		if !ok {
+			break
+		}
+		if tok.kind != pdfOperator {
+			operands = append(operands, tok)
+			continue
+		}
+
+		switch tok.text {
+		case "Tj":
+			writeOperandStrings(&sb, operands, false)
+		case "'", "\"":
+			sb.WriteString("\n")
+			writeOperandStrings(&sb, operands, false)
+		case "TJ":
+			writeOperandStrings(&sb, operands, true)
+		case "T*", "ET":
+			sb.WriteString("\n")
+		case "Td", "TD":
+			if len(operands) >= 2 && operands[len(operands)-1].text != "0" {
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString(" ")
+			}
+		case "Tm":
+			sb.WriteString("\n")
+		}
+		operands = operands[:0]
+	}
+
+	return sb.String()
+}
+
+// writeOperandStrings writes string operands; inside TJ arrays a large
+// negative kerning adjustment stands for a word space
+func writeOperandStrings(sb *strings.Builder, operands []pdfToken, kerning bool) {
+	for _, op := range operands {
+		switch op.kind {
+		case pdfString:
+			sb.WriteString(op.text)
+		case pdfNumber:
+			if kerning && strings.HasPrefix(op.text, "-") && len(op.text) >= 4 {
+				sb.WriteString(" ")
+			}
+		}
+	}
+}
+
+// pdfInfoString finds an uncompressed document information entry such as /Title
+func pdfInfoString(data []byte, key string) string {
+	idx := bytes.Index(data, []byte(key))
+	if idx < 0 {
+		return ""
+	}
+
+	lex := &pdfLexer{data: data[idx+len(key):]}
+	tok, ok := lex.next()
+	if !ok || tok.kind != pdfString {
+		return ""
+	}
+	return strings.TrimSpace(tok.text)
+}
+
+type pdfTokenKind int
+
+const (
+	pdfOperator pdfTokenKind = iota
+	pdfString
+	pdfNumber
+	pdfOther
+)
+
+type pdfToken struct {
+	kind pdfTokenKind
+	text string
+}
+
+// pdfLexer tokenizes PDF content streams. Array brackets are dropped so TJ
+// operands appear as a flat list of strings and numbers.
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func (l *pdfLexer) next() (pdfToken, bool) {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case isPDFSpace(c) || c == '[' || c == ']':
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		case c == '(':
+			return pdfToken{kind: pdfString, text: decodePDFBytes(l.literalString())}, true
+		case c == '<':
+			if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+				l.pos += 2
+				return pdfToken{kind: pdfOther, text: "<<"}, true
+			}
+			return pdfToken{kind: pdfString, text: decodePDFBytes(l.hexString())}, true
+		case c == '>':
+			l.pos++
+			if l.pos < len(l.data) && l.data[l.pos] == '>' {
+				l.pos++
+			}
+			return pdfToken{kind: pdfOther, text: ">>"}, true
+		case c == '/':
+			start := l.pos
+			l.pos++
+			for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+				l.pos++
+			}
+			return pdfToken{kind: pdfOther, text: string(l.data[start:l.pos])}, true
+		default:
+			start := l.pos
+			for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+				l.pos++
+			}
+			if l.pos == start {
+				l.pos++
+				continue
+			}
+			word := string(l.data[start:l.pos])
+			if isPDFNumber(word) {
+				return pdfToken{kind: pdfNumber, text: word}, true
+			}
+			return pdfToken{kind: pdfOperator, text: word}, true
+		}
+	}
+	return pdfToken{}, false
+}
+
+func (l *pdfLexer) literalString() []byte {
+	l.pos++ // opening parenthesis
+	var out []byte
+	depth := 1
+
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+
+		switch c {
+		case '\\':
+			if l.pos >= len(l.data) {
+				return out
+			}
+			esc := l.data[l.pos]
+			l.pos++
+			switch esc {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r', '\n':
+				// Line continuation
+			default:
+				if esc >= '0' && esc <= '7' {
+					value := int(esc - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						value = value*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					out = append(out, byte(value))
+				} else {
+					out = append(out, esc)
+				}
+			}
+		case '(':
+			depth++
+			out = append(out, c)
+		case ')':
+			depth--
+			if depth == 0 {
+				return out
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+func (l *pdfLexer) hexString() []byte {
+	l.pos++ // opening angle bracket
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; isHexDigit(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++ // closing angle bracket
+
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	for i := range out {
+		out[i] = hexValue(digits[2*i])<<4 | hexValue(digits[2*i+1])
+	}
+	return out
+}
+
+// decodePDFBytes interprets a PDF string as UTF-16BE when it carries a
+// byte order mark and as Latin-1 otherwise, which matches PDFDocEncoding
+// and the standard encodings of simple fonts for most Latin text
+func decodePDFBytes(b []byte) string {
+	if len(b) >= 2 && b[0] == 0xFE && b[1] == 0xFF {
+		units := make([]uint16, 0, (len(b)-2)/2)
+		for i := 2; i+1 < len(b); i += 2 {
+			units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+
+	runes := make([]rune, 0, len(b))
+	for _, c := range b {
+		if c < 0x20 && c != '\n' && c != '\t' {
+			continue
+		}
+		runes = append(runes, rune(c))
+	}
+	return string(runes)
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func isPDFNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		if (c < '0' || c > '9') && c != '.' && !(i == 0 && (c == '-' || c == '+')) {
+			return false
+		}
+	}
+	return s != "-" && s != "+" && s != "."
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func hexValue(c byte) byte {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0'
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
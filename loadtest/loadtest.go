@@ -0,0 +1,193 @@
+// Package loadtest fires concurrent chat completion requests at a model and
+// reports latency percentiles, error rates and throughput. It is intended for
+// capacity planning before moving traffic to a new model.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	vultrai "github.com/eqba1/vultrai"
+)
+
+// Completer is the subset of the client used by the load tester
+type Completer interface {
+	CreateChatCompletion(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.ChatCompletionResponse, error)
+}
+
+// Config describes a load test run
+type Config struct {
+	Model       string
+	Prompt      string
+	Requests    int           // total number of requests to send
+	Concurrency int           // number of requests in flight at once
+	MaxTokens   int           // optional completion token limit
+	Timeout     time.Duration // optional per-request timeout
+}
+
+// Report summarizes the results of a load test run
+type Report struct {
+	Requests         int
+	Errors           int
+	ErrorRate        float64
+	Duration         time.Duration
+	Throughput       float64 // successful requests per second
+	TokensPerSecond  float64 // completion tokens per second across all requests
+	CompletionTokens int
+	Min              time.Duration
+	Mean             time.Duration
+	P50              time.Duration
+	P90              time.Duration
+	P95              time.Duration
+	P99              time.Duration
+	Max              time.Duration
+	ErrorCounts      map[string]int // error messages and how often they occurred
+}
+
+type result struct {
+	latency time.Duration
+	tokens  int
+	err     error
+}
+
+// Run executes the load test described by cfg and returns its report
+func Run(ctx context.Context, client Completer, cfg Config) (*Report, error) {
+	if cfg.Requests <= 0 {
+		return nil, errors.New("requests must be greater than 0")
+	}
+	if cfg.Model == "" || cfg.Prompt == "" {
+		return nil, errors.New("model and prompt are required")
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+
+	req := vultrai.ChatCompletionRequest{
+		Model:    cfg.Model,
+		Messages: []vultrai.Message{vultrai.CreateUserMessage(cfg.Prompt)},
+	}
+	if cfg.MaxTokens > 0 {
+		req.MaxTokens = vultrai.Int(cfg.MaxTokens)
+	}
+
+	jobs := make(chan struct{})
+	results := make(chan result, cfg.Requests)
+
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				results <- send(ctx, client, req, cfg.Timeout)
+			}
+		}()
+	}
+
+	start := time.Now()
+	sent := 0
+	for ; sent < cfg.Requests; sent++ {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- struct{}{}
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+
+	collected := make([]result, 0, sent)
+	for r := range results {
+		collected = append(collected, r)
+	}
+
+	return buildReport(collected, time.Since(start)), nil
+}
+
+func send(ctx context.Context, client Completer, req vultrai.ChatCompletionRequest, timeout time.Duration) result {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	resp, err := client.CreateChatCompletion(ctx, req)
+	r := result{latency: time.Since(start), err: err}
+	if err == nil && resp != nil {
+		r.tokens = resp.Usage.CompletionTokens
+	}
+	return r
+}
+
+func buildReport(results []result, elapsed time.Duration) *Report {
+	report := &Report{
+		Requests:    len(results),
+		Duration:    elapsed,
+		ErrorCounts: make(map[string]int),
+	}
+
+	var latencies []time.Duration
+	var total time.Duration
+	for _, r := range results {
+		if r.err != nil {
+			report.Errors++
+			report.ErrorCounts[r.err.Error()]++
+			continue
+		}
+		latencies = append(latencies, r.latency)
+		total += r.latency
+		report.CompletionTokens += r.tokens
+	}
+
+	if report.Requests > 0 {
+		report.ErrorRate = float64(report.Errors) / float64(report.Requests)
+	}
+	if elapsed > 0 {
+		report.Throughput = float64(len(latencies)) / elapsed.Seconds()
+		report.TokensPerSecond = float64(report.CompletionTokens) / elapsed.Seconds()
+	}
+
+	if len(latencies) == 0 {
+		return report
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	report.Min = latencies[0]
+	report.Max = latencies[len(latencies)-1]
+	report.Mean = total / time.Duration(len(latencies))
+	report.P50 = percentile(latencies, 50)
+	report.P90 = percentile(latencies, 90)
+	report.P95 = percentile(latencies, 95)
+	report.P99 = percentile(latencies, 99)
+
+	return report
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// String formats the report for terminal output
+func (r *Report) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Requests:    %d (%d errors, %.1f%%)\n", r.Requests, r.Errors, r.ErrorRate*100)
+	fmt.Fprintf(&sb, "Duration:    %s\n", r.Duration.Round(time.Millisecond))
+	fmt.Fprintf(&sb, "Throughput:  %.2f req/s, %.1f tokens/s\n", r.Throughput, r.TokensPerSecond)
+	fmt.Fprintf(&sb, "Latency:     min %s, mean %s, max %s\n", r.Min, r.Mean, r.Max)
+	fmt.Fprintf(&sb, "Percentiles: p50 %s, p90 %s, p95 %s, p99 %s\n", r.P50, r.P90, r.P95, r.P99)
+	for msg, count := range r.ErrorCounts {
+		fmt.Fprintf(&sb, "Error (%dx): %s\n", count, msg)
+	}
+	return sb.String()
+}
@@ -0,0 +1,59 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCompleter struct {
+	calls int32
+}
+
+func (f *fakeCompleter) CreateChatCompletion(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.ChatCompletionResponse, error) {
+	n := atomic.AddInt32(&f.calls, 1)
+	time.Sleep(time.Millisecond)
+	if n%5 == 0 {
+		return nil, errors.New("overloaded")
+	}
+	return &vultrai.ChatCompletionResponse{Usage: vultrai.Usage{CompletionTokens: 10}}, nil
+}
+
+func TestRun(t *testing.T) {
+	client := &fakeCompleter{}
+
+	report, err := Run(context.Background(), client, Config{
+		Model:       "test-model",
+		Prompt:      "Hello",
+		Requests:    20,
+		Concurrency: 4,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 20, report.Requests)
+	assert.Equal(t, 4, report.Errors)
+	assert.InDelta(t, 0.2, report.ErrorRate, 0.001)
+	assert.Equal(t, 160, report.CompletionTokens)
+	assert.Equal(t, 4, report.ErrorCounts["overloaded"])
+	assert.True(t, report.P50 <= report.P99)
+	assert.True(t, report.Min <= report.Max)
+	assert.NotEmpty(t, report.String())
+}
+
+func TestRunValidatesConfig(t *testing.T) {
+	_, err := Run(context.Background(), &fakeCompleter{}, Config{Model: "m", Prompt: "p"})
+	assert.Error(t, err)
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.Equal(t, time.Duration(5), percentile(sorted, 50))
+	assert.Equal(t, time.Duration(9), percentile(sorted, 90))
+	assert.Equal(t, time.Duration(10), percentile(sorted, 99))
+}
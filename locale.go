@@ -0,0 +1,276 @@
+package vultrai
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Language is a guess at the language of a text
+type Language struct {
+	// Code is the ISO-639-1 code, or "" when unknown
+	Code string
+	// Confidence is the share of the evidence supporting Code, from 0 to 1
+	Confidence float64
+}
+
+// languageNames maps supported codes to their English names
+var languageNames = map[string]string{
+	"ar": "Arabic", "de": "German", "el": "Greek", "en": "English", "es": "Spanish",
+	"fa": "Persian", "fr": "French", "he": "Hebrew", "hi": "Hindi", "it": "Italian",
+	"ja": "Japanese", "ko": "Korean", "nl": "Dutch", "pt": "Portuguese", "ru": "Russian",
+	"th": "Thai", "tr": "Turkish", "uk": "Ukrainian", "ur": "Urdu", "zh": "Chinese",
+}
+
+// rtlLanguages are written right to left
+var rtlLanguages = map[string]bool{"ar": true, "fa": true, "he": true, "ur": true}
+
+// latinStopwords are frequent short words telling Latin-script languages
+// apart
+var latinStopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "of", "to", "in", "that", "it", "you", "what", "how", "with", "for", "this"},
+	"es": {"el", "la", "los", "las", "de", "que", "y", "es", "en", "un", "una", "por", "con", "para", "cómo", "qué"},
+	"fr": {"le", "la", "les", "de", "des", "et", "est", "un", "une", "que", "en", "pour", "avec", "vous", "je", "pas"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "ich", "sie", "mit", "zu", "wie", "was", "auf"},
+	"it": {"il", "lo", "la", "di", "che", "e", "è", "un", "una", "per", "non", "sono", "come", "con", "gli"},
+	"pt": {"o", "a", "os", "as", "de", "que", "e", "é", "um", "uma", "não", "para", "com", "como", "do", "da"},
+	"nl": {"de", "het", "een", "en", "is", "van", "niet", "ik", "je", "dat", "met", "op", "wat", "hoe", "zijn"},
+	"tr": {"ve", "bir", "bu", "da", "de", "ne", "için", "ile", "mi", "çok", "nasıl", "var", "değil", "ben", "sen"},
+}
+
+// DetectLanguage guesses the language of text from its script and, for
+// Latin script, from common words. It is meant for routing prompts on user
+// input, not for linguistic accuracy; short texts yield low confidence.
+func DetectLanguage(text string) Language {
+	scripts := make(map[string]int)
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		scripts[scriptLanguage(r)]++
+	}
+	if letters == 0 {
+		return Language{}
+	}
+
+	best, count := "", 0
+	for code, n := range scripts {
+		if n > count || n == count && code < best {
+			best, count = code, n
+		}
+	}
+	confidence := float64(count) / float64(letters)
+
+	switch best {
+	case "latin":
+		code, share := latinLanguage(text)
+		return Language{Code: code, Confidence: confidence * share}
+	case "arabic":
+		return Language{Code: arabicLanguage(text), Confidence: confidence}
+	case "cyrillic":
+		if strings.ContainsAny(text, "іїєґІЇЄҐ") {
+			return Language{Code: "uk", Confidence: confidence}
+		}
+		return Language{Code: "ru", Confidence: confidence}
+	case "han":
+		// Kanji is shared by Japanese, which is recognized by its kana
+		if scripts["ja"] > 0 {
+			return Language{Code: "ja", Confidence: float64(count+scripts["ja"]) / float64(letters)}
+		}
+		return Language{Code: "zh", Confidence: confidence}
+	case "ja":
+		return Language{Code: "ja", Confidence: float64(count+scripts["han"]) / float64(letters)}
+	}
+	return Language{Code: best, Confidence: confidence}
+}
+
+// scriptLanguage maps a letter to its script, or directly to a language
+// when the script has one main language
+func scriptLanguage(r rune) string {
+	switch {
+	case r < 0x250 || unicode.Is(unicode.Latin, r):
+		return "latin"
+	case unicode.Is(unicode.Arabic, r):
+		return "arabic"
+	case unicode.Is(unicode.Hebrew, r):
+		return "he"
+	case unicode.Is(unicode.Cyrillic, r):
+		return "cyrillic"
+	case unicode.Is(unicode.Greek, r):
+		return "el"
+	case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+		return "ja"
+	case unicode.Is(unicode.Hangul, r):
+		return "ko"
+	case unicode.Is(unicode.Han, r):
+		return "han"
+	case unicode.Is(unicode.Thai, r):
+		return "th"
+	case unicode.Is(unicode.Devanagari, r):
+		return "hi"
+	}
+	return ""
+}
+
+// arabicLanguage tells Arabic-script languages apart by the letters
+// specific to each
+func arabicLanguage(text string) string {
+	switch {
+	case strings.ContainsAny(text, "ٹڈڑےں"):
+		return "ur"
+	case strings.ContainsAny(text, "پچژگکی"):
+		return "fa"
+	}
+	return "ar"
+}
+
+// latinLanguage scores Latin-script text against the stopword lists,
+// returning the best language and its share of the matches. Text without
+// any stopword is assumed to be English with low confidence.
+func latinLanguage(text string) (string, float64) {
+	scores := make(map[string]int)
+	total := 0
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		for code, stopwords := range latinStopwords {
+			for _, stopword := range stopwords {
+				if word == stopword {
+					scores[code]++
+					total++
+					break
+				}
+			}
+		}
+	}
+	if total == 0 {
+		return "en", 0.2
+	}
+
+	best, count := "", 0
+	for code, n := range scores {
+		if n > count || n == count && code < best {
+			best, count = code, n
+		}
+	}
+	return best, float64(count) / float64(total)
+}
+
+// LanguageName returns the English name of an ISO-639-1 code or locale
+// such as "fa-IR", or the code itself when unknown
+func LanguageName(code string) string {
+	if name, ok := languageNames[baseLanguage(code)]; ok {
+		return name
+	}
+	return code
+}
+
+// IsRTL reports whether the language of a code or locale is written right
+// to left
+func IsRTL(code string) bool {
+	return rtlLanguages[baseLanguage(code)]
+}
+
+// LanguageInstruction returns a sentence asking the model to answer in the
+// language of code, for appending to system prompts
+func LanguageInstruction(code string) string {
+	if code == "" {
+		return "Respond in the same language as the user."
+	}
+	return "Respond in " + LanguageName(code) + "."
+}
+
+// baseLanguage reduces a locale such as "pt_BR" or "fa-IR" to its language
+func baseLanguage(locale string) string {
+	locale = strings.ToLower(locale)
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+// LocalePrompts holds a system prompt per locale. Keys are locales such as
+// "pt-BR" or languages such as "pt"; the "" key is the fallback.
+type LocalePrompts map[string]string
+
+// For returns the prompt for locale, falling back to its language and then
+// to the default prompt
+func (p LocalePrompts) For(locale string) string {
+	if prompt, ok := p[locale]; ok {
+		return prompt
+	}
+	normalized := strings.ReplaceAll(strings.ToLower(locale), "_", "-")
+	for key, prompt := range p {
+		if strings.ReplaceAll(strings.ToLower(key), "_", "-") == normalized {
+			return prompt
+		}
+	}
+	if prompt, ok := p[baseLanguage(locale)]; ok {
+		return prompt
+	}
+	return p[""]
+}
+
+// SystemMessageFor detects the language of input and returns the matching
+// system prompt. When only the default prompt applies, an instruction to
+// respond in the detected language is appended to it.
+func (p LocalePrompts) SystemMessageFor(input string) Message {
+	lang := DetectLanguage(input)
+	if _, ok := p[lang.Code]; ok && lang.Code != "" {
+		return CreateSystemMessage(p[lang.Code])
+	}
+	prompt := p[""]
+	if lang.Code != "" {
+		prompt = strings.TrimSpace(prompt + " " + LanguageInstruction(lang.Code))
+	}
+	return CreateSystemMessage(prompt)
+}
+
+// Unicode directional isolates and the controls StripBidiControls removes
+const (
+	firstStrongIsolate = '\u2068'
+	popDirectional     = '\u2069'
+)
+
+// IsolateBidi wraps text containing right-to-left characters in Unicode
+// directional isolates, so it cannot reorder the text around it when
+// concatenated with left-to-right text. Other text is returned unchanged.
+func IsolateBidi(text string) string {
+	if !hasRTL(text) {
+		return text
+	}
+	return string(firstStrongIsolate) + text + string(popDirectional)
+}
+
+// JoinBidi joins parts with sep, isolating those containing right-to-left
+// text so that mixed-direction pieces such as names in templates keep
+// their order
+func JoinBidi(parts []string, sep string) string {
+	isolated := make([]string, len(parts))
+	for i, part := range parts {
+		isolated[i] = IsolateBidi(part)
+	}
+	return strings.Join(isolated, sep)
+}
+
+// StripBidiControls removes explicit directional formatting characters,
+// which can hide or reorder text shown to users and reviewers
+func StripBidiControls(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '\u202a' && r <= '\u202e', r >= '\u2066' && r <= '\u2069', r == '\u200e', r == '\u200f', r == '\u061c':
+			return -1
+		}
+		return r
+	}, text)
+}
+
+func hasRTL(text string) bool {
+	for _, r := range text {
+		if unicode.In(r, unicode.Arabic, unicode.Hebrew, unicode.Syriac, unicode.Thaana, unicode.Nko) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package vultrai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := map[string]string{
+		"فارسی من چطوره؟":                      "fa",
+		"كيف حالك اليوم؟":                      "ar",
+		"שלום, מה שלומך?":                      "he",
+		"Привет, как дела?":                    "ru",
+		"Привіт, як справи? Їжак":              "uk",
+		"今日はいい天気ですね":                           "ja",
+		"今天天气很好":                               "zh",
+		"안녕하세요":                                "ko",
+		"What is the capital of France?":       "en",
+		"¿Cómo está el clima en la ciudad?":    "es",
+		"Je ne sais pas ce que vous voulez":    "fr",
+		"Ich weiß nicht, was das ist":          "de",
+		"Dit is niet wat ik wil, het is fout.": "nl",
+	}
+	for text, want := range tests {
+		assert.Equal(t, want, DetectLanguage(text).Code, text)
+	}
+
+	assert.Equal(t, Language{}, DetectLanguage("123 !?"))
+	assert.Less(t, DetectLanguage("Xyzzy").Confidence, 0.5)
+}
+
+func TestLocalePrompts(t *testing.T) {
+	prompts := LocalePrompts{
+		"":      "You are a helpful assistant.",
+		"fa":    "شما یک دستیار مفید هستید.",
+		"pt-BR": "Você é um assistente útil.",
+	}
+	assert.Equal(t, prompts["pt-BR"], prompts.For("pt_br"))
+	assert.Equal(t, prompts["fa"], prompts.For("fa-IR"))
+	assert.Equal(t, prompts[""], prompts.For("de"))
+
+	assert.Equal(t, prompts["fa"], prompts.SystemMessageFor("فارسی من چطوره؟").Content)
+	assert.Equal(t, "You are a helpful assistant. Respond in German.", prompts.SystemMessageFor("Wie ist das Wetter in der Stadt?").Content)
+
+	assert.True(t, IsRTL("fa-IR"))
+	assert.False(t, IsRTL("en"))
+	assert.Equal(t, "Persian", LanguageName("fa_IR"))
+}
+
+func TestBidi(t *testing.T) {
+	assert.Equal(t, "plain", IsolateBidi("plain"))
+	assert.Equal(t, "\u2068سلام\u2069", IsolateBidi("سلام"))
+	assert.Equal(t, "Hello \u2068علی\u2069!", JoinBidi([]string{"Hello", "علی"}, " ")+"!")
+	assert.Equal(t, "safe text", StripBidiControls("safe\u202e \u2066text\u2069\u200f"))
+}
@@ -0,0 +1,219 @@
+package vultrai
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MarkdownBlock is the kind of a markdown block
+type MarkdownBlock int
+
+const (
+	// MarkdownParagraph is a run of text lines
+	MarkdownParagraph MarkdownBlock = iota
+	// MarkdownHeading is an ATX heading such as "## Usage"
+	MarkdownHeading
+	// MarkdownListItem is a bullet or numbered list item
+	MarkdownListItem
+	// MarkdownCodeBlock is a fenced code block
+	MarkdownCodeBlock
+	// MarkdownQuote is a block quote
+	MarkdownQuote
+	// MarkdownRule is a thematic break such as "---"
+	MarkdownRule
+)
+
+// MarkdownEvent is a complete markdown block, emitted once it can no
+// longer change
+type MarkdownEvent struct {
+	Type MarkdownBlock
+	// Text is the content without markup: heading text, item text, code
+	// or quoted text. Inline formatting is left as is.
+	Text string
+	// Level is the heading level, or the nesting depth of a list item
+	// starting at 0
+	Level int
+	// Ordered and Number describe numbered list items
+	Ordered bool
+	Number  int
+	// Language is the info string of a fenced code block
+	Language string
+	// Raw is the markdown source of the block
+	Raw string
+}
+
+var (
+	mdHeading  = regexp.MustCompile(`^(#{1,6})(?:[ \t]+(.*?))?[ \t]*#*[ \t]*$`)
+	mdRule     = regexp.MustCompile(`^(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	mdBullet   = regexp.MustCompile(`^[-*+][ \t]+(.*)$`)
+	mdNumbered = regexp.MustCompile(`^(\d{1,9})[.)][ \t]+(.*)$`)
+	mdFence    = regexp.MustCompile("^(`{3,}|~{3,})[ \t]*([^`]*)$")
+)
+
+// MarkdownStream parses markdown incrementally as deltas arrive and emits
+// each block once it is complete, so clients can render partial answers
+// without flicker or broken code fences. Text not yet emitted is available
+// from Pending for a plain-text preview.
+type MarkdownStream struct {
+	emit func(MarkdownEvent) error
+	err  error
+
+	partial string // incomplete last line
+	current *MarkdownEvent
+	lines   []string // raw lines of current
+	fence   string   // opening fence while inside a code block
+}
+
+// NewMarkdownStream creates a parser calling emit for each complete block
+func NewMarkdownStream(emit func(MarkdownEvent) error) *MarkdownStream {
+	return &MarkdownStream{emit: emit}
+}
+
+// Write feeds a delta to the parser. It returns the first error returned
+// by emit, after which the stream stops emitting.
+func (m *MarkdownStream) Write(delta string) error {
+	if m.err != nil {
+		return m.err
+	}
+	text := m.partial + delta
+	for {
+		nl := strings.IndexByte(text, '\n')
+		if nl < 0 {
+			break
+		}
+		m.line(strings.TrimSuffix(text[:nl], "\r"))
+		text = text[nl+1:]
+		if m.err != nil {
+			return m.err
+		}
+	}
+	m.partial = text
+	return nil
+}
+
+// Callback returns a StreamCallback feeding the content of the first
+// choice to the parser, then calling next if it is not nil
+func (m *MarkdownStream) Callback(next StreamCallback) StreamCallback {
+	return func(chunk *StreamChatCompletion) error {
+		if len(chunk.Choices) > 0 {
+			if err := m.Write(chunk.Choices[0].Delta.Content); err != nil {
+				return err
+			}
+		}
+		if next != nil {
+			return next(chunk)
+		}
+		return nil
+	}
+}
+
+// Flush ends the stream, emitting the last block even if incomplete. An
+// unclosed code block is emitted with the code received so far.
+func (m *MarkdownStream) Flush() error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.partial != "" {
+		m.line(m.partial)
+		m.partial = ""
+	}
+	m.finish()
+	return m.err
+}
+
+// Pending returns the markdown received but not yet emitted
+func (m *MarkdownStream) Pending() string {
+	if len(m.lines) == 0 {
+		return m.partial
+	}
+	return strings.Join(m.lines, "\n") + "\n" + m.partial
+}
+
+// line processes a complete line
+func (m *MarkdownStream) line(line string) {
+	if m.fence != "" {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, m.fence) && strings.Trim(trimmed, m.fence[:1]) == "" {
+			m.lines = append(m.lines, line)
+			m.finish()
+			return
+		}
+		if m.current.Text != "" || len(m.lines) > 1 {
+			m.current.Text += "\n"
+		}
+		m.current.Text += line
+		m.lines = append(m.lines, line)
+		return
+	}
+
+	trimmed := strings.TrimLeft(line, " \t")
+	indent := len(line) - len(trimmed)
+
+	switch {
+	case strings.TrimSpace(line) == "":
+		m.finish()
+
+	case indent < 4 && mdFence.MatchString(trimmed):
+		match := mdFence.FindStringSubmatch(trimmed)
+		m.start(MarkdownEvent{Type: MarkdownCodeBlock, Language: strings.TrimSpace(match[2])}, line)
+		m.fence = match[1]
+
+	case indent < 4 && mdHeading.MatchString(trimmed):
+		match := mdHeading.FindStringSubmatch(trimmed)
+		m.start(MarkdownEvent{Type: MarkdownHeading, Level: len(match[1]), Text: match[2]}, line)
+		m.finish()
+
+	case indent < 4 && mdRule.MatchString(trimmed):
+		m.start(MarkdownEvent{Type: MarkdownRule}, line)
+		m.finish()
+
+	case mdBullet.MatchString(trimmed):
+		match := mdBullet.FindStringSubmatch(trimmed)
+		m.start(MarkdownEvent{Type: MarkdownListItem, Level: indent / 2, Text: match[1]}, line)
+
+	case mdNumbered.MatchString(trimmed):
+		match := mdNumbered.FindStringSubmatch(trimmed)
+		number, _ := strconv.Atoi(match[1])
+		m.start(MarkdownEvent{Type: MarkdownListItem, Level: indent / 3, Ordered: true, Number: number, Text: match[2]}, line)
+
+	case strings.HasPrefix(trimmed, ">"):
+		text := strings.TrimPrefix(strings.TrimPrefix(trimmed, ">"), " ")
+		if m.current != nil && m.current.Type == MarkdownQuote {
+			m.append(line, text)
+		} else {
+			m.start(MarkdownEvent{Type: MarkdownQuote, Text: text}, line)
+		}
+
+	case m.current != nil:
+		// Continuation of a paragraph, list item or quote
+		m.append(line, strings.TrimSpace(line))
+
+	default:
+		m.start(MarkdownEvent{Type: MarkdownParagraph, Text: strings.TrimSpace(line)}, line)
+	}
+}
+
+// start emits the current block and begins a new one
+func (m *MarkdownStream) start(event MarkdownEvent, line string) {
+	m.finish()
+	m.current = &event
+	m.lines = []string{line}
+}
+
+// append adds a line to the current block
+func (m *MarkdownStream) append(line, text string) {
+	m.current.Text += "\n" + text
+	m.lines = append(m.lines, line)
+}
+
+// finish emits the current block, if any
+func (m *MarkdownStream) finish() {
+	if m.current == nil || m.err != nil {
+		return
+	}
+	event := *m.current
+	event.Raw = strings.Join(m.lines, "\n")
+	m.current, m.lines, m.fence = nil, nil, ""
+	m.err = m.emit(event)
+}
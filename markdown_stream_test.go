@@ -0,0 +1,66 @@
+package vultrai
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const streamedMarkdown = "# Setup\n\nInstall the SDK and\nset your key.\n\n" +
+	"- first\n  continued\n- second\n  - nested\n\n" +
+	"2. two\n3. three\n\n" +
+	"```go\nfmt.Println(\"hi\")\n\n// done\n```\n" +
+	"> quoted\n> text\n\n---\nTrailing"
+
+func TestMarkdownStream(t *testing.T) {
+	want := []MarkdownEvent{
+		{Type: MarkdownHeading, Level: 1, Text: "Setup", Raw: "# Setup"},
+		{Type: MarkdownParagraph, Text: "Install the SDK and\nset your key.", Raw: "Install the SDK and\nset your key."},
+		{Type: MarkdownListItem, Text: "first\ncontinued", Raw: "- first\n  continued"},
+		{Type: MarkdownListItem, Text: "second", Raw: "- second"},
+		{Type: MarkdownListItem, Level: 1, Text: "nested", Raw: "  - nested"},
+		{Type: MarkdownListItem, Ordered: true, Number: 2, Text: "two", Raw: "2. two"},
+		{Type: MarkdownListItem, Ordered: true, Number: 3, Text: "three", Raw: "3. three"},
+		{Type: MarkdownCodeBlock, Language: "go", Text: "fmt.Println(\"hi\")\n\n// done", Raw: "```go\nfmt.Println(\"hi\")\n\n// done\n```"},
+		{Type: MarkdownQuote, Text: "quoted\ntext", Raw: "> quoted\n> text"},
+		{Type: MarkdownRule, Raw: "---"},
+		{Type: MarkdownParagraph, Text: "Trailing", Raw: "Trailing"},
+	}
+
+	// The events do not depend on how the text is split into deltas
+	for _, size := range []int{1, 3, 7, len(streamedMarkdown)} {
+		var got []MarkdownEvent
+		stream := NewMarkdownStream(func(e MarkdownEvent) error {
+			got = append(got, e)
+			return nil
+		})
+		for i := 0; i < len(streamedMarkdown); i += size {
+			require.NoError(t, stream.Write(streamedMarkdown[i:min(i+size, len(streamedMarkdown))]))
+		}
+		assert.Equal(t, "Trailing", stream.Pending())
+		require.NoError(t, stream.Flush())
+		assert.Equal(t, want, got, "delta size %d", size)
+	}
+}
+
+func TestMarkdownStreamUnclosedFence(t *testing.T) {
+	var got []MarkdownEvent
+	stream := NewMarkdownStream(func(e MarkdownEvent) error {
+		got = append(got, e)
+		return nil
+	})
+	require.NoError(t, stream.Write("Code:\n```\nx := 1\n"))
+	// The paragraph is complete, the code block is not
+	require.Len(t, got, 1)
+	assert.Equal(t, "```\nx := 1\n", stream.Pending())
+
+	require.NoError(t, stream.Flush())
+	require.Len(t, got, 2)
+	assert.Equal(t, "x := 1", got[1].Text)
+
+	failing := NewMarkdownStream(func(MarkdownEvent) error { return errors.New("closed") })
+	assert.Error(t, failing.Write("# a\n# b\n"))
+	assert.Error(t, failing.Write("more"))
+}
@@ -0,0 +1,22 @@
+package vultrai
+
+import "time"
+
+// MetricsCollector receives a measurement for every API request. endpoint
+// is the API path with IDs replaced by "{id}", e.g.
+// "/vector-stores/collections/{id}/search"; model is empty when the request
+// names none. status is the HTTP status code, or 0 when no response was
+// received. duration runs until the response body is closed, so it covers
+// whole streams. usage is zero for endpoints that don't report tokens.
+type MetricsCollector interface {
+	ObserveRequest(endpoint, model string, status int, duration time.Duration, usage Usage)
+}
+
+// WithMetrics passes a measurement of every API request to collector. The
+// vultraiprom package provides a collector exposing Prometheus metrics.
+// ObserveRequest runs on the calling goroutine and should return quickly.
+func WithMetrics(collector MetricsCollector) ClientOption {
+	return func(c *Client) {
+		c.metrics = collector
+	}
+}
@@ -1,5 +1,11 @@
 package vultrai
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
 var (
 	MistralNemoInstruct2407   = "mistral-nemo-instruct-2407"
 	Qwq32bAwq                 = "qwq-32b-awq"
@@ -13,3 +19,64 @@ var (
 	GptOss120b                = "gpt-oss-120b"
 	KimiK2Instruct            = "kimi-k2-instruct"
 )
+
+// Model types reported by ListModels
+const (
+	ModelTypeChat      = "chat"
+	ModelTypeEmbedding = "embedding"
+	ModelTypeTTS       = "tts"
+	ModelTypeImage     = "image"
+)
+
+// Model describes a model served by the API
+type Model struct {
+	ID      string `json:"id"`
+	Type    string `json:"type,omitempty"`
+	Created int64  `json:"created,omitempty"`
+	OwnedBy string `json:"owned_by,omitempty"`
+	// ContextWindow is the maximum number of tokens of prompt and
+	// completion, zero when not reported
+	ContextWindow int `json:"context_window,omitempty"`
+}
+
+// ListModelsResponse represents the response from listing models
+type ListModelsResponse struct {
+	Data []Model `json:"data"`
+}
+
+// Find returns the model with the given ID
+func (r *ListModelsResponse) Find(id string) (Model, bool) {
+	for _, model := range r.Data {
+		if model.ID == id {
+			return model, true
+		}
+	}
+	return Model{}, false
+}
+
+// OfType returns the models of the given type
+func (r *ListModelsResponse) OfType(modelType string) []Model {
+	var models []Model
+	for _, model := range r.Data {
+		if model.Type == modelType {
+			models = append(models, model)
+		}
+	}
+	return models
+}
+
+// ListModels lists the models available to the account
+func (c *Client) ListModels(ctx context.Context) (*ListModelsResponse, error) {
+	resp, err := c.doRequest(ctx, "GET", "/models", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var modelsResp ListModelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&modelsResp); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return &modelsResp, nil
+}
@@ -0,0 +1,33 @@
+package vultrai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListModels(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+	mockTransport.SetResponse("GET", "/models", 200, map[string]interface{}{
+		"object": "list",
+		"data": []map[string]interface{}{
+			{"id": Llama33_70bInstructFp8, "type": "chat", "owned_by": "vultr", "context_window": 131072},
+			{"id": "bge-large-en", "type": "embedding"},
+		},
+	})
+
+	resp, err := client.ListModels(context.Background())
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 2)
+
+	model, ok := resp.Find(Llama33_70bInstructFp8)
+	require.True(t, ok)
+	assert.Equal(t, 131072, model.ContextWindow)
+	assert.Equal(t, "vultr", model.OwnedBy)
+
+	_, ok = resp.Find("gpt-4")
+	assert.False(t, ok)
+	assert.Equal(t, []Model{{ID: "bge-large-en", Type: ModelTypeEmbedding}}, resp.OfType(ModelTypeEmbedding))
+}
@@ -0,0 +1,103 @@
+package vultrai
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Content part types
+const (
+	PartText  = "text"
+	PartImage = "image_url"
+)
+
+// ContentPart is a piece of a multimodal message
+type ContentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+// ImageURL points a vision model at an image, either over HTTP or inline
+// as a data URL
+type ImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"` // "low", "high" or "auto"
+}
+
+// TextPart creates a text content part
+func TextPart(text string) ContentPart {
+	return ContentPart{Type: PartText, Text: text}
+}
+
+// ImagePart creates an image content part from an HTTP or data URL
+func ImagePart(url string) ContentPart {
+	return ContentPart{Type: PartImage, ImageURL: &ImageURL{URL: url}}
+}
+
+// ImageDataURL encodes an image as a data URL, detecting its MIME type
+// from its content
+func ImageDataURL(image []byte) string {
+	mimeType := http.DetectContentType(image)
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(image)
+}
+
+// CreateImageMessage creates a user message with text followed by images
+// given as HTTP or data URLs
+func CreateImageMessage(text string, imageURLs ...string) Message {
+	parts := make([]ContentPart, 0, len(imageURLs)+1)
+	if text != "" {
+		parts = append(parts, TextPart(text))
+	}
+	for _, url := range imageURLs {
+		parts = append(parts, ImagePart(url))
+	}
+	return Message{Role: "user", Parts: parts}
+}
+
+// MarshalJSON sends Parts as the content array when set
+func (m Message) MarshalJSON() ([]byte, error) {
+	type plain Message
+	if len(m.Parts) == 0 {
+		return json.Marshal(plain(m))
+	}
+	return json.Marshal(struct {
+		plain
+		Content []ContentPart `json:"content"`
+	}{plain(m), m.Parts})
+}
+
+// UnmarshalJSON accepts content as a string or an array of parts. The text
+// of array content is also joined into Content.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	type plain Message
+	var raw struct {
+		plain
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*m = Message(raw.plain)
+
+	content := strings.TrimSpace(string(raw.Content))
+	switch {
+	case content == "" || content == "null":
+	case content[0] == '[':
+		if err := json.Unmarshal(raw.Content, &m.Parts); err != nil {
+			return err
+		}
+		var texts []string
+		for _, part := range m.Parts {
+			if part.Type == PartText {
+				texts = append(texts, part.Text)
+			}
+		}
+		m.Content = strings.Join(texts, "\n")
+	default:
+		return json.Unmarshal(raw.Content, &m.Content)
+	}
+	return nil
+}
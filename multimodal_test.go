@@ -0,0 +1,36 @@
+package vultrai
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageParts(t *testing.T) {
+	msg := CreateImageMessage("What is this?", "https://example.com/cat.png")
+	data, err := json.Marshal(msg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"role":"user","content":[
+		{"type":"text","text":"What is this?"},
+		{"type":"image_url","image_url":{"url":"https://example.com/cat.png"}}
+	]}`, string(data))
+
+	var decoded Message
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, msg.Parts, decoded.Parts)
+	assert.Equal(t, "What is this?", decoded.Content)
+
+	// Plain messages are unchanged
+	data, err = json.Marshal(CreateUserMessage("hi"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"role":"user","content":"hi"}`, string(data))
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, CreateUserMessage("hi"), decoded)
+}
+
+func TestImageDataURL(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n")
+	assert.Equal(t, "data:image/png;base64,iVBORw0KGgo=", ImageDataURL(png))
+}
@@ -0,0 +1,218 @@
+package vultrai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	modelKey       struct{}
+	observationKey struct{}
+)
+
+// observing reports whether requests are traced or measured
+func (c *Client) observing() bool {
+	return c.tracer != nil || c.metrics != nil
+}
+
+// withModel records the model of a request for its observation
+func withModel(ctx context.Context, model string) context.Context {
+	if model == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, modelKey{}, model)
+}
+
+// requestModel returns the model named by a request body
+func requestModel(body interface{}) string {
+	switch r := body.(type) {
+	case ChatCompletionRequest:
+		return r.Model
+	case RAGChatCompletionRequest:
+		return r.Model
+	case EmbeddingRequest:
+		return r.Model
+	case TTSRequest:
+		return r.Model
+	case ImageGenerationRequest:
+		return r.Model
+	}
+	return ""
+}
+
+// endpointRoute replaces the IDs in endpoint with "{id}", so requests for
+// the same operation share a span name and metric labels
+func endpointRoute(endpoint string) string {
+	segments := strings.Split(endpoint, "/")
+	for i := 1; i < len(segments); i++ {
+		switch segments[i-1] {
+		case "collections", "items", "files", "batches":
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// observation tracks one request for the tracer and the metrics collector.
+// A nil observation ignores all calls.
+type observation struct {
+	span      Span
+	metrics   MetricsCollector
+	route     string
+	model     string
+	start     time.Time
+	streaming bool
+
+	mu       sync.Mutex
+	status   int
+	usage    Usage
+	gotToken bool
+	ended    bool
+}
+
+// observe starts observing req and returns req with the observation in its
+// context
+func (c *Client) observe(req *http.Request) (*http.Request, *observation) {
+	return c.observeRoute(req, endpointRoute(c.endpointOf(req)))
+}
+
+// observeRoute is observe for requests outside the API, reported under
+// route
+func (c *Client) observeRoute(req *http.Request, route string) (*http.Request, *observation) {
+	if !c.observing() {
+		return req, nil
+	}
+
+	o := &observation{
+		metrics:   c.metrics,
+		route:     route,
+		start:     time.Now(),
+		streaming: strings.Contains(req.Header.Get("Accept"), "text/event-stream"),
+	}
+	o.model, _ = req.Context().Value(modelKey{}).(string)
+
+	ctx := req.Context()
+	if c.tracer != nil {
+		ctx, o.span = c.tracer.Start(ctx, req.Method+" "+o.route)
+		attrs := []Attribute{
+			{Key: AttrEndpoint, Value: o.route},
+			{Key: AttrMethod, Value: req.Method},
+			{Key: AttrStreaming, Value: o.streaming},
+		}
+		if o.model != "" {
+			attrs = append(attrs, Attribute{Key: AttrModel, Value: o.model})
+		}
+		o.span.SetAttributes(attrs...)
+	}
+
+	return req.WithContext(context.WithValue(ctx, observationKey{}, o)), o
+}
+
+// observationOf returns the observation of the request behind resp
+func observationOf(resp *http.Response) *observation {
+	if resp == nil || resp.Request == nil {
+		return nil
+	}
+	o, _ := resp.Request.Context().Value(observationKey{}).(*observation)
+	return o
+}
+
+func (o *observation) setAttributes(attrs ...Attribute) {
+	if o.span != nil {
+		o.span.SetAttributes(attrs...)
+	}
+}
+
+func (o *observation) setStatus(code int) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.status = code
+	o.setAttributes(Attribute{Key: AttrStatusCode, Value: code})
+}
+
+func (o *observation) setUsage(u Usage) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.usage = u
+	o.setAttributes(
+		Attribute{Key: AttrInputTokens, Value: u.PromptTokens},
+		Attribute{Key: AttrOutputTokens, Value: u.CompletionTokens},
+	)
+}
+
+// firstToken records the arrival of the first content token of a streamed
+// answer
+func (o *observation) firstToken() {
+	if o == nil || !o.streaming {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.gotToken {
+		return
+	}
+	o.gotToken = true
+	o.setAttributes(Attribute{Key: AttrFirstTokenLatency, Value: time.Since(o.start).Milliseconds()})
+}
+
+func (o *observation) recordError(err error) {
+	if o == nil || o.span == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.span.RecordError(err)
+}
+
+// end finishes the observation once, recording err if not nil
+func (o *observation) end(err error) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.ended {
+		return
+	}
+	o.ended = true
+
+	if o.metrics != nil {
+		o.metrics.ObserveRequest(o.route, o.model, o.status, time.Since(o.start), o.usage)
+	}
+	if o.span != nil {
+		if err != nil {
+			o.span.RecordError(err)
+		}
+		o.span.End()
+	}
+}
+
+// observedBody ends the observation when the response body is closed
+type observedBody struct {
+	io.ReadCloser
+	obs *observation
+}
+
+func (b *observedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		b.obs.recordError(err)
+	}
+	return n, err
+}
+
+func (b *observedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.obs.end(nil)
+	return err
+}
@@ -0,0 +1,136 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const ocrPrompt = "Transcribe all of the text in this image exactly as written, in reading order. " +
+	"Keep paragraphs and list items on their own lines and render tables with one row per line. " +
+	"Do not describe the image, translate, summarize or add commentary. " +
+	"If the image contains no text, respond with nothing."
+
+const ocrLayoutPrompt = "Transcribe all of the text in this image exactly as written, in reading order, " +
+	"grouped into layout blocks. Respond with JSON only, in the form " +
+	`{"blocks":[{"type":"heading","text":"..."}]}` + ", where type is one of " +
+	"heading, paragraph, list, table, caption or other. Do not translate, summarize or add commentary."
+
+// LayoutBlock is a region of text found by ExtractTextFromImage, such as a
+// heading or a table
+type LayoutBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// OCRResult is the text read from an image
+type OCRResult struct {
+	Text string
+	// Blocks holds layout hints when requested with WithOCRLayout and the
+	// model provided them
+	Blocks []LayoutBlock
+	Usage  Usage
+}
+
+// OCROption configures ExtractTextFromImage
+type OCROption func(*ocrConfig)
+
+type ocrConfig struct {
+	layout   bool
+	language string
+}
+
+// WithOCRLayout asks the model for layout hints along with the text
+func WithOCRLayout() OCROption {
+	return func(c *ocrConfig) {
+		c.layout = true
+	}
+}
+
+// WithOCRLanguage tells the model which language the text is in
+func WithOCRLanguage(language string) OCROption {
+	return func(c *ocrConfig) {
+		c.language = language
+	}
+}
+
+// ExtractTextFromImage reads the text of an image, such as a scanned page
+// or a screenshot, with a vision-capable model. The text is cleaned of
+// markdown fences and stray whitespace so it can be fed to ingestion
+// directly.
+func (c *Client) ExtractTextFromImage(ctx context.Context, model string, image []byte, options ...OCROption) (*OCRResult, error) {
+	if len(image) == 0 {
+		return nil, errors.New("image is empty")
+	}
+	var cfg ocrConfig
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	prompt := ocrPrompt
+	if cfg.layout {
+		prompt = ocrLayoutPrompt
+	}
+	if cfg.language != "" {
+		prompt += " The text is in " + cfg.language + "."
+	}
+
+	resp, err := c.CreateChatCompletion(ctx, ChatCompletionRequest{
+		Model:       model,
+		Messages:    []Message{CreateImageMessage(prompt, ImageDataURL(image))},
+		Temperature: Float64(0),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error extracting text from image: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, errors.New("error extracting text from image: no choices returned")
+	}
+
+	content := resp.Choices[0].Message.Content
+	result := &OCRResult{Usage: resp.Usage}
+	if cfg.layout {
+		if layout, err := DecodeModelJSON[struct {
+			Blocks []LayoutBlock `json:"blocks"`
+		}](content); err == nil && len(layout.Blocks) > 0 {
+			texts := make([]string, 0, len(layout.Blocks))
+			for _, block := range layout.Blocks {
+				block.Text = cleanOCRText(block.Text)
+				if block.Text == "" {
+					continue
+				}
+				result.Blocks = append(result.Blocks, block)
+				texts = append(texts, block.Text)
+			}
+			result.Text = strings.Join(texts, "\n\n")
+			return result, nil
+		}
+		// Fall back to the raw answer when the model ignored the format
+	}
+
+	result.Text = cleanOCRText(content)
+	return result, nil
+}
+
+var (
+	ocrTrailingSpace = regexp.MustCompile(`[ \t]+\n`)
+	ocrBlankLines    = regexp.MustCompile(`\n{3,}`)
+)
+
+// cleanOCRText strips a surrounding markdown fence, trailing spaces and
+// runs of blank lines, keeping indentation
+func cleanOCRText(s string) string {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "\r\n", "\n")
+	if strings.HasPrefix(s, "```") {
+		s = strings.TrimPrefix(s, "```")
+		if nl := strings.IndexByte(s, '\n'); nl >= 0 {
+			s = s[nl+1:]
+		}
+		s = strings.TrimSuffix(strings.TrimSpace(s), "```")
+	}
+	s = ocrTrailingSpace.ReplaceAllString(s+"\n", "\n")
+	s = ocrBlankLines.ReplaceAllString(s, "\n\n")
+	return strings.Trim(s, "\n")
+}
@@ -0,0 +1,44 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractTextFromImage(t *testing.T) {
+	client, transport := setupSequenceClient("```\nINVOICE   \n\n\n\nTotal: 42 EUR\n```")
+	image := []byte("\x89PNG\r\n\x1a\nrest")
+
+	result, err := client.ExtractTextFromImage(context.Background(), "vision-model", image, WithOCRLanguage("German"))
+	require.NoError(t, err)
+	assert.Equal(t, "INVOICE\n\nTotal: 42 EUR", result.Text)
+	assert.Empty(t, result.Blocks)
+
+	var req ChatCompletionRequest
+	require.NoError(t, json.NewDecoder(transport.requests[0].Body).Decode(&req))
+	require.Len(t, req.Messages, 1)
+	parts := req.Messages[0].Parts
+	require.Len(t, parts, 2)
+	assert.Contains(t, parts[0].Text, "The text is in German.")
+	assert.True(t, strings.HasPrefix(parts[1].ImageURL.URL, "data:image/png;base64,"))
+}
+
+func TestExtractTextFromImageLayout(t *testing.T) {
+	client, _ := setupSequenceClient(`{"blocks":[{"type":"heading","text":"Menu"},{"type":"list","text":"- Soup\n- Bread"},{"type":"other","text":" "}]}`, "just text")
+
+	result, err := client.ExtractTextFromImage(context.Background(), "vision-model", []byte("img"), WithOCRLayout())
+	require.NoError(t, err)
+	assert.Equal(t, "Menu\n\n- Soup\n- Bread", result.Text)
+	assert.Equal(t, []LayoutBlock{{Type: "heading", Text: "Menu"}, {Type: "list", Text: "- Soup\n- Bread"}}, result.Blocks)
+
+	// Models ignoring the format still yield text
+	result, err = client.ExtractTextFromImage(context.Background(), "vision-model", []byte("img"), WithOCRLayout())
+	require.NoError(t, err)
+	assert.Equal(t, "just text", result.Text)
+	assert.Nil(t, result.Blocks)
+}
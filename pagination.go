@@ -0,0 +1,243 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ListOptions selects the page returned by a list endpoint
+type ListOptions struct {
+	// PerPage is the number of entries per page; zero uses the API default
+	PerPage int
+	// Cursor resumes listing where an earlier page left off
+	Cursor string
+}
+
+// ListMeta holds the pagination data of a list response
+type ListMeta struct {
+	Total int       `json:"total,omitempty"`
+	Links ListLinks `json:"links"`
+}
+
+// ListLinks holds the cursors of the neighboring pages
+type ListLinks struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// next returns the cursor of the following page, empty on the last page
+func (m *ListMeta) next() string {
+	if m == nil {
+		return ""
+	}
+	return m.Links.Next
+}
+
+// PageFunc fetches the page starting at cursor, empty for the first page.
+// It returns the entries and the cursor of the following page, empty on the
+// last page.
+type PageFunc[T any] func(ctx context.Context, cursor string) ([]T, string, error)
+
+// Pager walks the pages of a list endpoint. Endpoints that don't paginate
+// are returned as a single page.
+type Pager[T any] struct {
+	fetch  PageFunc[T]
+	cursor string
+	done   bool
+}
+
+// NewPager creates a pager starting at cursor
+func NewPager[T any](cursor string, fetch PageFunc[T]) *Pager[T] {
+	return &Pager[T]{fetch: fetch, cursor: cursor}
+}
+
+// Next returns the entries of the next page, or io.EOF after the last one
+func (p *Pager[T]) Next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, io.EOF
+	}
+	entries, next, err := p.fetch(ctx, p.cursor)
+	if err != nil {
+		return nil, err
+	}
+	// A server repeating the cursor would never reach the end
+	p.done = next == "" || next == p.cursor
+	p.cursor = next
+	return entries, nil
+}
+
+// Cursor returns the cursor of the next page, which can be stored to
+// resume listing later with ListOptions.Cursor
+func (p *Pager[T]) Cursor() string {
+	return p.cursor
+}
+
+// All fetches the remaining pages and returns their entries
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	for {
+		entries, err := p.Next(ctx)
+		if err == io.EOF {
+			return all, nil
+		}
+		if err != nil {
+			return all, err
+		}
+		all = append(all, entries...)
+	}
+}
+
+// Seq iterates over the remaining entries, fetching pages as needed. An
+// error is yielded once with a zero entry and ends the iteration.
+func (p *Pager[T]) Seq(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			entries, err := p.Next(ctx)
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, entry := range entries {
+				if !yield(entry, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ListCollectionsPager pages through the vector store collections
+func (c *Client) ListCollectionsPager(opts ListOptions) *Pager[VectorStoreCollection] {
+	return NewPager(opts.Cursor, func(ctx context.Context, cursor string) ([]VectorStoreCollection, string, error) {
+		resp, err := c.listCollections(ctx, ListOptions{PerPage: opts.PerPage, Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Collections, resp.Meta.next(), nil
+	})
+}
+
+// ListItemsPager pages through the items of a vector store collection
+func (c *Client) ListItemsPager(collectionID string, opts ListOptions) *Pager[CollectionItem] {
+	return NewPager(opts.Cursor, func(ctx context.Context, cursor string) ([]CollectionItem, string, error) {
+		resp, err := c.listItems(ctx, collectionID, ListOptions{PerPage: opts.PerPage, Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Items, resp.Meta.next(), nil
+	})
+}
+
+// ListFilesPager pages through the files of a vector store collection
+func (c *Client) ListFilesPager(collectionID string, opts ListOptions) *Pager[CollectionFile] {
+	return NewPager(opts.Cursor, func(ctx context.Context, cursor string) ([]CollectionFile, string, error) {
+		resp, err := c.listFiles(ctx, collectionID, ListOptions{PerPage: opts.PerPage, Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Files, resp.Meta.next(), nil
+	})
+}
+
+// RequestLogsPager pages through the API request logs
+func (c *Client) RequestLogsPager(req RequestLogsRequest, opts ListOptions) *Pager[RequestLog] {
+	return NewPager(opts.Cursor, func(ctx context.Context, cursor string) ([]RequestLog, string, error) {
+		resp, err := c.getRequestLogs(ctx, req, ListOptions{PerPage: opts.PerPage, Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Requests, resp.Meta.next(), nil
+	})
+}
+
+// RequestLogsFilter narrows the logs walked by RequestLogsIterator
+type RequestLogsFilter struct {
+	// Endpoint keeps the logs of one endpoint name
+	Endpoint string
+}
+
+// maxRequestLogsPeriod is the longest period of a request logs query
+const maxRequestLogsPeriod = 60 * time.Minute
+
+// RequestLogsIterator iterates over the request logs from from up to, but
+// excluding, to. The range is walked in windows of at most an hour, the
+// longest period GetRequestLogs accepts, each paged through in turn. An
+// error is yielded once with a zero log and ends the iteration.
+func (c *Client) RequestLogsIterator(ctx context.Context, from, to time.Time, filter RequestLogsFilter) iter.Seq2[RequestLog, error] {
+	return func(yield func(RequestLog, error) bool) {
+		for start := from; start.Before(to); start = start.Add(maxRequestLogsPeriod) {
+			req := RequestLogsRequest{
+				Period:    requestLogsPeriod(to.Sub(start)),
+				Timestamp: start.UTC().Format(time.RFC3339),
+				Endpoint:  filter.Endpoint,
+			}
+			end := start.Add(maxRequestLogsPeriod)
+			if to.Before(end) {
+				end = to
+			}
+
+			for log, err := range c.RequestLogsPager(req, ListOptions{}).Seq(ctx) {
+				if err != nil {
+					yield(RequestLog{}, err)
+					return
+				}
+				// Periods are rounded up to 15 minutes, which can reach past
+				// the window
+				if !log.Timestamp.IsZero() && (log.Timestamp.Before(start) || !log.Timestamp.Before(end)) {
+					continue
+				}
+				if !yield(log, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// requestLogsPeriod returns the period in minutes covering remaining,
+// rounded up to the 15 minute steps the API accepts
+func requestLogsPeriod(remaining time.Duration) int {
+	if remaining >= maxRequestLogsPeriod {
+		return 60
+	}
+	minutes := int((remaining + time.Minute - 1) / time.Minute)
+	return min((minutes+14)/15*15, 60)
+}
+
+// getList performs a GET on a list endpoint and decodes the response into
+// out. params holds the endpoint's own query parameters and may be nil.
+func (c *Client) getList(ctx context.Context, endpoint string, params url.Values, opts ListOptions, out interface{}) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	if opts.PerPage > 0 {
+		params.Set("per_page", strconv.Itoa(opts.PerPage))
+	}
+	if opts.Cursor != "" {
+		params.Set("cursor", opts.Cursor)
+	}
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	return nil
+}
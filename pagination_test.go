@@ -0,0 +1,189 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedServer serves items in pages of per_page, using the index of the
+// first entry as cursor
+func pagedServer(t *testing.T, total int) (*Client, *[]string) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		start, perPage := 0, 2
+		fmt.Sscan(r.URL.Query().Get("cursor"), &start)
+		fmt.Sscan(r.URL.Query().Get("per_page"), &perPage)
+
+		resp := ListItemsResponse{Meta: &ListMeta{Total: total}}
+		for i := start; i < total && i < start+perPage; i++ {
+			resp.Items = append(resp.Items, CollectionItem{ID: fmt.Sprintf("item-%d", i)})
+		}
+		if start+perPage < total {
+			resp.Meta.Links.Next = fmt.Sprint(start + perPage)
+		}
+		writeJSON(w, resp)
+	}))
+	t.Cleanup(server.Close)
+	return NewClient("key", WithBaseURL(server.URL)), &queries
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	data, _ := json.Marshal(v)
+	w.Write(data)
+}
+
+func itemIDs(items []CollectionItem) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+func TestPagerNext(t *testing.T) {
+	client, queries := pagedServer(t, 5)
+	pager := client.ListItemsPager("col", ListOptions{PerPage: 2})
+
+	var pages [][]string
+	for {
+		items, err := pager.Next(context.Background())
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		pages = append(pages, itemIDs(items))
+	}
+
+	assert.Equal(t, [][]string{{"item-0", "item-1"}, {"item-2", "item-3"}, {"item-4"}}, pages)
+	assert.Equal(t, []string{"per_page=2", "cursor=2&per_page=2", "cursor=4&per_page=2"}, *queries)
+	_, err := pager.Next(context.Background())
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestPagerAllAndSeq(t *testing.T) {
+	client, _ := pagedServer(t, 5)
+
+	all, err := client.ListItemsPager("col", ListOptions{PerPage: 3}).All(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, all, 5)
+
+	var ids []string
+	for item, err := range client.ListItemsPager("col", ListOptions{Cursor: "1"}).Seq(context.Background()) {
+		require.NoError(t, err)
+		ids = append(ids, item.ID)
+		if len(ids) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"item-1", "item-2", "item-3"}, ids)
+}
+
+func TestPagerResume(t *testing.T) {
+	client, _ := pagedServer(t, 5)
+	pager := client.ListItemsPager("col", ListOptions{PerPage: 2})
+	_, err := pager.Next(context.Background())
+	require.NoError(t, err)
+
+	resumed := client.ListItemsPager("col", ListOptions{PerPage: 2, Cursor: pager.Cursor()})
+	items, err := resumed.All(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"item-2", "item-3", "item-4"}, itemIDs(items))
+}
+
+func TestPagerErrors(t *testing.T) {
+	calls := 0
+	failure := errors.New("boom")
+	pager := NewPager("", func(ctx context.Context, cursor string) ([]int, string, error) {
+		calls++
+		if cursor == "b" {
+			return nil, "", failure
+		}
+		return []int{calls}, "b", nil
+	})
+
+	var got []int
+	var errs []error
+	for n, err := range pager.Seq(context.Background()) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		got = append(got, n)
+	}
+	assert.Equal(t, []int{1}, got)
+	assert.Equal(t, []error{failure}, errs)
+
+	// A repeated cursor ends the listing instead of looping
+	repeating := NewPager("a", func(ctx context.Context, cursor string) ([]int, string, error) {
+		return []int{1}, "a", nil
+	})
+	all, err := repeating.All(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, all)
+}
+
+func TestListEndpointsPaginate(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	transport.SetResponse("GET", "/vector-stores/collections", 200, ListCollectionsResponse{
+		Collections: []VectorStoreCollection{{ID: "a"}},
+	})
+
+	resp, err := client.ListCollections(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, resp.Collections, 1)
+	assert.Nil(t, resp.Meta)
+
+	_, err = client.RequestLogsPager(RequestLogsRequest{Period: 15}, ListOptions{PerPage: 10}).All(context.Background())
+	require.NoError(t, err)
+	_, err = client.ListFilesPager("col", ListOptions{Cursor: "c1"}).All(context.Background())
+	require.NoError(t, err)
+
+	requests := transport.GetRequests()
+	require.Len(t, requests, 3)
+	assert.Equal(t, "/request-logs", requests[1].URL.Path)
+	assert.Equal(t, "per_page=10&period=15", requests[1].URL.RawQuery)
+	assert.Equal(t, "/vector-stores/collections/col/files", requests[2].URL.Path)
+	assert.Equal(t, "cursor=c1", requests[2].URL.RawQuery)
+}
+
+func TestRequestLogsIterator(t *testing.T) {
+	var queries []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		queries = append(queries, query.Get("timestamp")+" "+query.Get("period"))
+		var resp RequestLogsResponse
+		switch query.Get("timestamp") {
+		case "2024-01-01T10:00:00Z":
+			resp.Requests = []RequestLog{{Timestamp: time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC), Endpoint: "a"}}
+		case "2024-01-01T12:00:00Z":
+			resp.Requests = []RequestLog{{Timestamp: time.Date(2024, 1, 1, 12, 10, 0, 0, time.UTC), Endpoint: "b"}, {Timestamp: time.Date(2024, 1, 1, 12, 25, 0, 0, time.UTC), Endpoint: "c"}}
+		}
+		body, _ := json.Marshal(resp)
+		return textResponse(200, string(body)), nil
+	})
+	client := NewClient("key", WithBaseURL("https://api.test.local"), WithHTTPClient(&http.Client{Transport: transport}))
+
+	from := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	var endpoints []string
+	for log, err := range client.RequestLogsIterator(context.Background(), from, from.Add(140*time.Minute), RequestLogsFilter{}) {
+		require.NoError(t, err)
+		endpoints = append(endpoints, log.Endpoint)
+	}
+	assert.Equal(t, []string{"a", "b"}, endpoints)
+	assert.Equal(t, []string{"2024-01-01T10:00:00Z 60", "2024-01-01T11:00:00Z 60", "2024-01-01T12:00:00Z 30"}, queries)
+
+	assert.Equal(t, 15, requestLogsPeriod(time.Minute))
+	assert.Equal(t, 45, requestLogsPeriod(31*time.Minute))
+}
@@ -0,0 +1,241 @@
+package vultrai
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Built-in PII kinds detected by PIIRedactor
+const (
+	PIIEmail      = "EMAIL"
+	PIIPhone      = "PHONE"
+	PIICreditCard = "CREDIT_CARD"
+)
+
+var (
+	emailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	creditCardPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	phonePattern      = regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{1,4}\)[\s.-]?)?\d{2,4}(?:[\s.-]?\d{2,4}){1,4}`)
+	datePattern       = regexp.MustCompile(`^\d{4}[-./]\d{1,2}[-./]\d{1,2}$|^\d{1,2}[-./]\d{1,2}[-./]\d{4}$`)
+)
+
+type piiPattern struct {
+	builtin  bool
+	kind     string
+	re       *regexp.Regexp
+	validate func(string) bool
+}
+
+// PIIRedactor masks personal data in outgoing messages with placeholder
+// tokens such as [EMAIL_1]. Detection is pattern based: emails, phone
+// numbers and Luhn-valid card numbers are built in, and custom patterns can
+// be added. It reduces accidental leakage but is not a guarantee that no
+// personal data is sent.
+type PIIRedactor struct {
+	patterns []piiPattern
+}
+
+// PIIOption configures a PIIRedactor
+type PIIOption func(*PIIRedactor)
+
+// WithPIIKinds limits the built-in detectors to the given kinds
+func WithPIIKinds(kinds ...string) PIIOption {
+	return func(r *PIIRedactor) {
+		keep := make(map[string]bool, len(kinds))
+		for _, k := range kinds {
+			keep[k] = true
+		}
+		filtered := r.patterns[:0]
+		for _, p := range r.patterns {
+			if keep[p.kind] || !p.builtin {
+				filtered = append(filtered, p)
+			}
+		}
+		r.patterns = filtered
+	}
+}
+
+// WithPIIPattern adds a custom detector. Matches are replaced with tokens
+// named after kind, e.g. [EMPLOYEE_ID_1].
+func WithPIIPattern(kind string, re *regexp.Regexp) PIIOption {
+	return func(r *PIIRedactor) {
+		r.patterns = append(r.patterns, piiPattern{kind: strings.ToUpper(kind), re: re})
+	}
+}
+
+// NewPIIRedactor creates a redactor with the built-in detectors
+func NewPIIRedactor(options ...PIIOption) *PIIRedactor {
+	r := &PIIRedactor{
+		patterns: []piiPattern{
+			{builtin: true, kind: PIIEmail, re: emailPattern},
+			// Cards are matched before phone numbers, which would otherwise
+			// claim long digit runs
+			{builtin: true, kind: PIICreditCard, re: creditCardPattern, validate: luhnValid},
+			{builtin: true, kind: PIIPhone, re: phonePattern, validate: plausiblePhone},
+		},
+	}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+// Redact replaces personal data in text with tokens recorded in tokens, so
+// the same value always maps to the same token
+func (r *PIIRedactor) Redact(text string, tokens *TokenMap) string {
+	for _, p := range r.patterns {
+		text = p.re.ReplaceAllStringFunc(text, func(match string) string {
+			if isPIIToken(match) || (p.validate != nil && !p.validate(match)) {
+				return match
+			}
+			return tokens.tokenFor(p.kind, match)
+		})
+	}
+	return text
+}
+
+// RedactMessages returns a copy of messages with personal data redacted
+func (r *PIIRedactor) RedactMessages(messages []Message, tokens *TokenMap) []Message {
+	redacted := make([]Message, len(messages))
+	for i, m := range messages {
+		m.Content = r.Redact(m.Content, tokens)
+		redacted[i] = m
+	}
+	return redacted
+}
+
+// Interceptor returns a ChatInterceptor that redacts outgoing messages.
+// When restore is true, tokens in the assistant's reply are replaced with
+// the original values locally, so the application sees real data while the
+// API never does.
+func (r *PIIRedactor) Interceptor(restore bool) ChatInterceptor {
+	return func(ctx context.Context, req ChatCompletionRequest, next ChatHandler) (*ChatCompletionResponse, error) {
+		tokens := NewTokenMap()
+		req.Messages = r.RedactMessages(req.Messages, tokens)
+
+		resp, err := next(ctx, req)
+		if err != nil || !restore || tokens.Len() == 0 {
+			return resp, err
+		}
+
+		restored := *resp
+		restored.Choices = make([]Choice, len(resp.Choices))
+		for i, choice := range resp.Choices {
+			choice.Message.Content = tokens.Restore(choice.Message.Content)
+			restored.Choices[i] = choice
+		}
+		return &restored, nil
+	}
+}
+
+var piiTokenPattern = regexp.MustCompile(`\[[A-Z][A-Z0-9_]*_\d+\]`)
+
+func isPIIToken(s string) bool {
+	return piiTokenPattern.MatchString(s)
+}
+
+// TokenMap records the mapping between redacted values and their tokens. It
+// is safe for concurrent use.
+type TokenMap struct {
+	mu       sync.Mutex
+	tokens   map[string]string
+	values   map[string]string
+	counters map[string]int
+}
+
+// NewTokenMap returns an empty token map
+func NewTokenMap() *TokenMap {
+	return &TokenMap{
+		tokens:   make(map[string]string),
+		values:   make(map[string]string),
+		counters: make(map[string]int),
+	}
+}
+
+func (m *TokenMap) tokenFor(kind, value string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := kind + "\x00" + value
+	if token, ok := m.tokens[key]; ok {
+		return token
+	}
+	m.counters[kind]++
+	token := fmt.Sprintf("[%s_%d]", kind, m.counters[kind])
+	m.tokens[key] = token
+	m.values[token] = value
+	return token
+}
+
+// Restore replaces every known token in text with its original value
+func (m *TokenMap) Restore(text string) string {
+	return piiTokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		if value, ok := m.Value(token); ok {
+			return value
+		}
+		return token
+	})
+}
+
+// Value returns the original value of token
+func (m *TokenMap) Value(token string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.values[token]
+	return value, ok
+}
+
+// Len returns the number of distinct redacted values
+func (m *TokenMap) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.values)
+}
+
+func digitsOf(s string) string {
+	var sb strings.Builder
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum used by
+// payment card numbers
+func luhnValid(s string) bool {
+	digits := digitsOf(s)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// plausiblePhone filters phone candidates down to 7-15 digit sequences with
+// some separator or international prefix, which avoids masking plain
+// numbers such as dates, amounts and IDs
+func plausiblePhone(s string) bool {
+	digits := digitsOf(s)
+	if len(digits) < 7 || len(digits) > 15 || datePattern.MatchString(s) {
+		return false
+	}
+	return strings.HasPrefix(s, "+") || strings.ContainsAny(s, " .-()")
+}
@@ -0,0 +1,80 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPIIRedactorRedact(t *testing.T) {
+	r := NewPIIRedactor(WithPIIPattern("employee_id", regexp.MustCompile(`EMP-\d{5}`)))
+	tokens := NewTokenMap()
+
+	text := "Mail jane.doe@example.com or call +1 (555) 123-4567. Card 4111 1111 1111 1111, badge EMP-12345. " +
+		"Mail jane.doe@example.com again. Order 12345678 shipped on 2024-01-15."
+	redacted := r.Redact(text, tokens)
+
+	assert.Equal(t, "Mail [EMAIL_1] or call [PHONE_1]. Card [CREDIT_CARD_1], badge [EMPLOYEE_ID_1]. "+
+		"Mail [EMAIL_1] again. Order 12345678 shipped on 2024-01-15.", redacted)
+	assert.Equal(t, 4, tokens.Len())
+	assert.Equal(t, text, tokens.Restore(redacted))
+}
+
+func TestPIIRedactorSkipsInvalidCards(t *testing.T) {
+	r := NewPIIRedactor(WithPIIKinds(PIICreditCard))
+	tokens := NewTokenMap()
+
+	assert.Equal(t, "Ref 1234 5678 9012 3456", r.Redact("Ref 1234 5678 9012 3456", tokens))
+	assert.Equal(t, "Mail a@b.io", r.Redact("Mail a@b.io", tokens))
+}
+
+func TestPIIRedactorInterceptor(t *testing.T) {
+	client, _ := setupSequenceClient("I emailed [EMAIL_1] as requested.")
+
+	var sent ChatCompletionRequest
+	WithChatInterceptor(
+		NewPIIRedactor().Interceptor(true),
+		func(ctx context.Context, req ChatCompletionRequest, next ChatHandler) (*ChatCompletionResponse, error) {
+			sent = req
+			return next(ctx, req)
+		},
+	)(client)
+
+	resp, err := client.SimpleChatCompletion(context.Background(), "test-model", "Email bob@example.com the report")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Email [EMAIL_1] the report", sent.Messages[0].Content)
+	assert.Equal(t, "I emailed bob@example.com as requested.", resp.Choices[0].Message.Content)
+}
+
+func TestChatInterceptorOrder(t *testing.T) {
+	client, transport := setupSequenceClient("ok")
+
+	var order []string
+	trace := func(name string) ChatInterceptor {
+		return func(ctx context.Context, req ChatCompletionRequest, next ChatHandler) (*ChatCompletionResponse, error) {
+			order = append(order, name+" before")
+			resp, err := next(ctx, req)
+			order = append(order, name+" after")
+			return resp, err
+		}
+	}
+	WithChatInterceptor(trace("outer"), trace("inner"))(client)
+
+	_, err := client.SimpleChatCompletion(context.Background(), "test-model", "hi")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"outer before", "inner before", "inner after", "outer after"}, order)
+
+	blocked := errors.New("blocked")
+	WithChatInterceptor(func(ctx context.Context, req ChatCompletionRequest, next ChatHandler) (*ChatCompletionResponse, error) {
+		return nil, blocked
+	})(client)
+
+	_, err = client.SimpleChatCompletion(context.Background(), "test-model", "hi")
+	assert.Equal(t, blocked, err)
+	assert.Len(t, transport.requests, 1)
+}
@@ -0,0 +1,109 @@
+// Package pricing estimates the cost of requests from per-model rates, so
+// budgets can be tracked per request instead of waiting for the monthly
+// usage endpoint:
+//
+//	cost := pricing.EstimateChatCost(resp.Model, resp.Usage)
+//
+// Estimates use DefaultTable unless a Table is used directly. Rates change;
+// check them against the current price list and override them as needed.
+package pricing
+
+import vultrai "github.com/eqba1/vultrai"
+
+// Rate holds the prices of a model in US dollars
+type Rate struct {
+	// InputPerMillion is the price of a million prompt tokens
+	InputPerMillion float64
+	// OutputPerMillion is the price of a million completion tokens
+	OutputPerMillion float64
+	// PerImage is the price of a generated image
+	PerImage float64
+	// ImageSizes overrides PerImage for given sizes, e.g. "1024x1024"
+	ImageSizes map[string]float64
+	// PerMillionCharacters is the price of a million characters of speech
+	PerMillionCharacters float64
+}
+
+// Table maps model IDs to their rates
+type Table map[string]Rate
+
+// chatRate is the per-token overage price of the serverless inference plan
+var chatRate = Rate{InputPerMillion: 0.2, OutputPerMillion: 0.2}
+
+// DefaultTable holds the rates of the chat models in the vultrai package.
+// Image and speech models have no default rate; add them with Set.
+var DefaultTable = Table{
+	vultrai.MistralNemoInstruct2407:   chatRate,
+	vultrai.Qwq32bAwq:                 chatRate,
+	vultrai.DeepseekR1DistillQwen32b:  chatRate,
+	vultrai.Qwen25_32bInstruct:        chatRate,
+	vultrai.Qwen25Coder32bInstruct:    chatRate,
+	vultrai.Hermes3Llama31_70bFp8:     chatRate,
+	vultrai.Llama31_70bInstructFp8:    chatRate,
+	vultrai.Llama33_70bInstructFp8:    chatRate,
+	vultrai.DeepseekR1DistillLlama70b: chatRate,
+	vultrai.GptOss120b:                chatRate,
+	vultrai.KimiK2Instruct:            chatRate,
+}
+
+// Set adds or replaces the rate of model
+func (t Table) Set(model string, rate Rate) {
+	t[model] = rate
+}
+
+// ChatCost returns the cost of a chat completion's usage and whether the
+// model has a rate
+func (t Table) ChatCost(model string, usage vultrai.Usage) (float64, bool) {
+	rate, ok := t[model]
+	if !ok {
+		return 0, false
+	}
+	return float64(usage.PromptTokens)*rate.InputPerMillion/1e6 +
+		float64(usage.CompletionTokens)*rate.OutputPerMillion/1e6, true
+}
+
+// ImageCost returns the cost of n images of the given size and whether the
+// model has a rate
+func (t Table) ImageCost(model string, n int, size string) (float64, bool) {
+	rate, ok := t[model]
+	if !ok {
+		return 0, false
+	}
+	price := rate.PerImage
+	if sized, ok := rate.ImageSizes[size]; ok {
+		price = sized
+	}
+	return float64(n) * price, true
+}
+
+// SpeechCost returns the cost of speaking characters characters and
+// whether the model has a rate
+func (t Table) SpeechCost(model string, characters int) (float64, bool) {
+	rate, ok := t[model]
+	if !ok {
+		return 0, false
+	}
+	return float64(characters) * rate.PerMillionCharacters / 1e6, true
+}
+
+// EstimateChatCost returns the cost of a chat completion's usage with the
+// DefaultTable rates, zero for unknown models. It can be used as an
+// eval.CostFunc.
+func EstimateChatCost(model string, usage vultrai.Usage) float64 {
+	cost, _ := DefaultTable.ChatCost(model, usage)
+	return cost
+}
+
+// EstimateImageCost returns the cost of n images of the given size with
+// the DefaultTable rates, zero for unknown models
+func EstimateImageCost(model string, n int, size string) float64 {
+	cost, _ := DefaultTable.ImageCost(model, n, size)
+	return cost
+}
+
+// EstimateSpeechCost returns the cost of speaking text with the
+// DefaultTable rates, zero for unknown models
+func EstimateSpeechCost(model, text string) float64 {
+	cost, _ := DefaultTable.SpeechCost(model, len([]rune(text)))
+	return cost
+}
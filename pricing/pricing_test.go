@@ -0,0 +1,40 @@
+package pricing
+
+import (
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTable(t *testing.T) {
+	table := Table{}
+	table.Set("chat", Rate{InputPerMillion: 1, OutputPerMillion: 2})
+	table.Set("image", Rate{PerImage: 0.02, ImageSizes: map[string]float64{"1024x1024": 0.04}})
+	table.Set("tts", Rate{PerMillionCharacters: 15})
+
+	cost, ok := table.ChatCost("chat", vultrai.Usage{PromptTokens: 1000, CompletionTokens: 500})
+	assert.True(t, ok)
+	assert.InDelta(t, 0.002, cost, 1e-12)
+
+	cost, ok = table.ImageCost("image", 3, "512x512")
+	assert.True(t, ok)
+	assert.InDelta(t, 0.06, cost, 1e-12)
+	cost, _ = table.ImageCost("image", 2, "1024x1024")
+	assert.InDelta(t, 0.08, cost, 1e-12)
+
+	cost, ok = table.SpeechCost("tts", 2000)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.03, cost, 1e-12)
+
+	_, ok = table.ChatCost("missing", vultrai.Usage{PromptTokens: 1})
+	assert.False(t, ok)
+}
+
+func TestEstimates(t *testing.T) {
+	usage := vultrai.Usage{PromptTokens: 600000, CompletionTokens: 400000}
+	assert.InDelta(t, 0.2, EstimateChatCost(vultrai.Llama33_70bInstructFp8, usage), 1e-12)
+	assert.Zero(t, EstimateChatCost("unknown", usage))
+	assert.Zero(t, EstimateImageCost("unknown", 1, "1024x1024"))
+	assert.Zero(t, EstimateSpeechCost("unknown", "hello"))
+}
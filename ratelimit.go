@@ -0,0 +1,138 @@
+package vultrai
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitInfo holds the rate limit state reported in response headers.
+// Fields the API didn't report are zero; Remaining fields are -1 when
+// unknown, since zero remaining is meaningful.
+type RateLimitInfo struct {
+	LimitRequests     int
+	RemainingRequests int
+	// ResetRequests is the time until the request limit resets
+	ResetRequests time.Duration
+
+	LimitTokens     int
+	RemainingTokens int
+	// ResetTokens is the time until the token limit resets
+	ResetTokens time.Duration
+
+	// RetryAfter is the wait requested with a Retry-After header
+	RetryAfter time.Duration
+}
+
+// Exhausted reports whether no requests or tokens remain until the next
+// reset
+func (r *RateLimitInfo) Exhausted() bool {
+	return r != nil && (r.RemainingRequests == 0 || r.RemainingTokens == 0)
+}
+
+// Wait returns how long to pause before the next request: RetryAfter when
+// set, otherwise the reset time of an exhausted limit, otherwise zero
+func (r *RateLimitInfo) Wait() time.Duration {
+	if r == nil {
+		return 0
+	}
+	if r.RetryAfter > 0 {
+		return r.RetryAfter
+	}
+	var wait time.Duration
+	if r.RemainingRequests == 0 && r.ResetRequests > wait {
+		wait = r.ResetRequests
+	}
+	if r.RemainingTokens == 0 && r.ResetTokens > wait {
+		wait = r.ResetTokens
+	}
+	return wait
+}
+
+// LastRateLimit returns the rate limit state reported by the most recent
+// response carrying rate limit headers, or nil when none did
+func (c *Client) LastRateLimit() *RateLimitInfo {
+	return c.rateLimit.Load()
+}
+
+// recordRateLimit parses the rate limit headers of header and remembers
+// them as the latest state
+func (c *Client) recordRateLimit(header http.Header) *RateLimitInfo {
+	info := parseRateLimitInfo(header, time.Now())
+	if info != nil {
+		c.rateLimit.Store(info)
+	}
+	return info
+}
+
+// parseRateLimitInfo reads the X-RateLimit-* and Retry-After headers. Both
+// the generic X-RateLimit-Limit/Remaining/Reset headers, taken as request
+// limits, and the -requests/-tokens variants are understood. It returns nil
+// when header has none of them.
+func parseRateLimitInfo(header http.Header, now time.Time) *RateLimitInfo {
+	info := &RateLimitInfo{RemainingRequests: -1, RemainingTokens: -1}
+	found := false
+
+	count := func(target *int, names ...string) {
+		for _, name := range names {
+			if n, err := strconv.Atoi(strings.TrimSpace(header.Get(name))); err == nil {
+				*target = n
+				found = true
+				return
+			}
+		}
+	}
+	reset := func(target *time.Duration, names ...string) {
+		for _, name := range names {
+			if d, ok := parseResetValue(header.Get(name), now); ok {
+				*target = d
+				found = true
+				return
+			}
+		}
+	}
+
+	count(&info.LimitRequests, "X-RateLimit-Limit-Requests", "X-RateLimit-Limit")
+	count(&info.RemainingRequests, "X-RateLimit-Remaining-Requests", "X-RateLimit-Remaining")
+	reset(&info.ResetRequests, "X-RateLimit-Reset-Requests", "X-RateLimit-Reset")
+	count(&info.LimitTokens, "X-RateLimit-Limit-Tokens")
+	count(&info.RemainingTokens, "X-RateLimit-Remaining-Tokens")
+	reset(&info.ResetTokens, "X-RateLimit-Reset-Tokens")
+
+	if value := strings.TrimSpace(header.Get("Retry-After")); value != "" {
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+			info.RetryAfter = time.Duration(seconds * float64(time.Second))
+			found = true
+		} else if at, err := http.ParseTime(value); err == nil {
+			info.RetryAfter = max(at.Sub(now), 0)
+			found = true
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return info
+}
+
+// parseResetValue reads a reset header, which is a Go-style duration such
+// as "6m0s", a number of seconds or a Unix timestamp
+func parseResetValue(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return d, true
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	// Values this large are timestamps, not waits
+	if seconds > 1e9 {
+		return max(time.Unix(int64(seconds), 0).Sub(now), 0), true
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
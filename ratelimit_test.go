@@ -0,0 +1,80 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRateLimitInfo(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	header := http.Header{}
+	header.Set("X-RateLimit-Limit-Requests", "100")
+	header.Set("X-RateLimit-Remaining-Requests", "0")
+	header.Set("X-RateLimit-Reset-Requests", "1m30s")
+	header.Set("X-RateLimit-Limit-Tokens", "50000")
+	header.Set("X-RateLimit-Remaining-Tokens", "1200")
+	header.Set("X-RateLimit-Reset-Tokens", "250ms")
+	info := parseRateLimitInfo(header, now)
+	require.NotNil(t, info)
+	assert.Equal(t, RateLimitInfo{
+		LimitRequests: 100, RemainingRequests: 0, ResetRequests: 90 * time.Second,
+		LimitTokens: 50000, RemainingTokens: 1200, ResetTokens: 250 * time.Millisecond,
+	}, *info)
+	assert.True(t, info.Exhausted())
+	assert.Equal(t, 90*time.Second, info.Wait())
+
+	header = http.Header{}
+	header.Set("X-RateLimit-Limit", "10")
+	header.Set("X-RateLimit-Reset", "1704067230")
+	header.Set("Retry-After", "Mon, 01 Jan 2024 00:00:05 GMT")
+	info = parseRateLimitInfo(header, now)
+	require.NotNil(t, info)
+	assert.Equal(t, 10, info.LimitRequests)
+	assert.Equal(t, -1, info.RemainingRequests)
+	assert.Equal(t, 30*time.Second, info.ResetRequests)
+	assert.Equal(t, 5*time.Second, info.RetryAfter)
+	assert.False(t, info.Exhausted())
+	assert.Equal(t, 5*time.Second, info.Wait())
+
+	assert.Nil(t, parseRateLimitInfo(http.Header{"Content-Type": {"application/json"}}, now))
+	var none *RateLimitInfo
+	assert.Zero(t, none.Wait())
+}
+
+func TestRateLimitSurfacing(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	assert.Nil(t, client.LastRateLimit())
+
+	ok := textResponse(200, `{"choices":[]}`)
+	ok.Header.Set("X-RateLimit-Remaining-Requests", "7")
+	transport.responses["POST /chat/completions"] = ok
+
+	resp, err := client.CreateChatCompletion(context.Background(), ChatCompletionRequest{Model: "m"})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Meta)
+	assert.Equal(t, 7, resp.Meta.RateLimit.RemainingRequests)
+	assert.Equal(t, 7, client.LastRateLimit().RemainingRequests)
+
+	limited := &http.Response{
+		StatusCode: 429,
+		Header:     http.Header{"Retry-After": {"2"}},
+		Body:       io.NopCloser(strings.NewReader(`{"message":"slow down"}`)),
+	}
+	transport.responses["GET /usage"] = limited
+
+	_, err = client.GetUsage(context.Background())
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	require.NotNil(t, apiErr.RateLimit)
+	assert.Equal(t, 2*time.Second, apiErr.RateLimit.RetryAfter)
+	assert.Equal(t, 2*time.Second, client.LastRateLimit().Wait())
+}
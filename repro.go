@@ -0,0 +1,147 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// reproVersion is the format version of ReproRecord artifacts
+const reproVersion = 1
+
+// ReproRecord captures everything needed to review and re-run a chat
+// completion: the exact request sent and what came back
+type ReproRecord struct {
+	Version    int       `json:"version"`
+	CapturedAt time.Time `json:"captured_at"`
+	// Request is the request body as sent to the API
+	Request           json.RawMessage         `json:"request"`
+	Model             string                  `json:"model"`
+	Seed              *int                    `json:"seed,omitempty"`
+	SystemFingerprint string                  `json:"system_fingerprint,omitempty"`
+	Response          *ChatCompletionResponse `json:"response,omitempty"`
+	Usage             Usage                   `json:"usage"`
+	// Error holds the error message of failed completions
+	Error string `json:"error,omitempty"`
+}
+
+// Marshal serializes the record into a single JSON artifact
+func (r *ReproRecord) Marshal() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// ParseReproRecord parses an artifact produced by ReproRecord.Marshal
+func ParseReproRecord(artifact []byte) (*ReproRecord, error) {
+	var record ReproRecord
+	if err := json.Unmarshal(artifact, &record); err != nil {
+		return nil, fmt.Errorf("error parsing repro record: %w", err)
+	}
+	if record.Version != reproVersion {
+		return nil, fmt.Errorf("unsupported repro record version %d", record.Version)
+	}
+	if len(record.Request) == 0 {
+		return nil, fmt.Errorf("repro record has no request")
+	}
+	return &record, nil
+}
+
+// WithReproCapture passes a ReproRecord of every chat completion to
+// record. Add it after other interceptors to capture the request as
+// finally sent, e.g. the model a fallback switched to.
+func WithReproCapture(record func(*ReproRecord)) ClientOption {
+	return WithChatInterceptor(ReproInterceptor(record))
+}
+
+// ReproInterceptor returns a ChatInterceptor passing a ReproRecord of each
+// completion to record. Attach it to a context with
+// ContextWithChatInterceptors to capture single requests.
+func ReproInterceptor(record func(*ReproRecord)) ChatInterceptor {
+	return func(ctx context.Context, req ChatCompletionRequest, next ChatHandler) (*ChatCompletionResponse, error) {
+		body, err := json.Marshal(req)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling request body: %w", err)
+		}
+		r := &ReproRecord{
+			Version:    reproVersion,
+			CapturedAt: time.Now().UTC(),
+			Request:    body,
+			Model:      req.Model,
+			Seed:       req.Seed,
+		}
+
+		resp, err := next(ctx, req)
+		if err != nil {
+			r.Error = err.Error()
+		} else {
+			r.Response = resp
+			r.Usage = resp.Usage
+			r.SystemFingerprint = resp.SystemFingerprint
+			if resp.Model != "" {
+				r.Model = resp.Model
+			}
+		}
+		record(r)
+		return resp, err
+	}
+}
+
+// ReplayResult compares a replayed completion with the recorded one
+type ReplayResult struct {
+	Record   *ReproRecord
+	Response *ChatCompletionResponse
+	// Identical is true when every choice has the same content and tool
+	// calls as the recorded response
+	Identical bool
+	// FingerprintChanged is true when the backend configuration reported
+	// by system_fingerprint differs, which explains diverging answers
+	FingerprintChanged bool
+}
+
+// Replay sends the request of a ReproRecord artifact again, bypassing
+// interceptors and the cache, and compares the answer with the recorded
+// one
+func (c *Client) Replay(ctx context.Context, artifact []byte) (*ReplayResult, error) {
+	record, err := ParseReproRecord(artifact)
+	if err != nil {
+		return nil, err
+	}
+	var req ChatCompletionRequest
+	if err := json.Unmarshal(record.Request, &req); err != nil {
+		return nil, fmt.Errorf("error parsing recorded request: %w", err)
+	}
+
+	resp, err := c.createChatCompletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReplayResult{Record: record, Response: resp}
+	if record.Response != nil {
+		result.Identical = sameChoices(record.Response.Choices, resp.Choices)
+		result.FingerprintChanged = record.SystemFingerprint != resp.SystemFingerprint
+	}
+	return result, nil
+}
+
+// sameChoices reports whether two responses answered the same
+func sameChoices(a, b []Choice) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Message.Content != b[i].Message.Content {
+			return false
+		}
+		if len(a[i].Message.ToolCalls) != len(b[i].Message.ToolCalls) {
+			return false
+		}
+		for j, call := range a[i].Message.ToolCalls {
+			other := b[i].Message.ToolCalls[j]
+			if call.Function.Name != other.Function.Name || call.Function.Arguments != other.Function.Arguments {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReproCaptureAndReplay(t *testing.T) {
+	client, transport := setupSequenceClient("Paris", "Paris", "Lyon")
+	var records []*ReproRecord
+	WithReproCapture(func(r *ReproRecord) { records = append(records, r) })(client)
+
+	seed := 42
+	req := ChatCompletionRequest{Model: "alias", Seed: &seed, Messages: []Message{{Role: "user", Content: "Capital of France?"}}}
+	_, err := client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+
+	require.Len(t, records, 1)
+	record := records[0]
+	assert.Equal(t, "test-model", record.Model)
+	assert.Equal(t, &seed, record.Seed)
+	assert.Equal(t, "Paris", record.Response.Choices[0].Message.Content)
+
+	sent, err := io.ReadAll(transport.requests[0].Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(sent), string(record.Request))
+
+	artifact, err := record.Marshal()
+	require.NoError(t, err)
+
+	result, err := client.Replay(context.Background(), artifact)
+	require.NoError(t, err)
+	assert.True(t, result.Identical)
+	assert.False(t, result.FingerprintChanged)
+
+	result, err = client.Replay(context.Background(), artifact)
+	require.NoError(t, err)
+	assert.False(t, result.Identical)
+	assert.Equal(t, "Lyon", result.Response.Choices[0].Message.Content)
+
+	// Replays are not captured again
+	assert.Len(t, records, 1)
+}
+
+func TestReproCaptureErrors(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	transport.SetResponse("POST", "/chat/completions", 400, Error{Message: "invalid model"})
+
+	var record *ReproRecord
+	ctx := ContextWithChatInterceptors(context.Background(), ReproInterceptor(func(r *ReproRecord) { record = r }))
+	_, err := client.CreateChatCompletion(ctx, ChatCompletionRequest{Model: "nope"})
+	require.Error(t, err)
+
+	require.NotNil(t, record)
+	assert.Equal(t, "nope", record.Model)
+	assert.Nil(t, record.Response)
+	assert.Contains(t, record.Error, "invalid model")
+}
+
+func TestParseReproRecord(t *testing.T) {
+	_, err := ParseReproRecord([]byte("not json"))
+	assert.Error(t, err)
+
+	_, err = ParseReproRecord([]byte(`{"version": 2, "request": {}}`))
+	assert.ErrorContains(t, err, "version")
+
+	_, err = ParseReproRecord([]byte(`{"version": 1}`))
+	assert.ErrorContains(t, err, "no request")
+
+	data, _ := json.Marshal(ReproRecord{Version: 1, Request: json.RawMessage(`{"model":"m"}`)})
+	record, err := ParseReproRecord(data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"model":"m"}`, string(record.Request))
+}
@@ -0,0 +1,127 @@
+package vultrai
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// requestLogTimeLayouts are the timestamp formats accepted in request logs
+var requestLogTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02T15:04:05.999999999"}
+
+// LogBody is a request or response body recorded in a request log. It holds
+// the body as text and is decoded on demand.
+type LogBody string
+
+// Decode unmarshals the body as JSON into v
+func (b LogBody) Decode(v interface{}) error {
+	if err := json.Unmarshal([]byte(b), v); err != nil {
+		return fmt.Errorf("error decoding logged body: %w", err)
+	}
+	return nil
+}
+
+// IsJSON reports whether the body is valid JSON
+func (b LogBody) IsJSON() bool {
+	return json.Valid([]byte(b))
+}
+
+// UnmarshalJSON accepts the body as a string or as embedded JSON
+func (b *LogBody) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = LogBody(s)
+		return nil
+	}
+	if string(data) == "null" {
+		*b = ""
+		return nil
+	}
+	*b = LogBody(data)
+	return nil
+}
+
+// UnmarshalJSON decodes a log, parsing the timestamp and headers the API
+// sends as strings. A timestamp in an unknown format is kept in
+// RawTimestamp.
+func (l *RequestLog) UnmarshalJSON(data []byte) error {
+	type plain RequestLog
+	var raw struct {
+		plain
+		Timestamp      string          `json:"timestamp"`
+		RequestHeaders json.RawMessage `json:"request_headers"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*l = RequestLog(raw.plain)
+	if raw.Timestamp != "" {
+		// An unknown format doesn't fail the page the log is on
+		if at, err := parseRequestLogTime(raw.Timestamp); err == nil {
+			l.Timestamp = at
+		} else {
+			l.RawTimestamp = raw.Timestamp
+		}
+	}
+	headers, err := parseLogHeaders(raw.RequestHeaders)
+	if err != nil {
+		return err
+	}
+	l.RequestHeaders = headers
+	return nil
+}
+
+func parseRequestLogTime(value string) (time.Time, error) {
+	for _, layout := range requestLogTimeLayouts {
+		if at, err := time.Parse(layout, value); err == nil {
+			return at, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("error parsing log timestamp %q", value)
+}
+
+// parseLogHeaders reads headers sent as an object, a string holding one, or
+// a string of "Name: value" lines
+func parseLogHeaders(data json.RawMessage) (http.Header, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		if text == "" {
+			return nil, nil
+		}
+		data = json.RawMessage(text)
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		if text == "" {
+			return nil, fmt.Errorf("error parsing log headers: %w", err)
+		}
+		header := make(http.Header)
+		for _, line := range strings.Split(text, "\n") {
+			if name, value, ok := strings.Cut(line, ":"); ok {
+				header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+			}
+		}
+		return header, nil
+	}
+
+	header := make(http.Header, len(object))
+	for name, value := range object {
+		switch v := value.(type) {
+		case []interface{}:
+			for _, item := range v {
+				header.Add(name, fmt.Sprint(item))
+			}
+		case nil:
+		default:
+			header.Add(name, fmt.Sprint(v))
+		}
+	}
+	return header, nil
+}
@@ -0,0 +1,67 @@
+package vultrai
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogDecoding(t *testing.T) {
+	data := `{"requests":[
+		{"timestamp":"2024-01-01T10:30:00Z","method":"POST","endpoint":"chat/completions",
+		 "request_headers":"{\"Content-Type\":\"application/json\",\"Accept\":[\"a\",\"b\"]}",
+		 "request_body":"{\"model\":\"m\",\"messages\":[{\"role\":\"user\",\"content\":\"Hi\"}]}",
+		 "response_body":"upstream error","response_code":502},
+		{"timestamp":"2024-01-01 10:31:00","request_headers":"X-Test: 1\nX-Other: two",
+		 "request_body":{"input":"x"},"response_body":null}
+	]}`
+
+	var resp RequestLogsResponse
+	require.NoError(t, json.Unmarshal([]byte(data), &resp))
+	require.Len(t, resp.Requests, 2)
+
+	first := resp.Requests[0]
+	assert.Equal(t, time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC), first.Timestamp)
+	assert.Equal(t, "application/json", first.RequestHeaders.Get("Content-Type"))
+	assert.Equal(t, []string{"a", "b"}, first.RequestHeaders.Values("Accept"))
+	var req ChatCompletionRequest
+	require.NoError(t, first.RequestBody.Decode(&req))
+	assert.Equal(t, "Hi", req.Messages[0].Content)
+	assert.False(t, first.ResponseBody.IsJSON())
+	assert.Equal(t, LogBody("upstream error"), first.ResponseBody)
+	assert.Equal(t, 502, first.ResponseCode)
+
+	second := resp.Requests[1]
+	assert.Equal(t, time.Date(2024, 1, 1, 10, 31, 0, 0, time.UTC), second.Timestamp)
+	assert.Equal(t, "two", second.RequestHeaders.Get("X-Other"))
+	assert.Equal(t, LogBody(`{"input":"x"}`), second.RequestBody)
+	assert.Empty(t, second.ResponseBody)
+
+	// Logs survive a round trip through JSON
+	encoded, err := json.Marshal(first)
+	require.NoError(t, err)
+	var decoded RequestLog
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, first, decoded)
+}
+
+func TestRequestLogBadTimestamp(t *testing.T) {
+	data := `{"requests":[
+		{"timestamp":"yesterday","endpoint":"chat/completions","response_code":200},
+		{"timestamp":"2024-01-01T10:30:00Z","endpoint":"embeddings"}
+	]}`
+
+	var resp RequestLogsResponse
+	require.NoError(t, json.Unmarshal([]byte(data), &resp))
+	require.Len(t, resp.Requests, 2)
+
+	assert.True(t, resp.Requests[0].Timestamp.IsZero())
+	assert.Equal(t, "yesterday", resp.Requests[0].RawTimestamp)
+	assert.Equal(t, "chat/completions", resp.Requests[0].Endpoint)
+	assert.Equal(t, 200, resp.Requests[0].ResponseCode)
+	assert.Empty(t, resp.Requests[1].RawTimestamp)
+	assert.Equal(t, "embeddings", resp.Requests[1].Endpoint)
+}
@@ -0,0 +1,132 @@
+package vultrai
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestOption overrides client defaults for individual requests. Client
+// methods don't take RequestOption parameters: the options are attached to
+// the context with ContextWithRequestOptions and apply to every request
+// made with it.
+//
+//	ctx := vultrai.ContextWithRequestOptions(ctx, vultrai.WithRequestTimeout(5*time.Second))
+//	resp, err := client.CreateChatCompletion(ctx, req)
+//
+// Going through the context works for every method alike, including those
+// that already take ChatOption, StreamOption or other variadic options,
+// and for helpers that make several requests. It also leaves method
+// signatures unchanged, so *Client keeps satisfying API and the narrow
+// client interfaces declared by other packages. Do, which calls arbitrary
+// endpoints, takes the options directly as well.
+//
+// The options belong to the call they are attached to. Requests the client
+// makes on its own behalf while serving it, such as the model listing of
+// WithContextWindowPolicy, history summaries and semantic cache lookups,
+// are sent without them. Retries of the call itself, including model
+// fallbacks, keep them.
+type RequestOption func(*requestConfig)
+
+type requestConfig struct {
+	headers  http.Header
+	timeout  time.Duration
+	baseURL  string
+	progress func(sent, total int64)
+}
+
+// WithRequestHeader sets a header on the request, replacing the client's
+// value, e.g. the Authorization header for a different API key
+func WithRequestHeader(key, value string) RequestOption {
+	return func(c *requestConfig) {
+		if c.headers == nil {
+			c.headers = make(http.Header)
+		}
+		c.headers.Set(key, value)
+	}
+}
+
+// WithRequestTimeout sets the overall timeout of the request, replacing the
+// endpoint timeout and the HTTP client's Timeout. It applies to streams
+// too, including the time spent reading them.
+func WithRequestTimeout(timeout time.Duration) RequestOption {
+	return func(c *requestConfig) {
+		c.timeout = timeout
+	}
+}
+
+// WithRequestBaseURL sends the request to another base URL, e.g. a
+// regional endpoint
+func WithRequestBaseURL(baseURL string) RequestOption {
+	return func(c *requestConfig) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithUploadProgress calls progress as the file of an upload, such as
+// AddFile or CreateTranscription, is sent, with the bytes sent so far and
+// the file size, or -1 when it isn't known
+func WithUploadProgress(progress func(sent, total int64)) RequestOption {
+	return func(c *requestConfig) {
+		c.progress = progress
+	}
+}
+
+type requestOptionsKey struct{}
+
+// ContextWithRequestOptions returns a context that applies options to every
+// API request made with it, so a single call can override the client's
+// defaults without a second client. Options added to a context that
+// already has some are applied after them.
+func ContextWithRequestOptions(ctx context.Context, options ...RequestOption) context.Context {
+	existing, _ := ctx.Value(requestOptionsKey{}).([]RequestOption)
+	combined := make([]RequestOption, 0, len(existing)+len(options))
+	combined = append(combined, existing...)
+	combined = append(combined, options...)
+	return context.WithValue(ctx, requestOptionsKey{}, combined)
+}
+
+// withoutRequestOptions returns ctx without the options attached to it,
+// for requests the client makes on its own behalf
+func withoutRequestOptions(ctx context.Context) context.Context {
+	if ctx.Value(requestOptionsKey{}) == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, requestOptionsKey{}, []RequestOption(nil))
+}
+
+// requestOptions returns the options attached to ctx
+func requestOptions(ctx context.Context) requestConfig {
+	var cfg requestConfig
+	options, _ := ctx.Value(requestOptionsKey{}).([]RequestOption)
+	for _, option := range options {
+		option(&cfg)
+	}
+	return cfg
+}
+
+// baseURLFor returns the base URL of requests made with ctx
+func (c *Client) baseURLFor(ctx context.Context) string {
+	if cfg := requestOptions(ctx); cfg.baseURL != "" {
+		return cfg.baseURL
+	}
+	if base, ok := ctx.Value(endpointKey{}).(string); ok {
+		return base
+	}
+	return c.baseURL
+}
+
+// endpointOf returns the API path of req relative to its base URL, without
+// the query
+func (c *Client) endpointOf(req *http.Request) string {
+	endpoint, _, _ := strings.Cut(strings.TrimPrefix(req.URL.String(), c.baseURLFor(req.Context())), "?")
+	return endpoint
+}
+
+// applyRequestHeaders sets the headers attached to the context of req
+func applyRequestHeaders(req *http.Request) {
+	for key, values := range requestOptions(req.Context()).headers {
+		req.Header[key] = values
+	}
+}
@@ -0,0 +1,70 @@
+package vultrai
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestOptions(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	WithEndpointTimeouts(map[string]time.Duration{"/usage": time.Second})(client)
+
+	ctx := ContextWithRequestOptions(context.Background(),
+		WithRequestHeader("X-Tenant", "a"),
+		WithRequestHeader("Authorization", "Bearer other-key"),
+	)
+	ctx = ContextWithRequestOptions(ctx, WithRequestHeader("X-Tenant", "b"), WithRequestBaseURL("https://eu.test.local/v2/"))
+
+	_, err := client.GetUsage(ctx)
+	require.NoError(t, err)
+	_, err = client.GetUsage(context.Background())
+	require.NoError(t, err)
+
+	requests := transport.GetRequests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "https://eu.test.local/v2/usage", requests[0].URL.String())
+	assert.Equal(t, "b", requests[0].Header.Get("X-Tenant"))
+	assert.Equal(t, "Bearer other-key", requests[0].Header.Get("Authorization"))
+	assert.Equal(t, "https://api.test.local/usage", requests[1].URL.String())
+	assert.Empty(t, requests[1].Header.Get("X-Tenant"))
+	assert.Equal(t, "Bearer test-api-key", requests[1].Header.Get("Authorization"))
+
+	timeout, ok := client.endpointTimeout(requests[0])
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, timeout)
+}
+
+func TestRequestTimeout(t *testing.T) {
+	client := NewClient("key", WithEndpointTimeouts(map[string]time.Duration{"/usage": time.Second}))
+	ctx := ContextWithRequestOptions(context.Background(), WithRequestTimeout(5*time.Minute))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", defaultBaseURL+"/usage", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, client.httpClientFor(req).Timeout)
+
+	req, err = http.NewRequestWithContext(context.Background(), "GET", defaultBaseURL+"/usage", nil)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, client.httpClientFor(req).Timeout)
+}
+
+func TestRequestOptionsNotInherited(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	WithContextWindowPolicy(ContextWindowPolicy{})(client)
+	transport.SetResponse("GET", "/models", 200, ListModelsResponse{Data: []Model{{ID: "m", ContextWindow: 8192}}})
+
+	// The model listing is the client's own request, not part of the call
+	ctx := ContextWithRequestOptions(context.Background(), WithRequestHeader("X-Tenant", "a"))
+	_, err := client.CreateChatCompletion(ctx, ChatCompletionRequest{Model: "m", Messages: []Message{CreateUserMessage("Hi")}})
+	require.NoError(t, err)
+
+	requests := transport.GetRequests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "/models", requests[0].URL.Path)
+	assert.Empty(t, requests[0].Header.Get("X-Tenant"))
+	assert.Equal(t, "a", requests[1].Header.Get("X-Tenant"))
+}
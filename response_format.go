@@ -0,0 +1,105 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Response format types
+const (
+	ResponseFormatText       = "text"
+	ResponseFormatJSONObject = "json_object"
+	ResponseFormatJSONSchema = "json_schema"
+)
+
+// ResponseFormat constrains the output of a chat completion
+type ResponseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *JSONSchemaFormat `json:"json_schema,omitempty"`
+}
+
+// JSONSchemaFormat is the schema of a ResponseFormatJSONSchema output
+type JSONSchemaFormat struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Schema      interface{} `json:"schema"`
+	Strict      *bool       `json:"strict,omitempty"`
+}
+
+// WithJSONMode makes the model answer with a JSON object
+func WithJSONMode() ChatOption {
+	return func(req *ChatCompletionRequest) {
+		req.ResponseFormat = &ResponseFormat{Type: ResponseFormatJSONObject}
+	}
+}
+
+// WithJSONSchema makes the model answer with JSON conforming to schema,
+// e.g. JSONSchemaOf(MyStruct{})
+func WithJSONSchema(name string, schema interface{}, strict bool) ChatOption {
+	return func(req *ChatCompletionRequest) {
+		req.ResponseFormat = &ResponseFormat{
+			Type:       ResponseFormatJSONSchema,
+			JSONSchema: &JSONSchemaFormat{Name: name, Schema: schema, Strict: &strict},
+		}
+	}
+}
+
+// CreateChatCompletionInto creates a chat completion answered in JSON and
+// decodes the assistant message into target, which must be a non-nil
+// pointer. JSON mode is requested unless req sets a ResponseFormat. Output
+// is repaired with RepairJSON if needed; when it still doesn't decode, the
+// model is asked once to correct it.
+func (c *Client) CreateChatCompletionInto(ctx context.Context, req ChatCompletionRequest, target interface{}) (*ChatCompletionResponse, error) {
+	return createChatCompletionInto(ctx, c, req, target)
+}
+
+// createChatCompletionInto is CreateChatCompletionInto for any API
+func createChatCompletionInto(ctx context.Context, client API, req ChatCompletionRequest, target interface{}) (*ChatCompletionResponse, error) {
+	if v := reflect.ValueOf(target); v.Kind() != reflect.Pointer || v.IsNil() {
+		return nil, errors.New("target must be a non-nil pointer")
+	}
+	if req.ResponseFormat == nil {
+		req.ResponseFormat = &ResponseFormat{Type: ResponseFormatJSONObject}
+	}
+
+	var decodeErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		resp, err := client.CreateChatCompletion(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Choices) == 0 {
+			return resp, errors.New("error decoding model JSON: no choices returned")
+		}
+
+		content := resp.Choices[0].Message.Content
+		if decodeErr = decodeModelJSONInto(content, target); decodeErr == nil {
+			return resp, nil
+		}
+
+		req.Messages = append(append([]Message(nil), req.Messages...),
+			CreateAssistantMessage(content),
+			CreateUserMessage(fmt.Sprintf("That response was not valid JSON (%v). Respond again with corrected JSON only.", decodeErr)),
+		)
+	}
+	return nil, decodeErr
+}
+
+// decodeModelJSONInto is DecodeModelJSON for a target pointer
+func decodeModelJSONInto(content string, target interface{}) error {
+	strictErr := json.Unmarshal([]byte(extractJSONText(content)), target)
+	if strictErr == nil {
+		return nil
+	}
+
+	// Drop what the failed attempt decoded before trying the repair
+	elem := reflect.ValueOf(target).Elem()
+	elem.Set(reflect.Zero(elem.Type()))
+	if err := json.Unmarshal([]byte(RepairJSON(content)), target); err != nil {
+		return fmt.Errorf("error decoding model JSON: %w", strictErr)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cityInfo struct {
+	City       string `json:"city"`
+	Population int    `json:"population"`
+}
+
+func TestCreateChatCompletionInto(t *testing.T) {
+	client, transport := setupSequenceClient("```json\n{'city': 'Paris', population: 2100000,}\n```")
+
+	var info cityInfo
+	resp, err := client.CreateChatCompletionInto(context.Background(), ChatCompletionRequest{Model: "m"}, &info)
+	require.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, cityInfo{City: "Paris", Population: 2100000}, info)
+
+	var sent ChatCompletionRequest
+	require.NoError(t, json.NewDecoder(transport.requests[0].Body).Decode(&sent))
+	assert.Equal(t, &ResponseFormat{Type: ResponseFormatJSONObject}, sent.ResponseFormat)
+}
+
+func TestCreateChatCompletionIntoReask(t *testing.T) {
+	client, transport := setupSequenceClient("I don't know", `{"city": "Lyon", "population": 520000}`)
+
+	var info cityInfo
+	req := ChatCompletionRequest{Model: "m", Messages: []Message{CreateUserMessage("Lyon?")}}
+	WithJSONSchema("city", JSONSchemaOf(info), true)(&req)
+	_, err := client.CreateChatCompletionInto(context.Background(), req, &info)
+	require.NoError(t, err)
+	assert.Equal(t, "Lyon", info.City)
+
+	require.Len(t, transport.requests, 2)
+	var reask ChatCompletionRequest
+	require.NoError(t, json.NewDecoder(transport.requests[1].Body).Decode(&reask))
+	require.Len(t, reask.Messages, 3)
+	assert.Equal(t, "I don't know", reask.Messages[1].Content)
+	assert.Equal(t, ResponseFormatJSONSchema, reask.ResponseFormat.Type)
+	assert.Equal(t, "city", reask.ResponseFormat.JSONSchema.Name)
+	assert.Len(t, req.Messages, 1)
+}
+
+func TestCreateChatCompletionIntoErrors(t *testing.T) {
+	client, transport := setupSequenceClient("nope")
+
+	var info cityInfo
+	_, err := client.CreateChatCompletionInto(context.Background(), ChatCompletionRequest{Model: "m"}, &info)
+	assert.ErrorContains(t, err, "error decoding model JSON")
+	assert.Len(t, transport.requests, 2)
+
+	_, err = client.CreateChatCompletionInto(context.Background(), ChatCompletionRequest{Model: "m"}, info)
+	assert.Error(t, err)
+
+	req := ChatCompletionRequest{}
+	WithJSONMode()(&req)
+	assert.Equal(t, ResponseFormatJSONObject, req.ResponseFormat.Type)
+}
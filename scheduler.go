@@ -0,0 +1,254 @@
+package vultrai
+
+import (
+	"container/heap"
+	"context"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+// Priority orders requests waiting in a Scheduler; higher values are
+// dispatched first
+type Priority int
+
+const (
+	// PriorityBatch is for background and bulk work
+	PriorityBatch Priority = 0
+	// PriorityNormal is used when a request carries no priority
+	PriorityNormal Priority = 50
+	// PriorityInteractive is for requests a user is waiting on
+	PriorityInteractive Priority = 100
+)
+
+type priorityKey struct{}
+
+// ContextWithPriority returns a context whose requests are scheduled with p
+func ContextWithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority attached to ctx, or
+// PriorityNormal
+func PriorityFromContext(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return PriorityNormal
+}
+
+// SchedulerConfig limits how fast a Scheduler dispatches requests. Zero
+// values disable the corresponding limit.
+type SchedulerConfig struct {
+	// MaxConcurrent caps the number of requests in flight
+	MaxConcurrent int
+	// RequestsPerSecond caps the sustained dispatch rate
+	RequestsPerSecond float64
+	// Burst is the number of requests that may be dispatched at once when
+	// rate limited (default RequestsPerSecond rounded up, at least 1)
+	Burst int
+}
+
+// SchedulerStats is a snapshot of a Scheduler's state
+type SchedulerStats struct {
+	InFlight int
+	Queued   int
+}
+
+// Scheduler dispatches requests under a global concurrency and rate cap,
+// highest priority first and in arrival order within a priority. Share one
+// Scheduler between clients using the same API key so that interactive
+// traffic is not starved by batch jobs.
+type Scheduler struct {
+	cfg SchedulerConfig
+
+	mu       sync.Mutex
+	queue    waitQueue
+	seq      uint64
+	inFlight int
+	tokens   float64
+	last     time.Time
+	timer    *time.Timer
+}
+
+// NewScheduler creates a scheduler with the given limits
+func NewScheduler(cfg SchedulerConfig) *Scheduler {
+	if cfg.RequestsPerSecond > 0 && cfg.Burst <= 0 {
+		cfg.Burst = int(math.Max(1, math.Ceil(cfg.RequestsPerSecond)))
+	}
+	return &Scheduler{cfg: cfg, tokens: float64(cfg.Burst), last: time.Now()}
+}
+
+// WithScheduler routes every request made by the client through s. The
+// priority of a request is read from its context with PriorityFromContext.
+func WithScheduler(s *Scheduler) ClientOption {
+	return func(c *Client) {
+		c.scheduler = s
+	}
+}
+
+// WithMaxConcurrentRequests caps the number of requests the client has in
+// flight at n, so a client shared by many goroutines doesn't open hundreds
+// of connections at once. Further requests wait, by priority, until a
+// request finishes; streams hold their slot until closed. It replaces any
+// scheduler set with WithScheduler.
+func WithMaxConcurrentRequests(n int) ClientOption {
+	return WithScheduler(NewScheduler(SchedulerConfig{MaxConcurrent: n}))
+}
+
+// waiter is a request queued for dispatch
+type waiter struct {
+	priority Priority
+	seq      uint64
+	ready    chan struct{}
+	index    int
+}
+
+// Acquire blocks until a request with priority p may be sent or ctx is
+// done. The returned release function must be called once the request has
+// finished.
+func (s *Scheduler) Acquire(ctx context.Context, p Priority) (release func(), err error) {
+	s.mu.Lock()
+	if s.queue.Len() == 0 && s.hasSlot() && s.takeToken() {
+		s.inFlight++
+		s.mu.Unlock()
+		return s.releaseFunc(), nil
+	}
+
+	s.seq++
+	w := &waiter{priority: p, seq: s.seq, ready: make(chan struct{})}
+	heap.Push(&s.queue, w)
+	s.dispatch()
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return s.releaseFunc(), nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		select {
+		case <-w.ready:
+			// Dispatched while cancelling; hand the slot on
+			s.inFlight--
+			s.dispatch()
+		default:
+			heap.Remove(&s.queue, w.index)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// Stats returns the current number of requests in flight and queued
+func (s *Scheduler) Stats() SchedulerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return SchedulerStats{InFlight: s.inFlight, Queued: s.queue.Len()}
+}
+
+func (s *Scheduler) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			s.inFlight--
+			s.dispatch()
+			s.mu.Unlock()
+		})
+	}
+}
+
+func (s *Scheduler) hasSlot() bool {
+	return s.cfg.MaxConcurrent <= 0 || s.inFlight < s.cfg.MaxConcurrent
+}
+
+// takeToken refills the rate bucket and consumes a token if one is
+// available
+func (s *Scheduler) takeToken() bool {
+	if s.cfg.RequestsPerSecond <= 0 {
+		return true
+	}
+	now := time.Now()
+	s.tokens = math.Min(float64(s.cfg.Burst), s.tokens+now.Sub(s.last).Seconds()*s.cfg.RequestsPerSecond)
+	s.last = now
+	if s.tokens < 1 {
+		return false
+	}
+	s.tokens--
+	return true
+}
+
+// dispatch hands free slots to queued requests; s.mu must be held
+func (s *Scheduler) dispatch() {
+	for s.queue.Len() > 0 && s.hasSlot() {
+		if !s.takeToken() {
+			if s.timer == nil {
+				wait := time.Duration((1 - s.tokens) / s.cfg.RequestsPerSecond * float64(time.Second))
+				s.timer = time.AfterFunc(wait, func() {
+					s.mu.Lock()
+					s.timer = nil
+					s.dispatch()
+					s.mu.Unlock()
+				})
+			}
+			return
+		}
+		w := heap.Pop(&s.queue).(*waiter)
+		s.inFlight++
+		close(w.ready)
+	}
+}
+
+// acquire waits for the client's scheduler, if any
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	if c.scheduler == nil {
+		return func() {}, nil
+	}
+	return c.scheduler.Acquire(ctx, PriorityFromContext(ctx))
+}
+
+// releaseOnClose releases a scheduler slot when the response body is closed,
+// so streams hold their slot until the caller is done reading
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.release()
+	return err
+}
+
+// waitQueue is a heap of waiters ordered by priority, then arrival
+type waitQueue []*waiter
+
+func (q waitQueue) Len() int { return len(q) }
+
+func (q waitQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q waitQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *waitQueue) Push(x interface{}) {
+	w := x.(*waiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *waitQueue) Pop() interface{} {
+	old := *q
+	w := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return w
+}
@@ -0,0 +1,134 @@
+package vultrai
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerPriorityOrder(t *testing.T) {
+	s := NewScheduler(SchedulerConfig{MaxConcurrent: 1})
+	ctx := context.Background()
+
+	release, err := s.Acquire(ctx, PriorityNormal)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	enqueue := func(name string, p Priority) {
+		queued := s.Stats().Queued
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := s.Acquire(ctx, p)
+			assert.NoError(t, err)
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			release()
+		}()
+		require.Eventually(t, func() bool { return s.Stats().Queued == queued+1 }, time.Second, time.Millisecond)
+	}
+
+	enqueue("batch-1", PriorityBatch)
+	enqueue("batch-2", PriorityBatch)
+	enqueue("interactive", PriorityInteractive)
+	enqueue("normal", PriorityNormal)
+
+	release()
+	wg.Wait()
+
+	assert.Equal(t, []string{"interactive", "normal", "batch-1", "batch-2"}, order)
+	assert.Equal(t, SchedulerStats{}, s.Stats())
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	s := NewScheduler(SchedulerConfig{MaxConcurrent: 1})
+
+	release, err := s.Acquire(context.Background(), PriorityNormal)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = s.Acquire(ctx, PriorityInteractive)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, SchedulerStats{InFlight: 1}, s.Stats())
+
+	release()
+	release() // releasing twice is harmless
+	assert.Equal(t, SchedulerStats{}, s.Stats())
+}
+
+func TestSchedulerRate(t *testing.T) {
+	s := NewScheduler(SchedulerConfig{RequestsPerSecond: 50, Burst: 1})
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		release, err := s.Acquire(context.Background(), PriorityNormal)
+		require.NoError(t, err)
+		release()
+	}
+	// The first request uses the burst; the other three wait 20ms each
+	assert.GreaterOrEqual(t, time.Since(start), 55*time.Millisecond)
+}
+
+func TestClientScheduler(t *testing.T) {
+	s := NewScheduler(SchedulerConfig{MaxConcurrent: 1})
+	client, mockTransport := setupLocalTestClient()
+	WithScheduler(s)(client)
+
+	mockTransport.SetResponse("POST", "/chat/completions", 200, ChatCompletionResponse{
+		Choices: []Choice{{Message: Message{Role: "assistant", Content: "Hi"}}},
+	})
+	ctx := ContextWithPriority(context.Background(), PriorityInteractive)
+	assert.Equal(t, PriorityInteractive, PriorityFromContext(ctx))
+
+	resp, err := client.CreateChatCompletion(ctx, ChatCompletionRequest{Model: "test-model"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", resp.Choices[0].Message.Content)
+	assert.Equal(t, SchedulerStats{}, s.Stats())
+
+	// Error responses release their slot too
+	mockTransport.SetResponse("GET", "/usage", 500, Error{Message: "boom"})
+	_, err = client.GetUsage(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, SchedulerStats{}, s.Stats())
+}
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	client := NewClient("key", WithBaseURL("https://api.test.local"), WithMaxConcurrentRequests(2), WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			inFlight++
+			peak = max(peak, inFlight)
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+			return textResponse(200, `{}`), nil
+		}),
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetUsage(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 2, peak)
+	assert.Equal(t, SchedulerStats{}, client.scheduler.Stats())
+}
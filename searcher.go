@@ -0,0 +1,53 @@
+package vultrai
+
+import (
+	"context"
+	"sort"
+)
+
+// Searcher retrieves content relevant to a query. Hosted collections and
+// local vector indexes both implement it, so retrieval code can work with
+// either.
+type Searcher interface {
+	Search(ctx context.Context, req SearchRequest) (*SearchResponse, error)
+}
+
+// CollectionSearcher is a Searcher over a hosted vector store collection
+type CollectionSearcher struct {
+	Client interface {
+		SearchCollection(ctx context.Context, id string, req SearchRequest) (*SearchResponse, error)
+	}
+	CollectionID string
+}
+
+// Search searches the collection
+func (s CollectionSearcher) Search(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
+	return s.Client.SearchCollection(ctx, s.CollectionID, req)
+}
+
+// RankSearchResults applies the ranking options of req to results, for
+// searchers that don't apply them themselves: results scoring under
+// req.ScoreThreshold are dropped, including unscored ones, the rest are
+// ordered most similar first, keeping the original order between equal
+// scores, and only the first req.TopK are kept. Every search path of the
+// package ranks its results with it.
+func RankSearchResults(req SearchRequest, results []SearchResult) []SearchResult {
+	if req.ScoreThreshold > 0 {
+		kept := results[:0]
+		for _, result := range results {
+			if result.Score >= req.ScoreThreshold {
+				kept = append(kept, result)
+			}
+		}
+		results = kept
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	if req.TopK > 0 && len(results) > req.TopK {
+		results = results[:req.TopK]
+	}
+	return results
+}
@@ -0,0 +1,117 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// DefaultSemanticCacheThreshold is the similarity above which a stored
+// prompt counts as a near-duplicate
+const DefaultSemanticCacheThreshold = 0.95
+
+// SemanticCache answers chat completions whose prompt is a near-duplicate
+// of an earlier one with the stored answer. Prompts are stored as items of
+// a dedicated vector store collection, which embeds them, with the answer
+// in the item description; on a miss, the new answer is written back.
+// Requests with tools or several choices are not cached, nor are empty,
+// filtered or tool-call answers.
+type SemanticCache struct {
+	client       API
+	collectionID string
+	// Threshold is the minimum similarity score of a hit (default
+	// DefaultSemanticCacheThreshold). Searches that report no scores never
+	// hit.
+	Threshold float64
+}
+
+// semanticCacheEntry is stored in the item description
+type semanticCacheEntry struct {
+	Model    string                  `json:"model"`
+	Response *ChatCompletionResponse `json:"response"`
+}
+
+// NewSemanticCache creates a cache stored in the given collection
+func NewSemanticCache(client API, collectionID string) *SemanticCache {
+	return &SemanticCache{client: client, collectionID: collectionID, Threshold: DefaultSemanticCacheThreshold}
+}
+
+// WithSemanticCache serves chat completions from cache
+func WithSemanticCache(cache *SemanticCache) ClientOption {
+	return WithChatInterceptor(cache.Interceptor())
+}
+
+// Interceptor returns a chat interceptor serving completions from the
+// cache. Cache failures are treated as misses.
+func (s *SemanticCache) Interceptor() ChatInterceptor {
+	return func(ctx context.Context, req ChatCompletionRequest, next ChatHandler) (*ChatCompletionResponse, error) {
+		if len(req.Tools) > 0 || (req.N != nil && *req.N > 1) || len(req.Messages) == 0 {
+			return next(ctx, req)
+		}
+
+		prompt := strings.TrimSpace(FormatTranscript(req.Messages))
+		if resp, ok := s.lookup(ctx, req.Model, prompt); ok {
+			return resp, nil
+		}
+
+		resp, err := next(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		s.store(ctx, req.Model, prompt, resp)
+		return resp, nil
+	}
+}
+
+// lookup returns the stored answer of the most similar prompt for model
+func (s *SemanticCache) lookup(ctx context.Context, model, prompt string) (*ChatCompletionResponse, bool) {
+	ctx = withoutRequestOptions(ctx)
+	results, err := s.client.SearchCollection(ctx, s.collectionID, SearchRequest{Input: prompt})
+	if err != nil {
+		return nil, false
+	}
+
+	for _, result := range results.Results {
+		if result.Score < s.Threshold {
+			continue
+		}
+		if result.ItemID == "" {
+			continue
+		}
+		item, err := s.client.GetItem(ctx, s.collectionID, result.ItemID)
+		if err != nil {
+			continue
+		}
+		var entry semanticCacheEntry
+		if json.Unmarshal([]byte(item.Item.Description), &entry) != nil || entry.Model != model || entry.Response == nil {
+			continue
+		}
+
+		entry.Response.Meta = &ResponseMeta{CacheScore: result.Score}
+		return entry.Response, true
+	}
+	return nil, false
+}
+
+// store writes the answer to prompt; failures are ignored
+func (s *SemanticCache) store(ctx context.Context, model, prompt string, resp *ChatCompletionResponse) {
+	if !cacheableAnswer(resp) {
+		return
+	}
+	data, err := json.Marshal(semanticCacheEntry{Model: model, Response: resp})
+	if err != nil {
+		return
+	}
+	_, _ = s.client.AddItem(withoutRequestOptions(ctx), s.collectionID, AddItemRequest{Content: prompt, Description: string(data), AutoChunk: Bool(false)})
+}
+
+// cacheableAnswer reports whether resp is a plain text answer worth
+// repeating: filtered, empty and tool-call answers are not
+func cacheableAnswer(resp *ChatCompletionResponse) bool {
+	if len(resp.Choices) == 0 {
+		return false
+	}
+	choice := resp.Choices[0]
+	return choice.FinishReason != "content_filter" && len(choice.Message.ToolCalls) == 0 &&
+		strings.TrimSpace(choice.Message.Content) != ""
+}
@@ -0,0 +1,84 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSemanticCache(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	WithSemanticCache(NewSemanticCache(client, "cache"))(client)
+
+	req := ChatCompletionRequest{Model: "m", Messages: []Message{CreateUserMessage("What is the capital of France?")}}
+	transport.SetResponse("POST", "/chat/completions", 200, &ChatCompletionResponse{
+		ID:      "chat-1",
+		Choices: []Choice{{Message: CreateAssistantMessage("Paris")}},
+	})
+
+	// A miss calls the model and stores the answer
+	resp, err := client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "Paris", resp.Choices[0].Message.Content)
+
+	requests := transport.GetRequests()
+	require.Len(t, requests, 3)
+	assert.Equal(t, "/vector-stores/collections/cache/search", requests[0].URL.Path)
+	assert.Equal(t, "/vector-stores/collections/cache/items", requests[2].URL.Path)
+	var added AddItemRequest
+	require.NoError(t, json.NewDecoder(requests[2].Body).Decode(&added))
+	assert.Equal(t, "user: What is the capital of France?", added.Content)
+
+	// A near-duplicate is answered from the collection
+	transport.SetResponse("POST", "/vector-stores/collections/cache/search", 200, SearchResponse{
+		Results: []SearchResult{{ID: "chunk-0", ItemID: "low", Score: 0.5}, {ID: "chunk-1", ItemID: "item-1", Score: 0.97}},
+	})
+	transport.SetResponse("GET", "/vector-stores/collections/cache/items/item-1", 200, GetItemResponse{
+		Item: CollectionItem{ID: "item-1", Description: added.Description},
+	})
+	req.Messages[0].Content = "what's the capital of France"
+	resp, err = client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "chat-1", resp.ID)
+	assert.Equal(t, "Paris", resp.Choices[0].Message.Content)
+	require.NotNil(t, resp.Meta)
+	assert.Equal(t, 0.97, resp.Meta.CacheScore)
+	assert.Len(t, transport.GetRequests(), 5)
+
+	// Answers stored for another model are ignored
+	transport.SetResponse("POST", "/vector-stores/collections/cache/search", 200, SearchResponse{
+		Results: []SearchResult{{ID: "chunk-1", ItemID: "item-1", Score: 0.99}},
+	})
+	transport.SetResponse("GET", "/vector-stores/collections/cache/items/item-1", 200, GetItemResponse{
+		Item: CollectionItem{ID: "item-1", Description: added.Description},
+	})
+	transport.SetResponse("POST", "/chat/completions", 200, &ChatCompletionResponse{ID: "chat-2"})
+	req.Model = "other"
+	resp, err = client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "chat-2", resp.ID)
+
+	// The empty answer isn't stored
+	assert.Len(t, transport.GetRequests(), 8)
+}
+
+func TestSemanticCacheSkipsUnusableAnswers(t *testing.T) {
+	for _, resp := range []*ChatCompletionResponse{
+		{Choices: []Choice{{Message: CreateAssistantMessage(" ")}}},
+		{Choices: []Choice{{Message: CreateAssistantMessage("partial"), FinishReason: "content_filter"}}},
+		{Choices: []Choice{{Message: Message{Role: "assistant", ToolCalls: []ToolCall{{ID: "call-1"}}}, FinishReason: "tool_calls"}}},
+	} {
+		client, transport := setupLocalTestClient()
+		WithSemanticCache(NewSemanticCache(client, "cache"))(client)
+		transport.SetResponse("POST", "/chat/completions", 200, resp)
+
+		_, err := client.CreateChatCompletion(context.Background(), ChatCompletionRequest{Model: "m", Messages: []Message{CreateUserMessage("Hi")}})
+		require.NoError(t, err)
+		for _, req := range transport.GetRequests() {
+			assert.NotEqual(t, "/vector-stores/collections/cache/items", req.URL.Path)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package vultrai
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// textSeparators are tried in order, from the most to the least meaningful
+// boundary
+var textSeparators = []string{"\n\n", "\n", ". ", "? ", "! ", " "}
+
+// SplitText splits text into chunks of at most chunkSize characters,
+// preferring paragraph, then line, then sentence, then word boundaries.
+// Consecutive chunks share up to overlap characters of context.
+func SplitText(text string, chunkSize, overlap int) []string {
+	if chunkSize <= 0 {
+		return nil
+	}
+	if overlap < 0 || overlap >= chunkSize {
+		overlap = 0
+	}
+
+	pieces := splitPieces(strings.TrimSpace(text), chunkSize, 0)
+	return mergePieces(pieces, chunkSize, overlap)
+}
+
+// splitPieces breaks text into pieces no longer than chunkSize, recursing
+// to finer separators only where needed
+func splitPieces(text string, chunkSize, level int) []string {
+	if utf8.RuneCountInString(text) <= chunkSize {
+		if text == "" {
+			return nil
+		}
+		return []string{text}
+	}
+
+	if level >= len(textSeparators) {
+		// No separator left: hard-split on rune boundaries
+		var pieces []string
+		runes := []rune(text)
+		for start := 0; start < len(runes); start += chunkSize {
+			end := start + chunkSize
+			if end > len(runes) {
+				end = len(runes)
+			}
+			pieces = append(pieces, string(runes[start:end]))
+		}
+		return pieces
+	}
+
+	sep := textSeparators[level]
+	parts := strings.SplitAfter(text, sep)
+	if len(parts) == 1 {
+		return splitPieces(text, chunkSize, level+1)
+	}
+
+	var pieces []string
+	for _, part := range parts {
+		pieces = append(pieces, splitPieces(part, chunkSize, level+1)...)
+	}
+	return pieces
+}
+
+// mergePieces packs pieces into chunks up to chunkSize, carrying the tail of
+// each chunk into the next as overlap
+func mergePieces(pieces []string, chunkSize, overlap int) []string {
+	var chunks []string
+	var current []string
+	currentLen := 0
+	fresh := false // current holds content not yet emitted
+
+	flush := func() {
+		chunk := strings.TrimSpace(strings.Join(current, ""))
+		if chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+
+		// Keep trailing pieces that fit within the overlap window
+		var kept []string
+		keptLen := 0
+		for i := len(current) - 1; i >= 0; i-- {
+			n := utf8.RuneCountInString(current[i])
+			if keptLen+n > overlap {
+				break
+			}
+			kept = append([]string{current[i]}, kept...)
+			keptLen += n
+		}
+		current, currentLen = kept, keptLen
+		fresh = false
+	}
+
+	for _, piece := range pieces {
+		n := utf8.RuneCountInString(piece)
+		if currentLen+n > chunkSize && currentLen > 0 {
+			flush()
+			// Drop overlap that would not leave room for the new piece
+			for currentLen+n > chunkSize && len(current) > 0 {
+				currentLen -= utf8.RuneCountInString(current[0])
+				current = current[1:]
+			}
+		}
+		current = append(current, piece)
+		currentLen += n
+		fresh = true
+	}
+
+	if fresh {
+		flush()
+	}
+
+	return chunks
+}
@@ -0,0 +1,161 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// SSEEvent is a server-sent event
+type SSEEvent struct {
+	// Event is the event type; browsers dispatch events without one as
+	// "message"
+	Event string
+	Data  string
+	ID    string
+}
+
+// SSEWriter writes server-sent events to an HTTP response, flushing each
+// event so it reaches the browser immediately
+type SSEWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+}
+
+// NewSSEWriter sets the event stream headers on w and returns a writer for
+// its body
+func NewSSEWriter(w http.ResponseWriter) *SSEWriter {
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// Keep reverse proxies such as nginx from buffering the stream
+	header.Set("X-Accel-Buffering", "no")
+
+	flusher, _ := w.(http.Flusher)
+	return &SSEWriter{w: w, flusher: flusher}
+}
+
+// Write sends an event. Multi-line data is split over several data lines
+// and joined back by the browser.
+func (s *SSEWriter) Write(event SSEEvent) error {
+	var sb strings.Builder
+	if event.Event != "" {
+		fmt.Fprintf(&sb, "event: %s\n", singleLine(event.Event))
+	}
+	if event.ID != "" {
+		fmt.Fprintf(&sb, "id: %s\n", singleLine(event.ID))
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(event.Data, "\r\n", "\n"), "\n") {
+		fmt.Fprintf(&sb, "data: %s\n", line)
+	}
+	sb.WriteString("\n")
+
+	if _, err := io.WriteString(s.w, sb.String()); err != nil {
+		return err
+	}
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+	return nil
+}
+
+// singleLine drops line breaks, which would end an SSE field
+func singleLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+// StreamFormatter turns the chunks of a chat completion stream into events
+// for a browser
+type StreamFormatter interface {
+	// Format returns the events for a chunk
+	Format(chunk *StreamChatCompletion) ([]SSEEvent, error)
+	// Finish returns the events closing the stream
+	Finish() ([]SSEEvent, error)
+}
+
+// JSONStreamFormatter relays chunks unchanged as JSON, ending with the
+// "[DONE]" marker like the API
+type JSONStreamFormatter struct{}
+
+// Format encodes chunk as JSON
+func (JSONStreamFormatter) Format(chunk *StreamChatCompletion) ([]SSEEvent, error) {
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding chunk: %w", err)
+	}
+	return []SSEEvent{{Data: string(data)}}, nil
+}
+
+// Finish sends the "[DONE]" marker
+func (JSONStreamFormatter) Finish() ([]SSEEvent, error) {
+	return []SSEEvent{{Data: "[DONE]"}}, nil
+}
+
+// RelayStream reads stream to the end and sends it to the browser as
+// server-sent events produced by formatter, JSONStreamFormatter when nil.
+// The stream is closed when RelayStream returns.
+func RelayStream(w http.ResponseWriter, stream *StreamReader, formatter StreamFormatter) error {
+	defer stream.Close()
+	if formatter == nil {
+		formatter = JSONStreamFormatter{}
+	}
+	sse := NewSSEWriter(w)
+
+	send := func(events []SSEEvent, err error) error {
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if err := sse.Write(event); err != nil {
+				return fmt.Errorf("error writing event: %w", err)
+			}
+		}
+		return nil
+	}
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := send(formatter.Format(chunk)); err != nil {
+			return err
+		}
+	}
+	return send(formatter.Finish())
+}
+
+// ProxyStreamToHTTP streams a chat completion to the browser as server-sent
+// events relaying the chunks as JSON, as RelayStream does. Pass the
+// incoming request's context as ctx so the upstream stream stops when the
+// browser disconnects. When the stream can't be opened, nothing is written
+// and the error is returned for the caller to answer; an error in the
+// middle of the stream is sent as an "error" event before being returned.
+func (c *Client) ProxyStreamToHTTP(ctx context.Context, req ChatCompletionRequest, w http.ResponseWriter) error {
+	stream, err := c.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if err := RelayStream(w, stream, nil); err != nil {
+		if ctx.Err() == nil {
+			apiError := Error{Message: err.Error()}
+			var streamErr *StreamError
+			if errors.As(err, &streamErr) {
+				apiError = Error{Message: streamErr.Message, Type: streamErr.Type, Code: streamErr.Code}
+			}
+			data, _ := json.Marshal(map[string]Error{"error": apiError})
+			NewSSEWriter(w).Write(SSEEvent{Event: "error", Data: string(data)})
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deltaStream builds a stream reader sending each delta as a chunk
+func deltaStream(deltas ...string) *StreamReader {
+	var sb strings.Builder
+	for _, delta := range deltas {
+		data, _ := json.Marshal(StreamChatCompletion{Choices: []StreamChoice{{Delta: StreamDelta{Content: delta}}}})
+		fmt.Fprintf(&sb, "data: %s\n\n", data)
+	}
+	sb.WriteString("data: [DONE]\n\n")
+	return NewStreamReader(io.NopCloser(strings.NewReader(sb.String())))
+}
+
+func TestRelayStream(t *testing.T) {
+	rec := httptest.NewRecorder()
+	require.NoError(t, RelayStream(rec, deltaStream("Hel", "lo"), nil))
+
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.True(t, rec.Flushed)
+	body := rec.Body.String()
+	assert.Contains(t, body, `data: {"id":"","created":0,"model":"","choices":[{"index":0,"delta":{"content":"Hel"}}]}`+"\n\n")
+	assert.True(t, strings.HasSuffix(body, "data: [DONE]\n\n"))
+}
+
+func TestSSEWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	sse := NewSSEWriter(rec)
+	require.NoError(t, sse.Write(SSEEvent{Event: "block\ninjected", ID: "1", Data: "<p>a\nb</p>"}))
+	assert.Equal(t, "event: blockinjected\nid: 1\ndata: <p>a\ndata: b</p>\n\n", rec.Body.String())
+}
+
+func TestHTMLStreamFormatter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	stream := deltaStream("# Ti", "tle\n\nUse `<b>` and **bo", "ld** [x](javascript:alert) <script>", "\n\n- [docs](https://example.com/?a=1&b=2)")
+	require.NoError(t, RelayStream(rec, stream, NewHTMLStreamFormatter("answer", false)))
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "event: block\ndata: <h1>Title</h1>\n\n")
+	assert.Contains(t, body, "event: pending\ndata: <p class=\"pending\">Use `&lt;b&gt;` and **bo</p>\n\n")
+	assert.Contains(t, body, "event: block\ndata: <p>Use <code>&lt;b&gt;</code> and <strong>bold</strong> x &lt;script&gt;</p>\n\n")
+	assert.Contains(t, body, `data: <ul><li><a href="https://example.com/?a=1&amp;b=2" rel="noopener noreferrer">docs</a></li></ul>`)
+	assert.NotContains(t, body, "<script>")
+	assert.True(t, strings.HasSuffix(body, "event: pending\ndata: \n\nevent: done\ndata: \n\n"))
+}
+
+func TestHTMLStreamFormatterTurbo(t *testing.T) {
+	rec := httptest.NewRecorder()
+	require.NoError(t, RelayStream(rec, deltaStream("```go\nx < 1\n```\n"), NewHTMLStreamFormatter("answer", true)))
+
+	assert.Contains(t, rec.Body.String(), `data: <turbo-stream action="append" target="answer"><template><pre><code class="language-go">x &lt; 1</code></pre></template></turbo-stream>`)
+}
+
+func TestProxyStreamToHTTP(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	transport.responses["POST /chat/completions"] = textResponse(200,
+		`data: {"id":"chat-1","choices":[{"index":0,"delta":{"content":"Hi"}}]}`+"\n\n"+
+			`event: error`+"\n"+`data: {"message":"overloaded","code":"capacity"}`+"\n\n")
+
+	rec := httptest.NewRecorder()
+	err := client.ProxyStreamToHTTP(context.Background(), ChatCompletionRequest{Model: "m"}, rec)
+	var streamErr *StreamError
+	require.ErrorAs(t, err, &streamErr)
+
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.Contains(t, body, `"content":"Hi"`)
+	assert.True(t, strings.HasSuffix(body, "event: error\ndata: {\"error\":{\"message\":\"overloaded\",\"code\":\"capacity\"}}\n\n"))
+
+	// Errors opening the stream leave the response untouched
+	transport.SetResponse("POST", "/chat/completions", 401, map[string]string{"message": "bad key"})
+	rec = httptest.NewRecorder()
+	err = client.ProxyStreamToHTTP(context.Background(), ChatCompletionRequest{Model: "m"}, rec)
+	assert.ErrorIs(t, err, ErrAuth)
+	assert.Empty(t, rec.Header().Get("Content-Type"))
+	assert.Zero(t, rec.Body.Len())
+}
@@ -0,0 +1,189 @@
+package vultrai
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultStreamBuffer is the default capacity of a StreamChannel
+const DefaultStreamBuffer = 16
+
+// StreamChannelOptions configures StreamReader.Channel
+type StreamChannelOptions struct {
+	// Buffer is the number of chunks held for a slow consumer (default
+	// DefaultStreamBuffer)
+	Buffer int
+	// DropWhenFull drops chunks the consumer has no room for instead of
+	// pausing reading. Only use it when losing deltas is acceptable, e.g.
+	// for progress indicators.
+	DropWhenFull bool
+}
+
+// StreamChannelStats reports how a StreamChannel coped with its consumer
+type StreamChannelStats struct {
+	Received  int
+	Delivered int
+	Dropped   int
+	// Paused is the time reading was paused because the buffer was full
+	Paused time.Duration
+}
+
+// StreamChannel delivers the chunks of a stream on a bounded channel. When
+// the consumer falls behind and the buffer fills up, reading from the
+// connection pauses, so the server is slowed down by TCP flow control
+// instead of chunks piling up in memory.
+type StreamChannel struct {
+	// C receives the chunks and is closed at the end of the stream
+	C <-chan *StreamChatCompletion
+
+	stream   *StreamReader
+	done     chan struct{}
+	finished chan struct{}
+	once     sync.Once
+
+	mu     sync.Mutex
+	stats  StreamChannelStats
+	err    error
+	closed bool
+}
+
+// Channel starts reading the stream in the background and delivers its
+// chunks on a channel. The stream is closed when the end is reached, ctx
+// is done or the channel is closed.
+func (s *StreamReader) Channel(ctx context.Context, opts StreamChannelOptions) *StreamChannel {
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultStreamBuffer
+	}
+	ch := make(chan *StreamChatCompletion, opts.Buffer)
+	sc := &StreamChannel{C: ch, stream: s, done: make(chan struct{}), finished: make(chan struct{})}
+	go sc.run(ctx, ch, opts)
+	return sc
+}
+
+// ChatCompletionChannel creates a streaming chat completion delivered on a
+// bounded channel
+func (c *Client) ChatCompletionChannel(ctx context.Context, req ChatCompletionRequest, opts StreamChannelOptions) (*StreamChannel, error) {
+	stream, err := c.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return stream.Channel(ctx, opts), nil
+}
+
+// StreamChatCompletionChan streams a chat completion on a channel, for
+// consumers that select over several channels. It wraps
+// ChatCompletionChannel with the default options: errs receives at most one
+// error, including ctx.Err() when ctx ends the stream, and is closed after
+// chunks. Cancel ctx to stop early; chunks must otherwise be drained. Use
+// ChatCompletionChannel to tune buffering or read the delivery stats.
+func (c *Client) StreamChatCompletionChan(ctx context.Context, req ChatCompletionRequest) (<-chan *StreamChatCompletion, <-chan error) {
+	errs := make(chan error, 1)
+	sc, err := c.ChatCompletionChannel(ctx, req, StreamChannelOptions{})
+	if err != nil {
+		chunks := make(chan *StreamChatCompletion)
+		close(chunks)
+		errs <- err
+		close(errs)
+		return chunks, errs
+	}
+
+	go func() {
+		defer close(errs)
+		<-sc.finished
+		if err := sc.Err(); err != nil {
+			errs <- err
+		}
+	}()
+	return sc.C, errs
+}
+
+func (sc *StreamChannel) run(ctx context.Context, ch chan<- *StreamChatCompletion, opts StreamChannelOptions) {
+	defer close(sc.finished)
+	defer close(ch)
+	defer sc.stream.Close()
+
+	for {
+		chunk, err := sc.stream.Recv()
+		if err != nil {
+			if err != io.EOF {
+				// Reads fail when ctx ends the stream, so report why
+				if ctx.Err() != nil {
+					err = ctx.Err()
+				}
+				sc.fail(err)
+			}
+			return
+		}
+		sc.update(func(s *StreamChannelStats) { s.Received++ })
+
+		select {
+		case ch <- chunk:
+			sc.update(func(s *StreamChannelStats) { s.Delivered++ })
+			continue
+		default:
+		}
+
+		if opts.DropWhenFull {
+			sc.update(func(s *StreamChannelStats) { s.Dropped++ })
+			continue
+		}
+
+		paused := time.Now()
+		select {
+		case ch <- chunk:
+			sc.update(func(s *StreamChannelStats) {
+				s.Delivered++
+				s.Paused += time.Since(paused)
+			})
+		case <-ctx.Done():
+			sc.fail(ctx.Err())
+			return
+		case <-sc.done:
+			return
+		}
+	}
+}
+
+func (sc *StreamChannel) update(fn func(*StreamChannelStats)) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	fn(&sc.stats)
+}
+
+// fail records err unless the channel was closed by the consumer, which
+// makes reads fail too
+func (sc *StreamChannel) fail(err error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if !sc.closed {
+		sc.err = err
+	}
+}
+
+// Err returns the error that ended the stream early, if any. It is
+// meaningful once C is closed.
+func (sc *StreamChannel) Err() error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.err
+}
+
+// Stats returns the current delivery statistics
+func (sc *StreamChannel) Stats() StreamChannelStats {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.stats
+}
+
+// Close stops reading and closes the stream. C is closed shortly after.
+func (sc *StreamChannel) Close() error {
+	sc.once.Do(func() {
+		sc.mu.Lock()
+		sc.closed = true
+		sc.mu.Unlock()
+		close(sc.done)
+	})
+	return sc.stream.Close()
+}
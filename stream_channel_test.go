@@ -0,0 +1,118 @@
+package vultrai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventSource serves one SSE event per Read and counts the reads
+type eventSource struct {
+	events, served int
+	reads          atomic.Int32
+}
+
+func (s *eventSource) Read(p []byte) (int, error) {
+	s.reads.Add(1)
+	if s.served == s.events {
+		return copy(p, "data: [DONE]\n\n"), nil
+	}
+	s.served++
+	return copy(p, fmt.Sprintf("data: {\"choices\":[{\"delta\":{\"content\":\"%d \"}}]}\n\n", s.served)), nil
+}
+
+func (s *eventSource) Close() error { return nil }
+
+func TestStreamChannelBackpressure(t *testing.T) {
+	source := &eventSource{events: 100}
+	sc := NewStreamReader(source).Channel(context.Background(), StreamChannelOptions{Buffer: 2})
+
+	// Without a consumer, reading stops once the buffer is full
+	time.Sleep(50 * time.Millisecond)
+	assert.LessOrEqual(t, source.reads.Load(), int32(5))
+
+	var chunks []*StreamChatCompletion
+	for chunk := range sc.C {
+		chunks = append(chunks, chunk)
+	}
+	require.NoError(t, sc.Err())
+	assert.Len(t, chunks, 100)
+	assert.Equal(t, "1 ", chunks[0].Choices[0].Delta.Content)
+
+	stats := sc.Stats()
+	assert.Equal(t, 100, stats.Received)
+	assert.Equal(t, 100, stats.Delivered)
+	assert.Zero(t, stats.Dropped)
+	assert.Greater(t, stats.Paused, 40*time.Millisecond)
+}
+
+func TestStreamChannelDrop(t *testing.T) {
+	sc := NewStreamReader(&eventSource{events: 50}).Channel(context.Background(), StreamChannelOptions{Buffer: 1, DropWhenFull: true})
+	time.Sleep(20 * time.Millisecond)
+
+	n := 0
+	for range sc.C {
+		n++
+	}
+	stats := sc.Stats()
+	assert.Equal(t, 50, stats.Received)
+	assert.Equal(t, n, stats.Delivered)
+	assert.Equal(t, 50-n, stats.Dropped)
+	assert.Positive(t, stats.Dropped)
+}
+
+func TestStreamChannelClose(t *testing.T) {
+	sc := NewStreamReader(io.NopCloser(&eventSource{events: 1000})).Channel(context.Background(), StreamChannelOptions{Buffer: 1})
+	<-sc.C
+	require.NoError(t, sc.Close())
+	for range sc.C {
+	}
+	assert.NoError(t, sc.Err())
+	assert.Less(t, sc.Stats().Received, 1000)
+}
+
+func TestStreamChatCompletionChan(t *testing.T) {
+	server := pacedServer(t, 0, 0, 0)
+	client := NewClient("key", WithBaseURL(server.URL))
+
+	chunks, errs := client.StreamChatCompletionChan(context.Background(), ChatCompletionRequest{Model: "m"})
+	content := ""
+	for chunk := range chunks {
+		content += chunk.Choices[0].Delta.Content
+	}
+	assert.Equal(t, "012", content)
+	assert.NoError(t, <-errs)
+}
+
+func TestStreamChatCompletionChanCancel(t *testing.T) {
+	server := pacedServer(t, 0, time.Second)
+	client := NewClient("key", WithBaseURL(server.URL))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	chunks, errs := client.StreamChatCompletionChan(ctx, ChatCompletionRequest{Model: "m"})
+	first := <-chunks
+	require.NotNil(t, first)
+	cancel()
+
+	for range chunks {
+	}
+	assert.ErrorIs(t, <-errs, context.Canceled)
+}
+
+func TestStreamChatCompletionChanError(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	transport.SetResponse("POST", "/chat/completions", 401, Error{Message: "bad key"})
+
+	chunks, errs := client.StreamChatCompletionChan(context.Background(), ChatCompletionRequest{Model: "m"})
+	_, open := <-chunks
+	assert.False(t, open)
+	assert.True(t, IsAuthError(<-errs))
+	_, open = <-errs
+	assert.False(t, open)
+}
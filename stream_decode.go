@@ -0,0 +1,141 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ItemFunc is called for each item decoded from a list response
+type ItemFunc func(CollectionItem) error
+
+// SearchResultFunc is called for each result decoded from a search response
+type SearchResultFunc func(SearchResult) error
+
+// ListItemsFunc lists items in a vector store collection, decoding the
+// response incrementally and calling fn for each item. Memory use stays
+// constant regardless of collection size. Returning an error from fn stops
+// decoding and returns that error.
+func (c *Client) ListItemsFunc(ctx context.Context, collectionID string, fn ItemFunc) error {
+	endpoint := fmt.Sprintf("/vector-stores/collections/%s/items", collectionID)
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return decodeArrayField(resp.Body, "items", fn, nil)
+}
+
+// SearchCollectionFunc searches a vector store collection, decoding the
+// response incrementally and calling fn for each result. The results are
+// ranked with RankSearchResults like those of SearchCollection, so fn is
+// called once the response is decoded; when req.TopK is set, only a few
+// times TopK results are held at once.
+func (c *Client) SearchCollectionFunc(ctx context.Context, id string, req SearchRequest, fn SearchResultFunc) (*Usage, error) {
+	endpoint := fmt.Sprintf("/vector-stores/collections/%s/search", id)
+	resp, err := c.doRequest(ctx, "POST", endpoint, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var usage Usage
+	var results []SearchResult
+	collect := func(result SearchResult) error {
+		results = append(results, result)
+		if req.TopK > 0 && len(results) >= 2*req.TopK {
+			results = RankSearchResults(req, results)
+		}
+		return nil
+	}
+	if err := decodeArrayField(resp.Body, "results", collect, map[string]interface{}{"usage": &usage}); err != nil {
+		return nil, err
+	}
+	for _, result := range RankSearchResults(req, results) {
+		if err := fn(result); err != nil {
+			return nil, err
+		}
+	}
+
+	c.reportUsage(resp, endpoint, "", usage)
+	return &usage, nil
+}
+
+// decodeArrayField walks a JSON object and streams the elements of the array
+// stored under field to fn one at a time. Other keys listed in extra are
+// decoded into the given targets; everything else is skipped.
+func decodeArrayField[T any](r io.Reader, field string, fn func(T) error, extra map[string]interface{}) error {
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("error decoding response: %w", err)
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("error decoding response: unexpected token %v", tok)
+		}
+
+		switch {
+		case key == field:
+			if err := decodeArray(dec, fn); err != nil {
+				return err
+			}
+		case extra[key] != nil:
+			if err := dec.Decode(extra[key]); err != nil {
+				return fmt.Errorf("error decoding response: %w", err)
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return fmt.Errorf("error decoding response: %w", err)
+			}
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+// decodeArray decodes a JSON array element by element, tolerating null
+func decodeArray[T any](dec *json.Decoder, fn func(T) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("error decoding response: expected array, got %v", tok)
+	}
+
+	for dec.More() {
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			return fmt.Errorf("error decoding response: %w", err)
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("error decoding response: expected %v, got %v", want, tok)
+	}
+	return nil
+}
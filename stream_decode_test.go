@@ -0,0 +1,98 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListItemsFunc(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+
+	mockTransport.SetResponse("GET", "/vector-stores/collections/coll-123/items", 200, &ListItemsResponse{
+		Items: []CollectionItem{
+			{ID: "item-1", Description: "first"},
+			{ID: "item-2", Description: "second"},
+		},
+	})
+
+	var ids []string
+	err := client.ListItemsFunc(context.Background(), "coll-123", func(item CollectionItem) error {
+		ids = append(ids, item.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"item-1", "item-2"}, ids)
+}
+
+func TestListItemsFuncStopsOnError(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+
+	mockTransport.SetResponse("GET", "/vector-stores/collections/coll-123/items", 200, &ListItemsResponse{
+		Items: []CollectionItem{{ID: "item-1"}, {ID: "item-2"}},
+	})
+
+	stop := errors.New("stop")
+	count := 0
+	err := client.ListItemsFunc(context.Background(), "coll-123", func(item CollectionItem) error {
+		count++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestSearchCollectionFunc(t *testing.T) {
+	client, mockTransport := setupLocalTestClient()
+
+	mockTransport.SetResponse("POST", "/vector-stores/collections/coll-123/search", 200, &SearchResponse{
+		Results: []SearchResult{{ID: "r-1", Content: "match"}},
+		Usage:   Usage{PromptTokens: 3, TotalTokens: 3},
+	})
+
+	var results []SearchResult
+	usage, err := client.SearchCollectionFunc(context.Background(), "coll-123", SearchRequest{Input: "q"}, func(r SearchResult) error {
+		results = append(results, r)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, 3, usage.TotalTokens)
+
+	mockTransport.SetResponse("POST", "/vector-stores/collections/coll-123/search", 200, &SearchResponse{
+		Results: []SearchResult{{ID: "r-1", Score: 0.2}, {ID: "r-2", Score: 0.8}, {ID: "r-3", Score: 0.6}, {ID: "r-4", Score: 0.9}},
+	})
+	results = nil
+	_, err = client.SearchCollectionFunc(context.Background(), "coll-123", SearchRequest{Input: "q", TopK: 2, ScoreThreshold: 0.5}, func(r SearchResult) error {
+		results = append(results, r)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "r-4", results[0].ID)
+	assert.Equal(t, "r-2", results[1].ID)
+}
+
+func TestDecodeArrayFieldSkipsUnknownKeys(t *testing.T) {
+	body := `{"meta":{"total":2,"links":{"next":""}},"items":[{"id":"a"},{"id":"b"}],"extra":[1,2]}`
+
+	var ids []string
+	err := decodeArrayField(strings.NewReader(body), "items", func(item CollectionItem) error {
+		ids = append(ids, item.ID)
+		return nil
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, ids)
+}
+
+func TestDecodeArrayFieldMalformed(t *testing.T) {
+	err := decodeArrayField(strings.NewReader(`{"items":{}}`), "items", func(item CollectionItem) error {
+		return nil
+	}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected array")
+}
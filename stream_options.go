@@ -0,0 +1,186 @@
+package vultrai
+
+import (
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// StreamOption configures StreamChatCompletion and StreamRAGChatCompletion
+type StreamOption func(*streamConfig)
+
+type streamConfig struct {
+	flushInterval  time.Duration
+	flushRunes     int
+	resumeAttempts int
+}
+
+// streamOptions returns the configuration set by options
+func streamOptions(options []StreamOption) streamConfig {
+	var cfg streamConfig
+	for _, option := range options {
+		option(&cfg)
+	}
+	return cfg
+}
+
+// WithDeltaCoalescing merges chunks arriving in quick succession before
+// calling the callback: buffered content is delivered every interval, or
+// as soon as it reaches runes runes. A zero value disables that trigger.
+// This reduces UI updates and websocket frames for fast models.
+func WithDeltaCoalescing(interval time.Duration, runes int) StreamOption {
+	return func(c *streamConfig) {
+		c.flushInterval = interval
+		c.flushRunes = runes
+	}
+}
+
+// streamCallback wraps callback according to options. finish must be
+// called once the stream ends: with true it delivers buffered content,
+// with false it discards it.
+func streamCallback(callback StreamCallback, options []StreamOption) (StreamCallback, func(flush bool) error) {
+	cfg := streamOptions(options)
+	if cfg.flushInterval <= 0 && cfg.flushRunes <= 0 {
+		return callback, func(bool) error { return nil }
+	}
+
+	coalescer := NewDeltaCoalescer(callback, cfg.flushInterval, cfg.flushRunes)
+	return coalescer.Add, func(flush bool) error {
+		if flush {
+			return coalescer.Flush()
+		}
+		coalescer.Stop()
+		return nil
+	}
+}
+
+// DeltaCoalescer buffers stream chunks and passes them to a callback
+// merged into one chunk, per interval or per number of runes. The callback
+// may be called from a timer goroutine, but never concurrently.
+type DeltaCoalescer struct {
+	callback StreamCallback
+	interval time.Duration
+	runes    int
+
+	mu      sync.Mutex
+	pending *StreamChatCompletion
+	count   int
+	timer   *time.Timer
+	err     error
+}
+
+// NewDeltaCoalescer creates a coalescer calling callback. A zero interval
+// or runes disables that trigger.
+func NewDeltaCoalescer(callback StreamCallback, interval time.Duration, runes int) *DeltaCoalescer {
+	return &DeltaCoalescer{callback: callback, interval: interval, runes: runes}
+}
+
+// Add buffers chunk, calling the callback if enough content is pending. It
+// has the signature of a StreamCallback and returns the callback's errors,
+// including those of timer flushes.
+func (d *DeltaCoalescer) Add(chunk *StreamChatCompletion) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err != nil {
+		return d.err
+	}
+
+	if d.pending == nil {
+		d.pending = &StreamChatCompletion{}
+		if d.interval > 0 {
+			d.timer = time.AfterFunc(d.interval, d.timerFlush)
+		}
+	}
+	mergeStreamChunk(d.pending, chunk)
+	for _, choice := range chunk.Choices {
+		d.count += utf8.RuneCountInString(choice.Delta.Content)
+	}
+
+	if d.runes > 0 && d.count >= d.runes {
+		d.flush()
+	}
+	return d.err
+}
+
+// Flush delivers buffered content and stops the timer
+func (d *DeltaCoalescer) Flush() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err == nil {
+		d.flush()
+	}
+	return d.err
+}
+
+// Stop discards buffered content and stops the timer
+func (d *DeltaCoalescer) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.pending, d.count, d.timer = nil, 0, nil
+}
+
+func (d *DeltaCoalescer) timerFlush() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err == nil {
+		d.flush()
+	}
+}
+
+// flush calls the callback with the pending chunk; d.mu must be held
+func (d *DeltaCoalescer) flush() {
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	chunk := d.pending
+	d.pending, d.count, d.timer = nil, 0, nil
+	if chunk != nil {
+		d.err = d.callback(chunk)
+	}
+}
+
+// mergeStreamChunk appends the deltas of src to dst, matching choices by
+// index
+func mergeStreamChunk(dst, src *StreamChatCompletion) {
+	if dst.ID == "" {
+		dst.ID, dst.Created = src.ID, src.Created
+	}
+	if src.Model != "" {
+		dst.Model = src.Model
+	}
+	if src.Usage != nil {
+		dst.Usage = src.Usage
+	}
+
+	for _, choice := range src.Choices {
+		var target *StreamChoice
+		for i := range dst.Choices {
+			if dst.Choices[i].Index == choice.Index {
+				target = &dst.Choices[i]
+				break
+			}
+		}
+		if target == nil {
+			dst.Choices = append(dst.Choices, StreamChoice{Index: choice.Index})
+			target = &dst.Choices[len(dst.Choices)-1]
+		}
+
+		if choice.Delta.Role != "" {
+			target.Delta.Role = choice.Delta.Role
+		}
+		target.Delta.Content += choice.Delta.Content
+		target.Delta.ToolCalls = mergeToolCalls(target.Delta.ToolCalls, choice.Delta.ToolCalls)
+		if choice.LogProbs != nil {
+			if target.LogProbs == nil {
+				target.LogProbs = &LogProbs{}
+			}
+			target.LogProbs.Content = append(target.LogProbs.Content, choice.LogProbs.Content...)
+		}
+		if choice.FinishReason != nil {
+			target.FinishReason = choice.FinishReason
+		}
+	}
+}
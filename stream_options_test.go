@@ -0,0 +1,56 @@
+package vultrai
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamCoalescingByRunes(t *testing.T) {
+	var got []string
+	callback := func(chunk *StreamChatCompletion) error {
+		got = append(got, chunk.Choices[0].Delta.Content)
+		return nil
+	}
+
+	stream := deltaStream("a", "b", "c", "dé", "f", "g")
+	require.NoError(t, ConsumeStream(stream, callback, WithDeltaCoalescing(0, 3)))
+	assert.Equal(t, []string{"abc", "déf", "g"}, got)
+}
+
+func TestDeltaCoalescerInterval(t *testing.T) {
+	var mu sync.Mutex
+	var got []*StreamChatCompletion
+	coalescer := NewDeltaCoalescer(func(chunk *StreamChatCompletion) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, chunk)
+		return nil
+	}, 20*time.Millisecond, 0)
+
+	stop := "stop"
+	require.NoError(t, coalescer.Add(&StreamChatCompletion{ID: "1", Choices: []StreamChoice{{Delta: StreamDelta{Role: "assistant", Content: "Hel"}}}}))
+	require.NoError(t, coalescer.Add(&StreamChatCompletion{ID: "1", Choices: []StreamChoice{{Delta: StreamDelta{Content: "lo"}}, {Index: 1, Delta: StreamDelta{Content: "Hi"}}}}))
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, coalescer.Add(&StreamChatCompletion{ID: "1", Choices: []StreamChoice{{Delta: StreamDelta{Content: "!"}, FinishReason: &stop}}}))
+	require.NoError(t, coalescer.Flush())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, got, 2)
+	assert.Equal(t, "1", got[0].ID)
+	assert.Equal(t, []StreamChoice{
+		{Delta: StreamDelta{Role: "assistant", Content: "Hello"}},
+		{Index: 1, Delta: StreamDelta{Content: "Hi"}},
+	}, got[0].Choices)
+	assert.Equal(t, "!", got[1].Choices[0].Delta.Content)
+	assert.Equal(t, &stop, got[1].Choices[0].FinishReason)
+}
@@ -0,0 +1,97 @@
+package vultrai
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+// WithStreamResume makes StreamChatCompletion reconnect up to attempts
+// times when the connection drops or stalls mid-stream. The request is sent
+// again with the content received so far appended as an assistant message
+// for the model to continue, and the callback keeps receiving chunks as one
+// stream: resumed chunks carry the first chunk's ID and no role. Streams
+// with several choices or tool calls are not resumed, and neither are
+// errors reported by the server.
+func WithStreamResume(attempts int) StreamOption {
+	return func(c *streamConfig) {
+		c.resumeAttempts = attempts
+	}
+}
+
+// streamWithResume streams req to callback, resuming it after interruptions
+func (c *Client) streamWithResume(ctx context.Context, req ChatCompletionRequest, callback StreamCallback, attempts int, options []StreamOption) error {
+	callback, finish := streamCallback(callback, options)
+
+	var id string
+	var content strings.Builder
+	current := req
+	for attempt := 0; ; attempt++ {
+		stream, err := c.CreateChatCompletionStream(ctx, current)
+		if err != nil {
+			finish(false)
+			return err
+		}
+
+		// finished is set once a finish reason arrives; resumable is cleared
+		// by output that can't be continued from text alone
+		finished, resumable := false, true
+		var streamErr error
+		for {
+			chunk, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					streamErr = err
+				}
+				break
+			}
+
+			if id == "" {
+				id = chunk.ID
+			} else if attempt > 0 {
+				chunk.ID = id
+			}
+			for i := range chunk.Choices {
+				choice := &chunk.Choices[i]
+				if attempt > 0 {
+					choice.Delta.Role = ""
+				}
+				if choice.Index != 0 || len(choice.Delta.ToolCalls) > 0 {
+					resumable = false
+				}
+				if choice.FinishReason != nil {
+					finished = true
+				}
+				content.WriteString(choice.Delta.Content)
+			}
+
+			if err := callback(chunk); err != nil {
+				stream.Close()
+				finish(false)
+				return err
+			}
+		}
+		stream.Close()
+
+		if !stream.interrupted || finished || !resumable || ctx.Err() != nil || attempt >= attempts {
+			if streamErr != nil {
+				finish(false)
+				return streamErr
+			}
+			return finish(true)
+		}
+		current = resumeRequest(req, content.String())
+	}
+}
+
+// resumeRequest returns req with the partial answer appended for the model
+// to continue
+func resumeRequest(req ChatCompletionRequest, partial string) ChatCompletionRequest {
+	if partial == "" {
+		return req
+	}
+	messages := make([]Message, 0, len(req.Messages)+1)
+	messages = append(messages, req.Messages...)
+	req.Messages = append(messages, Message{Role: "assistant", Content: partial})
+	return req
+}
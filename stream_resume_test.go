@@ -0,0 +1,71 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// brokenBody returns its content, then fails like a dropped connection
+type brokenBody struct {
+	io.Reader
+}
+
+func (b brokenBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF {
+		return n, io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (b brokenBody) Close() error { return nil }
+
+func TestStreamResume(t *testing.T) {
+	var requests []ChatCompletionRequest
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var body ChatCompletionRequest
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		requests = append(requests, body)
+
+		if len(requests) == 1 {
+			resp := textResponse(200, "")
+			resp.Body = brokenBody{strings.NewReader(
+				`data: {"id":"chat-1","choices":[{"index":0,"delta":{"role":"assistant","content":"Hello"}}]}` + "\n\n")}
+			return resp, nil
+		}
+		return textResponse(200,
+			`data: {"id":"chat-2","choices":[{"index":0,"delta":{"role":"assistant","content":" world"},"finish_reason":"stop"}]}`+"\n\n"+
+				"data: [DONE]\n\n"), nil
+	})
+	client := NewClient("key", WithBaseURL("https://api.test.local"), WithHTTPClient(&http.Client{Transport: transport}))
+
+	req := ChatCompletionRequest{Model: "m", Messages: []Message{{Role: "user", Content: "Say hello"}}}
+	var chunks []*StreamChatCompletion
+	err := client.StreamChatCompletion(context.Background(), req, func(chunk *StreamChatCompletion) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}, WithStreamResume(1))
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, []Message{{Role: "user", Content: "Say hello"}, {Role: "assistant", Content: "Hello"}}, requests[1].Messages)
+
+	require.Len(t, chunks, 2)
+	assert.Equal(t, "chat-1", chunks[1].ID)
+	assert.Empty(t, chunks[1].Choices[0].Delta.Role)
+	assert.Equal(t, "Hello world", StreamToComplete(chunks).Choices[0].Message.Content)
+
+	// Without attempts left, the drop is returned
+	requests = nil
+	err = client.StreamChatCompletion(context.Background(), req, func(*StreamChatCompletion) error { return nil })
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+	assert.Len(t, requests, 1)
+}
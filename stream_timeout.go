@@ -0,0 +1,163 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrStreamTimeout is matched by errors returned when a stream exceeds the
+// timeouts set with WithStreamTimeouts
+var ErrStreamTimeout = errors.New("stream timeout")
+
+// WithStreamTimeouts gives streaming requests their own deadlines in place
+// of the HTTP client's overall Timeout, which would otherwise cut long
+// streams short. firstByte bounds connecting and waiting for the first
+// byte of the answer; idle bounds the wait for each following read. A zero
+// value disables that timeout; with both zero, streams are subject to the
+// overall Timeout again. Both default to 30 seconds.
+func WithStreamTimeouts(firstByte, idle time.Duration) ClientOption {
+	return func(c *Client) {
+		c.streamFirstByte = firstByte
+		c.streamIdle = idle
+	}
+}
+
+// WithStreamIdleTimeout sets how long a stream may stay silent before it
+// fails with ErrStreamTimeout, keeping the first byte timeout
+func WithStreamIdleTimeout(idle time.Duration) ClientOption {
+	return func(c *Client) {
+		c.streamIdle = idle
+	}
+}
+
+type streamingKey struct{}
+
+// httpClientFor returns the HTTP client to send req with. A timeout set
+// with WithRequestTimeout wins; otherwise streaming requests get a copy
+// without the overall timeout when stream timeouts are configured, and
+// other requests get the timeout of their endpoint.
+func (c *Client) httpClientFor(req *http.Request) *http.Client {
+	timeout, ok := c.endpointTimeout(req)
+	if req.Context().Value(streamingKey{}) != nil && (c.streamFirstByte > 0 || c.streamIdle > 0) {
+		timeout, ok = 0, true
+	}
+	if cfg := requestOptions(req.Context()); cfg.timeout > 0 {
+		timeout, ok = cfg.timeout, true
+	}
+	if !ok {
+		return c.httpClient
+	}
+	client := *c.httpClient
+	client.Timeout = timeout
+	return &client
+}
+
+// doStreamRequest performs a POST expecting a server-sent event stream,
+// enforcing the stream timeouts
+func (c *Client) doStreamRequest(ctx context.Context, endpoint string, body interface{}) (*http.Response, error) {
+	headers := map[string]string{"Accept": "text/event-stream"}
+	if c.streamFirstByte <= 0 && c.streamIdle <= 0 {
+		return c.doRequest(ctx, "POST", endpoint, body, headers)
+	}
+
+	ctx, cancel := context.WithCancelCause(context.WithValue(ctx, streamingKey{}, true))
+	watchdog := &streamWatchdog{cancel: cancel, firstByte: c.streamFirstByte, idle: c.streamIdle}
+	watchdog.start()
+
+	resp, err := c.doRequest(ctx, "POST", endpoint, body, headers)
+	if err != nil {
+		watchdog.stop()
+		if cause := context.Cause(ctx); errors.Is(cause, ErrStreamTimeout) {
+			return nil, cause
+		}
+		return nil, err
+	}
+
+	resp.Body = &timeoutBody{ReadCloser: resp.Body, ctx: ctx, watchdog: watchdog}
+	return resp, nil
+}
+
+// streamWatchdog cancels a stream that stays silent for too long
+type streamWatchdog struct {
+	cancel    context.CancelCauseFunc
+	firstByte time.Duration
+	idle      time.Duration
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	started bool
+}
+
+func (w *streamWatchdog) start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.firstByte > 0 {
+		w.timer = time.AfterFunc(w.firstByte, func() {
+			w.cancel(fmt.Errorf("%w: no data within %s", ErrStreamTimeout, w.firstByte))
+		})
+	} else {
+		w.received()
+	}
+}
+
+// received records that data arrived, switching to the idle timeout
+func (w *streamWatchdog) received() {
+	if w.started {
+		if w.timer != nil {
+			w.timer.Reset(w.idle)
+		}
+		return
+	}
+	w.started = true
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	if w.idle > 0 {
+		w.timer = time.AfterFunc(w.idle, func() {
+			w.cancel(fmt.Errorf("%w: stream idle for %s", ErrStreamTimeout, w.idle))
+		})
+	}
+}
+
+func (w *streamWatchdog) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.cancel(context.Canceled)
+}
+
+// timeoutBody feeds the watchdog and reports its timeouts as read errors
+type timeoutBody struct {
+	io.ReadCloser
+	ctx      context.Context
+	watchdog *streamWatchdog
+}
+
+func (b *timeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.watchdog.mu.Lock()
+		b.watchdog.received()
+		b.watchdog.mu.Unlock()
+	}
+	if err != nil && err != io.EOF {
+		if cause := context.Cause(b.ctx); errors.Is(cause, ErrStreamTimeout) {
+			return n, cause
+		}
+	}
+	return n, err
+}
+
+func (b *timeoutBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.watchdog.stop()
+	return err
+}
@@ -0,0 +1,118 @@
+package vultrai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pacedServer streams chunks after the given delays
+func pacedServer(t *testing.T, delays ...time.Duration) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		for i, delay := range delays {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":\"%d\"}}]}\n\n", i)
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func collectStream(client *Client) (string, error) {
+	content := ""
+	err := client.StreamChatCompletion(context.Background(), ChatCompletionRequest{Model: "m"}, func(chunk *StreamChatCompletion) error {
+		content += chunk.Choices[0].Delta.Content
+		return nil
+	})
+	return content, err
+}
+
+func TestStreamTimeouts(t *testing.T) {
+	delays := []time.Duration{10 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond}
+	server := pacedServer(t, delays...)
+	httpClient := &http.Client{Timeout: 60 * time.Millisecond}
+
+	// The overall timeout kills the stream when stream timeouts are disabled
+	_, err := collectStream(NewClient("key", WithBaseURL(server.URL), WithHTTPClient(httpClient), WithStreamTimeouts(0, 0)))
+	require.Error(t, err)
+
+	// Streams are exempt by default
+	content, err := collectStream(NewClient("key", WithBaseURL(server.URL), WithHTTPClient(httpClient)))
+	require.NoError(t, err)
+	assert.Equal(t, "0123", content)
+
+	// Stream timeouts replace it
+	client := NewClient("key", WithBaseURL(server.URL), WithHTTPClient(httpClient), WithStreamTimeouts(time.Second, 200*time.Millisecond))
+	content, err = collectStream(client)
+	require.NoError(t, err)
+	assert.Equal(t, "0123", content)
+
+	// Regular requests keep the overall timeout
+	assert.Equal(t, httpClient, client.httpClientFor(httptest.NewRequest("GET", server.URL+"/usage", nil)))
+}
+
+func TestStreamTimeoutFirstByte(t *testing.T) {
+	server := pacedServer(t, 300*time.Millisecond)
+	client := NewClient("key", WithBaseURL(server.URL), WithStreamTimeouts(50*time.Millisecond, 0))
+
+	start := time.Now()
+	_, err := collectStream(client)
+	assert.ErrorIs(t, err, ErrStreamTimeout)
+	assert.Less(t, time.Since(start), 250*time.Millisecond)
+}
+
+func TestStreamTimeoutIdle(t *testing.T) {
+	server := pacedServer(t, 0, 300*time.Millisecond)
+	client := NewClient("key", WithBaseURL(server.URL), WithStreamTimeouts(0, 50*time.Millisecond))
+
+	content, err := collectStream(client)
+	assert.ErrorIs(t, err, ErrStreamTimeout)
+	assert.Contains(t, err.Error(), "idle")
+	assert.Equal(t, "0", content)
+}
+
+func TestStreamIdleTimeout(t *testing.T) {
+	server := pacedServer(t, 0, 300*time.Millisecond)
+	client := NewClient("key", WithBaseURL(server.URL), WithStreamIdleTimeout(50*time.Millisecond))
+	assert.Equal(t, defaultTimeout, client.streamFirstByte)
+
+	content, err := collectStream(client)
+	assert.ErrorIs(t, err, ErrStreamTimeout)
+	assert.Equal(t, "0", content)
+}
+
+func TestTransportTimeouts(t *testing.T) {
+	httpClient := &http.Client{Timeout: time.Minute}
+	client := NewClient("key", WithHTTPClient(httpClient), WithConnectTimeout(2*time.Second), WithResponseHeaderTimeout(5*time.Second))
+
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 2*time.Second, transport.TLSHandshakeTimeout)
+	assert.Equal(t, 5*time.Second, transport.ResponseHeaderTimeout)
+	assert.Equal(t, time.Minute, client.httpClient.Timeout)
+	assert.Nil(t, httpClient.Transport)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client = NewClient("key", WithBaseURL(server.URL), WithResponseHeaderTimeout(50*time.Millisecond))
+	_, err := client.GetUsage(context.Background())
+	assert.Error(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 )
 
@@ -15,6 +16,9 @@ type StreamChatCompletion struct {
 	Created int64          `json:"created"`
 	Model   string         `json:"model"`
 	Choices []StreamChoice `json:"choices"`
+	// Usage is set on the final chunk when usage was requested with
+	// WithStreamIncludeUsage; that chunk has no choices
+	Usage *Usage `json:"usage,omitempty"`
 }
 
 // StreamChoice represents a streaming choice
@@ -32,54 +36,95 @@ type StreamDelta struct {
 	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
 }
 
+// maxStreamLineSize bounds a single SSE line; chunks carrying large tool call
+// arguments or logprobs can exceed bufio.Scanner's 64KB default
+const maxStreamLineSize = 4 * 1024 * 1024
+
 // StreamReader wraps the streaming response reader
 type StreamReader struct {
 	reader  *bufio.Scanner
 	closer  io.Closer
 	isFirst bool
+	// interrupted is set when the stream broke off before [DONE]
+	interrupted bool
+	// onUsage receives the usage of a final usage chunk
+	onUsage func(Usage)
+	// onToken is called for the first chunk carrying content or tool calls
+	onToken func()
 }
 
 // NewStreamReader creates a new stream reader
 func NewStreamReader(reader io.ReadCloser) *StreamReader {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineSize)
+	scanner.Split(scanSSELines)
+
 	return &StreamReader{
-		reader:  bufio.NewScanner(reader),
+		reader:  scanner,
 		closer:  reader,
 		isFirst: true,
 	}
 }
 
-// Recv receives the next streaming chunk
-func (s *StreamReader) Recv() (*StreamChatCompletion, error) {
-	for s.reader.Scan() {
-		line := s.reader.Text()
+// StreamError is returned by Recv when the server reports an error in the
+// middle of a stream, with an "error" event or an error object in place of
+// a chunk
+type StreamError struct {
+	Message string
+	Type    string
+	Code    string
+	// Data is the raw event data
+	Data string
+}
 
-		// Skip empty lines
-		if line == "" {
-			continue
-		}
+func (e *StreamError) Error() string {
+	return "stream error: " + e.Message
+}
 
-		// Check for data prefix
-		if !strings.HasPrefix(line, "data: ") {
-			continue
+// Recv receives the next streaming chunk. Events are parsed per the SSE
+// spec: data lines are joined with newlines, comments and unknown fields
+// are skipped, and an event still pending at the end of the stream is
+// delivered.
+func (s *StreamReader) Recv() (*StreamChatCompletion, error) {
+	for {
+		event, data, ok := s.nextEvent()
+		if !ok {
+			break
 		}
 
-		// Extract JSON data
-		data := strings.TrimPrefix(line, "data: ")
-
 		// Check for stream end
 		if data == "[DONE]" {
 			return nil, io.EOF
 		}
+		if event == "error" {
+			return nil, newStreamError(data)
+		}
 
 		// Parse JSON
 		var chunk StreamChatCompletion
 		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
 			return nil, fmt.Errorf("error parsing streaming response: %w", err)
 		}
+		if chunk.ID == "" && len(chunk.Choices) == 0 {
+			var wrapped struct {
+				Error *Error `json:"error"`
+			}
+			if json.Unmarshal([]byte(data), &wrapped) == nil && wrapped.Error != nil {
+				return nil, newStreamError(data)
+			}
+		}
+		if chunk.Usage != nil && s.onUsage != nil {
+			s.onUsage(*chunk.Usage)
+		}
+		if s.onToken != nil && hasToken(&chunk) {
+			s.onToken()
+			s.onToken = nil
+		}
 
 		return &chunk, nil
 	}
 
+	s.interrupted = true
 	if err := s.reader.Err(); err != nil {
 		return nil, fmt.Errorf("error reading stream: %w", err)
 	}
@@ -87,6 +132,96 @@ func (s *StreamReader) Recv() (*StreamChatCompletion, error) {
 	return nil, io.EOF
 }
 
+// hasToken reports whether chunk carries generated content, as opposed to
+// only a role or a finish reason
+func hasToken(chunk *StreamChatCompletion) bool {
+	for _, choice := range chunk.Choices {
+		if choice.Delta.Content != "" || len(choice.Delta.ToolCalls) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// nextEvent reads lines up to the end of the next event with data. It
+// returns false when the stream ends without one.
+func (s *StreamReader) nextEvent() (event, data string, ok bool) {
+	var lines []string
+	hasData := false
+	for s.reader.Scan() {
+		line := s.reader.Text()
+
+		// A blank line dispatches the event
+		if line == "" {
+			if hasData {
+				return event, strings.Join(lines, "\n"), true
+			}
+			event = ""
+			continue
+		}
+
+		// Lines starting with a colon are comments, e.g. keep-alives
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "data":
+			lines = append(lines, value)
+			hasData = true
+		case "event":
+			event = value
+		}
+	}
+	if hasData {
+		return event, strings.Join(lines, "\n"), true
+	}
+	return "", "", false
+}
+
+// newStreamError decodes the data of an error event, which may be an
+// error object, an object wrapping one under "error", or plain text
+func newStreamError(data string) *StreamError {
+	streamErr := &StreamError{Data: data}
+	apiError, ok := decodeError([]byte(data))
+	if !ok {
+		apiError.Message = data
+	}
+	streamErr.Message, streamErr.Type, streamErr.Code = apiError.Message, apiError.Type, apiError.Code
+	return streamErr
+}
+
+// scanSSELines splits lines ending in CRLF, LF or a lone CR
+func scanSSELines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	for i, b := range data {
+		switch b {
+		case '\n':
+			return i + 1, data[:i], nil
+		case '\r':
+			if i+1 < len(data) {
+				if data[i+1] == '\n' {
+					return i + 2, data[:i], nil
+				}
+				return i + 1, data[:i], nil
+			}
+			if atEOF {
+				return i + 1, data[:i], nil
+			}
+			// Wait for the next byte to tell CR from CRLF
+			return 0, nil, nil
+		}
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
 // Close closes the stream reader
 func (s *StreamReader) Close() error {
 	if s.closer != nil {
@@ -100,14 +235,15 @@ func (c *Client) CreateChatCompletionStream(ctx context.Context, req ChatComplet
 	// Ensure streaming is enabled
 	req.Stream = Bool(true)
 
-	resp, err := c.doRequest(ctx, "POST", "/chat/completions", req, map[string]string{
-		"Accept": "text/event-stream",
-	})
+	resp, err := c.doStreamRequest(ctx, "/chat/completions", req)
 	if err != nil {
 		return nil, err
 	}
 
-	return NewStreamReader(resp.Body), nil
+	stream := NewStreamReader(resp.Body)
+	stream.onUsage = func(u Usage) { c.reportUsage(resp, "/chat/completions", req.Model, u) }
+	stream.onToken = observationOf(resp).firstToken
+	return stream, nil
 }
 
 // CreateRAGChatCompletionStream creates a streaming RAG chat completion
@@ -115,51 +251,47 @@ func (c *Client) CreateRAGChatCompletionStream(ctx context.Context, req RAGChatC
 	// Ensure streaming is enabled
 	req.Stream = Bool(true)
 
-	resp, err := c.doRequest(ctx, "POST", "/chat/completions/rag", req, map[string]string{
-		"Accept": "text/event-stream",
-	})
+	resp, err := c.doStreamRequest(ctx, "/chat/completions/rag", req)
 	if err != nil {
 		return nil, err
 	}
 
-	return NewStreamReader(resp.Body), nil
+	stream := NewStreamReader(resp.Body)
+	stream.onUsage = func(u Usage) { c.reportUsage(resp, "/chat/completions/rag", req.Model, u) }
+	stream.onToken = observationOf(resp).firstToken
+	return stream, nil
 }
 
 // StreamCallback represents a callback function for streaming responses
 type StreamCallback func(*StreamChatCompletion) error
 
 // StreamChatCompletion streams a chat completion with a callback
-func (c *Client) StreamChatCompletion(ctx context.Context, req ChatCompletionRequest, callback StreamCallback) error {
+func (c *Client) StreamChatCompletion(ctx context.Context, req ChatCompletionRequest, callback StreamCallback, options ...StreamOption) error {
+	if cfg := streamOptions(options); cfg.resumeAttempts > 0 {
+		return c.streamWithResume(ctx, req, callback, cfg.resumeAttempts, options)
+	}
+
 	stream, err := c.CreateChatCompletionStream(ctx, req)
 	if err != nil {
 		return err
 	}
-	defer stream.Close()
-
-	for {
-		chunk, err := stream.Recv()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return err
-		}
-
-		if err := callback(chunk); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return ConsumeStream(stream, callback, options...)
 }
 
 // StreamRAGChatCompletion streams a RAG chat completion with a callback
-func (c *Client) StreamRAGChatCompletion(ctx context.Context, req RAGChatCompletionRequest, callback StreamCallback) error {
+func (c *Client) StreamRAGChatCompletion(ctx context.Context, req RAGChatCompletionRequest, callback StreamCallback, options ...StreamOption) error {
 	stream, err := c.CreateRAGChatCompletionStream(ctx, req)
 	if err != nil {
 		return err
 	}
+	return ConsumeStream(stream, callback, options...)
+}
+
+// ConsumeStream passes every chunk of stream to callback and closes the
+// stream
+func ConsumeStream(stream *StreamReader, callback StreamCallback, options ...StreamOption) error {
 	defer stream.Close()
+	callback, finish := streamCallback(callback, options)
 
 	for {
 		chunk, err := stream.Recv()
@@ -167,31 +299,50 @@ func (c *Client) StreamRAGChatCompletion(ctx context.Context, req RAGChatComplet
 			break
 		}
 		if err != nil {
+			finish(false)
 			return err
 		}
 
 		if err := callback(chunk); err != nil {
+			finish(false)
 			return err
 		}
 	}
 
-	return nil
+	return finish(true)
 }
 
-// AccumulateStreamContent accumulates content from streaming chunks
+// AccumulateStreamContent accumulates content from streaming chunks. With
+// several choices, only the first one's content is returned; see
+// AccumulateStreamContentByIndex.
 func AccumulateStreamContent(chunks []*StreamChatCompletion) string {
-	var content strings.Builder
+	return AccumulateStreamContentByIndex(chunks)[0]
+}
 
+// AccumulateStreamContentByIndex accumulates the content of each choice of
+// streaming chunks, keyed by choice index, for requests with N > 1
+func AccumulateStreamContentByIndex(chunks []*StreamChatCompletion) map[int]string {
+	builders := make(map[int]*strings.Builder)
 	for _, chunk := range chunks {
-		if len(chunk.Choices) > 0 {
-			content.WriteString(chunk.Choices[0].Delta.Content)
+		for _, choice := range chunk.Choices {
+			b, ok := builders[choice.Index]
+			if !ok {
+				b = &strings.Builder{}
+				builders[choice.Index] = b
+			}
+			b.WriteString(choice.Delta.Content)
 		}
 	}
 
-	return content.String()
+	content := make(map[int]string, len(builders))
+	for index, b := range builders {
+		content[index] = b.String()
+	}
+	return content
 }
 
-// StreamToComplete converts a streaming response to a complete response
+// StreamToComplete converts a streaming response to a complete response,
+// with one choice per choice index seen in the stream
 func StreamToComplete(chunks []*StreamChatCompletion) *ChatCompletionResponse {
 	if len(chunks) == 0 {
 		return nil
@@ -200,34 +351,101 @@ func StreamToComplete(chunks []*StreamChatCompletion) *ChatCompletionResponse {
 	// Use the first chunk as base
 	first := chunks[0]
 
-	// Accumulate content
-	var content strings.Builder
-	var finishReason string
+	type assembly struct {
+		content      strings.Builder
+		role         string
+		toolCalls    []ToolCall
+		finishReason string
+	}
+	var choices []*assembly
+	byIndex := make(map[int]*assembly)
+	var indexes []int
+	var usage Usage
 
 	for _, chunk := range chunks {
-		if len(chunk.Choices) > 0 {
-			choice := chunk.Choices[0]
-			content.WriteString(choice.Delta.Content)
+		if chunk.Usage != nil {
+			usage = *chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			a, ok := byIndex[choice.Index]
+			if !ok {
+				a = &assembly{role: "assistant"}
+				byIndex[choice.Index] = a
+				choices = append(choices, a)
+				indexes = append(indexes, choice.Index)
+			}
+			if choice.Delta.Role != "" {
+				a.role = choice.Delta.Role
+			}
+			a.content.WriteString(choice.Delta.Content)
+			a.toolCalls = mergeToolCalls(a.toolCalls, choice.Delta.ToolCalls)
 
 			if choice.FinishReason != nil {
-				finishReason = *choice.FinishReason
+				a.finishReason = *choice.FinishReason
 			}
 		}
 	}
 
-	return &ChatCompletionResponse{
+	// A stream without choices still yields an empty assistant message
+	if len(choices) == 0 {
+		choices = append(choices, &assembly{role: "assistant"})
+		indexes = append(indexes, 0)
+	}
+
+	resp := &ChatCompletionResponse{
 		ID:      first.ID,
 		Created: first.Created,
 		Model:   first.Model,
-		Choices: []Choice{
-			{
-				Index: 0,
-				Message: Message{
-					Role:    "assistant",
-					Content: content.String(),
-				},
-				FinishReason: finishReason,
+		Choices: make([]Choice, len(choices)),
+		Usage:   usage,
+	}
+	for i, a := range choices {
+		for j := range a.toolCalls {
+			a.toolCalls[j].Index = nil
+		}
+		resp.Choices[i] = Choice{
+			Index: indexes[i],
+			Message: Message{
+				Role:      a.role,
+				Content:   a.content.String(),
+				ToolCalls: a.toolCalls,
 			},
-		},
+			FinishReason: a.finishReason,
+		}
+	}
+	sort.SliceStable(resp.Choices, func(i, j int) bool { return resp.Choices[i].Index < resp.Choices[j].Index })
+	return resp
+}
+
+// mergeToolCalls adds streamed tool call fragments to calls. A fragment
+// continues the call with the same index, or the last call when it has
+// neither an index nor an ID; its name and arguments are appended.
+func mergeToolCalls(calls, deltas []ToolCall) []ToolCall {
+	for _, delta := range deltas {
+		target := -1
+		for i := range calls {
+			if delta.Index != nil && calls[i].Index != nil && *calls[i].Index == *delta.Index {
+				target = i
+				break
+			}
+		}
+		if target < 0 && delta.Index == nil && delta.ID == "" && len(calls) > 0 {
+			target = len(calls) - 1
+		}
+		if target < 0 {
+			calls = append(calls, delta)
+			continue
+		}
+
+		call := &calls[target]
+		if delta.ID != "" {
+			call.ID = delta.ID
+		}
+		if delta.Type != "" {
+			call.Type = delta.Type
+		}
+		call.Function.Name += delta.Function.Name
+		call.Function.Arguments += delta.Function.Arguments
 	}
+	return calls
 }
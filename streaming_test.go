@@ -166,7 +166,7 @@ func TestStreamToCompleteEmpty(t *testing.T) {
 }
 
 func TestCreateChatCompletionStream(t *testing.T) {
-	client, mockTransport := setupTestClient()
+	client, mockTransport := setupLocalTestClient()
 
 	streamData := `data: {"id":"chat-123","created":1640995200,"model":"test-model","choices":[{"index":0,"delta":{"role":"assistant","content":"Hello"}}]}
 
@@ -204,7 +204,7 @@ data: [DONE]
 }
 
 func TestStreamChatCompletion(t *testing.T) {
-	client, mockTransport := setupTestClient()
+	client, mockTransport := setupLocalTestClient()
 
 	streamData := `data: {"id":"chat-123","created":1640995200,"model":"test-model","choices":[{"index":0,"delta":{"role":"assistant","content":"Hello"}}]}
 
@@ -240,7 +240,7 @@ data: [DONE]
 }
 
 func TestStreamChatCompletionCallbackError(t *testing.T) {
-	client, mockTransport := setupTestClient()
+	client, mockTransport := setupLocalTestClient()
 
 	streamData := `data: {"id":"chat-123","created":1640995200,"model":"test-model","choices":[{"index":0,"delta":{"content":"Hello"}}]}
 
@@ -269,7 +269,7 @@ data: [DONE]
 }
 
 func TestCreateRAGChatCompletionStream(t *testing.T) {
-	client, mockTransport := setupTestClient()
+	client, mockTransport := setupLocalTestClient()
 
 	streamData := `data: {"id":"rag-chat-123","created":1640995200,"model":"test-model","choices":[{"index":0,"delta":{"role":"assistant","content":"Based on context"}}]}
 
@@ -303,3 +303,179 @@ data: [DONE]
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestStreamReaderLargeLine(t *testing.T) {
+	content := strings.Repeat("x", 100*1024)
+	streamData := `data: {"id":"chat-123","choices":[{"index":0,"delta":{"content":"` + content + `"}}]}` + "\n\ndata: [DONE]\n\n"
+
+	reader := NewStreamReader(io.NopCloser(strings.NewReader(streamData)))
+	defer reader.Close()
+
+	chunk, err := reader.Recv()
+	require.NoError(t, err)
+	assert.Len(t, chunk.Choices[0].Delta.Content, len(content))
+}
+
+func TestStreamReaderSSEFormat(t *testing.T) {
+	streamData := ": keep-alive\r\n" +
+		"retry: 1000\r\n" +
+		"event: message\r\n" +
+		"data:{\"id\":\"chat-1\",\r\n" +
+		"data: \"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\r\n" +
+		"\r\n" +
+		"event: ping\r\r" +
+		"data: {\"id\":\"chat-1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"!\"}}]}\n" +
+		"\n" +
+		"data: [DONE]"
+
+	reader := NewStreamReader(io.NopCloser(strings.NewReader(streamData)))
+	defer reader.Close()
+
+	chunk, err := reader.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "chat-1", chunk.ID)
+	assert.Equal(t, "Hi", chunk.Choices[0].Delta.Content)
+
+	chunk, err = reader.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "!", chunk.Choices[0].Delta.Content)
+
+	_, err = reader.Recv()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestStreamReaderErrorEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		message string
+		code    string
+	}{
+		{"error event", "event: error\ndata: {\"message\":\"overloaded\",\"code\":\"server_busy\"}\n\n", "overloaded", "server_busy"},
+		{"plain error event", "event: error\ndata: upstream reset\n\n", "upstream reset", ""},
+		{"error object", "data: {\"error\":{\"message\":\"context too long\",\"type\":\"invalid_request_error\"}}\n\n", "context too long", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := NewStreamReader(io.NopCloser(strings.NewReader(tt.data)))
+			_, err := reader.Recv()
+
+			var streamErr *StreamError
+			require.ErrorAs(t, err, &streamErr)
+			assert.Equal(t, tt.message, streamErr.Message)
+			assert.Equal(t, tt.code, streamErr.Code)
+		})
+	}
+}
+
+func TestStreamIncludeUsage(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	var reported []Usage
+	WithUsageCallback(func(endpoint, model string, u Usage) {
+		assert.Equal(t, "/chat/completions", endpoint)
+		assert.Equal(t, "test-model", model)
+		reported = append(reported, u)
+	})(client)
+
+	transport.responses["POST /chat/completions"] = textResponse(200,
+		`data: {"id":"chat-1","choices":[{"index":0,"delta":{"content":"Hi"},"finish_reason":"stop"}]}`+"\n\n"+
+			`data: {"id":"chat-1","choices":[],"usage":{"prompt_tokens":5,"completion_tokens":1,"total_tokens":6}}`+"\n\n"+
+			"data: [DONE]\n\n")
+
+	req := ChatCompletionRequest{Model: "test-model"}
+	WithStreamIncludeUsage(true)(&req)
+	stream, err := client.CreateChatCompletionStream(context.Background(), req)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var chunks []*StreamChatCompletion
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+
+	body, _ := io.ReadAll(transport.GetRequests()[0].Body)
+	assert.Contains(t, string(body), `"stream_options":{"include_usage":true}`)
+
+	require.Len(t, chunks, 2)
+	assert.Empty(t, chunks[1].Choices)
+	resp := StreamToComplete(chunks)
+	assert.Equal(t, "Hi", resp.Choices[0].Message.Content)
+	assert.Equal(t, Usage{PromptTokens: 5, CompletionTokens: 1, TotalTokens: 6}, resp.Usage)
+	assert.Equal(t, []Usage{resp.Usage}, reported)
+}
+
+func TestStreamToCompleteToolCalls(t *testing.T) {
+	streamData := `data: {"id":"chat-1","choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}
+
+data: {"id":"chat-1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]}}]}
+
+data: {"id":"chat-1","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_2","type":"function","function":{"name":"get_time","arguments":"{}"}}]}}]}
+
+data: {"id":"chat-1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]},"finish_reason":"tool_calls"}]}
+
+data: [DONE]
+
+`
+
+	reader := NewStreamReader(io.NopCloser(strings.NewReader(streamData)))
+	defer reader.Close()
+
+	var chunks []*StreamChatCompletion
+	for {
+		chunk, err := reader.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+
+	resp := StreamToComplete(chunks)
+	assert.Equal(t, "tool_calls", resp.Choices[0].FinishReason)
+	assert.Equal(t, []ToolCall{
+		{ID: "call_1", Type: "function", Function: Function{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+		{ID: "call_2", Type: "function", Function: Function{Name: "get_time", Arguments: "{}"}},
+	}, resp.Choices[0].Message.ToolCalls)
+
+	// Coalesced chunks stay mergeable
+	merged := &StreamChatCompletion{}
+	for _, chunk := range chunks[:2] {
+		mergeStreamChunk(merged, chunk)
+	}
+	calls := merged.Choices[0].Delta.ToolCalls
+	require.Len(t, calls, 1)
+	assert.Equal(t, 0, *calls[0].Index)
+	assert.Equal(t, `{"city":`, calls[0].Function.Arguments)
+	assert.Equal(t, `{"city":"Paris"}`, StreamToComplete(append([]*StreamChatCompletion{merged}, chunks[2:]...)).Choices[0].Message.ToolCalls[0].Function.Arguments)
+}
+
+func TestStreamToCompleteMultipleChoices(t *testing.T) {
+	stop := "stop"
+	length := "length"
+	chunks := []*StreamChatCompletion{
+		{ID: "chat-1", Choices: []StreamChoice{{Index: 1, Delta: StreamDelta{Role: "assistant", Content: "B"}}}},
+		{ID: "chat-1", Choices: []StreamChoice{{Index: 0, Delta: StreamDelta{Role: "assistant", Content: "A"}}}},
+		{ID: "chat-1", Choices: []StreamChoice{
+			{Index: 0, Delta: StreamDelta{Content: "a"}, FinishReason: &stop},
+			{Index: 1, Delta: StreamDelta{Content: "b"}, FinishReason: &length},
+		}},
+	}
+
+	assert.Equal(t, map[int]string{0: "Aa", 1: "Bb"}, AccumulateStreamContentByIndex(chunks))
+	assert.Equal(t, "Aa", AccumulateStreamContent(chunks))
+
+	resp := StreamToComplete(chunks)
+	require.Len(t, resp.Choices, 2)
+	assert.Equal(t, 0, resp.Choices[0].Index)
+	assert.Equal(t, "Aa", resp.Choices[0].Message.Content)
+	assert.Equal(t, "stop", resp.Choices[0].FinishReason)
+	assert.Equal(t, 1, resp.Choices[1].Index)
+	assert.Equal(t, "Bb", resp.Choices[1].Message.Content)
+	assert.Equal(t, "length", resp.Choices[1].FinishReason)
+}
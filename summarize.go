@@ -0,0 +1,167 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// SummarizeOptions configures SummarizeLong. Sizes are in estimated tokens.
+type SummarizeOptions struct {
+	ChunkSize        int    // size of each map chunk (default 2000)
+	Overlap          int    // overlap between chunks (default 100)
+	Concurrency      int    // chunks summarized in parallel (default 4)
+	MaxSummaryTokens int    // length limit for each summary (default 512)
+	ContextWindow    int    // model context window used to size the reduce step (default 8192)
+	Instructions     string // optional extra guidance, e.g. audience or focus
+}
+
+func (o *SummarizeOptions) setDefaults() {
+	if o.ChunkSize <= 0 {
+		o.ChunkSize = 2000
+	}
+	if o.Overlap < 0 || o.Overlap >= o.ChunkSize {
+		o.Overlap = 0
+	} else if o.Overlap == 0 {
+		o.Overlap = 100
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 4
+	}
+	if o.MaxSummaryTokens <= 0 {
+		o.MaxSummaryTokens = 512
+	}
+	if o.ContextWindow <= 0 {
+		o.ContextWindow = 8192
+	}
+}
+
+// SummarizeLong summarizes a document that may not fit in the model's
+// context window. The document is split into chunks which are summarized
+// concurrently (map), then the partial summaries are combined (reduce),
+// collapsing them in groups first if they are still too long.
+func SummarizeLong(ctx context.Context, client *Client, model string, r io.Reader, opts SummarizeOptions) (string, error) {
+	opts.setDefaults()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("error reading document: %w", err)
+	}
+
+	// SplitText works in characters; tokens are roughly four characters
+	chunks := SplitText(string(data), opts.ChunkSize*4, opts.Overlap*4)
+	if len(chunks) == 0 {
+		return "", errors.New("document is empty")
+	}
+
+	summaries, err := summarizeAll(ctx, client, model, chunks, opts, "Summarize this section of a longer document.")
+	if err != nil {
+		return "", err
+	}
+
+	// Leave room for the instructions and the generated summary
+	budget := opts.ContextWindow - opts.MaxSummaryTokens - 256
+	for len(summaries) > 1 && EstimateTokens(strings.Join(summaries, "\n\n")) > budget {
+		groups := groupByBudget(summaries, budget)
+		if len(groups) == len(summaries) {
+			// Each summary fills the window alone; collapsing cannot shrink further
+			break
+		}
+		summaries, err = summarizeAll(ctx, client, model, groups, opts, "Combine these partial summaries of a document into one summary.")
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if len(summaries) == 1 && len(chunks) == 1 {
+		return summaries[0], nil
+	}
+
+	return summarizeText(ctx, client, model, strings.Join(summaries, "\n\n"), opts,
+		"Write a final, coherent summary of the whole document from these section summaries.")
+}
+
+// summarizeAll summarizes texts concurrently, preserving order
+func summarizeAll(ctx context.Context, client *Client, model string, texts []string, opts SummarizeOptions, task string) ([]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]string, len(texts))
+	sem := make(chan struct{}, opts.Concurrency)
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+
+	for i, text := range texts {
+		wg.Add(1)
+		go func(i int, text string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			summary, err := summarizeText(ctx, client, model, text, opts, task)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = summary
+		}(i, text)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+func summarizeText(ctx context.Context, client *Client, model, text string, opts SummarizeOptions, task string) (string, error) {
+	system := task + " Preserve key facts, figures and conclusions."
+	if opts.Instructions != "" {
+		system += " " + opts.Instructions
+	}
+
+	resp, err := client.CreateChatCompletion(ctx, ChatCompletionRequest{
+		Model:       model,
+		Messages:    []Message{CreateSystemMessage(system), CreateUserMessage(text)},
+		MaxTokens:   Int(opts.MaxSummaryTokens),
+		Temperature: Float64(0),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error summarizing: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", errors.New("error summarizing: no choices returned")
+	}
+
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
+
+// groupByBudget joins consecutive texts into groups that fit within budget tokens
+func groupByBudget(texts []string, budget int) []string {
+	var groups []string
+	var current []string
+	size := 0
+
+	for _, text := range texts {
+		n := EstimateTokens(text)
+		if size+n > budget && len(current) > 0 {
+			groups = append(groups, strings.Join(current, "\n\n"))
+			current, size = nil, 0
+		}
+		current = append(current, text)
+		size += n
+	}
+	if len(current) > 0 {
+		groups = append(groups, strings.Join(current, "\n\n"))
+	}
+
+	return groups
+}
@@ -0,0 +1,85 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitText(t *testing.T) {
+	text := "First paragraph. It has two sentences.\n\nSecond paragraph is here.\n\nThird one."
+
+	chunks := SplitText(text, 40, 0)
+	assert.Equal(t, []string{
+		"First paragraph. It has two sentences.",
+		"Second paragraph is here.\n\nThird one.",
+	}, chunks)
+
+	for _, chunk := range SplitText(strings.Repeat("word ", 200), 50, 10) {
+		assert.LessOrEqual(t, utf8.RuneCountInString(chunk), 50)
+	}
+
+	assert.Equal(t, []string{"abcd", "efgh", "ij"}, SplitText("abcdefghij", 4, 0))
+	assert.Empty(t, SplitText("   ", 10, 0))
+}
+
+func TestSplitTextOverlap(t *testing.T) {
+	chunks := SplitText("one two three four five six", 14, 6)
+	require.Greater(t, len(chunks), 1)
+	for i := 1; i < len(chunks); i++ {
+		prevWords := strings.Fields(chunks[i-1])
+		assert.True(t, strings.HasPrefix(chunks[i], prevWords[len(prevWords)-1]), "chunk %d should start with overlap", i)
+	}
+}
+
+func TestSummarizeLong(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		var req ChatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		content := "partial"
+		if strings.Contains(req.Messages[0].Content, "final") {
+			content = "final summary"
+		}
+		json.NewEncoder(w).Encode(ChatCompletionResponse{
+			Choices: []Choice{{Message: Message{Role: "assistant", Content: content}}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-api-key", WithBaseURL(server.URL))
+	document := strings.Repeat("A sentence about something important. ", 100)
+
+	summary, err := SummarizeLong(context.Background(), client, "test-model", strings.NewReader(document), SummarizeOptions{
+		ChunkSize: 200,
+		Overlap:   -1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "final summary", summary)
+
+	// ~3800 characters in 800-character chunks, plus the final reduce
+	assert.Equal(t, int32(6), atomic.LoadInt32(&calls))
+}
+
+func TestSummarizeLongEmpty(t *testing.T) {
+	client, _ := setupTestClient()
+	_, err := SummarizeLong(context.Background(), client, "test-model", io.LimitReader(strings.NewReader(""), 0), SummarizeOptions{})
+	assert.Error(t, err)
+}
+
+func TestGroupByBudget(t *testing.T) {
+	groups := groupByBudget([]string{strings.Repeat("a", 40), strings.Repeat("b", 40), strings.Repeat("c", 40)}, 20)
+	assert.Len(t, groups, 2)
+}
@@ -0,0 +1,30 @@
+package vultrai
+
+import "unicode/utf8"
+
+// messageTokenOverhead approximates the per-message framing tokens added by
+// chat templates (role markers and separators)
+const messageTokenOverhead = 4
+
+// EstimateTokens returns a rough token count for text, using the common
+// heuristic of about four characters per token. It is intended for budget
+// decisions, not billing.
+func EstimateTokens(text string) int {
+	runes := utf8.RuneCountInString(text)
+	if runes == 0 {
+		return 0
+	}
+	return (runes + 3) / 4
+}
+
+// EstimateMessagesTokens returns a rough token count for a message history
+func EstimateMessagesTokens(messages []Message) int {
+	total := 0
+	for _, msg := range messages {
+		total += messageTokenOverhead + EstimateTokens(msg.Content)
+		for _, call := range msg.ToolCalls {
+			total += EstimateTokens(call.Function.Name) + EstimateTokens(call.Function.Arguments)
+		}
+	}
+	return total
+}
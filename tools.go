@@ -0,0 +1,97 @@
+package vultrai
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Tool choice modes
+const (
+	// ToolChoiceAuto lets the model decide whether to call a tool
+	ToolChoiceAuto = "auto"
+	// ToolChoiceNone prevents tool calls
+	ToolChoiceNone = "none"
+	// ToolChoiceRequired makes the model call at least one tool
+	ToolChoiceRequired = "required"
+)
+
+// ToolChoice controls whether and which tool the model calls. It is sent
+// as the mode string, or as a named function when Function is set.
+type ToolChoice struct {
+	Mode     string
+	Function string
+}
+
+type namedToolChoice struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name string `json:"name"`
+	} `json:"function"`
+}
+
+// MarshalJSON encodes the choice in the API's format
+func (t ToolChoice) MarshalJSON() ([]byte, error) {
+	if t.Function == "" {
+		return json.Marshal(t.Mode)
+	}
+	var named namedToolChoice
+	named.Type = "function"
+	named.Function.Name = t.Function
+	return json.Marshal(named)
+}
+
+// UnmarshalJSON accepts a mode string or a named function
+func (t *ToolChoice) UnmarshalJSON(data []byte) error {
+	var mode string
+	if err := json.Unmarshal(data, &mode); err == nil {
+		*t = ToolChoice{Mode: mode}
+		return nil
+	}
+	var named namedToolChoice
+	if err := json.Unmarshal(data, &named); err != nil {
+		return fmt.Errorf("error decoding tool choice: %w", err)
+	}
+	*t = ToolChoice{Function: named.Function.Name}
+	return nil
+}
+
+// NewFunctionTool creates a function tool. parameters is a JSON Schema
+// object describing the arguments, such as one built with JSONSchemaOf.
+func NewFunctionTool(name, description string, parameters interface{}) Tool {
+	return Tool{
+		Type:     "function",
+		Function: ToolFunction{Name: name, Description: description, Parameters: parameters},
+	}
+}
+
+// CreateToolMessage creates a message answering the tool call with the
+// given ID
+func CreateToolMessage(toolCallID, content string) Message {
+	return Message{
+		Role:       "tool",
+		Content:    content,
+		ToolCallID: toolCallID,
+	}
+}
+
+// WithTools declares tools the model may call
+func WithTools(tools ...Tool) ChatOption {
+	return func(req *ChatCompletionRequest) {
+		req.Tools = append(req.Tools, tools...)
+	}
+}
+
+// WithToolChoice sets the tool choice mode: ToolChoiceAuto, ToolChoiceNone
+// or ToolChoiceRequired
+func WithToolChoice(mode string) ChatOption {
+	return func(req *ChatCompletionRequest) {
+		req.ToolChoice = &ToolChoice{Mode: mode}
+	}
+}
+
+// WithForcedTool makes the model call the named function
+func WithForcedTool(name string) ChatOption {
+	return func(req *ChatCompletionRequest) {
+		req.ToolChoice = &ToolChoice{Function: name}
+	}
+}
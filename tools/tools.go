@@ -0,0 +1,253 @@
+// Package tools turns Go functions into agents.Tool values the model may
+// call. The JSON Schema of each tool is derived from the function's
+// parameter struct, and tool calls returned by the model are dispatched to
+// the functions with their arguments decoded:
+//
+//	registry := tools.NewRegistry()
+//	registry.Register("get_weather", func(ctx context.Context, args WeatherArgs) (Weather, error) {
+//		...
+//	}, tools.WithDescription("Get the weather for a city"))
+//
+//	resp, err := client.ChatWithMessages(ctx, model, messages, registry.ChatOption())
+//	messages = append(messages, resp.Choices[0].Message)
+//	messages = append(messages, registry.Dispatch(ctx, resp.Choices[0].Message.ToolCalls)...)
+//
+// The registered tools are plain agents.Tool values, so the same registry
+// can equip an agent:
+//
+//	agent := agents.New(client, model, agents.WithTools(registry.Tools()...))
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/eqba1/vultrai/agents"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Option configures a registered function
+type Option func(*function)
+
+// WithDescription describes the tool to the model
+func WithDescription(description string) Option {
+	return func(f *function) {
+		f.description = description
+	}
+}
+
+// function is a registered Go function
+type function struct {
+	name        string
+	description string
+	fn          reflect.Value
+	hasContext  bool
+	// args is the type of the arguments parameter, nil when there is none
+	args      reflect.Type
+	hasResult bool
+	hasError  bool
+}
+
+// Func returns fn as the tool name. fn may take a context.Context, then a
+// struct or struct pointer holding the arguments; the struct's json,
+// description and enum tags shape the schema, as with vultrai.JSONSchemaOf.
+// fn may return a result, an error, or both. A string result is sent to
+// the model as is; other results are encoded as JSON.
+func Func(name string, fn interface{}, options ...Option) (agents.Tool, error) {
+	f, err := newFunction(name, fn)
+	if err != nil {
+		return agents.Tool{}, err
+	}
+	for _, option := range options {
+		option(f)
+	}
+	return agents.NewTool(f.name, f.description, f.schema(), f.call), nil
+}
+
+// Registry holds the tools the model may call
+type Registry struct {
+	mu    sync.RWMutex
+	tools map[string]agents.Tool
+	order []string
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{tools: make(map[string]agents.Tool)}
+}
+
+// Register adds fn as the tool name, as built by Func, replacing any tool
+// registered under that name
+func (r *Registry) Register(name string, fn interface{}, options ...Option) error {
+	tool, err := Func(name, fn, options...)
+	if err != nil {
+		return err
+	}
+	r.Add(tool)
+	return nil
+}
+
+// Add adds tools, such as ones made with agents.NewTool, replacing any
+// tools registered under the same names
+func (r *Registry) Add(tools ...agents.Tool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tool := range tools {
+		if _, ok := r.tools[tool.Name]; !ok {
+			r.order = append(r.order, tool.Name)
+		}
+		r.tools[tool.Name] = tool
+	}
+}
+
+func newFunction(name string, fn interface{}) (*function, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return nil, fmt.Errorf("error registering tool %q: %T is not a function", name, fn)
+	}
+	t := v.Type()
+	f := &function{name: name, fn: v}
+
+	in := 0
+	if in < t.NumIn() && t.In(in) == contextType {
+		f.hasContext = true
+		in++
+	}
+	if in < t.NumIn() {
+		args := t.In(in)
+		base := args
+		if base.Kind() == reflect.Ptr {
+			base = base.Elem()
+		}
+		if base.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("error registering tool %q: arguments must be a struct, not %s", name, args)
+		}
+		f.args = args
+		in++
+	}
+	if in != t.NumIn() || t.IsVariadic() {
+		return nil, fmt.Errorf("error registering tool %q: unsupported parameters in %s", name, t)
+	}
+
+	switch {
+	case t.NumOut() == 0:
+	case t.NumOut() == 1 && t.Out(0) == errorType:
+		f.hasError = true
+	case t.NumOut() == 1:
+		f.hasResult = true
+	case t.NumOut() == 2 && t.Out(1) == errorType:
+		f.hasResult, f.hasError = true, true
+	default:
+		return nil, fmt.Errorf("error registering tool %q: unsupported results in %s", name, t)
+	}
+	return f, nil
+}
+
+// Tools returns the registered tools in registration order
+func (r *Registry) Tools() []agents.Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tools := make([]agents.Tool, 0, len(r.order))
+	for _, name := range r.order {
+		tools = append(tools, r.tools[name])
+	}
+	return tools
+}
+
+// Definitions returns the declarations of the registered tools sent to the
+// model, in registration order
+func (r *Registry) Definitions() []vultrai.Tool {
+	tools := r.Tools()
+	defs := make([]vultrai.Tool, 0, len(tools))
+	for _, tool := range tools {
+		defs = append(defs, tool.Definition())
+	}
+	return defs
+}
+
+// schema returns the JSON Schema of the function's arguments
+func (f *function) schema() map[string]interface{} {
+	if f.args == nil {
+		return map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+	return vultrai.JSONSchemaOf(reflect.Zero(f.args).Interface())
+}
+
+// ChatOption declares the registered tools on a request
+func (r *Registry) ChatOption() vultrai.ChatOption {
+	return vultrai.WithTools(r.Definitions()...)
+}
+
+// Call runs the tool named by call with its arguments and returns the
+// result for the model
+func (r *Registry) Call(ctx context.Context, call vultrai.ToolCall) (string, error) {
+	r.mu.RLock()
+	tool, ok := r.tools[call.Function.Name]
+	r.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown tool %q", call.Function.Name)
+	}
+	return tool.Handler(ctx, call.Function.Arguments)
+}
+
+// Dispatch runs each tool call and returns the tool messages answering
+// them. Failures are reported to the model in the message rather than
+// returned, so it can correct its arguments.
+func (r *Registry) Dispatch(ctx context.Context, calls []vultrai.ToolCall) []vultrai.Message {
+	messages := make([]vultrai.Message, 0, len(calls))
+	for _, call := range calls {
+		output, err := r.Call(ctx, call)
+		if err != nil {
+			output = "error: " + err.Error()
+		}
+		messages = append(messages, vultrai.CreateToolMessage(call.ID, output))
+	}
+	return messages
+}
+
+// call is the agents.ToolHandler of the function
+func (f *function) call(ctx context.Context, arguments string) (string, error) {
+	var in []reflect.Value
+	if f.hasContext {
+		in = append(in, reflect.ValueOf(&ctx).Elem())
+	}
+	if f.args != nil {
+		if arguments == "" {
+			arguments = "{}"
+		}
+		args := reflect.New(f.args)
+		if err := json.Unmarshal([]byte(arguments), args.Interface()); err != nil {
+			return "", fmt.Errorf("error decoding arguments of %s: %w", f.name, err)
+		}
+		in = append(in, args.Elem())
+	}
+
+	out := f.fn.Call(in)
+	if f.hasError {
+		if err, _ := out[len(out)-1].Interface().(error); err != nil {
+			return "", err
+		}
+	}
+	if !f.hasResult {
+		return "", nil
+	}
+
+	result := out[0].Interface()
+	if s, ok := result.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("error encoding result of %s: %w", f.name, err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/eqba1/vultrai/agents"
+	"github.com/eqba1/vultrai/vultraitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type weatherArgs struct {
+	City string `json:"city" description:"City name"`
+	Unit string `json:"unit,omitempty" enum:"celsius,fahrenheit"`
+}
+
+type weather struct {
+	City        string  `json:"city"`
+	Temperature float64 `json:"temperature"`
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register("get_weather", func(ctx context.Context, args weatherArgs) (weather, error) {
+		if args.City == "" {
+			return weather{}, errors.New("city is required")
+		}
+		return weather{City: args.City, Temperature: 21.5}, nil
+	}, WithDescription("Get the weather for a city")))
+	require.NoError(t, registry.Register("get_time", func() string { return "noon" }))
+	require.NoError(t, registry.Register("reset", func(args *struct {
+		Force bool `json:"force"`
+	}) error {
+		return nil
+	}))
+
+	defs := registry.Definitions()
+	require.Len(t, defs, 3)
+	assert.Equal(t, "get_weather", defs[0].Function.Name)
+	assert.Equal(t, "Get the weather for a city", defs[0].Function.Description)
+	assert.Equal(t, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"city": map[string]interface{}{"type": "string", "description": "City name"},
+			"unit": map[string]interface{}{"type": "string", "enum": []interface{}{"celsius", "fahrenheit"}},
+		},
+		"required": []string{"city"},
+	}, defs[0].Function.Parameters)
+	assert.Equal(t, map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}, defs[1].Function.Parameters)
+
+	var req vultrai.ChatCompletionRequest
+	registry.ChatOption()(&req)
+	assert.Len(t, req.Tools, 3)
+
+	call := func(id, name, arguments string) vultrai.ToolCall {
+		return vultrai.ToolCall{ID: id, Type: "function", Function: vultrai.Function{Name: name, Arguments: arguments}}
+	}
+	messages := registry.Dispatch(context.Background(), []vultrai.ToolCall{
+		call("1", "get_weather", `{"city":"Paris"}`),
+		call("2", "get_time", ""),
+		call("3", "get_weather", `{}`),
+		call("4", "get_weather", `{"city":1}`),
+		call("5", "missing", `{}`),
+		call("6", "reset", ""),
+	})
+	require.Len(t, messages, 6)
+	assert.Equal(t, vultrai.CreateToolMessage("1", `{"city":"Paris","temperature":21.5}`), messages[0])
+	assert.Equal(t, "noon", messages[1].Content)
+	assert.Equal(t, "error: city is required", messages[2].Content)
+	assert.Contains(t, messages[3].Content, "error: error decoding arguments of get_weather")
+	assert.Equal(t, `error: unknown tool "missing"`, messages[4].Content)
+	assert.Equal(t, "", messages[5].Content)
+}
+
+func TestRegisterInvalid(t *testing.T) {
+	registry := NewRegistry()
+	assert.Error(t, registry.Register("a", "not a function"))
+	assert.Error(t, registry.Register("b", func(city string) string { return city }))
+	assert.Error(t, registry.Register("c", func(weatherArgs, weatherArgs) {}))
+	assert.Error(t, registry.Register("d", func() (string, string) { return "", "" }))
+	assert.Empty(t, registry.Tools())
+}
+
+func TestRegistryAgentTools(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register("get_weather", func(args weatherArgs) weather {
+		return weather{City: args.City, Temperature: 18}
+	}))
+	registry.Add(agents.NewTool("echo", "Echo the arguments", nil, func(ctx context.Context, arguments string) (string, error) {
+		return arguments, nil
+	}))
+
+	// Registered functions are agent tools, and agent tools can be registered
+	tools := registry.Tools()
+	require.Len(t, tools, 2)
+	out, err := tools[0].Handler(context.Background(), `{"city":"Oslo"}`)
+	require.NoError(t, err)
+	assert.Equal(t, `{"city":"Oslo","temperature":18}`, out)
+	assert.Equal(t, tools[1].Definition(), registry.Definitions()[1])
+
+	messages := registry.Dispatch(context.Background(), []vultrai.ToolCall{
+		{ID: "1", Type: "function", Function: vultrai.Function{Name: "echo", Arguments: `{"x":1}`}},
+	})
+	assert.Equal(t, `{"x":1}`, messages[0].Content)
+
+	tool, err := Func("get_time", func() string { return "noon" }, WithDescription("Current time"))
+	require.NoError(t, err)
+	assert.Equal(t, "Current time", tool.Definition().Function.Description)
+
+	// An agent runs the registered functions
+	mock := &vultraitest.MockClient{
+		CreateChatCompletionFunc: func(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.ChatCompletionResponse, error) {
+			last := req.Messages[len(req.Messages)-1]
+			if last.Role == "tool" {
+				return vultraitest.ChatResponse("It is " + last.Content), nil
+			}
+			return vultraitest.ToolCallResponse(vultraitest.ToolCall{Name: "get_time"}), nil
+		},
+	}
+	registry.Add(tool)
+	agent := agents.New(mock, "model", agents.WithTools(registry.Tools()...))
+	result, err := agent.Run(context.Background(), "What time is it?")
+	require.NoError(t, err)
+	assert.Equal(t, "It is noon", result.Output)
+}
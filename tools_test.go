@@ -0,0 +1,62 @@
+package vultrai
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolChoiceJSON(t *testing.T) {
+	tests := []struct {
+		choice ToolChoice
+		json   string
+	}{
+		{ToolChoice{Mode: ToolChoiceAuto}, `"auto"`},
+		{ToolChoice{Mode: ToolChoiceRequired}, `"required"`},
+		{ToolChoice{Function: "get_weather"}, `{"type":"function","function":{"name":"get_weather"}}`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.choice)
+		require.NoError(t, err)
+		assert.JSONEq(t, tt.json, string(data))
+
+		var decoded ToolChoice
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, tt.choice, decoded)
+	}
+	assert.Error(t, json.Unmarshal([]byte(`42`), &ToolChoice{}))
+}
+
+func TestChatWithTools(t *testing.T) {
+	client, transport := setupSequenceClient("done")
+	weather := NewFunctionTool("get_weather", "Current weather for a city", map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"city": map[string]interface{}{"type": "string"}},
+	})
+
+	messages := []Message{
+		CreateUserMessage("Weather in Paris?"),
+		{Role: "assistant", ToolCalls: []ToolCall{{ID: "call_1", Type: "function", Function: Function{Name: "get_weather", Arguments: `{"city":"Paris"}`}}}},
+		CreateToolMessage("call_1", "18°C, sunny"),
+	}
+	_, err := client.ChatWithMessages(context.Background(), "m", messages, WithTools(weather), WithForcedTool("get_weather"))
+	require.NoError(t, err)
+
+	var sent map[string]interface{}
+	require.NoError(t, json.NewDecoder(transport.requests[0].Body).Decode(&sent))
+	tools := sent["tools"].([]interface{})
+	require.Len(t, tools, 1)
+	assert.Equal(t, "get_weather", tools[0].(map[string]interface{})["function"].(map[string]interface{})["name"])
+	assert.Equal(t, map[string]interface{}{"type": "function", "function": map[string]interface{}{"name": "get_weather"}}, sent["tool_choice"])
+
+	tool := sent["messages"].([]interface{})[2].(map[string]interface{})
+	assert.Equal(t, "tool", tool["role"])
+	assert.Equal(t, "call_1", tool["tool_call_id"])
+
+	req := ChatCompletionRequest{}
+	WithToolChoice(ToolChoiceNone)(&req)
+	assert.Equal(t, &ToolChoice{Mode: ToolChoiceNone}, req.ToolChoice)
+}
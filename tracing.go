@@ -0,0 +1,50 @@
+package vultrai
+
+import "context"
+
+// Span attribute keys, following the OpenTelemetry semantic conventions
+// where one exists
+const (
+	AttrEndpoint          = "vultrai.endpoint"
+	AttrMethod            = "http.request.method"
+	AttrStatusCode        = "http.response.status_code"
+	AttrModel             = "gen_ai.request.model"
+	AttrInputTokens       = "gen_ai.usage.input_tokens"
+	AttrOutputTokens      = "gen_ai.usage.output_tokens"
+	AttrStreaming         = "vultrai.streaming"
+	AttrFirstTokenLatency = "vultrai.first_token_latency_ms"
+)
+
+// Attribute is a key-value pair recorded on a span. Values are strings,
+// ints, int64s or bools.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Span is a traced API request
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans. Package vultraiotel implements it on an
+// OpenTelemetry TracerProvider, so the core package doesn't depend on the
+// OpenTelemetry modules.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// WithTracer emits a span for every API request, named after the method
+// and endpoint, e.g. "POST /chat/completions", with IDs in the path
+// replaced by "{id}". Spans carry the model, status code and token usage.
+// Streams end their span when the body is closed and record the time to
+// the first chunk carrying content or tool calls as the first token
+// latency. vultraiotel.WithTracerProvider sets a Tracer exporting to
+// OpenTelemetry.
+func WithTracer(tracer Tracer) ClientOption {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
@@ -0,0 +1,131 @@
+package vultrai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedSpan struct {
+	name  string
+	attrs map[string]interface{}
+	err   error
+	ended bool
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) { s.err = err }
+
+func (s *recordedSpan) End() { s.ended = true }
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &recordedSpan{name: name, attrs: map[string]interface{}{}}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func TestTracer(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	tracer := &recordingTracer{}
+	WithTracer(tracer)(client)
+
+	transport.SetResponse("POST", "/chat/completions", 200, ChatCompletionResponse{
+		Usage: Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	})
+	transport.SetResponse("GET", "/vector-stores/collections/col-1/items/item-2", 404, Error{Message: "missing"})
+
+	_, err := client.CreateChatCompletion(context.Background(), ChatCompletionRequest{Model: "llama"})
+	require.NoError(t, err)
+	_, err = client.GetItem(context.Background(), "col-1", "item-2")
+	require.Error(t, err)
+
+	require.Len(t, tracer.spans, 2)
+	chat := tracer.spans[0]
+	assert.Equal(t, "POST /chat/completions", chat.name)
+	assert.True(t, chat.ended)
+	assert.NoError(t, chat.err)
+	assert.Equal(t, "llama", chat.attrs[AttrModel])
+	assert.Equal(t, 200, chat.attrs[AttrStatusCode])
+	assert.Equal(t, 10, chat.attrs[AttrInputTokens])
+	assert.Equal(t, 5, chat.attrs[AttrOutputTokens])
+	assert.Equal(t, false, chat.attrs[AttrStreaming])
+	assert.NotContains(t, chat.attrs, AttrFirstTokenLatency)
+
+	item := tracer.spans[1]
+	assert.Equal(t, "GET /vector-stores/collections/{id}/items/{id}", item.name)
+	assert.Equal(t, 404, item.attrs[AttrStatusCode])
+	assert.True(t, item.ended)
+	assert.True(t, IsNotFound(item.err))
+}
+
+func TestTracerStream(t *testing.T) {
+	server := pacedServer(t, 20*time.Millisecond, time.Millisecond)
+	tracer := &recordingTracer{}
+	client := NewClient("key", WithBaseURL(server.URL), WithTracer(tracer))
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), ChatCompletionRequest{Model: "llama"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	require.Len(t, tracer.spans, 1)
+	span := tracer.spans[0]
+	assert.False(t, span.ended)
+	assert.Equal(t, true, span.attrs[AttrStreaming])
+	assert.GreaterOrEqual(t, span.attrs[AttrFirstTokenLatency], int64(20))
+
+	require.NoError(t, stream.Close())
+	assert.True(t, span.ended)
+}
+
+func TestTracerStreamFirstToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"role\":\"assistant\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		time.Sleep(30 * time.Millisecond)
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\ndata: [DONE]\n\n")
+	}))
+	defer server.Close()
+	tracer := &recordingTracer{}
+	client := NewClient("key", WithBaseURL(server.URL), WithTracer(tracer))
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), ChatCompletionRequest{Model: "llama"})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	// A chunk with only the role is not a token
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	require.Len(t, tracer.spans, 1)
+	assert.NotContains(t, tracer.spans[0].attrs, AttrFirstTokenLatency)
+
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, tracer.spans[0].attrs[AttrFirstTokenLatency], int64(30))
+}
+
+func TestEndpointRoute(t *testing.T) {
+	assert.Equal(t, "/chat/completions", endpointRoute("/chat/completions"))
+	assert.Equal(t, "/vector-stores/collections/{id}/files/{id}", endpointRoute("/vector-stores/collections/a/files/b"))
+	assert.Equal(t, "/batches/{id}/results", endpointRoute("/batches/b1/results"))
+}
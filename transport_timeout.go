@@ -0,0 +1,56 @@
+package vultrai
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// WithConnectTimeout bounds establishing a connection, including the TLS
+// handshake. It configures a copy of the HTTP client's transport, so it has
+// no effect when WithHTTPClient sets a transport other than *http.Transport.
+func WithConnectTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.connectTimeout = timeout
+	}
+}
+
+// WithResponseHeaderTimeout bounds the wait for the response headers once
+// the request is sent. Unlike the overall Timeout it doesn't limit reading
+// the body, so it suits streams. Like WithConnectTimeout, it needs an
+// *http.Transport.
+func WithResponseHeaderTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.responseHeaderTimeout = timeout
+	}
+}
+
+// applyTransportTimeouts replaces the HTTP client with a copy whose
+// transport has the connect and response header timeouts
+func (c *Client) applyTransportTimeouts() {
+	if c.connectTimeout <= 0 && c.responseHeaderTimeout <= 0 {
+		return
+	}
+	base := c.httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return
+	}
+
+	transport = transport.Clone()
+	if c.connectTimeout > 0 {
+		dialer := &net.Dialer{Timeout: c.connectTimeout, KeepAlive: 30 * time.Second}
+		transport.DialContext = dialer.DialContext
+		transport.TLSHandshakeTimeout = c.connectTimeout
+	}
+	if c.responseHeaderTimeout > 0 {
+		transport.ResponseHeaderTimeout = c.responseHeaderTimeout
+	}
+
+	client := *c.httpClient
+	client.Transport = transport
+	c.httpClient = &client
+}
@@ -1,14 +1,26 @@
 package vultrai
 
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
 // Message represents a chat message in the conversation
 type Message struct {
-	Role      string     `json:"role"` // "system", "user", or "assistant"
-	Content   string     `json:"content"`
-	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	Role       string     `json:"role"` // "system", "user", "assistant", or "tool"
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"` // set on "tool" messages
+	// Parts replaces Content with text and images for vision models
+	Parts []ContentPart `json:"-"`
 }
 
 // ToolCall represents a function call in the message
 type ToolCall struct {
+	// Index identifies the call a streamed delta belongs to; it is nil
+	// outside of streams
+	Index    *int     `json:"index,omitempty"`
 	ID       string   `json:"id"`
 	Type     string   `json:"type"`
 	Function Function `json:"function"`
@@ -20,21 +32,44 @@ type Function struct {
 	Arguments string `json:"arguments"`
 }
 
+// Tool represents a tool the model may call
+type Tool struct {
+	Type     string       `json:"type"` // "function"
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction describes a function the model may call
+type ToolFunction struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Parameters  interface{} `json:"parameters,omitempty"` // JSON Schema object
+}
+
 // ChatCompletionRequest represents the request for chat completion
 type ChatCompletionRequest struct {
-	Model            string    `json:"model"`
-	Messages         []Message `json:"messages"`
-	Stream           *bool     `json:"stream,omitempty"`
-	MaxTokens        *int      `json:"max_tokens,omitempty"`
-	N                *int      `json:"n,omitempty"`
-	Seed             *int      `json:"seed,omitempty"`
-	Temperature      *float64  `json:"temperature,omitempty"`
-	TopP             *float64  `json:"top_p,omitempty"`
-	FrequencyPenalty *float64  `json:"frequency_penalty,omitempty"`
-	PresencePenalty  *float64  `json:"presence_penalty,omitempty"`
-	Stop             []string  `json:"stop,omitempty"`
-	LogProbs         *bool     `json:"logprobs,omitempty"`
-	TopLogProbs      *int      `json:"top_logprobs,omitempty"`
+	Model            string          `json:"model"`
+	Messages         []Message       `json:"messages"`
+	Stream           *bool           `json:"stream,omitempty"`
+	StreamOptions    *StreamOptions  `json:"stream_options,omitempty"`
+	MaxTokens        *int            `json:"max_tokens,omitempty"`
+	N                *int            `json:"n,omitempty"`
+	Seed             *int            `json:"seed,omitempty"`
+	Temperature      *float64        `json:"temperature,omitempty"`
+	TopP             *float64        `json:"top_p,omitempty"`
+	FrequencyPenalty *float64        `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64        `json:"presence_penalty,omitempty"`
+	Stop             []string        `json:"stop,omitempty"`
+	LogProbs         *bool           `json:"logprobs,omitempty"`
+	TopLogProbs      *int            `json:"top_logprobs,omitempty"`
+	Tools            []Tool          `json:"tools,omitempty"`
+	ToolChoice       *ToolChoice     `json:"tool_choice,omitempty"`
+	ResponseFormat   *ResponseFormat `json:"response_format,omitempty"`
+}
+
+// StreamOptions configures a streaming chat completion
+type StreamOptions struct {
+	// IncludeUsage adds a final chunk with the token usage and no choices
+	IncludeUsage bool `json:"include_usage"`
 }
 
 // RAGChatCompletionRequest represents the request for RAG chat completion
@@ -97,6 +132,51 @@ type ChatCompletionResponse struct {
 	Model   string   `json:"model"`
 	Choices []Choice `json:"choices"`
 	Usage   Usage    `json:"usage"`
+	// SystemFingerprint identifies the backend configuration that
+	// produced the response
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+
+	// Meta records how the client produced the response; it is not part of
+	// the API payload
+	Meta *ResponseMeta `json:"-"`
+}
+
+// ResponseMeta describes how the client produced a chat completion and
+// what the API reported alongside it
+type ResponseMeta struct {
+	// Model is the model whose answer was returned
+	Model string
+	// Fallbacks lists the attempts that failed before Model answered
+	Fallbacks []FallbackAttempt
+	// RateLimit is the rate limit state reported with the response
+	RateLimit *RateLimitInfo
+	// CacheScore is the similarity of the prompt whose answer was returned
+	// by a SemanticCache, zero when the model answered
+	CacheScore float64
+}
+
+// EmbeddingRequest represents the request for embeddings
+type EmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+	// EncodingFormat is "float" (default) or "base64", which is smaller on
+	// the wire; both are decoded into Embedding.Embedding
+	EncodingFormat string `json:"encoding_format,omitempty"`
+	// Dimensions truncates the vectors for models that support it
+	Dimensions int `json:"dimensions,omitempty"`
+}
+
+// Embedding is the vector for one input
+type Embedding struct {
+	Index     int       `json:"index"`
+	Embedding []float32 `json:"embedding"`
+}
+
+// EmbeddingResponse represents the response from the embeddings endpoint
+type EmbeddingResponse struct {
+	Model string      `json:"model"`
+	Data  []Embedding `json:"data"`
+	Usage Usage       `json:"usage"`
 }
 
 // TTSRequest represents the request for text-to-speech
@@ -104,6 +184,43 @@ type TTSRequest struct {
 	Model string `json:"model"`
 	Input string `json:"input"`
 	Voice string `json:"voice"`
+	// ResponseFormat is the audio format, e.g. "mp3" or "wav"; the
+	// audio package converts WAV for telephony
+	ResponseFormat string `json:"response_format,omitempty"`
+	// Speed scales the speaking rate, 1.0 being normal
+	Speed *float64 `json:"speed,omitempty"`
+	// Language is an optional ISO-639-1 code for multilingual models
+	Language string `json:"language,omitempty"`
+}
+
+// TranscriptionRequest represents the request for speech-to-text
+type TranscriptionRequest struct {
+	Model    string
+	Audio    io.Reader
+	Filename string // name of the audio file, used to detect its format (default "audio.wav")
+	Language string // optional ISO-639-1 code of the spoken language
+	// ResponseFormat is "json" (default), "verbose_json" for segments, or
+	// "text", "srt" or "vtt", which are returned in Text as-is
+	ResponseFormat string
+	Prompt         string   // optional text guiding style or vocabulary
+	Temperature    *float64 // optional sampling temperature
+}
+
+// TranscriptionSegment is a timed part of a transcript
+type TranscriptionSegment struct {
+	ID    int     `json:"id"`
+	Start float64 `json:"start"` // seconds
+	End   float64 `json:"end"`   // seconds
+	Text  string  `json:"text"`
+}
+
+// TranscriptionResponse represents the response from speech-to-text
+type TranscriptionResponse struct {
+	Text     string  `json:"text"`
+	Language string  `json:"language,omitempty"`
+	Duration float64 `json:"duration,omitempty"` // seconds
+	// Segments are returned with the verbose_json response format
+	Segments []TranscriptionSegment `json:"segments,omitempty"`
 }
 
 // VectorStoreCollection represents a vector store collection
@@ -113,6 +230,12 @@ type VectorStoreCollection struct {
 	Created string `json:"created"`
 }
 
+// ListCollectionsResponse represents the response from listing collections
+type ListCollectionsResponse struct {
+	Collections []VectorStoreCollection `json:"collections"`
+	Meta        *ListMeta               `json:"meta,omitempty"`
+}
+
 // CreateCollectionRequest represents the request to create a collection
 type CreateCollectionRequest struct {
 	Name string `json:"name"`
@@ -135,14 +258,20 @@ type UpdateCollectionResponse struct {
 
 // SearchRequest represents the request to search in a collection
 type SearchRequest struct {
-	Input string `json:"input"`
+	Input          string            `json:"input"`
+	TopK           int               `json:"top_k,omitempty"`           // maximum number of results
+	ScoreThreshold float64           `json:"score_threshold,omitempty"` // minimum similarity of a result
+	Filter         map[string]string `json:"filter,omitempty"`          // metadata values results must have
 }
 
 // SearchResult represents a search result
 type SearchResult struct {
-	ID      string `json:"id"`
-	Created string `json:"created"`
-	Content string `json:"content"`
+	ID      string  `json:"id"`
+	Created string  `json:"created"`
+	Content string  `json:"content"`
+	Score   float64 `json:"score,omitempty"`   // similarity, when the searcher reports one
+	ItemID  string  `json:"item_id,omitempty"` // collection item the content comes from
+	FileID  string  `json:"file_id,omitempty"` // uploaded file the item was created from
 }
 
 // SearchResponse represents the response from search
@@ -162,6 +291,7 @@ type CollectionItem struct {
 // ListItemsResponse represents the response from listing items
 type ListItemsResponse struct {
 	Items []CollectionItem `json:"items"`
+	Meta  *ListMeta        `json:"meta,omitempty"`
 }
 
 // AddItemRequest represents the request to add an item to collection
@@ -205,6 +335,7 @@ type CollectionFile struct {
 // ListFilesResponse represents the response from listing files
 type ListFilesResponse struct {
 	Files []CollectionFile `json:"files"`
+	Meta  *ListMeta        `json:"meta,omitempty"`
 }
 
 // AddFileResponse represents the response from adding a file
@@ -217,6 +348,44 @@ type GetFileResponse struct {
 	File CollectionFile `json:"file"`
 }
 
+// BatchRequestCounts holds the progress of a batch
+type BatchRequestCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// Batch represents an asynchronous batch job
+type Batch struct {
+	ID               string             `json:"id"`
+	Endpoint         string             `json:"endpoint"`
+	Status           string             `json:"status"` // "validating", "in_progress", "finalizing", "completed", "failed", "expired", "cancelled"
+	CompletionWindow string             `json:"completion_window"`
+	Created          string             `json:"created"`
+	Completed        string             `json:"completed,omitempty"`
+	RequestCounts    BatchRequestCounts `json:"request_counts"`
+	Errors           []Error            `json:"errors,omitempty"`
+}
+
+// CreateBatchRequest represents the request to create a batch. Input is a
+// JSONL file of requests, as built by BuildBatchJSONL.
+type CreateBatchRequest struct {
+	Input            io.Reader
+	Filename         string // default "batch.jsonl"
+	Endpoint         string // default "/v1/chat/completions"
+	CompletionWindow string // default "24h"
+}
+
+// BatchResponse represents the response from creating or getting a batch
+type BatchResponse struct {
+	Batch Batch `json:"batch"`
+}
+
+// ListBatchesResponse represents the response from listing batches
+type ListBatchesResponse struct {
+	Batches []Batch `json:"batches"`
+}
+
 // ImageGenerationRequest represents the request for image generation
 type ImageGenerationRequest struct {
 	Prompt         string `json:"prompt"`
@@ -255,13 +424,16 @@ type UsageResponse struct {
 
 // RequestLog represents a logged API request
 type RequestLog struct {
-	Timestamp      string `json:"timestamp"`
-	Method         string `json:"method"`
-	Endpoint       string `json:"endpoint"`
-	RequestHeaders string `json:"request_headers"`
-	RequestBody    string `json:"request_body"`
-	ResponseBody   string `json:"response_body"`
-	ResponseCode   int    `json:"response_code"`
+	Timestamp      time.Time   `json:"timestamp"`
+	Method         string      `json:"method"`
+	Endpoint       string      `json:"endpoint"`
+	RequestHeaders http.Header `json:"request_headers"`
+	RequestBody    LogBody     `json:"request_body"`
+	ResponseBody   LogBody     `json:"response_body"`
+	ResponseCode   int         `json:"response_code"`
+	// RawTimestamp holds the timestamp as sent when it couldn't be parsed,
+	// in which case Timestamp is zero
+	RawTimestamp string `json:"-"`
 }
 
 // RequestLogsRequest represents the request for request logs
@@ -274,6 +446,7 @@ type RequestLogsRequest struct {
 // RequestLogsResponse represents the response from request logs
 type RequestLogsResponse struct {
 	Requests []RequestLog `json:"requests"`
+	Meta     *ListMeta    `json:"meta,omitempty"`
 }
 
 // Error represents an API error response
@@ -0,0 +1,38 @@
+package vultrai
+
+import "net/http"
+
+// UsageCallback receives the token usage of a successful request. endpoint
+// is the API path, e.g. "/chat/completions"; model is empty for vector
+// store operations.
+type UsageCallback func(endpoint, model string, u Usage)
+
+// WithUsageCallback calls callback after every completion, embedding,
+// vector store search and item insertion that consumed tokens, so usage
+// can be pushed to a billing system without wrapping each call. Streamed
+// completions are reported when WithStreamIncludeUsage is set. Cached
+// answers don't consume tokens and are not reported. The callback runs on
+// the calling goroutine and should return quickly. Several callbacks can
+// be set.
+func WithUsageCallback(callback UsageCallback) ClientOption {
+	return func(c *Client) {
+		c.usageCallbacks = append(c.usageCallbacks, callback)
+	}
+}
+
+// reportUsage passes u to the usage callbacks and the observation of resp
+func (c *Client) reportUsage(resp *http.Response, endpoint, model string, u Usage) {
+	observationOf(resp).setUsage(u)
+	for _, callback := range c.usageCallbacks {
+		callback(endpoint, model, u)
+	}
+}
+
+// modelOf returns the model reported by the API, or the requested one when
+// the response has none
+func modelOf(reported, requested string) string {
+	if reported != "" {
+		return reported
+	}
+	return requested
+}
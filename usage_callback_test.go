@@ -0,0 +1,61 @@
+package vultrai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type usageRecord struct {
+	endpoint, model string
+	usage           Usage
+}
+
+func TestUsageCallback(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	var records []usageRecord
+	WithUsageCallback(func(endpoint, model string, u Usage) {
+		records = append(records, usageRecord{endpoint, model, u})
+	})(client)
+
+	transport.SetResponse("POST", "/chat/completions", 200, ChatCompletionResponse{
+		Model: "llama-3.1-70b", Usage: Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	})
+	transport.SetResponse("POST", "/embeddings", 200, EmbeddingResponse{Usage: Usage{PromptTokens: 3, TotalTokens: 3}})
+	transport.SetResponse("POST", "/vector-stores/collections/col/search", 200, SearchResponse{Usage: Usage{PromptTokens: 4, TotalTokens: 4}})
+	transport.SetResponse("POST", "/chat/completions/rag", 400, Error{Message: "invalid"})
+
+	_, err := client.CreateChatCompletion(context.Background(), ChatCompletionRequest{Model: "alias"})
+	require.NoError(t, err)
+	_, err = client.CreateEmbeddings(context.Background(), EmbeddingRequest{Model: "embed", Input: []string{"hi"}})
+	require.NoError(t, err)
+	_, err = client.SearchCollection(context.Background(), "col", SearchRequest{Input: "q"})
+	require.NoError(t, err)
+	_, err = client.CreateRAGChatCompletion(context.Background(), RAGChatCompletionRequest{Model: "m"})
+	require.Error(t, err)
+	_, err = client.GetUsage(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []usageRecord{
+		{"/chat/completions", "llama-3.1-70b", Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}},
+		{"/embeddings", "embed", Usage{PromptTokens: 3, TotalTokens: 3}},
+		{"/vector-stores/collections/col/search", "", Usage{PromptTokens: 4, TotalTokens: 4}},
+	}, records)
+}
+
+func TestUsageCallbackSkipsCache(t *testing.T) {
+	client, _ := setupTestClient()
+	calls := 0
+	WithUsageCallback(func(string, string, Usage) { calls++ })(client)
+	WithUsageCallback(func(string, string, Usage) { calls++ })(client)
+	WithCache(NewMemoryCache(10), 0)(client)
+
+	req := ChatCompletionRequest{Model: "m", Temperature: new(float64)}
+	for i := 0; i < 3; i++ {
+		_, err := client.CreateChatCompletion(context.Background(), req)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,67 @@
+package vultrai
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// UsagePeriod is the usage of one calendar month
+type UsagePeriod struct {
+	// Month is the first day of the month, in UTC
+	Month time.Time
+	Usage MonthlyUsage
+}
+
+// Total returns the usage summed over all services
+func (u MonthlyUsage) Total() float64 {
+	return u.Chat + u.TTS + u.TTSSM + u.Image + u.ImageSM
+}
+
+// GetUsageForMonth retrieves usage information with month reported as the
+// current month and the month before it as the previous month
+func (c *Client) GetUsageForMonth(ctx context.Context, month time.Time) (*UsageResponse, error) {
+	params := url.Values{}
+	params.Set("month", month.UTC().Format("2006-01"))
+
+	var usageResp UsageResponse
+	if err := c.getList(ctx, "/usage", params, ListOptions{}, &usageResp); err != nil {
+		return nil, err
+	}
+
+	return &usageResp, nil
+}
+
+// GetUsageHistory returns the monthly usage from the month of from to the
+// month of to, oldest first, for trend dashboards. Each request covers two
+// months, so a year of history takes six requests.
+func (c *Client) GetUsageHistory(ctx context.Context, from, to time.Time) ([]UsagePeriod, error) {
+	first, last := startOfMonth(from), startOfMonth(to)
+	if first.After(last) {
+		return nil, fmt.Errorf("invalid usage range: %s is after %s", first.Format("2006-01"), last.Format("2006-01"))
+	}
+
+	var periods []UsagePeriod
+	for month := first; !month.After(last); month = month.AddDate(0, 1, 0) {
+		periods = append(periods, UsagePeriod{Month: month})
+	}
+
+	for i := len(periods) - 1; i >= 0; i -= 2 {
+		resp, err := c.GetUsageForMonth(ctx, periods[i].Month)
+		if err != nil {
+			return nil, fmt.Errorf("error getting usage for %s: %w", periods[i].Month.Format("2006-01"), err)
+		}
+		periods[i].Usage = resp.CurrentMonth
+		if i > 0 {
+			periods[i-1].Usage = resp.PreviousMonth
+		}
+	}
+
+	return periods, nil
+}
+
+func startOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,48 @@
+package vultrai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUsageHistory(t *testing.T) {
+	var months []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		month := r.URL.Query().Get("month")
+		months = append(months, month)
+		var m int
+		fmt.Sscanf(month, "2024-%d", &m)
+		writeJSON(w, UsageResponse{
+			CurrentMonth:  MonthlyUsage{Chat: float64(m)},
+			PreviousMonth: MonthlyUsage{Chat: float64(m - 1)},
+		})
+	}))
+	defer server.Close()
+	client := NewClient("key", WithBaseURL(server.URL))
+
+	periods, err := client.GetUsageHistory(context.Background(),
+		time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 7, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2024-07", "2024-05", "2024-03"}, months)
+	require.Len(t, periods, 5)
+	for i, period := range periods {
+		assert.Equal(t, time.Date(2024, time.Month(3+i), 1, 0, 0, 0, 0, time.UTC), period.Month)
+		assert.Equal(t, float64(3+i), period.Usage.Chat)
+	}
+
+	_, err = client.GetUsageHistory(context.Background(), time.Now(), time.Now().AddDate(0, -1, 0))
+	assert.Error(t, err)
+}
+
+func TestMonthlyUsageTotal(t *testing.T) {
+	usage := MonthlyUsage{Chat: 1.5, TTS: 0.5, TTSSM: 0.25, Image: 2, ImageSM: 0.75}
+	assert.Equal(t, 5.0, usage.Total())
+}
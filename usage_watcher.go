@@ -0,0 +1,149 @@
+package vultrai
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Services whose spend a UsageWatcher can watch
+const (
+	UsageChat    = "chat"
+	UsageTTS     = "tts"
+	UsageTTSSM   = "tts_sm"
+	UsageImage   = "image"
+	UsageImageSM = "image_sm"
+	// UsageTotal is the spend summed over all services
+	UsageTotal = "total"
+)
+
+// DefaultUsagePollInterval is how often a UsageWatcher polls by default
+const DefaultUsagePollInterval = 5 * time.Minute
+
+// UsageAlert reports that the current month's spend on a service crossed a
+// threshold between two polls
+type UsageAlert struct {
+	Service   string
+	Threshold float64
+	Previous  float64
+	Current   float64
+}
+
+type usageThreshold struct {
+	service string
+	amount  float64
+	alert   func(UsageAlert)
+}
+
+// UsageWatcher polls GetUsage and calls back when the current month's
+// spend crosses configured thresholds, for teams without billing alerts of
+// their own
+type UsageWatcher struct {
+	client   *Client
+	interval time.Duration
+
+	// OnChange, when set, receives the previous and current snapshots
+	// whenever the usage changed
+	OnChange func(previous, current MonthlyUsage)
+	// OnError, when set, receives the errors of failed polls
+	OnError func(error)
+
+	mu         sync.Mutex
+	thresholds []usageThreshold
+	last       *MonthlyUsage
+}
+
+// NewUsageWatcher creates a watcher polling every interval, or every
+// DefaultUsagePollInterval when interval is zero
+func NewUsageWatcher(client *Client, interval time.Duration) *UsageWatcher {
+	if interval <= 0 {
+		interval = DefaultUsagePollInterval
+	}
+	return &UsageWatcher{client: client, interval: interval}
+}
+
+// AddThreshold calls alert when the spend on service, one of the Usage
+// constants, reaches amount. A threshold already reached at the first poll
+// alerts then; spend falling back, as at the start of a month, rearms it.
+func (w *UsageWatcher) AddThreshold(service string, amount float64, alert func(UsageAlert)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.thresholds = append(w.thresholds, usageThreshold{service: service, amount: amount, alert: alert})
+}
+
+// Run polls until ctx is done. It blocks, so run it in its own goroutine.
+func (w *UsageWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		err := w.Check(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil && w.OnError != nil {
+			w.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check polls the usage once, calling back for changes and crossed
+// thresholds
+func (w *UsageWatcher) Check(ctx context.Context) error {
+	resp, err := w.client.GetUsage(ctx)
+	if err != nil {
+		return err
+	}
+	current := resp.CurrentMonth
+
+	w.mu.Lock()
+	var previous MonthlyUsage
+	first := w.last == nil
+	if !first {
+		previous = *w.last
+	}
+	w.last = &current
+	var alerts []func()
+	for _, threshold := range w.thresholds {
+		before, now := usageOf(previous, threshold.service), usageOf(current, threshold.service)
+		if before < threshold.amount && now >= threshold.amount {
+			alert := UsageAlert{Service: threshold.service, Threshold: threshold.amount, Previous: before, Current: now}
+			fn := threshold.alert
+			alerts = append(alerts, func() { fn(alert) })
+		}
+	}
+	w.mu.Unlock()
+
+	if !first && previous != current && w.OnChange != nil {
+		w.OnChange(previous, current)
+	}
+	for _, alert := range alerts {
+		alert()
+	}
+	return nil
+}
+
+// usageOf returns the spend on service
+func usageOf(u MonthlyUsage, service string) float64 {
+	switch service {
+	case UsageChat:
+		return u.Chat
+	case UsageTTS:
+		return u.TTS
+	case UsageTTSSM:
+		return u.TTSSM
+	case UsageImage:
+		return u.Image
+	case UsageImageSM:
+		return u.ImageSM
+	case UsageTotal:
+		return u.Total()
+	}
+	return 0
+}
@@ -0,0 +1,49 @@
+package vultrai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageWatcher(t *testing.T) {
+	client, transport := setupLocalTestClient()
+	watcher := NewUsageWatcher(client, 0)
+
+	var alerts []UsageAlert
+	var changes [][2]MonthlyUsage
+	watcher.AddThreshold(UsageChat, 10, func(a UsageAlert) { alerts = append(alerts, a) })
+	watcher.AddThreshold(UsageTotal, 5, func(a UsageAlert) { alerts = append(alerts, a) })
+	watcher.OnChange = func(previous, current MonthlyUsage) {
+		changes = append(changes, [2]MonthlyUsage{previous, current})
+	}
+
+	poll := func(usage MonthlyUsage) {
+		transport.SetResponse("GET", "/usage", 200, UsageResponse{CurrentMonth: usage})
+		require.NoError(t, watcher.Check(context.Background()))
+	}
+
+	poll(MonthlyUsage{Chat: 2, TTS: 4})
+	assert.Equal(t, []UsageAlert{{Service: UsageTotal, Threshold: 5, Previous: 0, Current: 6}}, alerts)
+	assert.Empty(t, changes)
+
+	poll(MonthlyUsage{Chat: 2, TTS: 4})
+	assert.Empty(t, changes)
+
+	alerts = nil
+	poll(MonthlyUsage{Chat: 12, TTS: 4})
+	assert.Equal(t, []UsageAlert{{Service: UsageChat, Threshold: 10, Previous: 2, Current: 12}}, alerts)
+	assert.Equal(t, [][2]MonthlyUsage{{{Chat: 2, TTS: 4}, {Chat: 12, TTS: 4}}}, changes)
+
+	// A new month rearms the thresholds
+	alerts = nil
+	poll(MonthlyUsage{})
+	assert.Empty(t, alerts)
+	poll(MonthlyUsage{Chat: 11})
+	assert.Len(t, alerts, 2)
+
+	transport.SetResponse("GET", "/usage", 500, map[string]string{"message": "down"})
+	assert.Error(t, watcher.Check(context.Background()))
+}
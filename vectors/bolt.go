@@ -0,0 +1,187 @@
+package vectors
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	vultrai "github.com/eqba1/vultrai"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	documentsBucket = []byte("documents")
+	vectorsBucket   = []byte("vectors")
+)
+
+// storedDocument is the persisted form of a Document; the vector is kept in
+// its own bucket as little-endian float32s
+type storedDocument struct {
+	Content  string            `json:"content"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Created  time.Time         `json:"created"`
+}
+
+// BoltStore is a vector store persisted to a bbolt database file, for
+// offline caches and edge deployments. Documents are loaded into an
+// in-memory Index when the store is opened, so searches never touch the
+// disk; adds and deletes are written through before they become visible.
+type BoltStore struct {
+	db    *bolt.DB
+	index *Index
+}
+
+var _ Store = (*BoltStore)(nil)
+
+// OpenBoltStore opens or creates the store at path. The embedder and
+// options are used as for NewIndex.
+func OpenBoltStore(path string, embedder vultrai.Embedder, opts IndexOptions) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("error opening store: %w", err)
+	}
+
+	s := &BoltStore{db: db, index: NewIndex(embedder, opts)}
+	if err := s.load(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// load reads every document into the in-memory index
+func (s *BoltStore) load() error {
+	var docs []Document
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		documents, err := tx.CreateBucketIfNotExists(documentsBucket)
+		if err != nil {
+			return err
+		}
+		vecs, err := tx.CreateBucketIfNotExists(vectorsBucket)
+		if err != nil {
+			return err
+		}
+
+		return documents.ForEach(func(k, v []byte) error {
+			var stored storedDocument
+			if err := json.Unmarshal(v, &stored); err != nil {
+				return fmt.Errorf("error decoding document %s: %w", k, err)
+			}
+			docs = append(docs, Document{
+				ID:       string(k),
+				Content:  stored.Content,
+				Metadata: stored.Metadata,
+				Created:  stored.Created,
+				Vector:   decodeVector(vecs.Get(k)),
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("error loading store: %w", err)
+	}
+	return s.index.Add(context.Background(), docs...)
+}
+
+// Close closes the database file
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// Add embeds and stores docs, replacing documents with the same ID
+func (s *BoltStore) Add(ctx context.Context, docs ...Document) error {
+	if err := embedMissing(ctx, s.index.embedder, docs); err != nil {
+		return err
+	}
+	for i := range docs {
+		if docs[i].Created.IsZero() {
+			docs[i].Created = time.Now()
+		}
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		documents, vecs := tx.Bucket(documentsBucket), tx.Bucket(vectorsBucket)
+		for _, doc := range docs {
+			data, err := json.Marshal(storedDocument{Content: doc.Content, Metadata: doc.Metadata, Created: doc.Created})
+			if err != nil {
+				return err
+			}
+			if err := documents.Put([]byte(doc.ID), data); err != nil {
+				return err
+			}
+			if err := vecs.Put([]byte(doc.ID), encodeVector(doc.Vector)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error writing documents: %w", err)
+	}
+	return s.index.Add(ctx, docs...)
+}
+
+// Delete removes documents by ID; unknown IDs are ignored
+func (s *BoltStore) Delete(ids ...string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		documents, vecs := tx.Bucket(documentsBucket), tx.Bucket(vectorsBucket)
+		for _, id := range ids {
+			if err := documents.Delete([]byte(id)); err != nil {
+				return err
+			}
+			if err := vecs.Delete([]byte(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting documents: %w", err)
+	}
+	return s.index.Delete(ids...)
+}
+
+// Get returns the document with id
+func (s *BoltStore) Get(id string) (Document, bool) {
+	return s.index.Get(id)
+}
+
+// Len returns the number of documents
+func (s *BoltStore) Len() int {
+	return s.index.Len()
+}
+
+// Search embeds req.Input and returns the most similar documents
+func (s *BoltStore) Search(ctx context.Context, req vultrai.SearchRequest) (*vultrai.SearchResponse, error) {
+	return s.index.Search(ctx, req)
+}
+
+// SearchText embeds text and returns the k most similar documents passing
+// filter
+func (s *BoltStore) SearchText(ctx context.Context, text string, k int, filter Filter) ([]Match, error) {
+	return s.index.SearchText(ctx, text, k, filter)
+}
+
+// SearchVector returns the k documents nearest to query that pass filter
+func (s *BoltStore) SearchVector(query []float32, k int, filter Filter) []Match {
+	return s.index.SearchVector(query, k, filter)
+}
+
+func encodeVector(v []float32) []byte {
+	buf := make([]byte, 4*len(v))
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(x))
+	}
+	return buf
+}
+
+func decodeVector(buf []byte) []float32 {
+	v := make([]float32, len(buf)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return v
+}
@@ -0,0 +1,55 @@
+package vectors
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "index.db")
+
+	store, err := OpenBoltStore(path, keywordEmbedder, IndexOptions{Metric: Cosine})
+	require.NoError(t, err)
+	require.NoError(t, store.Add(ctx,
+		Document{ID: "cat-en", Content: "the cat sat", Metadata: map[string]string{"lang": "en"}},
+		Document{ID: "cat-fr", Content: "le cat", Metadata: map[string]string{"lang": "fr"}},
+		Document{ID: "car-en", Content: "a fast car", Metadata: map[string]string{"lang": "en"}},
+		Document{ID: "gone", Content: "dog"},
+	))
+	require.NoError(t, store.Delete("gone"))
+	require.NoError(t, store.Close())
+
+	store, err = OpenBoltStore(path, keywordEmbedder, IndexOptions{Metric: Cosine})
+	require.NoError(t, err)
+	defer store.Close()
+	assert.Equal(t, 3, store.Len())
+
+	doc, ok := store.Get("cat-fr")
+	require.True(t, ok)
+	assert.Equal(t, "le cat", doc.Content)
+	assert.Equal(t, []float32{1, 0, 0, 0}, doc.Vector)
+	assert.False(t, doc.Created.IsZero())
+
+	matches, err := store.SearchText(ctx, "cat", 5, Filter{"lang": "en"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cat-en", "car-en"}, matchIDs(matches))
+
+	var searcher vultrai.Searcher = store
+	resp, err := searcher.Search(ctx, vultrai.SearchRequest{Input: "car"})
+	require.NoError(t, err)
+	assert.Equal(t, "car-en", resp.Results[0].ID)
+}
+
+func TestFilter(t *testing.T) {
+	doc := Document{Metadata: map[string]string{"lang": "en", "source": "faq"}}
+	assert.True(t, Filter(nil).Match(doc))
+	assert.True(t, Filter{"lang": "en"}.Match(doc))
+	assert.False(t, Filter{"lang": "en", "source": "blog"}.Match(doc))
+	assert.False(t, Filter{"missing": ""}.Match(doc))
+}
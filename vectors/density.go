@@ -0,0 +1,320 @@
+package vectors
+
+import (
+	"math"
+	"sort"
+)
+
+// DensityOptions configures Density
+type DensityOptions struct {
+	// Metric is Euclidean by default
+	Metric Metric
+	// MinClusterSize is the smallest group reported as a cluster (default 5)
+	MinClusterSize int
+	// MinSamples sets how conservative the density estimate is; larger
+	// values label more points as noise (default MinClusterSize)
+	MinSamples int
+	// AllowSingleCluster lets the whole dataset be returned as one cluster
+	// when it has no denser subgroups
+	AllowSingleCluster bool
+}
+
+// Density clusters data by density in the manner of HDBSCAN, without
+// being told the number of clusters. Points in sparse regions are labeled
+// Noise. It computes all pairwise distances, so it suits up to a few
+// thousand points.
+func Density(data [][]float32, opts DensityOptions) (*Clustering, error) {
+	if err := checkDimensions(data); err != nil {
+		return nil, err
+	}
+	if opts.MinClusterSize < 2 {
+		opts.MinClusterSize = 5
+	}
+	if opts.MinSamples <= 0 {
+		opts.MinSamples = opts.MinClusterSize
+	}
+
+	n := len(data)
+	result := &Clustering{Labels: make([]int, n), Metric: opts.Metric}
+	for i := range result.Labels {
+		result.Labels[i] = Noise
+	}
+	if n < opts.MinClusterSize {
+		return result, nil
+	}
+
+	dist := make([][]float64, n)
+	for i := range dist {
+		dist[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			d := opts.Metric.Distance(data[i], data[j])
+			dist[i][j], dist[j][i] = d, d
+		}
+	}
+
+	tree := buildDendrogram(mutualReachabilityMST(dist, coreDistances(dist, opts.MinSamples)), n)
+	condensed := condense(tree, opts.MinClusterSize)
+	selected := condensed.selectClusters(opts.AllowSingleCluster)
+
+	// Number the selected clusters in order of creation
+	ids := make(map[int]int, len(selected))
+	for c := range condensed.clusters {
+		if selected[c] {
+			ids[c] = len(ids)
+		}
+	}
+	members := make([][][]float32, len(ids))
+	for p, c := range condensed.pointCluster {
+		for c >= 0 && !selected[c] {
+			c = condensed.clusters[c].parent
+		}
+		if c >= 0 {
+			result.Labels[p] = ids[c]
+			members[ids[c]] = append(members[ids[c]], data[p])
+		}
+	}
+	for _, m := range members {
+		result.Centroids = append(result.Centroids, Mean(m))
+	}
+	return result, nil
+}
+
+// coreDistances returns the distance from each point to its minSamples-th
+// nearest neighbor
+func coreDistances(dist [][]float64, minSamples int) []float64 {
+	n := len(dist)
+	k := minSamples
+	if k > n-1 {
+		k = n - 1
+	}
+	core := make([]float64, n)
+	row := make([]float64, 0, n)
+	for i := range dist {
+		row = row[:0]
+		for j, d := range dist[i] {
+			if j != i {
+				row = append(row, d)
+			}
+		}
+		sort.Float64s(row)
+		if k > 0 {
+			core[i] = row[k-1]
+		}
+	}
+	return core
+}
+
+// mstEdge joins two points at a mutual reachability distance
+type mstEdge struct {
+	a, b   int
+	weight float64
+}
+
+// mutualReachabilityMST builds a minimum spanning tree over the mutual
+// reachability distances with Prim's algorithm, returning its edges in
+// ascending order of weight
+func mutualReachabilityMST(dist [][]float64, core []float64) []mstEdge {
+	n := len(dist)
+	inTree := make([]bool, n)
+	best := make([]float64, n)
+	from := make([]int, n)
+	for i := range best {
+		best[i] = math.Inf(1)
+	}
+
+	edges := make([]mstEdge, 0, n-1)
+	current := 0
+	inTree[0] = true
+	for len(edges) < n-1 {
+		next := -1
+		for j := 0; j < n; j++ {
+			if inTree[j] {
+				continue
+			}
+			reach := math.Max(dist[current][j], math.Max(core[current], core[j]))
+			if reach < best[j] {
+				best[j], from[j] = reach, current
+			}
+			if next < 0 || best[j] < best[next] {
+				next = j
+			}
+		}
+		edges = append(edges, mstEdge{a: from[next], b: next, weight: best[next]})
+		inTree[next] = true
+		current = next
+	}
+
+	sort.SliceStable(edges, func(i, j int) bool { return edges[i].weight < edges[j].weight })
+	return edges
+}
+
+// dendrogram is a single-linkage hierarchy: nodes 0..n-1 are points and
+// node n+i is created by the i-th merge
+type dendrogram struct {
+	n        int
+	children [][2]int
+	distance []float64
+	size     []int
+}
+
+func buildDendrogram(edges []mstEdge, n int) *dendrogram {
+	t := &dendrogram{
+		n:        n,
+		children: make([][2]int, len(edges)),
+		distance: make([]float64, len(edges)),
+		size:     make([]int, n+len(edges)),
+	}
+	parent := make([]int, n)
+	node := make([]int, n) // dendrogram node of each union-find root
+	for i := range parent {
+		parent[i], node[i], t.size[i] = i, i, 1
+	}
+	var find func(int) int
+	find = func(x int) int {
+		for parent[x] != x {
+			parent[x] = parent[parent[x]]
+			x = parent[x]
+		}
+		return x
+	}
+
+	for i, e := range edges {
+		ra, rb := find(e.a), find(e.b)
+		id := n + i
+		t.children[i] = [2]int{node[ra], node[rb]}
+		t.distance[i] = e.weight
+		t.size[id] = t.size[node[ra]] + t.size[node[rb]]
+		parent[rb] = ra
+		node[ra] = id
+	}
+	return t
+}
+
+// leaves appends the points under node
+func (t *dendrogram) leaves(node int, out []int) []int {
+	stack := []int{node}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if top < t.n {
+			out = append(out, top)
+			continue
+		}
+		children := t.children[top-t.n]
+		stack = append(stack, children[0], children[1])
+	}
+	return out
+}
+
+// condensedCluster is a cluster of the condensed tree
+type condensedCluster struct {
+	parent    int
+	birth     float64 // lambda at which the cluster appeared
+	children  []int
+	stability float64
+}
+
+// condensedTree keeps only splits that produce two clusters of at least
+// the minimum size; smaller pieces are points falling out of their cluster
+type condensedTree struct {
+	clusters []condensedCluster
+	// pointCluster is the cluster each point was last part of
+	pointCluster []int
+}
+
+func lambda(distance float64) float64 {
+	if distance <= 0 {
+		return 1e12
+	}
+	return 1 / distance
+}
+
+func condense(t *dendrogram, minClusterSize int) *condensedTree {
+	ct := &condensedTree{
+		clusters:     []condensedCluster{{parent: -1}},
+		pointCluster: make([]int, t.n),
+	}
+
+	type frame struct{ node, cluster int }
+	root := t.n + len(t.children) - 1
+	stack := []frame{{root, 0}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		cl := &ct.clusters[f.cluster]
+
+		if f.node < t.n {
+			ct.pointCluster[f.node] = f.cluster
+			continue
+		}
+		children := t.children[f.node-t.n]
+		l := lambda(t.distance[f.node-t.n])
+		left, right := children[0], children[1]
+		bigLeft := t.size[left] >= minClusterSize
+		bigRight := t.size[right] >= minClusterSize
+
+		switch {
+		case bigLeft && bigRight:
+			cl.stability += float64(t.size[f.node]) * (l - cl.birth)
+			for _, child := range children {
+				id := len(ct.clusters)
+				ct.clusters[f.cluster].children = append(ct.clusters[f.cluster].children, id)
+				ct.clusters = append(ct.clusters, condensedCluster{parent: f.cluster, birth: l})
+				stack = append(stack, frame{child, id})
+			}
+		case bigLeft || bigRight:
+			small, big := right, left
+			if bigRight {
+				small, big = left, right
+			}
+			cl.stability += float64(t.size[small]) * (l - cl.birth)
+			for _, p := range t.leaves(small, nil) {
+				ct.pointCluster[p] = f.cluster
+			}
+			stack = append(stack, frame{big, f.cluster})
+		default:
+			cl.stability += float64(t.size[f.node]) * (l - cl.birth)
+			for _, p := range t.leaves(f.node, nil) {
+				ct.pointCluster[p] = f.cluster
+			}
+		}
+	}
+	return ct
+}
+
+// selectClusters picks the clusters of greatest total stability such that
+// no selected cluster contains another
+func (ct *condensedTree) selectClusters(allowSingle bool) []bool {
+	selected := make([]bool, len(ct.clusters))
+	subtree := make([]float64, len(ct.clusters))
+
+	// Children are always created after their parent
+	for c := len(ct.clusters) - 1; c >= 0; c-- {
+		cl := ct.clusters[c]
+		childSum := 0.0
+		for _, child := range cl.children {
+			childSum += subtree[child]
+		}
+
+		if c == 0 && !allowSingle {
+			break
+		}
+		if len(cl.children) == 0 || cl.stability >= childSum {
+			selected[c] = true
+			subtree[c] = cl.stability
+			ct.deselectDescendants(c, selected)
+		} else {
+			subtree[c] = childSum
+		}
+	}
+	return selected
+}
+
+func (ct *condensedTree) deselectDescendants(c int, selected []bool) {
+	for _, child := range ct.clusters[c].children {
+		selected[child] = false
+		ct.deselectDescendants(child, selected)
+	}
+}
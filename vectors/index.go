@@ -0,0 +1,413 @@
+package vectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	vultrai "github.com/eqba1/vultrai"
+)
+
+// DefaultTopK is the number of results Search returns by default
+const DefaultTopK = 10
+
+// ErrNoEmbedder is returned when text must be embedded by an index created
+// without an Embedder
+var ErrNoEmbedder = errors.New("index has no embedder")
+
+// Document is an entry in a local index
+type Document struct {
+	ID       string
+	Content  string
+	Metadata map[string]string
+	// Vector is computed from Content when left nil
+	Vector  []float32
+	Created time.Time
+}
+
+// Filter restricts a search to documents whose metadata has every key set
+// to the given value
+type Filter map[string]string
+
+// Match reports whether doc passes the filter; a nil filter matches all
+func (f Filter) Match(doc Document) bool {
+	for key, value := range f {
+		if got, ok := doc.Metadata[key]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Store is a local vector store. Index keeps documents in memory and
+// BoltStore persists them to disk; both implement vultrai.Searcher.
+type Store interface {
+	vultrai.Searcher
+	Add(ctx context.Context, docs ...Document) error
+	Delete(ids ...string) error
+	Get(id string) (Document, bool)
+	Len() int
+	SearchText(ctx context.Context, text string, k int, filter Filter) ([]Match, error)
+	SearchVector(query []float32, k int, filter Filter) []Match
+}
+
+// Match is a document found by a search and its similarity to the query
+type Match struct {
+	Document Document
+	Score    float64
+}
+
+// IndexOptions configures an Index
+type IndexOptions struct {
+	// Metric is Euclidean by default; Cosine suits most embedding models
+	Metric Metric
+	// TopK is the number of results Search returns (default DefaultTopK)
+	TopK int
+	// Lists enables approximate search once the index holds at least
+	// 4*Lists documents: vectors are grouped into Lists k-means clusters and
+	// only the Probes nearest clusters are scanned. Zero always searches
+	// exhaustively.
+	Lists int
+	// Probes is the number of clusters scanned by approximate search
+	// (default 1/8 of Lists, at least 1)
+	Probes int
+}
+
+// Index is an in-memory vector index for searching small datasets entirely
+// client-side. It implements vultrai.Searcher, so it can stand in for a
+// hosted collection. An Index is safe for concurrent use.
+type Index struct {
+	embedder vultrai.Embedder
+	opts     IndexOptions
+
+	mu   sync.RWMutex
+	docs map[string]*Document
+	ivf  *invertedLists
+}
+
+var _ Store = (*Index)(nil)
+
+// NewIndex creates an empty index that embeds documents and queries with
+// embedder. A nil embedder is allowed when every document carries its
+// Vector and only SearchVector is used.
+func NewIndex(embedder vultrai.Embedder, opts IndexOptions) *Index {
+	if opts.TopK <= 0 {
+		opts.TopK = DefaultTopK
+	}
+	if opts.Lists > 0 && opts.Probes <= 0 {
+		opts.Probes = opts.Lists / 8
+		if opts.Probes < 1 {
+			opts.Probes = 1
+		}
+	}
+	return &Index{embedder: embedder, opts: opts, docs: make(map[string]*Document)}
+}
+
+// Metric returns the distance the index ranks by
+func (ix *Index) Metric() Metric {
+	return ix.opts.Metric
+}
+
+// Add embeds and stores docs, replacing documents with the same ID
+func (ix *Index) Add(ctx context.Context, docs ...Document) error {
+	if err := embedMissing(ctx, ix.embedder, docs); err != nil {
+		return err
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for i := range docs {
+		doc := docs[i]
+		if doc.Created.IsZero() {
+			doc.Created = time.Now()
+		}
+		if old, ok := ix.docs[doc.ID]; ok && ix.ivf != nil {
+			ix.ivf.remove(old.ID, old.Vector)
+		}
+		ix.docs[doc.ID] = &doc
+		if ix.ivf != nil {
+			ix.ivf.add(doc.ID, doc.Vector)
+		}
+	}
+	return nil
+}
+
+// Delete removes documents by ID; unknown IDs are ignored
+func (ix *Index) Delete(ids ...string) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for _, id := range ids {
+		if doc, ok := ix.docs[id]; ok {
+			if ix.ivf != nil {
+				ix.ivf.remove(id, doc.Vector)
+			}
+			delete(ix.docs, id)
+		}
+	}
+	return nil
+}
+
+// Get returns the document with id
+func (ix *Index) Get(id string) (Document, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	doc, ok := ix.docs[id]
+	if !ok {
+		return Document{}, false
+	}
+	return *doc, true
+}
+
+// Len returns the number of documents
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.docs)
+}
+
+// Search embeds req.Input and returns the most similar documents whose
+// metadata matches req.Filter, most similar first. It returns req.TopK
+// results, or the index's TopK when unset, dropping those scoring under
+// req.ScoreThreshold.
+func (ix *Index) Search(ctx context.Context, req vultrai.SearchRequest) (*vultrai.SearchResponse, error) {
+	k := req.TopK
+	if k <= 0 {
+		k = ix.opts.TopK
+	}
+	matches, err := ix.SearchText(ctx, req.Input, k, Filter(req.Filter))
+	if err != nil {
+		return nil, err
+	}
+	resp := searchResponse(matches)
+	resp.Results = vultrai.RankSearchResults(req, resp.Results)
+	return resp, nil
+}
+
+// SearchText embeds text and returns the k most similar documents passing
+// filter, most similar first
+func (ix *Index) SearchText(ctx context.Context, text string, k int, filter Filter) ([]Match, error) {
+	query, err := embedQuery(ctx, ix.embedder, text)
+	if err != nil {
+		return nil, err
+	}
+	return ix.SearchVector(query, k, filter), nil
+}
+
+// SearchVector returns the k documents nearest to query that pass filter,
+// most similar first. Approximate search may return fewer than k matches
+// when the filter excludes most of the scanned clusters.
+func (ix *Index) SearchVector(query []float32, k int, filter Filter) []Match {
+	ix.mu.Lock()
+	if ix.opts.Lists > 0 && ix.ivf == nil && len(ix.docs) >= 4*ix.opts.Lists {
+		ix.ivf = trainLists(ix.docs, ix.opts.Lists, ix.opts.Metric)
+	}
+	ix.mu.Unlock()
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	top := newTopK(k)
+	if ix.ivf != nil {
+		for _, list := range ix.ivf.nearestLists(query, ix.opts.Probes) {
+			for _, id := range list {
+				if doc := ix.docs[id]; filter.Match(*doc) {
+					top.push(doc, similarity(ix.opts.Metric, query, doc.Vector))
+				}
+			}
+		}
+	} else {
+		for _, doc := range ix.docs {
+			if filter.Match(*doc) {
+				top.push(doc, similarity(ix.opts.Metric, query, doc.Vector))
+			}
+		}
+	}
+	return top.sorted()
+}
+
+// similarity scores a and b so that higher is more similar: the cosine
+// similarity, or the negated Euclidean distance
+func similarity(m Metric, a, b []float32) float64 {
+	if m == Cosine {
+		return CosineSimilarity(a, b)
+	}
+	return -Euclidean.Distance(a, b)
+}
+
+// embedMissing fills in the vectors of docs that lack one
+func embedMissing(ctx context.Context, embedder vultrai.Embedder, docs []Document) error {
+	var texts []string
+	var missing []int
+	for i, doc := range docs {
+		if doc.ID == "" {
+			return fmt.Errorf("document %d has no ID", i)
+		}
+		if doc.Vector == nil {
+			texts = append(texts, doc.Content)
+			missing = append(missing, i)
+		}
+	}
+	if len(texts) == 0 {
+		return nil
+	}
+	if embedder == nil {
+		return ErrNoEmbedder
+	}
+
+	vecs, err := embedder.Embed(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("error embedding documents: %w", err)
+	}
+	if len(vecs) != len(texts) {
+		return fmt.Errorf("embedder returned %d vectors for %d documents", len(vecs), len(texts))
+	}
+	for j, i := range missing {
+		docs[i].Vector = vecs[j]
+	}
+	return nil
+}
+
+// embedQuery embeds a single search query
+func embedQuery(ctx context.Context, embedder vultrai.Embedder, input string) ([]float32, error) {
+	if embedder == nil {
+		return nil, ErrNoEmbedder
+	}
+	vecs, err := embedder.Embed(ctx, []string{input})
+	if err != nil {
+		return nil, fmt.Errorf("error embedding query: %w", err)
+	}
+	if len(vecs) != 1 {
+		return nil, fmt.Errorf("embedder returned %d vectors for 1 query", len(vecs))
+	}
+	return vecs[0], nil
+}
+
+// searchResponse converts matches into the hosted collection search format
+func searchResponse(matches []Match) *vultrai.SearchResponse {
+	resp := &vultrai.SearchResponse{Results: make([]vultrai.SearchResult, len(matches))}
+	for i, m := range matches {
+		resp.Results[i] = vultrai.SearchResult{
+			ID:      m.Document.ID,
+			Created: m.Document.Created.UTC().Format(time.RFC3339),
+			Content: m.Document.Content,
+			Score:   m.Score,
+			ItemID:  m.Document.ID,
+		}
+	}
+	return resp
+}
+
+// topK keeps the k best matches seen
+type topK struct {
+	k       int
+	matches []Match
+}
+
+func newTopK(k int) *topK {
+	return &topK{k: k}
+}
+
+func (t *topK) push(doc *Document, score float64) {
+	if t.k <= 0 {
+		return
+	}
+	if len(t.matches) == t.k {
+		worst := t.matches[len(t.matches)-1]
+		if score < worst.Score || score == worst.Score && doc.ID > worst.Document.ID {
+			return
+		}
+		t.matches = t.matches[:len(t.matches)-1]
+	}
+	// Insert keeping matches ordered best first, ties by ID
+	i := sort.Search(len(t.matches), func(i int) bool {
+		m := t.matches[i]
+		return m.Score < score || m.Score == score && m.Document.ID > doc.ID
+	})
+	t.matches = append(t.matches, Match{})
+	copy(t.matches[i+1:], t.matches[i:])
+	t.matches[i] = Match{Document: *doc, Score: score}
+}
+
+func (t *topK) sorted() []Match {
+	return t.matches
+}
+
+// invertedLists groups document IDs by their nearest k-means centroid
+type invertedLists struct {
+	metric    Metric
+	centroids [][]float32
+	lists     [][]string
+}
+
+func trainLists(docs map[string]*Document, lists int, metric Metric) *invertedLists {
+	ids := make([]string, 0, len(docs))
+	for id := range docs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	data := make([][]float32, len(ids))
+	for i, id := range ids {
+		data[i] = docs[id].Vector
+	}
+
+	clustering, err := KMeans(data, lists, KMeansOptions{Metric: metric})
+	if err != nil {
+		return nil
+	}
+	ivf := &invertedLists{metric: metric, centroids: clustering.Centroids, lists: make([][]string, lists)}
+	for i, label := range clustering.Labels {
+		ivf.lists[label] = append(ivf.lists[label], ids[i])
+	}
+	return ivf
+}
+
+func (ivf *invertedLists) add(id string, v []float32) {
+	list, _ := Nearest(ivf.centroids, v, ivf.metric)
+	ivf.lists[list] = append(ivf.lists[list], id)
+}
+
+func (ivf *invertedLists) remove(id string, v []float32) {
+	// Training may have placed the vector in a list other than the nearest
+	nearest, _ := Nearest(ivf.centroids, v, ivf.metric)
+	if ivf.removeFrom(nearest, id) {
+		return
+	}
+	for list := range ivf.lists {
+		if ivf.removeFrom(list, id) {
+			return
+		}
+	}
+}
+
+func (ivf *invertedLists) removeFrom(list int, id string) bool {
+	ids := ivf.lists[list]
+	for i, other := range ids {
+		if other == id {
+			ivf.lists[list] = append(ids[:i], ids[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// nearestLists returns the probes lists whose centroids are nearest to v
+func (ivf *invertedLists) nearestLists(v []float32, probes int) [][]string {
+	order := make([]int, len(ivf.centroids))
+	dist := make([]float64, len(ivf.centroids))
+	for i, c := range ivf.centroids {
+		order[i] = i
+		dist[i] = ivf.metric.Distance(c, v)
+	}
+	sort.Slice(order, func(a, b int) bool { return dist[order[a]] < dist[order[b]] })
+	if probes > len(order) {
+		probes = len(order)
+	}
+	lists := make([][]string, probes)
+	for i := range lists {
+		lists[i] = ivf.lists[order[i]]
+	}
+	return lists
+}
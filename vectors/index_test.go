@@ -0,0 +1,120 @@
+package vectors
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keywordEmbedder embeds text as counts of a few keywords
+var keywordEmbedder = vultrai.EmbedderFunc(func(ctx context.Context, texts []string) ([][]float32, error) {
+	keywords := []string{"cat", "dog", "car", "boat"}
+	vecs := make([][]float32, len(texts))
+	for i, text := range texts {
+		vecs[i] = make([]float32, len(keywords))
+		for k, word := range keywords {
+			vecs[i][k] = float32(strings.Count(strings.ToLower(text), word))
+		}
+	}
+	return vecs, nil
+})
+
+func TestIndexSearch(t *testing.T) {
+	ctx := context.Background()
+	ix := NewIndex(keywordEmbedder, IndexOptions{Metric: Cosine, TopK: 2})
+	require.NoError(t, ix.Add(ctx,
+		Document{ID: "pets", Content: "a cat and a dog", Metadata: map[string]string{"kind": "pets"}},
+		Document{ID: "cats", Content: "cat cat cat"},
+		Document{ID: "cars", Content: "a car"},
+		Document{ID: "boats", Content: "boat and car"},
+	))
+	assert.Equal(t, 4, ix.Len())
+
+	var searcher vultrai.Searcher = ix
+	resp, err := searcher.Search(ctx, vultrai.SearchRequest{Input: "my cat"})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 2)
+	assert.Equal(t, "cats", resp.Results[0].ID)
+	assert.InDelta(t, 1.0, resp.Results[0].Score, 1e-6)
+	assert.Equal(t, "pets", resp.Results[1].ID)
+	assert.NotEmpty(t, resp.Results[0].Created)
+	assert.Equal(t, "cats", resp.Results[0].ItemID)
+
+	resp, err = ix.Search(ctx, vultrai.SearchRequest{Input: "my cat", TopK: 4, ScoreThreshold: 0.1})
+	require.NoError(t, err)
+	assert.Len(t, resp.Results, 2)
+
+	resp, err = ix.Search(ctx, vultrai.SearchRequest{Input: "my cat", Filter: map[string]string{"kind": "pets"}})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, "pets", resp.Results[0].ID)
+
+	require.NoError(t, ix.Delete("cats", "missing"))
+	resp, err = ix.Search(ctx, vultrai.SearchRequest{Input: "my cat"})
+	require.NoError(t, err)
+	assert.Equal(t, "pets", resp.Results[0].ID)
+
+	// Re-adding an ID replaces the document
+	require.NoError(t, ix.Add(ctx, Document{ID: "pets", Content: "a boat"}))
+	doc, ok := ix.Get("pets")
+	require.True(t, ok)
+	assert.Equal(t, "a boat", doc.Content)
+	assert.Equal(t, 3, ix.Len())
+}
+
+func TestIndexWithoutEmbedder(t *testing.T) {
+	ix := NewIndex(nil, IndexOptions{Metric: Euclidean})
+	err := ix.Add(context.Background(), Document{ID: "a", Content: "text"})
+	assert.ErrorIs(t, err, ErrNoEmbedder)
+
+	require.NoError(t, ix.Add(context.Background(),
+		Document{ID: "a", Vector: []float32{0, 0}},
+		Document{ID: "b", Vector: []float32{3, 4}},
+	))
+	matches := ix.SearchVector([]float32{3, 3}, 1, nil)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "b", matches[0].Document.ID)
+	assert.InDelta(t, -1.0, matches[0].Score, 1e-6)
+
+	_, err = ix.Search(context.Background(), vultrai.SearchRequest{Input: "q"})
+	assert.ErrorIs(t, err, ErrNoEmbedder)
+}
+
+func matchIDs(matches []Match) []string {
+	ids := make([]string, len(matches))
+	for i, m := range matches {
+		ids[i] = m.Document.ID
+	}
+	return ids
+}
+
+func TestIndexApproximate(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	centers := [][]float32{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}, {1, 1, 1}}
+	points := blobs(rng, centers, 25, 0.05)
+
+	exact := NewIndex(nil, IndexOptions{})
+	approx := NewIndex(nil, IndexOptions{Lists: 4, Probes: 1})
+	for i, p := range points {
+		doc := Document{ID: fmt.Sprintf("doc-%03d", i), Vector: p}
+		require.NoError(t, exact.Add(context.Background(), doc))
+		require.NoError(t, approx.Add(context.Background(), doc))
+	}
+
+	for _, query := range [][]float32{{0.9, 0.1, 0}, {0, 0.1, 1}, {1, 0.9, 1}} {
+		assert.Equal(t, matchIDs(exact.SearchVector(query, 5, nil)), matchIDs(approx.SearchVector(query, 5, nil)))
+	}
+	require.NotNil(t, approx.ivf)
+
+	// Documents added and removed after training stay searchable
+	require.NoError(t, approx.Add(context.Background(), Document{ID: "late", Vector: []float32{0, 1, 0}}))
+	assert.Equal(t, "late", approx.SearchVector([]float32{0, 1, 0}, 1, nil)[0].Document.ID)
+	require.NoError(t, approx.Delete("late"))
+	assert.NotEqual(t, "late", approx.SearchVector([]float32{0, 1, 0}, 1, nil)[0].Document.ID)
+}
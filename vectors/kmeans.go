@@ -0,0 +1,187 @@
+package vectors
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// ErrDimensionMismatch is returned when vectors of different lengths are
+// clustered together
+var ErrDimensionMismatch = errors.New("vectors have different dimensions")
+
+// Noise is the label of points that belong to no cluster
+const Noise = -1
+
+// Clustering is the result of KMeans or Density
+type Clustering struct {
+	// Labels holds the cluster of each input point, or Noise
+	Labels []int
+	// Centroids holds the mean of each cluster's points
+	Centroids [][]float32
+	// Metric is the distance used to build the clustering
+	Metric Metric
+}
+
+// K returns the number of clusters
+func (c *Clustering) K() int {
+	return len(c.Centroids)
+}
+
+// Members returns the indexes of the points in each cluster
+func (c *Clustering) Members() [][]int {
+	members := make([][]int, len(c.Centroids))
+	for i, label := range c.Labels {
+		if label != Noise {
+			members[label] = append(members[label], i)
+		}
+	}
+	return members
+}
+
+// Assign returns the cluster whose centroid is nearest to v and the
+// distance to it, labeling new points such as search results or items added
+// after clustering
+func (c *Clustering) Assign(v []float32) (int, float64) {
+	return Nearest(c.Centroids, v, c.Metric)
+}
+
+// KMeansOptions configures KMeans
+type KMeansOptions struct {
+	// Metric is Euclidean by default; Cosine clusters normalized vectors
+	Metric Metric
+	// MaxIterations bounds the refinement steps (default 100)
+	MaxIterations int
+	// Tolerance stops refinement once no centroid moves further (default 1e-4)
+	Tolerance float64
+	// Seed makes centroid initialization reproducible
+	Seed int64
+}
+
+// KMeans partitions data into k clusters using k-means++ initialization
+// followed by Lloyd refinement
+func KMeans(data [][]float32, k int, opts KMeansOptions) (*Clustering, error) {
+	if k <= 0 {
+		return nil, fmt.Errorf("k must be positive, got %d", k)
+	}
+	if k > len(data) {
+		return nil, fmt.Errorf("k (%d) exceeds the number of points (%d)", k, len(data))
+	}
+	if err := checkDimensions(data); err != nil {
+		return nil, err
+	}
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = 100
+	}
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = 1e-4
+	}
+
+	points := data
+	if opts.Metric == Cosine {
+		points = make([][]float32, len(data))
+		for i, v := range data {
+			points[i] = Normalize(v)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	centroids := seedCentroids(points, k, rng)
+	labels := make([]int, len(points))
+
+	for iter := 0; iter < opts.MaxIterations; iter++ {
+		for i, p := range points {
+			labels[i], _ = Nearest(centroids, p, Euclidean)
+		}
+
+		moved := 0.0
+		for c, members := range group(points, labels, k) {
+			var next []float32
+			if len(members) == 0 {
+				// Restart an empty cluster at the point furthest from its centroid
+				far := furthest(points, labels, centroids)
+				labels[far] = c
+				next = points[far]
+			} else {
+				next = Mean(members)
+			}
+			if opts.Metric == Cosine {
+				next = Normalize(next)
+			}
+			moved = math.Max(moved, math.Sqrt(SquaredDistance(centroids[c], next)))
+			centroids[c] = next
+		}
+		if moved <= opts.Tolerance {
+			break
+		}
+	}
+
+	for i, p := range points {
+		labels[i], _ = Nearest(centroids, p, Euclidean)
+	}
+	return &Clustering{Labels: labels, Centroids: centroids, Metric: opts.Metric}, nil
+}
+
+// seedCentroids picks k initial centroids, each chosen with probability
+// proportional to its squared distance from those already picked
+func seedCentroids(points [][]float32, k int, rng *rand.Rand) [][]float32 {
+	centroids := [][]float32{points[rng.Intn(len(points))]}
+	dist := make([]float64, len(points))
+	for len(centroids) < k {
+		total := 0.0
+		for i, p := range points {
+			_, d := Nearest(centroids, p, Euclidean)
+			dist[i] = d * d
+			total += dist[i]
+		}
+		if total == 0 {
+			centroids = append(centroids, points[rng.Intn(len(points))])
+			continue
+		}
+		target := rng.Float64() * total
+		chosen := len(points) - 1
+		for i, d := range dist {
+			target -= d
+			if target <= 0 {
+				chosen = i
+				break
+			}
+		}
+		centroids = append(centroids, points[chosen])
+	}
+
+	for i, c := range centroids {
+		centroids[i] = append([]float32(nil), c...)
+	}
+	return centroids
+}
+
+// group collects the points of each label
+func group(points [][]float32, labels []int, k int) [][][]float32 {
+	groups := make([][][]float32, k)
+	for i, label := range labels {
+		groups[label] = append(groups[label], points[i])
+	}
+	return groups
+}
+
+// furthest returns the point furthest from its assigned centroid
+func furthest(points [][]float32, labels []int, centroids [][]float32) int {
+	best, bestDist := 0, -1.0
+	for i, p := range points {
+		if d := SquaredDistance(p, centroids[labels[i]]); d > bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+func checkDimensions(data [][]float32) error {
+	for _, v := range data {
+		if len(v) != len(data[0]) {
+			return ErrDimensionMismatch
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Package vectors provides similarity measures, clustering and search over
+// embedding vectors without depending on a numerical computing library.
+package vectors
+
+import "math"
+
+// Metric measures the distance between two vectors
+type Metric int
+
+const (
+	// Euclidean is the straight-line distance
+	Euclidean Metric = iota
+	// Cosine is one minus the cosine similarity, suited to embeddings whose
+	// direction carries the meaning
+	Cosine
+)
+
+// Distance returns the distance between a and b under m
+func (m Metric) Distance(a, b []float32) float64 {
+	if m == Cosine {
+		return 1 - CosineSimilarity(a, b)
+	}
+	return math.Sqrt(SquaredDistance(a, b))
+}
+
+// Dot returns the dot product of a and b over their common length
+func Dot(a, b []float32) float64 {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	var sum float64
+	for i := 0; i < n; i++ {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+// Norm returns the Euclidean length of v
+func Norm(v []float32) float64 {
+	return math.Sqrt(Dot(v, v))
+}
+
+// CosineSimilarity returns the cosine of the angle between a and b, or 0
+// when either is empty, zero or their lengths differ
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	normA, normB := Norm(a), Norm(b)
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return Dot(a, b) / (normA * normB)
+}
+
+// SquaredDistance returns the squared Euclidean distance between a and b
+func SquaredDistance(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+	return sum
+}
+
+// Normalize returns a unit-length copy of v; a zero vector is returned
+// unchanged
+func Normalize(v []float32) []float32 {
+	out := make([]float32, len(v))
+	norm := Norm(v)
+	if norm == 0 {
+		copy(out, v)
+		return out
+	}
+	for i, x := range v {
+		out[i] = float32(float64(x) / norm)
+	}
+	return out
+}
+
+// Mean returns the element-wise mean of vs, or nil when vs is empty
+func Mean(vs [][]float32) []float32 {
+	if len(vs) == 0 {
+		return nil
+	}
+	sum := make([]float64, len(vs[0]))
+	for _, v := range vs {
+		for i := range sum {
+			sum[i] += float64(v[i])
+		}
+	}
+	mean := make([]float32, len(sum))
+	for i, s := range sum {
+		mean[i] = float32(s / float64(len(vs)))
+	}
+	return mean
+}
+
+// Nearest returns the index of the candidate closest to v under m and its
+// distance, or -1 when there are no candidates
+func Nearest(candidates [][]float32, v []float32, m Metric) (int, float64) {
+	best, bestDist := -1, math.Inf(1)
+	for i, c := range candidates {
+		if d := m.Distance(c, v); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best, bestDist
+}
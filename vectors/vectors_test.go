@@ -0,0 +1,120 @@
+package vectors
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blobs returns size points scattered around each center
+func blobs(rng *rand.Rand, centers [][]float32, size int, spread float64) [][]float32 {
+	var points [][]float32
+	for _, c := range centers {
+		for i := 0; i < size; i++ {
+			p := make([]float32, len(c))
+			for d := range c {
+				p[d] = c[d] + float32(rng.NormFloat64()*spread)
+			}
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// sameGrouping reports whether each block of size consecutive points shares
+// one label and different blocks have different labels
+func sameGrouping(t *testing.T, labels []int, blocks, size int) {
+	t.Helper()
+	seen := map[int]bool{}
+	for b := 0; b < blocks; b++ {
+		label := labels[b*size]
+		assert.NotEqual(t, Noise, label)
+		assert.False(t, seen[label], "blocks %d shares a label", b)
+		seen[label] = true
+		for i := b * size; i < (b+1)*size; i++ {
+			assert.Equal(t, label, labels[i], "point %d", i)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.InDelta(t, 1.0, CosineSimilarity([]float32{1, 2}, []float32{2, 4}), 1e-6)
+	assert.InDelta(t, 0.0, CosineSimilarity([]float32{1, 0}, []float32{0, 3}), 1e-6)
+	assert.Zero(t, CosineSimilarity([]float32{1}, []float32{1, 2}))
+	assert.Zero(t, CosineSimilarity([]float32{0, 0}, []float32{1, 2}))
+
+	assert.InDelta(t, 5.0, Euclidean.Distance([]float32{0, 0}, []float32{3, 4}), 1e-6)
+	assert.InDelta(t, 1.0, Cosine.Distance([]float32{1, 0}, []float32{0, 1}), 1e-6)
+	assert.InDelta(t, 1.0, Norm(Normalize([]float32{3, 4})), 1e-6)
+	assert.Equal(t, []float32{2, 3}, Mean([][]float32{{1, 2}, {3, 4}}))
+
+	i, d := Nearest([][]float32{{0, 0}, {10, 10}}, []float32{9, 9}, Euclidean)
+	assert.Equal(t, 1, i)
+	assert.InDelta(t, math.Sqrt2, d, 1e-6)
+}
+
+func TestKMeans(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	points := blobs(rng, [][]float32{{0, 0}, {10, 0}, {0, 10}}, 20, 0.5)
+
+	clustering, err := KMeans(points, 3, KMeansOptions{Seed: 7})
+	require.NoError(t, err)
+	assert.Equal(t, 3, clustering.K())
+	sameGrouping(t, clustering.Labels, 3, 20)
+
+	label, _ := clustering.Assign([]float32{9.5, 0.3})
+	assert.Equal(t, clustering.Labels[20], label)
+	for _, members := range clustering.Members() {
+		assert.Len(t, members, 20)
+	}
+}
+
+func TestKMeansCosine(t *testing.T) {
+	// Same directions at very different magnitudes
+	points := [][]float32{{1, 0.1}, {50, 4}, {0.2, 3}, {1, 40}}
+	clustering, err := KMeans(points, 2, KMeansOptions{Metric: Cosine, Seed: 3})
+	require.NoError(t, err)
+	assert.Equal(t, clustering.Labels[0], clustering.Labels[1])
+	assert.Equal(t, clustering.Labels[2], clustering.Labels[3])
+	assert.NotEqual(t, clustering.Labels[0], clustering.Labels[2])
+}
+
+func TestKMeansErrors(t *testing.T) {
+	_, err := KMeans([][]float32{{1}}, 2, KMeansOptions{})
+	assert.Error(t, err)
+	_, err = KMeans([][]float32{{1}, {1, 2}}, 1, KMeansOptions{})
+	assert.ErrorIs(t, err, ErrDimensionMismatch)
+}
+
+func TestDensity(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	points := blobs(rng, [][]float32{{0, 0}, {20, 20}}, 15, 0.5)
+	outliers := [][]float32{{10, -30}, {-25, 40}}
+	points = append(points, outliers...)
+
+	clustering, err := Density(points, DensityOptions{MinClusterSize: 5})
+	require.NoError(t, err)
+	assert.Equal(t, 2, clustering.K())
+	sameGrouping(t, clustering.Labels, 2, 15)
+	assert.Equal(t, Noise, clustering.Labels[30])
+	assert.Equal(t, Noise, clustering.Labels[31])
+
+	label, _ := clustering.Assign([]float32{19, 21})
+	assert.Equal(t, clustering.Labels[15], label)
+}
+
+func TestDensitySingleCluster(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	points := blobs(rng, [][]float32{{0, 0}}, 12, 0.5)
+
+	clustering, err := Density(points, DensityOptions{MinClusterSize: 10})
+	require.NoError(t, err)
+	assert.Zero(t, clustering.K())
+
+	clustering, err = Density(points, DensityOptions{MinClusterSize: 10, AllowSingleCluster: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, clustering.K())
+}
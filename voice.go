@@ -0,0 +1,197 @@
+package vultrai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// voiceChunkSize is the amount of audio written between checks for
+// barge-in
+const voiceChunkSize = 4096
+
+// VoiceHooks let a VoiceChat be customized between stages. Each hook may
+// rewrite the value it receives; nil hooks are skipped.
+type VoiceHooks struct {
+	// AfterTranscribe receives the user's words. Returning "" skips the
+	// turn, e.g. for noise or filler.
+	AfterTranscribe func(ctx context.Context, text string) (string, error)
+	// BeforeSpeak receives the reply before it is synthesized
+	BeforeSpeak func(ctx context.Context, text string) (string, error)
+	// BeforePlay receives the synthesized audio before it is written,
+	// e.g. to transcode it for the output device
+	BeforePlay func(ctx context.Context, audio []byte) ([]byte, error)
+	// OnError receives the errors of turns in Run. Returning nil keeps the
+	// loop going; without a hook Run stops at the first error.
+	OnError func(err error) error
+}
+
+// VoiceTurn is the outcome of one exchange with a VoiceChat
+type VoiceTurn struct {
+	Transcript string
+	Reply      string
+	// Interrupted is set when the reply was cut short by barge-in
+	Interrupted bool
+}
+
+// VoiceChat is a voice assistant loop: user audio is transcribed, answered
+// in a Conversation and the answer is spoken back.
+type VoiceChat struct {
+	Conversation *Conversation
+	// STTModel transcribes the user's audio
+	STTModel string
+	// Language is the optional ISO-639-1 code of the spoken language
+	Language string
+	// TTSModel and Voice speak the replies
+	TTSModel string
+	Voice    string
+	// SpeechFormat is the optional audio format requested for replies
+	SpeechFormat string
+	Hooks        VoiceHooks
+}
+
+// NewVoiceChat creates a voice loop around conv
+func NewVoiceChat(conv *Conversation, sttModel, ttsModel, voice string) *VoiceChat {
+	return &VoiceChat{
+		Conversation: conv,
+		STTModel:     sttModel,
+		TTSModel:     ttsModel,
+		Voice:        voice,
+	}
+}
+
+// Turn answers one utterance read from in, writing the spoken reply to
+// out. Cancelling ctx stops the turn at the current stage; a reply cut
+// short while playing stays in the conversation history.
+func (v *VoiceChat) Turn(ctx context.Context, in io.Reader, out io.Writer) (*VoiceTurn, error) {
+	client := v.Conversation.client
+	turn := &VoiceTurn{}
+
+	transcription, err := client.CreateTranscription(ctx, TranscriptionRequest{
+		Model:    v.STTModel,
+		Audio:    in,
+		Language: v.Language,
+	})
+	if err != nil {
+		return turn, fmt.Errorf("error transcribing: %w", err)
+	}
+	text := strings.TrimSpace(transcription.Text)
+	if v.Hooks.AfterTranscribe != nil && text != "" {
+		if text, err = v.Hooks.AfterTranscribe(ctx, text); err != nil {
+			return turn, err
+		}
+	}
+	if text == "" {
+		return turn, nil
+	}
+	turn.Transcript = text
+
+	resp, err := v.Conversation.Send(ctx, text)
+	if err != nil {
+		return turn, fmt.Errorf("error answering: %w", err)
+	}
+	reply := resp.Choices[0].Message.Content
+	if v.Hooks.BeforeSpeak != nil {
+		if reply, err = v.Hooks.BeforeSpeak(ctx, reply); err != nil {
+			return turn, err
+		}
+	}
+	turn.Reply = reply
+	if strings.TrimSpace(reply) == "" {
+		return turn, nil
+	}
+
+	audio, err := client.CreateSpeech(ctx, TTSRequest{
+		Model:          v.TTSModel,
+		Input:          reply,
+		Voice:          v.Voice,
+		ResponseFormat: v.SpeechFormat,
+	})
+	if err != nil {
+		return turn, fmt.Errorf("error synthesizing speech: %w", err)
+	}
+	if v.Hooks.BeforePlay != nil {
+		if audio, err = v.Hooks.BeforePlay(ctx, audio); err != nil {
+			return turn, err
+		}
+	}
+
+	for len(audio) > 0 {
+		if err := ctx.Err(); err != nil {
+			turn.Interrupted = true
+			return turn, err
+		}
+		n := min(voiceChunkSize, len(audio))
+		if _, err := out.Write(audio[:n]); err != nil {
+			return turn, fmt.Errorf("error writing audio: %w", err)
+		}
+		audio = audio[n:]
+	}
+	return turn, nil
+}
+
+// Run answers each utterance received from utterances in turn, writing the
+// replies to out, until utterances is closed or ctx is done. An utterance
+// arriving while the previous turn is still running cancels that turn, so
+// users can interrupt the assistant (barge-in).
+func (v *VoiceChat) Run(ctx context.Context, utterances <-chan io.Reader, out io.Writer) error {
+	cancel := context.CancelFunc(func() {})
+	defer func() { cancel() }()
+	var done <-chan error
+
+	for {
+		select {
+		case <-ctx.Done():
+			if done != nil {
+				<-done
+			}
+			return ctx.Err()
+
+		case in, ok := <-utterances:
+			if !ok {
+				if done == nil {
+					return nil
+				}
+				return v.handleError(<-done)
+			}
+			if done != nil {
+				// Barge-in: stop the current reply
+				cancel()
+				<-done
+			}
+			cancel, done = v.startTurn(ctx, in, out)
+
+		case err := <-done:
+			cancel()
+			done = nil
+			if err := v.handleError(err); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// startTurn runs a turn in the background, returning a function cancelling
+// it and a channel receiving its error
+func (v *VoiceChat) startTurn(ctx context.Context, in io.Reader, out io.Writer) (context.CancelFunc, <-chan error) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		_, err := v.Turn(ctx, in, out)
+		done <- err
+	}()
+	return cancel, done
+}
+
+// handleError passes the error of a finished turn to the OnError hook
+func (v *VoiceChat) handleError(err error) error {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return nil
+	}
+	if v.Hooks.OnError == nil {
+		return err
+	}
+	return v.Hooks.OnError(err)
+}
@@ -0,0 +1,96 @@
+package vultrai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// voiceServer transcribes audio as its own bytes, answers "reply to" the
+// last user message and speaks text as "audio:" followed by the text. A
+// user message of "slow" is signalled on slow and blocks until the request
+// is cancelled.
+func voiceServer(t *testing.T, slow chan<- struct{}) *Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/audio/transcriptions":
+			file, _, err := r.FormFile("file")
+			require.NoError(t, err)
+			data, _ := io.ReadAll(file)
+			json.NewEncoder(w).Encode(TranscriptionResponse{Text: string(data)})
+		case "/chat/completions":
+			var req ChatCompletionRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			last := req.Messages[len(req.Messages)-1].Content
+			if last == "slow" {
+				slow <- struct{}{}
+				<-r.Context().Done()
+				return
+			}
+			fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":%q}}]}`, "reply to "+last)
+		case "/audio/speech":
+			var req TTSRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			fmt.Fprint(w, "audio:"+req.Input)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return NewClient("key", WithBaseURL(server.URL))
+}
+
+func TestVoiceChatTurn(t *testing.T) {
+	client := voiceServer(t, nil)
+	voice := NewVoiceChat(NewConversation(client, "chat-model"), "stt", "tts", "alloy")
+	voice.Hooks.AfterTranscribe = func(ctx context.Context, text string) (string, error) {
+		if text == "um" {
+			return "", nil
+		}
+		return strings.ToLower(text), nil
+	}
+	voice.Hooks.BeforePlay = func(ctx context.Context, audio []byte) ([]byte, error) {
+		return bytes.ToUpper(audio), nil
+	}
+
+	var out bytes.Buffer
+	turn, err := voice.Turn(context.Background(), strings.NewReader("Hello"), &out)
+	require.NoError(t, err)
+	assert.Equal(t, &VoiceTurn{Transcript: "hello", Reply: "reply to hello"}, turn)
+	assert.Equal(t, "AUDIO:REPLY TO HELLO", out.String())
+
+	turn, err = voice.Turn(context.Background(), strings.NewReader("um"), &out)
+	require.NoError(t, err)
+	assert.Empty(t, turn.Transcript)
+	assert.Equal(t, 2, voice.Conversation.Len())
+}
+
+func TestVoiceChatBargeIn(t *testing.T) {
+	slow := make(chan struct{}, 1)
+	client := voiceServer(t, slow)
+	voice := NewVoiceChat(NewConversation(client, "chat-model"), "stt", "tts", "alloy")
+
+	utterances := make(chan io.Reader)
+	var out bytes.Buffer
+	errc := make(chan error, 1)
+	go func() { errc <- voice.Run(context.Background(), utterances, &out) }()
+
+	utterances <- strings.NewReader("slow")
+	// Wait for the first turn to reach the model before interrupting it
+	<-slow
+	utterances <- strings.NewReader("stop")
+	close(utterances)
+	require.NoError(t, <-errc)
+
+	assert.Equal(t, "audio:reply to stop", out.String())
+	messages := voice.Conversation.Messages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, "stop", messages[0].Content)
+}
@@ -0,0 +1,81 @@
+// Package vultraiotel exports the client's request spans to OpenTelemetry.
+// Pass the option to vultrai.NewClient with the application's
+// TracerProvider:
+//
+//	client := vultrai.NewClient(apiKey, vultraiotel.WithTracerProvider(otel.GetTracerProvider()))
+package vultraiotel
+
+import (
+	"context"
+	"fmt"
+
+	vultrai "github.com/eqba1/vultrai"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the spans
+const ScopeName = "github.com/eqba1/vultrai"
+
+// WithTracerProvider emits a client span through tp for every API request,
+// as described by vultrai.WithTracer
+func WithTracerProvider(tp trace.TracerProvider) vultrai.ClientOption {
+	return vultrai.WithTracer(NewTracer(tp))
+}
+
+// NewTracer returns a vultrai.Tracer starting spans from tp
+func NewTracer(tp trace.TracerProvider) vultrai.Tracer {
+	return tracer{tracer: tp.Tracer(ScopeName)}
+}
+
+type tracer struct {
+	tracer trace.Tracer
+}
+
+// Start starts a client span
+func (t tracer) Start(ctx context.Context, name string) (context.Context, vultrai.Span) {
+	ctx, s := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+	return ctx, span{span: s}
+}
+
+type span struct {
+	span trace.Span
+}
+
+// SetAttributes converts attrs to OpenTelemetry attributes
+func (s span) SetAttributes(attrs ...vultrai.Attribute) {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		kvs = append(kvs, keyValue(attr))
+	}
+	s.span.SetAttributes(kvs...)
+}
+
+// RecordError records err as an exception event and marks the span failed
+func (s span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End ends the span
+func (s span) End() {
+	s.span.End()
+}
+
+func keyValue(attr vultrai.Attribute) attribute.KeyValue {
+	switch v := attr.Value.(type) {
+	case string:
+		return attribute.String(attr.Key, v)
+	case int:
+		return attribute.Int(attr.Key, v)
+	case int64:
+		return attribute.Int64(attr.Key, v)
+	case bool:
+		return attribute.Bool(attr.Key, v)
+	case float64:
+		return attribute.Float64(attr.Key, v)
+	default:
+		return attribute.String(attr.Key, fmt.Sprint(v))
+	}
+}
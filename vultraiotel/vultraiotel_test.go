@@ -0,0 +1,65 @@
+package vultraiotel
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWithTracerProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(vultrai.ChatCompletionResponse{
+			Choices: []vultrai.Choice{{Message: vultrai.CreateAssistantMessage("hi")}},
+			Usage:   vultrai.Usage{PromptTokens: 10, CompletionTokens: 5},
+		})
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := vultrai.NewClient("key", vultrai.WithBaseURL(server.URL), WithTracerProvider(tp))
+
+	_, err := client.CreateChatCompletion(context.Background(), vultrai.ChatCompletionRequest{Model: "llama"})
+	require.NoError(t, err)
+	_, err = client.GetItem(context.Background(), "col-1", "item-2")
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	chat := spans[0]
+	assert.Equal(t, "POST /chat/completions", chat.Name())
+	assert.Equal(t, trace.SpanKindClient, chat.SpanKind())
+	assert.Equal(t, ScopeName, chat.InstrumentationScope().Name)
+	assert.Equal(t, codes.Unset, chat.Status().Code)
+	attrs := attribute.NewSet(chat.Attributes()...)
+	model, _ := attrs.Value(vultrai.AttrModel)
+	assert.Equal(t, "llama", model.AsString())
+	status, _ := attrs.Value(vultrai.AttrStatusCode)
+	assert.Equal(t, int64(200), status.AsInt64())
+	tokens, _ := attrs.Value(vultrai.AttrOutputTokens)
+	assert.Equal(t, int64(5), tokens.AsInt64())
+	streaming, _ := attrs.Value(vultrai.AttrStreaming)
+	assert.False(t, streaming.AsBool())
+
+	item := spans[1]
+	assert.Equal(t, "GET /vector-stores/collections/{id}/items/{id}", item.Name())
+	assert.Equal(t, codes.Error, item.Status().Code)
+	require.Len(t, item.Events(), 1)
+	assert.Equal(t, "exception", item.Events()[0].Name)
+}
@@ -0,0 +1,126 @@
+// Package vultraiprom collects client metrics with the Prometheus client
+// library. Pass a Collector to vultrai.WithMetrics and register it like any
+// other collector:
+//
+//	metrics := vultraiprom.NewCollector()
+//	prometheus.MustRegister(metrics)
+//	client := vultrai.NewClient(apiKey, vultrai.WithMetrics(metrics))
+//	http.Handle("/metrics", promhttp.Handler())
+package vultraiprom
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultBuckets are the request duration buckets in seconds, sized for
+// completions that take from a fraction of a second to minutes
+var DefaultBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// Option configures a Collector
+type Option func(*Collector)
+
+// WithNamespace sets the prefix of the metric names (default "vultrai")
+func WithNamespace(namespace string) Option {
+	return func(c *Collector) {
+		c.namespace = namespace
+	}
+}
+
+// WithBuckets sets the upper bounds of the request duration histogram in
+// seconds
+func WithBuckets(buckets []float64) Option {
+	return func(c *Collector) {
+		c.buckets = append([]float64(nil), buckets...)
+		sort.Float64s(c.buckets)
+	}
+}
+
+// Collector is a vultrai.MetricsCollector and a prometheus.Collector
+// gathering:
+//
+//	<namespace>_requests_total{endpoint,model,status}
+//	<namespace>_request_errors_total{endpoint,model}
+//	<namespace>_request_duration_seconds{endpoint,model}
+//	<namespace>_tokens_total{endpoint,model,type="input"|"output"}
+//
+// Requests that received no response have status "0" and count as errors,
+// as do statuses of 400 and above.
+type Collector struct {
+	namespace string
+	buckets   []float64
+
+	requests  *prometheus.CounterVec
+	errors    *prometheus.CounterVec
+	durations *prometheus.HistogramVec
+	tokens    *prometheus.CounterVec
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector creates a collector
+func NewCollector(options ...Option) *Collector {
+	c := &Collector{namespace: "vultrai", buckets: DefaultBuckets}
+	for _, option := range options {
+		option(c)
+	}
+
+	c.requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: c.namespace,
+		Name:      "requests_total",
+		Help:      "API requests by endpoint, model and HTTP status.",
+	}, []string{"endpoint", "model", "status"})
+	c.errors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: c.namespace,
+		Name:      "request_errors_total",
+		Help:      "API requests that failed or received an error status.",
+	}, []string{"endpoint", "model"})
+	c.durations = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: c.namespace,
+		Name:      "request_duration_seconds",
+		Help:      "Duration of API requests, including streamed bodies.",
+		Buckets:   c.buckets,
+	}, []string{"endpoint", "model"})
+	c.tokens = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: c.namespace,
+		Name:      "tokens_total",
+		Help:      "Tokens reported by the API by type.",
+	}, []string{"endpoint", "model", "type"})
+	return c
+}
+
+// ObserveRequest records a request
+func (c *Collector) ObserveRequest(endpoint, model string, status int, duration time.Duration, usage vultrai.Usage) {
+	c.requests.WithLabelValues(endpoint, model, strconv.Itoa(status)).Inc()
+	if status == 0 || status >= 400 {
+		c.errors.WithLabelValues(endpoint, model).Inc()
+	}
+	c.durations.WithLabelValues(endpoint, model).Observe(duration.Seconds())
+
+	if usage.PromptTokens > 0 {
+		c.tokens.WithLabelValues(endpoint, model, "input").Add(float64(usage.PromptTokens))
+	}
+	if usage.CompletionTokens > 0 {
+		c.tokens.WithLabelValues(endpoint, model, "output").Add(float64(usage.CompletionTokens))
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.errors.Describe(ch)
+	c.durations.Describe(ch)
+	c.tokens.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.errors.Collect(ch)
+	c.durations.Collect(ch)
+	c.tokens.Collect(ch)
+}
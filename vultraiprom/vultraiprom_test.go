@@ -0,0 +1,80 @@
+package vultraiprom
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrape registers c on a new registry and returns its metrics in the text
+// format
+func scrape(t *testing.T, c *Collector) string {
+	t.Helper()
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(c))
+
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	return rec.Body.String()
+}
+
+func TestCollector(t *testing.T) {
+	c := NewCollector(WithBuckets([]float64{1, 0.5}))
+	c.ObserveRequest("/chat/completions", "llama", 200, 250*time.Millisecond, vultrai.Usage{PromptTokens: 10, CompletionTokens: 4})
+	c.ObserveRequest("/chat/completions", "llama", 200, 750*time.Millisecond, vultrai.Usage{PromptTokens: 5, CompletionTokens: 2})
+	c.ObserveRequest("/chat/completions", "llama", 429, 500*time.Millisecond, vultrai.Usage{})
+	c.ObserveRequest("/usage", "", 0, 2*time.Second, vultrai.Usage{})
+
+	body := scrape(t, c)
+	for _, line := range []string{
+		"# TYPE vultrai_requests_total counter",
+		`vultrai_requests_total{endpoint="/chat/completions",model="llama",status="200"} 2`,
+		`vultrai_requests_total{endpoint="/chat/completions",model="llama",status="429"} 1`,
+		`vultrai_requests_total{endpoint="/usage",model="",status="0"} 1`,
+		`vultrai_request_errors_total{endpoint="/chat/completions",model="llama"} 1`,
+		`vultrai_request_errors_total{endpoint="/usage",model=""} 1`,
+		"# TYPE vultrai_request_duration_seconds histogram",
+		`vultrai_request_duration_seconds_bucket{endpoint="/chat/completions",model="llama",le="0.5"} 2`,
+		`vultrai_request_duration_seconds_bucket{endpoint="/chat/completions",model="llama",le="1"} 3`,
+		`vultrai_request_duration_seconds_bucket{endpoint="/chat/completions",model="llama",le="+Inf"} 3`,
+		`vultrai_request_duration_seconds_sum{endpoint="/chat/completions",model="llama"} 1.5`,
+		`vultrai_request_duration_seconds_count{endpoint="/usage",model=""} 1`,
+		`vultrai_tokens_total{endpoint="/chat/completions",model="llama",type="input"} 15`,
+		`vultrai_tokens_total{endpoint="/chat/completions",model="llama",type="output"} 6`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+	assert.NotContains(t, body, `endpoint="/usage",model="",type=`)
+}
+
+func TestCollectorLabelEscaping(t *testing.T) {
+	c := NewCollector(WithNamespace("app"))
+	c.ObserveRequest("/x", "a\"b\\c\nd", 200, time.Millisecond, vultrai.Usage{})
+
+	assert.Contains(t, scrape(t, c), `app_requests_total{endpoint="/x",model="a\"b\\c\nd",status="200"} 1`)
+}
+
+func TestCollectorWithClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(vultrai.ChatCompletionResponse{Usage: vultrai.Usage{PromptTokens: 7, CompletionTokens: 3}})
+	}))
+	defer server.Close()
+
+	metrics := NewCollector()
+	client := vultrai.NewClient("key", vultrai.WithBaseURL(server.URL), vultrai.WithMetrics(metrics))
+	_, err := client.CreateChatCompletion(context.Background(), vultrai.ChatCompletionRequest{Model: "llama"})
+	require.NoError(t, err)
+
+	body := scrape(t, metrics)
+	assert.Contains(t, body, `vultrai_requests_total{endpoint="/chat/completions",model="llama",status="200"} 1`)
+	assert.Contains(t, body, `vultrai_tokens_total{endpoint="/chat/completions",model="llama",type="input"} 7`)
+}
@@ -0,0 +1,131 @@
+package vultraitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	vultrai "github.com/eqba1/vultrai"
+)
+
+// Fixture defaults shared by all builders
+const (
+	FixtureID      = "chatcmpl-test"
+	FixtureModel   = "test-model"
+	FixtureCreated = int64(1640995200)
+	FixtureTime    = "2024-01-01T00:00:00Z"
+)
+
+// ChatResponse returns a fully populated chat completion whose single choice
+// is an assistant message with the given content
+func ChatResponse(content string) *vultrai.ChatCompletionResponse {
+	promptTokens := 10
+	completionTokens := estimateTokens(content)
+
+	return &vultrai.ChatCompletionResponse{
+		ID:      FixtureID,
+		Created: FixtureCreated,
+		Model:   FixtureModel,
+		Choices: []vultrai.Choice{
+			{
+				Index:        0,
+				Message:      vultrai.CreateAssistantMessage(content),
+				FinishReason: "stop",
+			},
+		},
+		Usage: vultrai.Usage{
+			PromptTokens:     promptTokens,
+			CompletionTokens: completionTokens,
+			TotalTokens:      promptTokens + completionTokens,
+		},
+	}
+}
+
+// ToolCall describes a tool invocation for ToolCallResponse
+type ToolCall struct {
+	Name      string
+	Arguments interface{} // marshaled to JSON unless already a string
+}
+
+// ToolCallResponse returns a chat completion in which the assistant requests
+// the given tool calls
+func ToolCallResponse(calls ...ToolCall) *vultrai.ChatCompletionResponse {
+	resp := ChatResponse("")
+	resp.Choices[0].FinishReason = "tool_calls"
+
+	for i, call := range calls {
+		args, ok := call.Arguments.(string)
+		if !ok {
+			args = string(MustJSON(call.Arguments))
+		}
+
+		resp.Choices[0].Message.ToolCalls = append(resp.Choices[0].Message.ToolCalls, vultrai.ToolCall{
+			ID:   fmt.Sprintf("call_%d", i+1),
+			Type: "function",
+			Function: vultrai.Function{
+				Name:      call.Name,
+				Arguments: args,
+			},
+		})
+	}
+
+	return resp
+}
+
+// SearchResults returns a search response with one result per content string
+func SearchResults(contents ...string) *vultrai.SearchResponse {
+	resp := &vultrai.SearchResponse{
+		Results: make([]vultrai.SearchResult, 0, len(contents)),
+		Usage:   vultrai.Usage{PromptTokens: 5, TotalTokens: 5},
+	}
+
+	for i, content := range contents {
+		resp.Results = append(resp.Results, vultrai.SearchResult{
+			ID:      fmt.Sprintf("result-%d", i+1),
+			Created: FixtureTime,
+			Content: content,
+		})
+	}
+
+	return resp
+}
+
+// Collection returns a collection with the given name
+func Collection(name string) vultrai.VectorStoreCollection {
+	return vultrai.VectorStoreCollection{
+		ID:      "coll-" + strings.ReplaceAll(strings.ToLower(name), " ", "-"),
+		Name:    name,
+		Created: FixtureTime,
+	}
+}
+
+// Items returns collection items with the given contents
+func Items(contents ...string) *vultrai.ListItemsResponse {
+	resp := &vultrai.ListItemsResponse{Items: make([]vultrai.CollectionItem, 0, len(contents))}
+
+	for i, content := range contents {
+		resp.Items = append(resp.Items, vultrai.CollectionItem{
+			ID:          fmt.Sprintf("item-%d", i+1),
+			Created:     FixtureTime,
+			Description: fmt.Sprintf("Item %d", i+1),
+			Content:     content,
+		})
+	}
+
+	return resp
+}
+
+// MustJSON marshals v to JSON and panics on failure. It is meant for building
+// canned HTTP bodies in tests.
+func MustJSON(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("vultraitest: marshaling fixture: %v", err))
+	}
+	return data
+}
+
+// estimateTokens gives a rough token count for fixture usage figures
+func estimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
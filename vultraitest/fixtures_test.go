@@ -0,0 +1,53 @@
+package vultraitest
+
+import (
+	"encoding/json"
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatResponse(t *testing.T) {
+	resp := ChatResponse("Hello there")
+
+	assert.Equal(t, FixtureID, resp.ID)
+	assert.Equal(t, "assistant", resp.Choices[0].Message.Role)
+	assert.Equal(t, "Hello there", resp.Choices[0].Message.Content)
+	assert.Equal(t, resp.Usage.PromptTokens+resp.Usage.CompletionTokens, resp.Usage.TotalTokens)
+
+	var decoded vultrai.ChatCompletionResponse
+	require.NoError(t, json.Unmarshal(MustJSON(resp), &decoded))
+	assert.Equal(t, *resp, decoded)
+}
+
+func TestToolCallResponse(t *testing.T) {
+	resp := ToolCallResponse(
+		ToolCall{Name: "get_weather", Arguments: map[string]string{"city": "Paris"}},
+		ToolCall{Name: "noop", Arguments: "{}"},
+	)
+
+	calls := resp.Choices[0].Message.ToolCalls
+	require.Len(t, calls, 2)
+	assert.Equal(t, "tool_calls", resp.Choices[0].FinishReason)
+	assert.Equal(t, "call_1", calls[0].ID)
+	assert.Equal(t, "get_weather", calls[0].Function.Name)
+	assert.JSONEq(t, `{"city":"Paris"}`, calls[0].Function.Arguments)
+	assert.Equal(t, "{}", calls[1].Function.Arguments)
+}
+
+func TestSearchResults(t *testing.T) {
+	resp := SearchResults("first", "second")
+
+	require.Len(t, resp.Results, 2)
+	assert.Equal(t, "result-2", resp.Results[1].ID)
+	assert.Equal(t, "second", resp.Results[1].Content)
+}
+
+func TestItems(t *testing.T) {
+	resp := Items("a", "b", "c")
+	require.Len(t, resp.Items, 3)
+	assert.Equal(t, "item-3", resp.Items[2].ID)
+	assert.Equal(t, "coll-my-docs", Collection("My Docs").ID)
+}
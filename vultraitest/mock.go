@@ -0,0 +1,463 @@
+package vultraitest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	vultrai "github.com/eqba1/vultrai"
+)
+
+// ErrNotStubbed is returned by MockClient methods whose function field is nil
+var ErrNotStubbed = errors.New("vultraitest: method not stubbed")
+
+// Call records a single invocation of a MockClient method
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// MockClient is a hand-rolled test double for *vultrai.Client. Each method
+// delegates to the matching function field, so tests can stub behavior per
+// case without HTTP-level mocking. Methods whose field is nil return
+// ErrNotStubbed. Convenience helpers such as SimpleChatCompletion are routed
+// through the core endpoint functions, just like on the real client.
+type MockClient struct {
+	CreateChatCompletionFunc          func(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.ChatCompletionResponse, error)
+	CreateChatCompletionStreamFunc    func(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.StreamReader, error)
+	CreateRAGChatCompletionFunc       func(ctx context.Context, req vultrai.RAGChatCompletionRequest) (*vultrai.ChatCompletionResponse, error)
+	CreateRAGChatCompletionStreamFunc func(ctx context.Context, req vultrai.RAGChatCompletionRequest) (*vultrai.StreamReader, error)
+	CreateEmbeddingsFunc              func(ctx context.Context, req vultrai.EmbeddingRequest) (*vultrai.EmbeddingResponse, error)
+	CreateSpeechFunc                  func(ctx context.Context, req vultrai.TTSRequest) ([]byte, error)
+	CreateTranscriptionFunc           func(ctx context.Context, req vultrai.TranscriptionRequest) (*vultrai.TranscriptionResponse, error)
+	GenerateImageFunc                 func(ctx context.Context, req vultrai.ImageGenerationRequest) (*vultrai.ImageGenerationResponse, error)
+	CreateCollectionFunc              func(ctx context.Context, req vultrai.CreateCollectionRequest) (*vultrai.CreateCollectionResponse, error)
+	ListCollectionsFunc               func(ctx context.Context) (*vultrai.ListCollectionsResponse, error)
+	UpdateCollectionFunc              func(ctx context.Context, id string, req vultrai.UpdateCollectionRequest) (*vultrai.UpdateCollectionResponse, error)
+	DeleteCollectionFunc              func(ctx context.Context, id string) error
+	SearchCollectionFunc              func(ctx context.Context, id string, req vultrai.SearchRequest) (*vultrai.SearchResponse, error)
+	ListItemsFunc                     func(ctx context.Context, collectionID string) (*vultrai.ListItemsResponse, error)
+	AddItemFunc                       func(ctx context.Context, collectionID string, req vultrai.AddItemRequest) (*vultrai.AddItemResponse, error)
+	GetItemFunc                       func(ctx context.Context, collectionID, itemID string) (*vultrai.GetItemResponse, error)
+	UpdateItemFunc                    func(ctx context.Context, collectionID, itemID string, req vultrai.UpdateItemRequest) (*vultrai.UpdateItemResponse, error)
+	DeleteItemFunc                    func(ctx context.Context, collectionID, itemID string) error
+	ListFilesFunc                     func(ctx context.Context, collectionID string) (*vultrai.ListFilesResponse, error)
+	AddFileFunc                       func(ctx context.Context, collectionID string, file io.Reader, filename string) (*vultrai.AddFileResponse, error)
+	GetFileFunc                       func(ctx context.Context, collectionID, fileID string) (*vultrai.GetFileResponse, error)
+	DeleteFileFunc                    func(ctx context.Context, collectionID, fileID string) error
+	CreateBatchFunc                   func(ctx context.Context, req vultrai.CreateBatchRequest) (*vultrai.BatchResponse, error)
+	GetBatchFunc                      func(ctx context.Context, batchID string) (*vultrai.BatchResponse, error)
+	ListBatchesFunc                   func(ctx context.Context) (*vultrai.ListBatchesResponse, error)
+	DownloadBatchResultsFunc          func(ctx context.Context, batchID string) ([]vultrai.BatchResult, error)
+	GetUsageFunc                      func(ctx context.Context) (*vultrai.UsageResponse, error)
+	GetAccountFunc                    func(ctx context.Context) (*vultrai.AccountResponse, error)
+	ListModelsFunc                    func(ctx context.Context) (*vultrai.ListModelsResponse, error)
+	GetRequestLogsFunc                func(ctx context.Context, req vultrai.RequestLogsRequest) (*vultrai.RequestLogsResponse, error)
+	DoFunc                            func(ctx context.Context, method, path string, in, out interface{}, options ...vultrai.RequestOption) error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ vultrai.API = (*MockClient)(nil)
+
+func (m *MockClient) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns every recorded invocation in order
+func (m *MockClient) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallCount returns how many times method was invoked
+func (m *MockClient) CallCount(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := 0
+	for _, call := range m.calls {
+		if call.Method == method {
+			count++
+		}
+	}
+	return count
+}
+
+func notStubbed(method string) error {
+	return fmt.Errorf("%w: %s", ErrNotStubbed, method)
+}
+
+// CreateChatCompletion calls CreateChatCompletionFunc
+func (m *MockClient) CreateChatCompletion(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.ChatCompletionResponse, error) {
+	m.record("CreateChatCompletion", req)
+	if m.CreateChatCompletionFunc == nil {
+		return nil, notStubbed("CreateChatCompletion")
+	}
+	return m.CreateChatCompletionFunc(ctx, req)
+}
+
+// CreateChatCompletionStream calls CreateChatCompletionStreamFunc
+func (m *MockClient) CreateChatCompletionStream(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.StreamReader, error) {
+	m.record("CreateChatCompletionStream", req)
+	if m.CreateChatCompletionStreamFunc == nil {
+		return nil, notStubbed("CreateChatCompletionStream")
+	}
+	return m.CreateChatCompletionStreamFunc(ctx, req)
+}
+
+// CreateRAGChatCompletion calls CreateRAGChatCompletionFunc
+func (m *MockClient) CreateRAGChatCompletion(ctx context.Context, req vultrai.RAGChatCompletionRequest) (*vultrai.ChatCompletionResponse, error) {
+	m.record("CreateRAGChatCompletion", req)
+	if m.CreateRAGChatCompletionFunc == nil {
+		return nil, notStubbed("CreateRAGChatCompletion")
+	}
+	return m.CreateRAGChatCompletionFunc(ctx, req)
+}
+
+// CreateRAGChatCompletionStream calls CreateRAGChatCompletionStreamFunc
+func (m *MockClient) CreateRAGChatCompletionStream(ctx context.Context, req vultrai.RAGChatCompletionRequest) (*vultrai.StreamReader, error) {
+	m.record("CreateRAGChatCompletionStream", req)
+	if m.CreateRAGChatCompletionStreamFunc == nil {
+		return nil, notStubbed("CreateRAGChatCompletionStream")
+	}
+	return m.CreateRAGChatCompletionStreamFunc(ctx, req)
+}
+
+// StreamChatCompletion reads the stream from CreateChatCompletionStreamFunc
+// and passes each chunk to callback
+func (m *MockClient) StreamChatCompletion(ctx context.Context, req vultrai.ChatCompletionRequest, callback vultrai.StreamCallback, options ...vultrai.StreamOption) error {
+	stream, err := m.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return err
+	}
+	return vultrai.ConsumeStream(stream, callback, options...)
+}
+
+// StreamRAGChatCompletion reads the stream from
+// CreateRAGChatCompletionStreamFunc and passes each chunk to callback
+func (m *MockClient) StreamRAGChatCompletion(ctx context.Context, req vultrai.RAGChatCompletionRequest, callback vultrai.StreamCallback, options ...vultrai.StreamOption) error {
+	stream, err := m.CreateRAGChatCompletionStream(ctx, req)
+	if err != nil {
+		return err
+	}
+	return vultrai.ConsumeStream(stream, callback, options...)
+}
+
+// SimpleChatCompletion builds a single-message request and calls CreateChatCompletion
+func (m *MockClient) SimpleChatCompletion(ctx context.Context, model, prompt string) (*vultrai.ChatCompletionResponse, error) {
+	return m.CreateChatCompletion(ctx, vultrai.ChatCompletionRequest{
+		Model:    model,
+		Messages: []vultrai.Message{vultrai.CreateUserMessage(prompt)},
+	})
+}
+
+// ChatWithMessages applies options and calls CreateChatCompletion
+func (m *MockClient) ChatWithMessages(ctx context.Context, model string, messages []vultrai.Message, options ...vultrai.ChatOption) (*vultrai.ChatCompletionResponse, error) {
+	req := vultrai.ChatCompletionRequest{
+		Model:    model,
+		Messages: messages,
+	}
+	for _, option := range options {
+		option(&req)
+	}
+	return m.CreateChatCompletion(ctx, req)
+}
+
+// CreateEmbeddings calls CreateEmbeddingsFunc
+func (m *MockClient) CreateEmbeddings(ctx context.Context, req vultrai.EmbeddingRequest) (*vultrai.EmbeddingResponse, error) {
+	m.record("CreateEmbeddings", req)
+	if m.CreateEmbeddingsFunc == nil {
+		return nil, notStubbed("CreateEmbeddings")
+	}
+	return m.CreateEmbeddingsFunc(ctx, req)
+}
+
+// CreateSpeech calls CreateSpeechFunc
+func (m *MockClient) CreateSpeech(ctx context.Context, req vultrai.TTSRequest) ([]byte, error) {
+	m.record("CreateSpeech", req)
+	if m.CreateSpeechFunc == nil {
+		return nil, notStubbed("CreateSpeech")
+	}
+	return m.CreateSpeechFunc(ctx, req)
+}
+
+// SimpleSpeech builds a request with the default model, voice and format
+// and calls CreateSpeech
+func (m *MockClient) SimpleSpeech(ctx context.Context, text string, options ...vultrai.TTSOption) ([]byte, error) {
+	req := vultrai.TTSRequest{
+		Model:          vultrai.DefaultTTSModel,
+		Input:          text,
+		Voice:          vultrai.DefaultTTSVoice,
+		ResponseFormat: vultrai.DefaultTTSFormat,
+	}
+	for _, option := range options {
+		option(&req)
+	}
+	return m.CreateSpeech(ctx, req)
+}
+
+// OpenSpeech calls CreateSpeech and returns a reader over the audio
+func (m *MockClient) OpenSpeech(ctx context.Context, req vultrai.TTSRequest) (io.ReadCloser, error) {
+	audio, err := m.CreateSpeech(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(audio)), nil
+}
+
+// CreateSpeechStream calls CreateSpeech and writes the audio to w
+func (m *MockClient) CreateSpeechStream(ctx context.Context, req vultrai.TTSRequest, w io.Writer) (int64, error) {
+	audio, err := m.CreateSpeech(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(audio)
+	return int64(n), err
+}
+
+// CreateTranscription calls CreateTranscriptionFunc
+func (m *MockClient) CreateTranscription(ctx context.Context, req vultrai.TranscriptionRequest) (*vultrai.TranscriptionResponse, error) {
+	m.record("CreateTranscription", req)
+	if m.CreateTranscriptionFunc == nil {
+		return nil, notStubbed("CreateTranscription")
+	}
+	return m.CreateTranscriptionFunc(ctx, req)
+}
+
+// GenerateImage calls GenerateImageFunc
+func (m *MockClient) GenerateImage(ctx context.Context, req vultrai.ImageGenerationRequest) (*vultrai.ImageGenerationResponse, error) {
+	m.record("GenerateImage", req)
+	if m.GenerateImageFunc == nil {
+		return nil, notStubbed("GenerateImage")
+	}
+	return m.GenerateImageFunc(ctx, req)
+}
+
+// SimpleImageGeneration builds a prompt-only request and calls GenerateImage
+func (m *MockClient) SimpleImageGeneration(ctx context.Context, prompt string) (*vultrai.ImageGenerationResponse, error) {
+	return m.GenerateImage(ctx, vultrai.ImageGenerationRequest{Prompt: prompt})
+}
+
+// GenerateImageWithOptions applies options and calls GenerateImage
+func (m *MockClient) GenerateImageWithOptions(ctx context.Context, prompt string, options ...vultrai.ImageOption) (*vultrai.ImageGenerationResponse, error) {
+	req := vultrai.ImageGenerationRequest{Prompt: prompt}
+	for _, option := range options {
+		option(&req)
+	}
+	return m.GenerateImage(ctx, req)
+}
+
+// CreateCollection calls CreateCollectionFunc
+func (m *MockClient) CreateCollection(ctx context.Context, req vultrai.CreateCollectionRequest) (*vultrai.CreateCollectionResponse, error) {
+	m.record("CreateCollection", req)
+	if m.CreateCollectionFunc == nil {
+		return nil, notStubbed("CreateCollection")
+	}
+	return m.CreateCollectionFunc(ctx, req)
+}
+
+// ListCollections calls ListCollectionsFunc
+func (m *MockClient) ListCollections(ctx context.Context) (*vultrai.ListCollectionsResponse, error) {
+	m.record("ListCollections")
+	if m.ListCollectionsFunc == nil {
+		return nil, notStubbed("ListCollections")
+	}
+	return m.ListCollectionsFunc(ctx)
+}
+
+// UpdateCollection calls UpdateCollectionFunc
+func (m *MockClient) UpdateCollection(ctx context.Context, id string, req vultrai.UpdateCollectionRequest) (*vultrai.UpdateCollectionResponse, error) {
+	m.record("UpdateCollection", id, req)
+	if m.UpdateCollectionFunc == nil {
+		return nil, notStubbed("UpdateCollection")
+	}
+	return m.UpdateCollectionFunc(ctx, id, req)
+}
+
+// DeleteCollection calls DeleteCollectionFunc
+func (m *MockClient) DeleteCollection(ctx context.Context, id string) error {
+	m.record("DeleteCollection", id)
+	if m.DeleteCollectionFunc == nil {
+		return notStubbed("DeleteCollection")
+	}
+	return m.DeleteCollectionFunc(ctx, id)
+}
+
+// SearchCollection calls SearchCollectionFunc
+func (m *MockClient) SearchCollection(ctx context.Context, id string, req vultrai.SearchRequest) (*vultrai.SearchResponse, error) {
+	m.record("SearchCollection", id, req)
+	if m.SearchCollectionFunc == nil {
+		return nil, notStubbed("SearchCollection")
+	}
+	return m.SearchCollectionFunc(ctx, id, req)
+}
+
+// ListItems calls ListItemsFunc
+func (m *MockClient) ListItems(ctx context.Context, collectionID string) (*vultrai.ListItemsResponse, error) {
+	m.record("ListItems", collectionID)
+	if m.ListItemsFunc == nil {
+		return nil, notStubbed("ListItems")
+	}
+	return m.ListItemsFunc(ctx, collectionID)
+}
+
+// AddItem calls AddItemFunc
+func (m *MockClient) AddItem(ctx context.Context, collectionID string, req vultrai.AddItemRequest) (*vultrai.AddItemResponse, error) {
+	m.record("AddItem", collectionID, req)
+	if m.AddItemFunc == nil {
+		return nil, notStubbed("AddItem")
+	}
+	return m.AddItemFunc(ctx, collectionID, req)
+}
+
+// GetItem calls GetItemFunc
+func (m *MockClient) GetItem(ctx context.Context, collectionID, itemID string) (*vultrai.GetItemResponse, error) {
+	m.record("GetItem", collectionID, itemID)
+	if m.GetItemFunc == nil {
+		return nil, notStubbed("GetItem")
+	}
+	return m.GetItemFunc(ctx, collectionID, itemID)
+}
+
+// UpdateItem calls UpdateItemFunc
+func (m *MockClient) UpdateItem(ctx context.Context, collectionID, itemID string, req vultrai.UpdateItemRequest) (*vultrai.UpdateItemResponse, error) {
+	m.record("UpdateItem", collectionID, itemID, req)
+	if m.UpdateItemFunc == nil {
+		return nil, notStubbed("UpdateItem")
+	}
+	return m.UpdateItemFunc(ctx, collectionID, itemID, req)
+}
+
+// DeleteItem calls DeleteItemFunc
+func (m *MockClient) DeleteItem(ctx context.Context, collectionID, itemID string) error {
+	m.record("DeleteItem", collectionID, itemID)
+	if m.DeleteItemFunc == nil {
+		return notStubbed("DeleteItem")
+	}
+	return m.DeleteItemFunc(ctx, collectionID, itemID)
+}
+
+// ListFiles calls ListFilesFunc
+func (m *MockClient) ListFiles(ctx context.Context, collectionID string) (*vultrai.ListFilesResponse, error) {
+	m.record("ListFiles", collectionID)
+	if m.ListFilesFunc == nil {
+		return nil, notStubbed("ListFiles")
+	}
+	return m.ListFilesFunc(ctx, collectionID)
+}
+
+// AddFile calls AddFileFunc
+func (m *MockClient) AddFile(ctx context.Context, collectionID string, file io.Reader, filename string) (*vultrai.AddFileResponse, error) {
+	m.record("AddFile", collectionID, filename)
+	if m.AddFileFunc == nil {
+		return nil, notStubbed("AddFile")
+	}
+	return m.AddFileFunc(ctx, collectionID, file, filename)
+}
+
+// GetFile calls GetFileFunc
+func (m *MockClient) GetFile(ctx context.Context, collectionID, fileID string) (*vultrai.GetFileResponse, error) {
+	m.record("GetFile", collectionID, fileID)
+	if m.GetFileFunc == nil {
+		return nil, notStubbed("GetFile")
+	}
+	return m.GetFileFunc(ctx, collectionID, fileID)
+}
+
+// DeleteFile calls DeleteFileFunc
+func (m *MockClient) DeleteFile(ctx context.Context, collectionID, fileID string) error {
+	m.record("DeleteFile", collectionID, fileID)
+	if m.DeleteFileFunc == nil {
+		return notStubbed("DeleteFile")
+	}
+	return m.DeleteFileFunc(ctx, collectionID, fileID)
+}
+
+// CreateBatch calls CreateBatchFunc
+func (m *MockClient) CreateBatch(ctx context.Context, req vultrai.CreateBatchRequest) (*vultrai.BatchResponse, error) {
+	m.record("CreateBatch", req.Filename)
+	if m.CreateBatchFunc == nil {
+		return nil, notStubbed("CreateBatch")
+	}
+	return m.CreateBatchFunc(ctx, req)
+}
+
+// GetBatch calls GetBatchFunc
+func (m *MockClient) GetBatch(ctx context.Context, batchID string) (*vultrai.BatchResponse, error) {
+	m.record("GetBatch", batchID)
+	if m.GetBatchFunc == nil {
+		return nil, notStubbed("GetBatch")
+	}
+	return m.GetBatchFunc(ctx, batchID)
+}
+
+// ListBatches calls ListBatchesFunc
+func (m *MockClient) ListBatches(ctx context.Context) (*vultrai.ListBatchesResponse, error) {
+	m.record("ListBatches")
+	if m.ListBatchesFunc == nil {
+		return nil, notStubbed("ListBatches")
+	}
+	return m.ListBatchesFunc(ctx)
+}
+
+// DownloadBatchResults calls DownloadBatchResultsFunc
+func (m *MockClient) DownloadBatchResults(ctx context.Context, batchID string) ([]vultrai.BatchResult, error) {
+	m.record("DownloadBatchResults", batchID)
+	if m.DownloadBatchResultsFunc == nil {
+		return nil, notStubbed("DownloadBatchResults")
+	}
+	return m.DownloadBatchResultsFunc(ctx, batchID)
+}
+
+// GetUsage calls GetUsageFunc
+func (m *MockClient) GetUsage(ctx context.Context) (*vultrai.UsageResponse, error) {
+	m.record("GetUsage")
+	if m.GetUsageFunc == nil {
+		return nil, notStubbed("GetUsage")
+	}
+	return m.GetUsageFunc(ctx)
+}
+
+// GetAccount calls GetAccountFunc
+func (m *MockClient) GetAccount(ctx context.Context) (*vultrai.AccountResponse, error) {
+	m.record("GetAccount")
+	if m.GetAccountFunc == nil {
+		return nil, notStubbed("GetAccount")
+	}
+	return m.GetAccountFunc(ctx)
+}
+
+// ListModels calls ListModelsFunc
+func (m *MockClient) ListModels(ctx context.Context) (*vultrai.ListModelsResponse, error) {
+	m.record("ListModels")
+	if m.ListModelsFunc == nil {
+		return nil, notStubbed("ListModels")
+	}
+	return m.ListModelsFunc(ctx)
+}
+
+// GetRequestLogs calls GetRequestLogsFunc
+func (m *MockClient) GetRequestLogs(ctx context.Context, req vultrai.RequestLogsRequest) (*vultrai.RequestLogsResponse, error) {
+	m.record("GetRequestLogs", req)
+	if m.GetRequestLogsFunc == nil {
+		return nil, notStubbed("GetRequestLogs")
+	}
+	return m.GetRequestLogsFunc(ctx, req)
+}
+
+// Do calls DoFunc
+func (m *MockClient) Do(ctx context.Context, method, path string, in, out interface{}, options ...vultrai.RequestOption) error {
+	m.record("Do", method, path, in)
+	if m.DoFunc == nil {
+		return notStubbed("Do")
+	}
+	return m.DoFunc(ctx, method, path, in, out, options...)
+}
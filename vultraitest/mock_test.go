@@ -0,0 +1,50 @@
+package vultraitest
+
+import (
+	"context"
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockClientStubbed(t *testing.T) {
+	mock := &MockClient{
+		CreateChatCompletionFunc: func(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.ChatCompletionResponse, error) {
+			return ChatResponse("echo: " + req.Messages[0].Content), nil
+		},
+	}
+
+	resp, err := mock.SimpleChatCompletion(context.Background(), "test-model", "hi")
+	require.NoError(t, err)
+	assert.Equal(t, "echo: hi", resp.Choices[0].Message.Content)
+	assert.Equal(t, 1, mock.CallCount("CreateChatCompletion"))
+
+	calls := mock.Calls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "test-model", calls[0].Args[0].(vultrai.ChatCompletionRequest).Model)
+}
+
+func TestMockClientNotStubbed(t *testing.T) {
+	mock := &MockClient{}
+
+	_, err := mock.GetUsage(context.Background())
+	assert.ErrorIs(t, err, ErrNotStubbed)
+}
+
+func TestMockClientStream(t *testing.T) {
+	mock := &MockClient{
+		CreateChatCompletionStreamFunc: func(ctx context.Context, req vultrai.ChatCompletionRequest) (*vultrai.StreamReader, error) {
+			return vultrai.NewStreamReader(NewStream("streamed reply").Reader()), nil
+		},
+	}
+
+	var chunks []*vultrai.StreamChatCompletion
+	err := mock.StreamChatCompletion(context.Background(), vultrai.ChatCompletionRequest{}, func(chunk *vultrai.StreamChatCompletion) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "streamed reply", vultrai.AccumulateStreamContent(chunks))
+}
@@ -0,0 +1,206 @@
+package vultraitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether a Recorder talks to the API
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette and fails requests it
+	// has no recording for
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the API and records the interactions,
+	// replacing the cassette on Save
+	ModeRecord
+	// ModeAuto replays when the cassette exists and records otherwise
+	ModeAuto
+)
+
+// ErrNoRecording is returned in replay mode for requests missing from the
+// cassette
+var ErrNoRecording = errors.New("vultraitest: no recorded interaction")
+
+// Interaction is a recorded request and its response. Request headers are
+// not recorded, so API keys never reach the cassette.
+type Interaction struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Body    string      `json:"body,omitempty"`
+	Status  int         `json:"status"`
+	Header  http.Header `json:"header,omitempty"`
+	Content string      `json:"content"`
+
+	used bool
+}
+
+// Recorder is an http.RoundTripper recording API interactions to a
+// cassette file and replaying them, so integration tests run without an
+// API key. Streams are recorded in full and replayed at once. URLs are
+// recorded without scheme and host, so a cassette replays against any host
+// with the same base path. Replay matches the method, URL and body of each
+// request against the interactions not yet used, in recording order.
+//
+//	rec, err := vultraitest.NewRecorder("testdata/chat.json", vultraitest.ModeAuto, nil)
+//	defer rec.Save()
+//	client := vultrai.NewClient(os.Getenv("VULTR_API_KEY"), vultrai.WithHTTPClient(rec.Client()))
+type Recorder struct {
+	path string
+	mode Mode
+	next http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []*Interaction
+}
+
+// NewRecorder creates a recorder for the cassette at path. Recording sends
+// requests with next, or http.DefaultTransport when nil.
+func NewRecorder(path string, mode Mode, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	r := &Recorder{path: path, mode: mode, next: next}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil && mode != ModeRecord:
+		r.mode = ModeReplay
+		if err := json.Unmarshal(data, &r.interactions); err != nil {
+			return nil, fmt.Errorf("error decoding cassette %s: %w", path, err)
+		}
+	case errors.Is(err, os.ErrNotExist) && mode == ModeAuto:
+		r.mode = ModeRecord
+	case err != nil && mode != ModeRecord:
+		return nil, fmt.Errorf("error reading cassette: %w", err)
+	}
+	return r, nil
+}
+
+// Mode returns whether the recorder replays or records
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an HTTP client using the recorder as transport
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip replays or records req
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, fmt.Errorf("error reading request body: %w", err)
+		}
+		req.Body.Close()
+	}
+	url := req.URL.RequestURI()
+
+	if r.mode == ModeReplay {
+		return r.replay(req, url, body)
+	}
+
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := r.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+	interaction := &Interaction{
+		Method: req.Method,
+		URL:    url,
+		Body:   string(body),
+		Status: resp.StatusCode,
+		Header: header,
+	}
+	resp.Body = &recordingBody{ReadCloser: resp.Body, done: func(content []byte) {
+		interaction.Content = string(content)
+		r.mu.Lock()
+		r.interactions = append(r.interactions, interaction)
+		r.mu.Unlock()
+	}}
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, url string, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, interaction := range r.interactions {
+		if interaction.used || interaction.Method != req.Method || interaction.URL != url || interaction.Body != string(body) {
+			continue
+		}
+		interaction.used = true
+		header := interaction.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			StatusCode: interaction.Status,
+			Status:     fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+			Header:     header,
+			Body:       io.NopCloser(strings.NewReader(interaction.Content)),
+			Request:    req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNoRecording, req.Method, url)
+}
+
+// Save writes the recorded interactions to the cassette. It does nothing
+// in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode == ModeReplay {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("error encoding cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("error creating cassette directory: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing cassette: %w", err)
+	}
+	return nil
+}
+
+// recordingBody keeps a copy of the response body and hands it over once
+// the body is closed, reading what the caller left unread
+type recordingBody struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	done func([]byte)
+	once sync.Once
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	io.Copy(&b.buf, b.ReadCloser)
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.done(b.buf.Bytes()) })
+	return err
+}
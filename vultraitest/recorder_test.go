@@ -0,0 +1,64 @@
+package vultraitest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "chat.json")
+	server := NewServer()
+	server.RespondOnce("POST", "/chat/completions", 200, ChatResponse("first"))
+	server.RespondOnce("POST", "/chat/completions", 200, ChatResponse("second"))
+	server.RespondStream("POST", "/chat/completions/rag", NewStream("streamed"))
+
+	rec, err := NewRecorder(path, ModeAuto, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ModeRecord, rec.Mode())
+
+	run := func(client *vultrai.Client) (string, string, string) {
+		first, err := client.SimpleChatCompletion(context.Background(), "m", "hi")
+		require.NoError(t, err)
+		second, err := client.SimpleChatCompletion(context.Background(), "m", "hi")
+		require.NoError(t, err)
+		streamed := ""
+		err = client.StreamRAGChatCompletion(context.Background(), vultrai.RAGChatCompletionRequest{Collection: "c", Model: "m"}, func(chunk *vultrai.StreamChatCompletion) error {
+			streamed += chunk.Choices[0].Delta.Content
+			return nil
+		})
+		require.NoError(t, err)
+		return first.Choices[0].Message.Content, second.Choices[0].Message.Content, streamed
+	}
+
+	client := vultrai.NewClient("secret-key", vultrai.WithBaseURL(server.URL), vultrai.WithHTTPClient(rec.Client()))
+	first, second, streamed := run(client)
+	assert.Equal(t, []string{"first", "second", "streamed"}, []string{first, second, streamed})
+	require.NoError(t, rec.Save())
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret-key")
+
+	rec, err = NewRecorder(path, ModeAuto, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ModeReplay, rec.Mode())
+
+	client = vultrai.NewClient("", vultrai.WithBaseURL("https://replay.invalid"), vultrai.WithHTTPClient(rec.Client()))
+	first, second, streamed = run(client)
+	assert.Equal(t, []string{"first", "second", "streamed"}, []string{first, second, streamed})
+
+	_, err = client.SimpleChatCompletion(context.Background(), "m", "hi")
+	assert.ErrorIs(t, err, ErrNoRecording)
+}
+
+func TestRecorderMissingCassette(t *testing.T) {
+	_, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,194 @@
+package vultraitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	vultrai "github.com/eqba1/vultrai"
+)
+
+// Request is a request received by a Server
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Decode unmarshals the JSON body of the request into v
+func (r Request) Decode(v interface{}) error {
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		return fmt.Errorf("error decoding request body: %w", err)
+	}
+	return nil
+}
+
+// Server is a fake Vultr Inference API listening on a local port. Routes
+// are matched on method and path; requests without a response get a 404
+// error naming the route, so missing stubs fail loudly. Every request is
+// captured and available from Requests.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]http.Handler
+	once     map[string][]http.Handler
+	requests []Request
+}
+
+// NewServer starts a fake API server. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		handlers: make(map[string]http.Handler),
+		once:     make(map[string][]http.Handler),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Client creates a client talking to the server
+func (s *Server) Client(options ...vultrai.ClientOption) *vultrai.Client {
+	options = append([]vultrai.ClientOption{vultrai.WithBaseURL(s.URL)}, options...)
+	return vultrai.NewClient("test-api-key", options...)
+}
+
+// Handle serves every request to method and path with handler
+func (s *Server) Handle(method, path string, handler http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method+" "+path] = handler
+}
+
+// HandleOnce serves the next request to method and path with handler.
+// Queued handlers take precedence over Handle and are used in order.
+func (s *Server) HandleOnce(method, path string, handler http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := method + " " + path
+	s.once[key] = append(s.once[key], handler)
+}
+
+// Respond answers every request to method and path with body encoded as
+// JSON
+func (s *Server) Respond(method, path string, status int, body interface{}) {
+	s.Handle(method, path, jsonHandler(status, body))
+}
+
+// RespondOnce answers the next request to method and path with body
+// encoded as JSON
+func (s *Server) RespondOnce(method, path string, status int, body interface{}) {
+	s.HandleOnce(method, path, jsonHandler(status, body))
+}
+
+// RespondError answers every request to method and path with an API error
+func (s *Server) RespondError(method, path string, status int, message, code string) {
+	s.Respond(method, path, status, vultrai.Error{Message: message, Code: code})
+}
+
+// RespondStream answers every request to method and path with the
+// server-sent events of stream, flushing each frame and honoring its delay.
+// An error injected with ErrorAfter aborts the connection.
+func (s *Server) RespondStream(method, path string, stream *StreamBuilder) {
+	s.Handle(method, path, streamHandler(stream))
+}
+
+// Requests returns the captured requests in order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// LastRequest returns the most recent request, or false when none was
+// received
+func (s *Server) LastRequest() (Request, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		return Request{}, false
+	}
+	return s.requests[len(s.requests)-1], true
+}
+
+// Reset drops all responses and captured requests
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = make(map[string]http.Handler)
+	s.once = make(map[string][]http.Handler)
+	s.requests = nil
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	key := r.Method + " " + r.URL.Path
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	handler := s.handlers[key]
+	if queued := s.once[key]; len(queued) > 0 {
+		handler, s.once[key] = queued[0], queued[1:]
+	}
+	s.mu.Unlock()
+
+	if handler == nil {
+		jsonHandler(http.StatusNotFound, vultrai.Error{
+			Message: "vultraitest: no response for " + key,
+			Code:    "not_found",
+		}).ServeHTTP(w, r)
+		return
+	}
+	handler.ServeHTTP(w, r)
+}
+
+func jsonHandler(status int, body interface{}) http.Handler {
+	data := MustJSON(body)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(data)
+	})
+}
+
+func streamHandler(stream *StreamBuilder) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		frames := stream.frames()
+		abort := stream.errAfter >= 0 && stream.errAfter < len(frames)
+		if abort {
+			frames = frames[:stream.errAfter]
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		flusher, _ := w.(http.Flusher)
+		for _, frame := range frames {
+			if stream.delay > 0 {
+				select {
+				case <-time.After(stream.delay):
+				case <-r.Context().Done():
+					return
+				}
+			}
+			w.Write(frame)
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if abort {
+			panic(http.ErrAbortHandler)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package vultraitest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerRespond(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.Respond("POST", "/chat/completions", 200, ChatResponse("hello"))
+	server.RespondOnce("POST", "/chat/completions", 200, ChatResponse("first"))
+
+	client := server.Client()
+	for _, want := range []string{"first", "hello", "hello"} {
+		resp, err := client.SimpleChatCompletion(context.Background(), "test-model", "hi")
+		require.NoError(t, err)
+		assert.Equal(t, want, resp.Choices[0].Message.Content)
+	}
+
+	requests := server.Requests()
+	require.Len(t, requests, 3)
+	assert.Equal(t, "/chat/completions", requests[0].Path)
+	assert.Equal(t, "Bearer test-api-key", requests[0].Header.Get("Authorization"))
+
+	var req vultrai.ChatCompletionRequest
+	require.NoError(t, requests[0].Decode(&req))
+	assert.Equal(t, "hi", req.Messages[0].Content)
+}
+
+func TestServerErrors(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.RespondError("GET", "/usage", 429, "slow down", "rate_limit_exceeded")
+
+	client := server.Client()
+	_, err := client.GetUsage(context.Background())
+	assert.True(t, vultrai.IsRateLimited(err))
+
+	_, err = client.ListModels(context.Background())
+	assert.True(t, vultrai.IsNotFound(err))
+	assert.Contains(t, err.Error(), "no response for GET /models")
+
+	last, ok := server.LastRequest()
+	require.True(t, ok)
+	assert.Equal(t, "/models", last.Path)
+
+	server.Reset()
+	assert.Empty(t, server.Requests())
+}
+
+func TestServerStream(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.RespondStream("POST", "/chat/completions", NewStream("streamed reply").ChunkSize(3))
+
+	client := server.Client()
+	content := ""
+	err := client.StreamChatCompletion(context.Background(), vultrai.ChatCompletionRequest{Model: "m"}, func(chunk *vultrai.StreamChatCompletion) error {
+		content += chunk.Choices[0].Delta.Content
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "streamed reply", content)
+
+	last, _ := server.LastRequest()
+	assert.Equal(t, "text/event-stream", last.Header.Get("Accept"))
+}
+
+func TestServerStreamError(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.RespondStream("POST", "/chat/completions", NewStream("streamed reply").ChunkSize(3).ErrorAfter(2, errors.New("boom")))
+
+	stream, err := server.Client().CreateChatCompletionStream(context.Background(), vultrai.ChatCompletionRequest{Model: "m"})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	for i := 0; i < 2; i++ {
+		_, err := stream.Recv()
+		require.NoError(t, err)
+	}
+	_, err = stream.Recv()
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, io.EOF)
+}
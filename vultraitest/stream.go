@@ -0,0 +1,208 @@
+// Package vultraitest provides utilities for testing code built on the
+// Vultr Inference SDK without talking to the real API.
+package vultraitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	vultrai "github.com/eqba1/vultrai"
+)
+
+// StreamBuilder generates valid chat completion SSE streams from a desired
+// final message. Chunking, delays, injected errors and malformed frames are
+// configurable so streaming consumers can be tested realistically.
+type StreamBuilder struct {
+	id             string
+	model          string
+	created        int64
+	content        string
+	chunkSize      int
+	delay          time.Duration
+	finishReason   string
+	errAfter       int
+	err            error
+	malformedAfter int
+	omitDone       bool
+}
+
+// NewStream creates a builder for a stream whose chunks add up to content
+func NewStream(content string) *StreamBuilder {
+	return &StreamBuilder{
+		id:             "chatcmpl-test",
+		model:          "test-model",
+		created:        1640995200,
+		content:        content,
+		chunkSize:      4,
+		finishReason:   "stop",
+		errAfter:       -1,
+		malformedAfter: -1,
+	}
+}
+
+// WithID sets the completion ID on every chunk
+func (b *StreamBuilder) WithID(id string) *StreamBuilder {
+	b.id = id
+	return b
+}
+
+// WithModel sets the model name on every chunk
+func (b *StreamBuilder) WithModel(model string) *StreamBuilder {
+	b.model = model
+	return b
+}
+
+// ChunkSize sets how many runes of content each chunk carries
+func (b *StreamBuilder) ChunkSize(runes int) *StreamBuilder {
+	if runes > 0 {
+		b.chunkSize = runes
+	}
+	return b
+}
+
+// Delay sets the pause before each frame is emitted by Reader
+func (b *StreamBuilder) Delay(d time.Duration) *StreamBuilder {
+	b.delay = d
+	return b
+}
+
+// FinishReason sets the finish reason reported on the last chunk
+func (b *StreamBuilder) FinishReason(reason string) *StreamBuilder {
+	b.finishReason = reason
+	return b
+}
+
+// ErrorAfter makes Reader fail with err after emitting n frames
+func (b *StreamBuilder) ErrorAfter(n int, err error) *StreamBuilder {
+	b.errAfter = n
+	b.err = err
+	return b
+}
+
+// MalformedAfter inserts a frame with invalid JSON after n chunks
+func (b *StreamBuilder) MalformedAfter(n int) *StreamBuilder {
+	b.malformedAfter = n
+	return b
+}
+
+// WithoutDone omits the terminating [DONE] frame
+func (b *StreamBuilder) WithoutDone() *StreamBuilder {
+	b.omitDone = true
+	return b
+}
+
+// Chunks returns the stream chunks that make up the final message
+func (b *StreamBuilder) Chunks() []*vultrai.StreamChatCompletion {
+	runes := []rune(b.content)
+
+	var chunks []*vultrai.StreamChatCompletion
+	for start := 0; start < len(runes) || len(chunks) == 0; start += b.chunkSize {
+		end := start + b.chunkSize
+		if end > len(runes) {
+			end = len(runes)
+		}
+
+		chunk := &vultrai.StreamChatCompletion{
+			ID:      b.id,
+			Created: b.created,
+			Model:   b.model,
+			Choices: []vultrai.StreamChoice{
+				{Index: 0, Delta: vultrai.StreamDelta{Content: string(runes[start:end])}},
+			},
+		}
+		if len(chunks) == 0 {
+			chunk.Choices[0].Delta.Role = "assistant"
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	if b.finishReason != "" {
+		reason := b.finishReason
+		chunks[len(chunks)-1].Choices[0].FinishReason = &reason
+	}
+
+	return chunks
+}
+
+// frames returns the raw SSE frames, including injected malformed frames
+func (b *StreamBuilder) frames() [][]byte {
+	var frames [][]byte
+	for i, chunk := range b.Chunks() {
+		if i == b.malformedAfter {
+			frames = append(frames, []byte("data: {\"id\":\"malformed\n\n"))
+		}
+		data, _ := json.Marshal(chunk)
+		frames = append(frames, []byte(fmt.Sprintf("data: %s\n\n", data)))
+	}
+	if b.malformedAfter >= len(b.Chunks()) {
+		frames = append(frames, []byte("data: {\"id\":\"malformed\n\n"))
+	}
+	if !b.omitDone {
+		frames = append(frames, []byte("data: [DONE]\n\n"))
+	}
+	return frames
+}
+
+// Bytes returns the complete SSE payload. Delays and injected errors are not
+// applied; use Reader for those.
+func (b *StreamBuilder) Bytes() []byte {
+	var out []byte
+	for _, frame := range b.frames() {
+		out = append(out, frame...)
+	}
+	return out
+}
+
+// String returns the complete SSE payload as a string
+func (b *StreamBuilder) String() string {
+	return string(b.Bytes())
+}
+
+// Reader returns a reader that emits the stream frame by frame, honoring the
+// configured delay and injected error
+func (b *StreamBuilder) Reader() io.ReadCloser {
+	frames := b.frames()
+	if b.errAfter >= 0 && b.errAfter < len(frames) {
+		frames = frames[:b.errAfter]
+	}
+	return &streamReader{frames: frames, delay: b.delay, err: b.err}
+}
+
+// streamReader emits one frame per Read call sequence
+type streamReader struct {
+	frames  [][]byte
+	current []byte
+	delay   time.Duration
+	err     error
+	closed  bool
+}
+
+func (r *streamReader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	if len(r.current) == 0 {
+		if len(r.frames) == 0 {
+			if r.err != nil {
+				return 0, r.err
+			}
+			return 0, io.EOF
+		}
+		if r.delay > 0 {
+			time.Sleep(r.delay)
+		}
+		r.current, r.frames = r.frames[0], r.frames[1:]
+	}
+
+	n := copy(p, r.current)
+	r.current = r.current[n:]
+	return n, nil
+}
+
+func (r *streamReader) Close() error {
+	r.closed = true
+	return nil
+}
@@ -0,0 +1,67 @@
+package vultraitest
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	vultrai "github.com/eqba1/vultrai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, r *vultrai.StreamReader) ([]*vultrai.StreamChatCompletion, error) {
+	t.Helper()
+	var chunks []*vultrai.StreamChatCompletion
+	for {
+		chunk, err := r.Recv()
+		if err == io.EOF {
+			return chunks, nil
+		}
+		if err != nil {
+			return chunks, err
+		}
+		chunks = append(chunks, chunk)
+	}
+}
+
+func TestStreamBuilder(t *testing.T) {
+	stream := NewStream("Hello, world!").ChunkSize(5)
+
+	chunks, err := readAll(t, vultrai.NewStreamReader(stream.Reader()))
+	require.NoError(t, err)
+
+	require.Len(t, chunks, 3)
+	assert.Equal(t, "assistant", chunks[0].Choices[0].Delta.Role)
+	assert.Equal(t, "Hello, world!", vultrai.AccumulateStreamContent(chunks))
+	assert.Equal(t, "stop", *chunks[2].Choices[0].FinishReason)
+}
+
+func TestStreamBuilderEmptyContent(t *testing.T) {
+	chunks := NewStream("").Chunks()
+	require.Len(t, chunks, 1)
+	assert.Equal(t, "stop", *chunks[0].Choices[0].FinishReason)
+}
+
+func TestStreamBuilderInjectedError(t *testing.T) {
+	boom := errors.New("connection reset")
+	stream := NewStream("abcdefgh").ChunkSize(2).ErrorAfter(2, boom)
+
+	chunks, err := readAll(t, vultrai.NewStreamReader(stream.Reader()))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, boom)
+	assert.Len(t, chunks, 2)
+}
+
+func TestStreamBuilderMalformedFrame(t *testing.T) {
+	stream := NewStream("abcd").ChunkSize(2).MalformedAfter(1)
+
+	chunks, err := readAll(t, vultrai.NewStreamReader(stream.Reader()))
+	require.Error(t, err)
+	assert.Len(t, chunks, 1)
+}
+
+func TestStreamBuilderWithoutDone(t *testing.T) {
+	assert.NotContains(t, NewStream("abc").WithoutDone().String(), "[DONE]")
+	assert.Contains(t, NewStream("abc").String(), "[DONE]")
+}